@@ -0,0 +1,21 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"eldar/model"
+)
+
+// ListActivity returns the entries of the event log of a board that follow the entry numbered after,
+// in ascending order. Pass 0 to fetch the whole log.
+func (c *Client) ListActivity(ctx context.Context, boardID string, after int64) ([]model.Activity, error) {
+	var entries []model.Activity
+	path := "/api/v1/boards/" + url.PathEscape(boardID) + "/activity?after=" + strconv.FormatInt(after, 10)
+	if err := c.do(ctx, http.MethodGet, path, nil, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
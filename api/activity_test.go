@@ -0,0 +1,29 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"eldar/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientListActivity(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/boards/b1/activity", r.URL.Path)
+		assert.Equal(t, "41", r.URL.Query().Get("after"))
+		_, _ = w.Write([]byte(`[{"seq":42,"board_id":"b1","task_id":"t1","task_title":"Write report","actor":"ana",` +
+			`"kind":"moved","from":"todo","to":"done","at":"2025-06-02T09:00:00Z"}]`))
+	}))
+	defer server.Close()
+
+	entries, err := NewClient(server.URL, nil).ListActivity(context.Background(), "b1", 41)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, int64(42), entries[0].Seq)
+	assert.Equal(t, model.ActivityMoved, entries[0].Kind)
+	assert.Equal(t, "done", entries[0].To)
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"eldar/model"
+)
+
+// UploadAttachment uploads a file and attaches it to a task. The server stores the content on disk
+// and rejects files larger than model.MaxAttachmentSize with status 413. Attaching a file does not
+// change the version of the task.
+//
+// Parameters:
+//   - taskID: The task to attach the file to
+//   - name: The file name
+//   - mediaType: The media type of the content, such as "image/png"
+//   - content: The content, exactly size bytes long
+//   - progress: A function called with the number of bytes sent so far, or nil
+//
+// Returns:
+//   - The attachment as stored by the server
+//   - An error if the upload failed
+func (c *Client) UploadAttachment(ctx context.Context, taskID, name, mediaType string, content io.Reader, size int64, progress func(sent int64)) (*model.Attachment, error) {
+	path := "/api/v1/tasks/" + url.PathEscape(taskID) + "/attachments?name=" + url.QueryEscape(name)
+	req, err := c.newRequest(ctx, http.MethodPost, path, &progressReader{r: content, progress: progress})
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", mediaType)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.send(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	var attachment model.Attachment
+	if err := json.NewDecoder(resp.Body).Decode(&attachment); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &attachment, nil
+}
+
+// DownloadAttachment writes the content of an attachment to w.
+//
+// Parameters:
+//   - attachmentID: The attachment to download
+//   - w: Where the content is written to
+//   - progress: A function called with the number of bytes received so far, or nil
+func (c *Client) DownloadAttachment(ctx context.Context, attachmentID string, w io.Writer, progress func(received int64)) error {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/attachments/"+url.PathEscape(attachmentID)+"/content", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.send(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if _, err := io.Copy(w, &progressReader{r: resp.Body, progress: progress}); err != nil {
+		return fmt.Errorf("failed to download attachment: %w", err)
+	}
+	return nil
+}
+
+// DeleteAttachment removes an attachment from its task
+func (c *Client) DeleteAttachment(ctx context.Context, attachmentID string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/attachments/"+url.PathEscape(attachmentID), nil, nil)
+}
+
+// progressReader reports the number of bytes read so far after every read
+type progressReader struct {
+	r        io.Reader
+	n        int64
+	progress func(int64)
+}
+
+// Read implements io.Reader
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.n += int64(n)
+		if p.progress != nil {
+			p.progress(p.n)
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientAttachments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /api/v1/tasks/t1/attachments":
+			assert.Equal(t, "plan v2.txt", r.URL.Query().Get("name"))
+			assert.Equal(t, "text/plain", r.Header.Get("Content-Type"))
+			assert.Equal(t, int64(11), r.ContentLength)
+			body, _ := io.ReadAll(r.Body)
+			assert.Equal(t, "hello world", string(body))
+			_, _ = w.Write([]byte(`{"id":"a1","task_id":"t1","name":"plan v2.txt","media_type":"text/plain","size":11}`))
+		case "POST /api/v1/tasks/t2/attachments":
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+		case "GET /api/v1/attachments/a1/content":
+			_, _ = w.Write([]byte("hello world"))
+		case "DELETE /api/v1/attachments/a1":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, nil)
+
+	var sent int64
+	attachment, err := client.UploadAttachment(context.Background(), "t1", "plan v2.txt", "text/plain",
+		strings.NewReader("hello world"), 11, func(n int64) { sent = n })
+	require.NoError(t, err)
+	assert.Equal(t, "a1", attachment.ID)
+	assert.Equal(t, int64(11), sent)
+
+	_, err = client.UploadAttachment(context.Background(), "t2", "big.bin", "application/octet-stream",
+		strings.NewReader("x"), 1, nil)
+	assert.True(t, IsStatus(err, http.StatusRequestEntityTooLarge))
+
+	var content bytes.Buffer
+	var received int64
+	require.NoError(t, client.DownloadAttachment(context.Background(), "a1", &content, func(n int64) { received = n }))
+	assert.Equal(t, "hello world", content.String())
+	assert.Equal(t, int64(11), received)
+
+	assert.NoError(t, client.DeleteAttachment(context.Background(), "a1"))
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"eldar/credentials"
+)
+
+// loginRequest is the body of the login endpoint
+type loginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// TokenResponse holds the tokens issued by the server after a login or refresh
+type TokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token,omitempty"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in,omitempty"`
+	RefreshExpiresIn int64  `json:"refresh_expires_in,omitempty"`
+	Scope            string `json:"scope,omitempty"`
+}
+
+// ApplyTo copies the tokens and their metadata into creds, computing expiry times from now.
+// The refresh token, its expiry and the scopes are kept when the response does not carry new ones.
+func (t *TokenResponse) ApplyTo(creds *credentials.Credentials, now time.Time) {
+	creds.AccessToken = t.AccessToken
+	creds.TokenType = t.TokenType
+	creds.AccessTokenExpiresAt = time.Time{}
+	if t.ExpiresIn > 0 {
+		creds.AccessTokenExpiresAt = now.Add(time.Duration(t.ExpiresIn) * time.Second)
+	}
+	if t.RefreshToken != "" {
+		creds.RefreshToken = t.RefreshToken
+		creds.RefreshTokenExpiresAt = time.Time{}
+	}
+	if t.RefreshExpiresIn > 0 {
+		creds.RefreshTokenExpiresAt = now.Add(time.Duration(t.RefreshExpiresIn) * time.Second)
+	}
+	if t.Scope != "" {
+		creds.Scopes = strings.Fields(t.Scope)
+	}
+}
+
+// Login signs in with an email and password.
+// It returns an *Error with status 401 when the credentials are wrong and with status 429 and
+// RetryAfter set when the server throttles attempts for the account or client address.
+func (c *Client) Login(ctx context.Context, email, password string) (*TokenResponse, error) {
+	var resp TokenResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/auth/login", loginRequest{
+		Email:    email,
+		Password: password,
+	}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// refreshRequest is the body of the token refresh endpoint
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Refresh exchanges a refresh token for a new access token.
+// It returns an *Error with status 401 when the refresh token is expired or revoked.
+func (c *Client) Refresh(ctx context.Context, refreshToken string) (*TokenResponse, error) {
+	var resp TokenResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/auth/refresh", refreshRequest{RefreshToken: refreshToken}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// revokeRequest is the body of the session revocation endpoints
+type revokeRequest struct {
+	RefreshToken string `json:"refresh_token"`
+	AllSessions  bool   `json:"all_sessions,omitempty"`
+}
+
+// Revoke revokes the session identified by refreshToken on the Eldar server.
+// When allSessions is true every session of the account is revoked, signing it out on all devices.
+func (c *Client) Revoke(ctx context.Context, refreshToken string, allSessions bool) error {
+	return c.do(ctx, http.MethodPost, "/api/v1/auth/revoke", revokeRequest{
+		RefreshToken: refreshToken,
+		AllSessions:  allSessions,
+	}, nil)
+}
+
+// verifyPasswordRequest is the body of the password verification endpoint
+type verifyPasswordRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// VerifyPassword checks the account password without creating a new session.
+// It returns an *Error with status 401 when the password is wrong.
+func (c *Client) VerifyPassword(ctx context.Context, email, password string) error {
+	return c.do(ctx, http.MethodPost, "/api/v1/auth/verify-password", verifyPasswordRequest{
+		Email:    email,
+		Password: password,
+	}, nil)
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"eldar/model"
+)
+
+// ListBoards returns the boards visible to the signed in account
+func (c *Client) ListBoards(ctx context.Context) ([]model.Board, error) {
+	var boards []model.Board
+	if err := c.do(ctx, http.MethodGet, "/api/v1/boards", nil, &boards); err != nil {
+		return nil, err
+	}
+	return boards, nil
+}
+
+// CreateBoard creates a board and returns it as stored by the server
+func (c *Client) CreateBoard(ctx context.Context, board model.Board) (*model.Board, error) {
+	var created model.Board
+	if err := c.do(ctx, http.MethodPost, "/api/v1/boards", board, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// UpdateBoard replaces the name, columns, labels and custom fields of a board and returns it as stored by the server.
+// The server rejects the update with status 409 when board.Version is not the current version.
+func (c *Client) UpdateBoard(ctx context.Context, board model.Board) (*model.Board, error) {
+	var updated model.Board
+	if err := c.do(ctx, http.MethodPut, "/api/v1/boards/"+url.PathEscape(board.ID), board, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// ListTasks returns the tasks of a board
+func (c *Client) ListTasks(ctx context.Context, boardID string) ([]model.Task, error) {
+	var tasks []model.Task
+	if err := c.do(ctx, http.MethodGet, "/api/v1/boards/"+url.PathEscape(boardID)+"/tasks", nil, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// CreateTask creates a task and returns it as stored by the server
+func (c *Client) CreateTask(ctx context.Context, task model.Task) (*model.Task, error) {
+	var created model.Task
+	if err := c.do(ctx, http.MethodPost, "/api/v1/boards/"+url.PathEscape(task.BoardID)+"/tasks", task, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// UpdateTask replaces a task and returns it as stored by the server.
+// The server rejects the update with status 409 when task.Version is not the current version.
+func (c *Client) UpdateTask(ctx context.Context, task model.Task) (*model.Task, error) {
+	var updated model.Task
+	if err := c.do(ctx, http.MethodPut, "/api/v1/tasks/"+url.PathEscape(task.ID), task, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteTask moves a task to the trash, from which it can be restored with RestoreTask
+// until model.TrashRetention has passed
+func (c *Client) DeleteTask(ctx context.Context, taskID string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/tasks/"+url.PathEscape(taskID), nil, nil)
+}
+
+// reorderChecklistRequest is the body of the checklist reorder endpoint
+type reorderChecklistRequest struct {
+	Version int64    `json:"version"`
+	ItemIDs []string `json:"item_ids"`
+}
+
+// ReorderChecklist sets the order of the checklist items of a task and returns the updated task.
+// itemIDs must list every item of the checklist once. The server rejects the change with status 409
+// when version is not the current version of the task.
+func (c *Client) ReorderChecklist(ctx context.Context, taskID string, version int64, itemIDs []string) (*model.Task, error) {
+	var updated model.Task
+	if err := c.do(ctx, http.MethodPut, "/api/v1/tasks/"+url.PathEscape(taskID)+"/checklist/order", reorderChecklistRequest{
+		Version: version,
+		ItemIDs: itemIDs,
+	}, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// convertedChecklistItem is the response of the checklist item conversion endpoint
+type convertedChecklistItem struct {
+	Parent  model.Task `json:"parent"`
+	Subtask model.Task `json:"subtask"`
+}
+
+// ConvertChecklistItem turns a checklist item into a subtask of its task in a single server transaction.
+// It returns the parent task without the item and the subtask created from it.
+func (c *Client) ConvertChecklistItem(ctx context.Context, taskID, itemID string) (*model.Task, *model.Task, error) {
+	var converted convertedChecklistItem
+	if err := c.do(ctx, http.MethodPost, "/api/v1/tasks/"+url.PathEscape(taskID)+"/checklist/"+url.PathEscape(itemID)+"/convert", nil, &converted); err != nil {
+		return nil, nil, err
+	}
+	return &converted.Parent, &converted.Subtask, nil
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"eldar/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientTasks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/v1/boards":
+			_, _ = w.Write([]byte(`[{"id":"b1","name":"Sprint","columns":[{"id":"c1","name":"To do"}]}]`))
+		case "GET /api/v1/boards/b1/tasks":
+			_, _ = w.Write([]byte(`[{"id":"t1","board_id":"b1","column_id":"c1","title":"Write report","priority":"high",` +
+				`"fields":{"f1":{"number":3}},"version":3}]`))
+		case "POST /api/v1/boards":
+			var board model.Board
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&board))
+			board.ID, board.Version = "b2", 1
+			_ = json.NewEncoder(w).Encode(board)
+		case "PUT /api/v1/boards/b1":
+			var board model.Board
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&board))
+			assert.Equal(t, []model.Label{{Name: "bug", Color: "#e53935"}}, board.Labels)
+			board.Version++
+			_ = json.NewEncoder(w).Encode(board)
+		case "PUT /api/v1/tasks/t1":
+			var task model.Task
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&task))
+			if task.Version != 3 {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			task.Version++
+			_ = json.NewEncoder(w).Encode(task)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, nil)
+
+	boards, err := client.ListBoards(context.Background())
+	require.NoError(t, err)
+	require.Len(t, boards, 1)
+	assert.Equal(t, "To do", boards[0].Columns[0].Name)
+
+	created, err := client.CreateBoard(context.Background(), model.Board{Name: "Launch", Swimlanes: model.SwimlanesLabel})
+	require.NoError(t, err)
+	assert.Equal(t, "b2", created.ID)
+	assert.Equal(t, model.SwimlanesLabel, created.Swimlanes)
+
+	board := boards[0]
+	board.Labels = []model.Label{{Name: "bug", Color: "#e53935"}}
+	board.Fields = []model.Field{{ID: "f1", Name: "Estimate", Type: model.FieldNumber}}
+	updatedBoard, err := client.UpdateBoard(context.Background(), board)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), updatedBoard.Version)
+	assert.Equal(t, model.FieldNumber, updatedBoard.Fields[0].Type)
+
+	tasks, err := client.ListTasks(context.Background(), "b1")
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, model.PriorityHigh, tasks[0].Priority)
+	assert.Equal(t, 3.0, *tasks[0].Fields["f1"].Number)
+
+	task := tasks[0]
+	task.Title = "Write monthly report"
+	updated, err := client.UpdateTask(context.Background(), task)
+	require.NoError(t, err)
+	assert.Equal(t, "Write monthly report", updated.Title)
+	assert.Equal(t, int64(4), updated.Version)
+
+	task.Version = 1
+	_, err = client.UpdateTask(context.Background(), task)
+	assert.True(t, IsStatus(err, http.StatusConflict))
+}
+
+func TestClientChecklist(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "PUT /api/v1/tasks/t1/checklist/order":
+			var req reorderChecklistRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, int64(3), req.Version)
+			assert.Equal(t, []string{"b", "a"}, req.ItemIDs)
+			_, _ = w.Write([]byte(`{"id":"t1","title":"Write report","checklist":[{"id":"b","text":"B"},{"id":"a","text":"A"}],"version":4}`))
+		case "POST /api/v1/tasks/t1/checklist/b/convert":
+			_, _ = w.Write([]byte(`{"parent":{"id":"t1","title":"Write report","checklist":[{"id":"a","text":"A"}],"version":5},` +
+				`"subtask":{"id":"t2","parent_id":"t1","title":"B","version":1}}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, nil)
+
+	reordered, err := client.ReorderChecklist(context.Background(), "t1", 3, []string{"b", "a"})
+	require.NoError(t, err)
+	assert.Equal(t, "b", reordered.Checklist[0].ID)
+	assert.Equal(t, int64(4), reordered.Version)
+
+	parent, subtask, err := client.ConvertChecklistItem(context.Background(), "t1", "b")
+	require.NoError(t, err)
+	assert.Len(t, parent.Checklist, 1)
+	assert.Equal(t, "t1", subtask.ParentID)
+	assert.Equal(t, "B", subtask.Title)
+}
@@ -0,0 +1,20 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"eldar/model"
+)
+
+// BatchTasks applies a batch of task updates and deletions in a single server transaction. Changes the
+// server cannot make, such as updates of tasks edited by someone else since, are listed in the
+// Failed field of the result while the others are still made; an error is only returned when the
+// batch as a whole was rejected.
+func (c *Client) BatchTasks(ctx context.Context, batch model.TaskBatch) (*model.BatchResult, error) {
+	var result model.BatchResult
+	if err := c.do(ctx, http.MethodPost, "/api/v1/tasks/batch", batch, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"eldar/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientBatchTasks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method+" "+r.URL.Path != "POST /api/v1/tasks/batch" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var batch model.TaskBatch
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&batch))
+		if len(batch.Update) == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		assert.Equal(t, []string{"t3"}, batch.Delete)
+		_, _ = w.Write([]byte(`{"updated":[{"id":"t1","board_id":"b1","column_id":"done","title":"Fix login","priority":"none","version":3}],` +
+			`"deleted":["t3"],"failed":[{"task_id":"t2","error":"version conflict"}]}`))
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, nil)
+
+	result, err := client.BatchTasks(context.Background(), model.TaskBatch{
+		Update: []model.Task{{ID: "t1", ColumnID: "done", Version: 2}, {ID: "t2", ColumnID: "done", Version: 1}},
+		Delete: []string{"t3"},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Updated, 1)
+	assert.Equal(t, int64(3), result.Updated[0].Version)
+	assert.Equal(t, []string{"t3"}, result.Deleted)
+	assert.Equal(t, []model.BatchFailure{{TaskID: "t2", Error: "version conflict"}}, result.Failed)
+
+	_, err = client.BatchTasks(context.Background(), model.TaskBatch{Delete: []string{"t3"}})
+	assert.True(t, IsStatus(err, http.StatusBadRequest))
+}
@@ -0,0 +1,158 @@
+// Package api provides the HTTP client used to talk to the Eldar server.
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBaseURL is used when no server URL is configured
+const DefaultBaseURL = "http://localhost:8080"
+
+// Error is returned when the Eldar server answers with a non-2xx status
+type Error struct {
+	StatusCode int
+	Code       string `json:"error"`
+	Message    string `json:"message"`
+	// RetryAfter is how long the server asked the client to wait before retrying, from the Retry-After header
+	RetryAfter time.Duration `json:"-"`
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("server returned %d %s: %s", e.StatusCode, e.Code, e.Message)
+	}
+	return fmt.Sprintf("server returned %d", e.StatusCode)
+}
+
+// IsStatus reports whether err is an *Error with the given status code
+func IsStatus(err error, statusCode int) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
+
+// Client is a client of the Eldar server REST API
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+
+	mu          sync.RWMutex
+	accessToken string
+}
+
+// NewClient creates a client for the Eldar server at baseURL.
+// DefaultBaseURL is used when baseURL is empty and http.DefaultClient when httpClient is nil.
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{baseURL: strings.TrimSuffix(baseURL, "/"), httpClient: httpClient}
+}
+
+// SetAccessToken sets the bearer token sent with every request
+func (c *Client) SetAccessToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.accessToken = token
+}
+
+// do sends a JSON request to the server and decodes the JSON response into out.
+// A nil body sends no request body and a nil out discards the response body.
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := c.newRequest(ctx, method, path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.send(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// newRequest creates a request to the server carrying the access token, if any
+func (c *Client) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	c.mu.RLock()
+	if c.accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	}
+	c.mu.RUnlock()
+	return req, nil
+}
+
+// send sends a request and returns the response when it has a 2xx status, or an *Error otherwise.
+// The caller must close the body of the returned response.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+		apiErr := &Error{StatusCode: resp.StatusCode, RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())}
+		_ = json.NewDecoder(resp.Body).Decode(apiErr)
+		return nil, apiErr
+	}
+	return resp, nil
+}
+
+// parseRetryAfter parses a Retry-After header value given either as delay seconds or as an
+// HTTP date (RFC 9110 section 10.2.3). It returns zero for an empty or invalid value.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
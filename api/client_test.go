@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"eldar/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientRevoke(t *testing.T) {
+	var got revokeRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/auth/revoke", r.URL.Path)
+		assert.Equal(t, "Bearer access-1", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, nil)
+	client.SetAccessToken("access-1")
+	require.NoError(t, client.Revoke(context.Background(), "refresh-1", true))
+	assert.Equal(t, revokeRequest{RefreshToken: "refresh-1", AllSessions: true}, got)
+}
+
+func TestClientError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":"invalid_token","message":"token expired"}`))
+	}))
+	defer server.Close()
+
+	err := NewClient(server.URL, nil).Revoke(context.Background(), "refresh-1", false)
+	assert.True(t, IsStatus(err, http.StatusUnauthorized))
+	assert.EqualError(t, err, "server returned 401 invalid_token: token expired")
+}
+
+func TestClientLoginThrottled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/auth/login", r.URL.Path)
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"error":"too_many_attempts","message":"try again later"}`))
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL, nil).Login(context.Background(), "eldar@ioluas.dev", "secret")
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode)
+	assert.Equal(t, 30*time.Second, apiErr.RetryAfter)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, 120*time.Second, parseRetryAfter("120", now))
+	assert.Equal(t, 90*time.Second, parseRetryAfter(now.Add(90*time.Second).Format(http.TimeFormat), now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter(now.Add(-time.Minute).Format(http.TimeFormat), now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("-5", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("", now))
+}
+
+func TestClientRefresh(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/auth/refresh", r.URL.Path)
+		var req refreshRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "refresh-1", req.RefreshToken)
+		_, _ = w.Write([]byte(`{"access_token":"access-2","token_type":"Bearer","expires_in":900,"scope":"tasks:read tasks:write"}`))
+	}))
+	defer server.Close()
+
+	tokens, err := NewClient(server.URL, nil).Refresh(context.Background(), "refresh-1")
+	require.NoError(t, err)
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	creds := &credentials.Credentials{Username: "eldar@ioluas.dev", AccessToken: "access-1", RefreshToken: "refresh-1"}
+	tokens.ApplyTo(creds, now)
+	assert.Equal(t, "access-2", creds.AccessToken)
+	assert.Equal(t, "refresh-1", creds.RefreshToken)
+	assert.Equal(t, now.Add(15*time.Minute), creds.AccessTokenExpiresAt)
+	assert.True(t, creds.HasScope("tasks:write"))
+}
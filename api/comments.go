@@ -0,0 +1,49 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"eldar/model"
+)
+
+// ListComments returns the comments of a task, including deleted ones, in no particular order
+func (c *Client) ListComments(ctx context.Context, taskID string) ([]model.Comment, error) {
+	var comments []model.Comment
+	if err := c.do(ctx, http.MethodGet, "/api/v1/tasks/"+url.PathEscape(taskID)+"/comments", nil, &comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// CreateComment posts a comment on its task and returns it as stored by the server.
+// The server notifies the members in comment.Mentions through the event stream, see Events.
+func (c *Client) CreateComment(ctx context.Context, comment model.Comment) (*model.Comment, error) {
+	var created model.Comment
+	if err := c.do(ctx, http.MethodPost, "/api/v1/tasks/"+url.PathEscape(comment.TaskID)+"/comments", comment, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// UpdateComment replaces the body of a comment and returns it as stored by the server, with the
+// previous body added to its history. The server rejects the update with status 409 when
+// comment.Version is not the current version, and with status 403 when another account wrote it.
+func (c *Client) UpdateComment(ctx context.Context, comment model.Comment) (*model.Comment, error) {
+	var updated model.Comment
+	if err := c.do(ctx, http.MethodPut, "/api/v1/comments/"+url.PathEscape(comment.ID), comment, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteComment deletes a comment and returns it as stored by the server, marked as deleted
+// with its body moved to the history
+func (c *Client) DeleteComment(ctx context.Context, commentID string) (*model.Comment, error) {
+	var deleted model.Comment
+	if err := c.do(ctx, http.MethodDelete, "/api/v1/comments/"+url.PathEscape(commentID), nil, &deleted); err != nil {
+		return nil, err
+	}
+	return &deleted, nil
+}
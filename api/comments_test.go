@@ -0,0 +1,56 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"eldar/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientComments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/v1/tasks/t1/comments":
+			_, _ = w.Write([]byte(`[{"id":"c1","task_id":"t1","author":"ana","body":"Draft is up","version":1},` +
+				`{"id":"c2","task_id":"t1","parent_id":"c1","author":"ben","deleted":true,"version":2}]`))
+		case "POST /api/v1/tasks/t1/comments":
+			var comment model.Comment
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&comment))
+			assert.Equal(t, []string{"ana"}, comment.Mentions)
+			comment.ID, comment.Version = "c3", 1
+			_ = json.NewEncoder(w).Encode(comment)
+		case "PUT /api/v1/comments/c1":
+			w.WriteHeader(http.StatusConflict)
+		case "DELETE /api/v1/comments/c3":
+			_, _ = w.Write([]byte(`{"id":"c3","task_id":"t1","author":"ben","deleted":true,"history":[{"body":"Thanks @ana"}],"version":2}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, nil)
+
+	comments, err := client.ListComments(context.Background(), "t1")
+	require.NoError(t, err)
+	require.Len(t, comments, 2)
+	assert.Equal(t, "c1", comments[1].ParentID)
+	assert.True(t, comments[1].Deleted)
+
+	created, err := client.CreateComment(context.Background(), model.Comment{TaskID: "t1", Body: "Thanks @ana", Mentions: []string{"ana"}})
+	require.NoError(t, err)
+	assert.Equal(t, "c3", created.ID)
+
+	_, err = client.UpdateComment(context.Background(), comments[0])
+	assert.True(t, IsStatus(err, http.StatusConflict))
+
+	deleted, err := client.DeleteComment(context.Background(), "c3")
+	require.NoError(t, err)
+	assert.True(t, deleted.Deleted)
+	assert.Equal(t, "Thanks @ana", deleted.History[0].Body)
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"eldar/model"
+)
+
+// Types of the events sent through the event stream
+const (
+	// EventComment is sent when a comment is posted, edited or deleted, its data is the model.Comment
+	EventComment = "comment"
+	// EventMention is sent to a member mentioned in a comment, its data is a Mention
+	EventMention = "mention"
+	// EventActivity is sent when an entry is appended to the event log of a board, its data is the model.Activity
+	EventActivity = "activity"
+)
+
+// Event is a message of the real-time event stream of the signed in account
+type Event struct {
+	// ID is passed back as lastEventID when reconnecting, so no event is missed
+	ID   string
+	Type string
+	Data json.RawMessage
+}
+
+// Mention is the data of an EventMention
+type Mention struct {
+	Comment   model.Comment `json:"comment"`
+	TaskTitle string        `json:"task_title"`
+}
+
+// Events streams the real-time events of the signed in account as server-sent events, calling handle
+// for each one from the calling goroutine. It blocks until ctx is done or the connection is lost,
+// and always returns an error, which is ctx.Err() when ctx is done.
+//
+// Parameters:
+//   - ctx: Cancelled to stop streaming
+//   - lastEventID: The ID of the last event handled before a reconnect, or an empty string
+//   - handle: A function called with every event
+func (c *Client) Events(ctx context.Context, lastEventID string, handle func(Event)) error {
+	req, err := c.newRequest(ctx, http.MethodGet, "/api/v1/events", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := c.send(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	var event Event
+	var data []string
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			// A blank line ends an event
+			if len(data) > 0 {
+				if event.Type == "" {
+					event.Type = "message"
+				}
+				event.Data = json.RawMessage(strings.Join(data, "\n"))
+				handle(event)
+			}
+			event, data = Event{ID: event.ID}, nil
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "id":
+			event.ID = value
+		case "event":
+			event.Type = value
+		case "data":
+			data = append(data, value)
+		}
+		// Lines starting with a colon are comments the server sends to keep the connection open
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read event stream: %w", err)
+	}
+	return errors.New("event stream closed by the server")
+}
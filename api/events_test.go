@@ -0,0 +1,56 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientEvents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/events", r.URL.Path)
+		assert.Equal(t, "text/event-stream", r.Header.Get("Accept"))
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		if r.Header.Get("Last-Event-ID") == "expired" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		assert.Equal(t, "41", r.Header.Get("Last-Event-ID"))
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(": keep-alive\n\n" +
+			"id: 42\nevent: mention\ndata: {\"task_title\":\"Write report\",\n" +
+			"data: \"comment\":{\"id\":\"c1\",\"body\":\"@ana please review\"}}\n\n" +
+			"event: comment\ndata: {\"id\":\"c2\"}\n\n" +
+			"data: incomplete"))
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, nil)
+	client.SetAccessToken("token")
+
+	var events []Event
+	err := client.Events(context.Background(), "41", func(e Event) {
+		events = append(events, e)
+	})
+	assert.ErrorContains(t, err, "closed by the server")
+	require.Len(t, events, 2)
+
+	assert.Equal(t, "42", events[0].ID)
+	assert.Equal(t, EventMention, events[0].Type)
+	var mention Mention
+	require.NoError(t, json.Unmarshal(events[0].Data, &mention))
+	assert.Equal(t, "Write report", mention.TaskTitle)
+	assert.Equal(t, "@ana please review", mention.Comment.Body)
+
+	// The last event ID carries over to events without one
+	assert.Equal(t, "42", events[1].ID)
+	assert.Equal(t, EventComment, events[1].Type)
+	assert.JSONEq(t, `{"id":"c2"}`, string(events[1].Data))
+
+	err = client.Events(context.Background(), "expired", func(Event) {})
+	assert.True(t, IsStatus(err, http.StatusUnauthorized))
+}
@@ -0,0 +1,17 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"eldar/model"
+)
+
+// ListMembers returns the members of the group of the signed in account, including itself
+func (c *Client) ListMembers(ctx context.Context) ([]model.Member, error) {
+	var members []model.Member
+	if err := c.do(ctx, http.MethodGet, "/api/v1/group/members", nil, &members); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
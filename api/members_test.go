@@ -0,0 +1,26 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"eldar/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientListMembers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/api/v1/group/members" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`[{"username":"ana@example.com","name":"Ana Lima","handle":"ana"}]`))
+	}))
+	defer server.Close()
+
+	members, err := NewClient(server.URL, nil).ListMembers(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []model.Member{{Username: "ana@example.com", Name: "Ana Lima", Handle: "ana"}}, members)
+}
@@ -0,0 +1,13 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// ClaimReminder claims the delivery of a reminder for this device.
+// It returns an *Error with status 409 when another device of the account already claimed it.
+func (c *Client) ClaimReminder(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPost, "/api/v1/reminders/"+url.PathEscape(id)+"/claim", nil, nil)
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientClaimReminder(t *testing.T) {
+	claimed := map[string]bool{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.EscapedPath() != "/api/v1/reminders/t1:1748779200:60/claim" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.EscapedPath())
+		}
+		if claimed[r.URL.Path] {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		claimed[r.URL.Path] = true
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, nil)
+
+	require.NoError(t, client.ClaimReminder(context.Background(), "t1:1748779200:60"))
+	assert.True(t, IsStatus(client.ClaimReminder(context.Background(), "t1:1748779200:60"), http.StatusConflict))
+}
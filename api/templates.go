@@ -0,0 +1,33 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"eldar/model"
+)
+
+// ListTemplates returns the board and task templates saved by the group of the signed in account
+func (c *Client) ListTemplates(ctx context.Context) ([]model.Template, error) {
+	var templates []model.Template
+	if err := c.do(ctx, http.MethodGet, "/api/v1/templates", nil, &templates); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// CreateTemplate saves a template and returns it as stored by the server
+func (c *Client) CreateTemplate(ctx context.Context, template model.Template) (*model.Template, error) {
+	var created model.Template
+	if err := c.do(ctx, http.MethodPost, "/api/v1/templates", template, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// DeleteTemplate deletes a template. The server rejects the deletion with status 403 when the template
+// belongs to another account.
+func (c *Client) DeleteTemplate(ctx context.Context, templateID string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/templates/"+url.PathEscape(templateID), nil, nil)
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"eldar/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientTemplates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/v1/templates":
+			_, _ = w.Write([]byte(`[{"id":"tp1","name":"Sprint","kind":"board","board":{"name":"Sprint","columns":[{"id":"todo","name":"To do"}]},` +
+				`"tasks":[{"column_id":"todo","title":"Planning","priority":"high"}],"owner":"ana","version":1}]`))
+		case "POST /api/v1/templates":
+			var template model.Template
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&template))
+			template.ID, template.Owner, template.Version = "tp2", "ana", 1
+			_ = json.NewEncoder(w).Encode(template)
+		case "DELETE /api/v1/templates/tp1":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, nil)
+
+	templates, err := client.ListTemplates(context.Background())
+	require.NoError(t, err)
+	require.Len(t, templates, 1)
+	assert.Equal(t, model.TemplateBoard, templates[0].Kind)
+	require.NotNil(t, templates[0].Board)
+	assert.Equal(t, "To do", templates[0].Board.Columns[0].Name)
+	assert.Equal(t, model.PriorityHigh, templates[0].Tasks[0].Priority)
+
+	created, err := client.CreateTemplate(context.Background(), model.NewTaskTemplate("Release", model.Task{Title: "Release"}))
+	require.NoError(t, err)
+	assert.Equal(t, "tp2", created.ID)
+	assert.Equal(t, "Release", created.Tasks[0].Title)
+
+	err = client.DeleteTemplate(context.Background(), "tp1")
+	assert.True(t, IsStatus(err, http.StatusForbidden))
+}
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"eldar/model"
+)
+
+// ListTimeEntries returns the time entries of every member on the tasks of a board
+func (c *Client) ListTimeEntries(ctx context.Context, boardID string) ([]model.TimeEntry, error) {
+	var entries []model.TimeEntry
+	if err := c.do(ctx, http.MethodGet, "/api/v1/boards/"+url.PathEscape(boardID)+"/time-entries", nil, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// CreateTimeEntry records time spent on a task and returns the entry as stored by the server
+func (c *Client) CreateTimeEntry(ctx context.Context, entry model.TimeEntry) (*model.TimeEntry, error) {
+	var created model.TimeEntry
+	if err := c.do(ctx, http.MethodPost, "/api/v1/tasks/"+url.PathEscape(entry.TaskID)+"/time-entries", entry, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// DeleteTimeEntry deletes a time entry, only the member who spent the time can delete it
+func (c *Client) DeleteTimeEntry(ctx context.Context, entryID string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/time-entries/"+url.PathEscape(entryID), nil, nil)
+}
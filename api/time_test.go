@@ -0,0 +1,53 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"eldar/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientTimeEntries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/v1/boards/b1/time-entries":
+			_, _ = w.Write([]byte(`[{"id":"e1","task_id":"t1","board_id":"b1","user":"ana",` +
+				`"start":"2025-06-02T09:00:00Z","end":"2025-06-02T10:30:00Z","manual":true}]`))
+		case "POST /api/v1/tasks/t1/time-entries":
+			var entry model.TimeEntry
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&entry))
+			assert.Equal(t, "Review", entry.Note)
+			entry.ID = "e2"
+			_ = json.NewEncoder(w).Encode(entry)
+		case "DELETE /api/v1/time-entries/e1":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, nil)
+
+	entries, err := client.ListTimeEntries(context.Background(), "b1")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, 90*time.Minute, entries[0].Duration())
+	assert.True(t, entries[0].Manual)
+
+	start := time.Date(2025, 6, 3, 9, 0, 0, 0, time.UTC)
+	created, err := client.CreateTimeEntry(context.Background(), model.TimeEntry{
+		TaskID: "t1", BoardID: "b1", User: "ana", Start: start, End: start.Add(time.Hour), Note: "Review",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "e2", created.ID)
+	assert.Equal(t, time.Hour, created.Duration())
+
+	require.NoError(t, client.DeleteTimeEntry(context.Background(), "e1"))
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"eldar/model"
+)
+
+// ListTrash returns the boards and tasks of the signed in account that are in the trash
+func (c *Client) ListTrash(ctx context.Context) (*model.Trash, error) {
+	var trash model.Trash
+	if err := c.do(ctx, http.MethodGet, "/api/v1/trash", nil, &trash); err != nil {
+		return nil, err
+	}
+	return &trash, nil
+}
+
+// RestoreTask takes a task out of the trash and returns it as stored by the server
+func (c *Client) RestoreTask(ctx context.Context, taskID string) (*model.Task, error) {
+	var restored model.Task
+	if err := c.do(ctx, http.MethodPost, "/api/v1/tasks/"+url.PathEscape(taskID)+"/restore", nil, &restored); err != nil {
+		return nil, err
+	}
+	return &restored, nil
+}
+
+// DeleteBoard moves a board and its tasks to the trash, from which they can be restored with RestoreBoard
+// until model.TrashRetention has passed
+func (c *Client) DeleteBoard(ctx context.Context, boardID string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/boards/"+url.PathEscape(boardID), nil, nil)
+}
+
+// RestoreBoard takes a board and its tasks out of the trash and returns the board as stored by the server
+func (c *Client) RestoreBoard(ctx context.Context, boardID string) (*model.Board, error) {
+	var restored model.Board
+	if err := c.do(ctx, http.MethodPost, "/api/v1/boards/"+url.PathEscape(boardID)+"/restore", nil, &restored); err != nil {
+		return nil, err
+	}
+	return &restored, nil
+}
+
+// archiveBoardRequest is the body of the board archive endpoint
+type archiveBoardRequest struct {
+	Archived bool `json:"archived"`
+}
+
+// ArchiveBoard archives or unarchives a board and returns it as stored by the server
+func (c *Client) ArchiveBoard(ctx context.Context, boardID string, archived bool) (*model.Board, error) {
+	var updated model.Board
+	if err := c.do(ctx, http.MethodPut, "/api/v1/boards/"+url.PathEscape(boardID)+"/archived", archiveBoardRequest{
+		Archived: archived,
+	}, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientTrash(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/v1/trash":
+			_, _ = w.Write([]byte(`{"boards":[{"id":"b2","name":"Old","deleted_at":"2025-06-01T10:00:00Z"}],` +
+				`"tasks":[{"id":"t1","board_id":"b1","title":"Write report","priority":"none","deleted_at":"2025-06-02T10:00:00Z"}]}`))
+		case "POST /api/v1/tasks/t1/restore":
+			_, _ = w.Write([]byte(`{"id":"t1","board_id":"b1","title":"Write report","priority":"none","version":4}`))
+		case "DELETE /api/v1/boards/b1":
+			w.WriteHeader(http.StatusNoContent)
+		case "POST /api/v1/boards/b2/restore":
+			w.WriteHeader(http.StatusNotFound)
+		case "PUT /api/v1/boards/b1/archived":
+			var body archiveBoardRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.True(t, body.Archived)
+			_, _ = w.Write([]byte(`{"id":"b1","name":"Sprint","archived_at":"2025-06-03T10:00:00Z","version":2}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, nil)
+
+	trash, err := client.ListTrash(context.Background())
+	require.NoError(t, err)
+	require.Len(t, trash.Boards, 1)
+	require.Len(t, trash.Tasks, 1)
+	assert.Equal(t, 2, trash.Tasks[0].DeletedAt.Day())
+
+	restored, err := client.RestoreTask(context.Background(), "t1")
+	require.NoError(t, err)
+	assert.Nil(t, restored.DeletedAt)
+	assert.Equal(t, int64(4), restored.Version)
+
+	require.NoError(t, client.DeleteBoard(context.Background(), "b1"))
+	_, err = client.RestoreBoard(context.Background(), "b2")
+	assert.True(t, IsStatus(err, http.StatusNotFound))
+
+	archived, err := client.ArchiveBoard(context.Background(), "b1", true)
+	require.NoError(t, err)
+	assert.True(t, archived.Archived())
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"eldar/model"
+)
+
+// ListViews returns the saved views of the signed in account and the views shared with its group
+func (c *Client) ListViews(ctx context.Context) ([]model.View, error) {
+	var views []model.View
+	if err := c.do(ctx, http.MethodGet, "/api/v1/views", nil, &views); err != nil {
+		return nil, err
+	}
+	return views, nil
+}
+
+// CreateView saves a view and returns it as stored by the server
+func (c *Client) CreateView(ctx context.Context, view model.View) (*model.View, error) {
+	var created model.View
+	if err := c.do(ctx, http.MethodPost, "/api/v1/views", view, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// UpdateView replaces a view and returns it as stored by the server.
+// The server rejects the update with status 409 when view.Version is not the current version,
+// and with status 403 when the view belongs to another account.
+func (c *Client) UpdateView(ctx context.Context, view model.View) (*model.View, error) {
+	var updated model.View
+	if err := c.do(ctx, http.MethodPut, "/api/v1/views/"+url.PathEscape(view.ID), view, &updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// DeleteView deletes a view
+func (c *Client) DeleteView(ctx context.Context, viewID string) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/views/"+url.PathEscape(viewID), nil, nil)
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"eldar/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientViews(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/v1/views":
+			_, _ = w.Write([]byte(`[{"id":"v1","name":"My open bugs","query":"label:bug assignee:me is:open","owner":"ana","version":1},` +
+				`{"id":"v2","name":"Release","query":"label:release","owner":"ben","shared":true,"version":2}]`))
+		case "POST /api/v1/views":
+			var view model.View
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&view))
+			view.ID, view.Version = "v3", 1
+			_ = json.NewEncoder(w).Encode(view)
+		case "PUT /api/v1/views/v2":
+			w.WriteHeader(http.StatusForbidden)
+		case "DELETE /api/v1/views/v1":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, nil)
+
+	views, err := client.ListViews(context.Background())
+	require.NoError(t, err)
+	require.Len(t, views, 2)
+	assert.True(t, views[1].Shared)
+
+	created, err := client.CreateView(context.Background(), model.View{Name: "Due this week", Query: "due:<7d", Shared: true})
+	require.NoError(t, err)
+	assert.Equal(t, "v3", created.ID)
+	assert.True(t, created.Shared)
+
+	_, err = client.UpdateView(context.Background(), views[1])
+	assert.True(t, IsStatus(err, http.StatusForbidden))
+
+	require.NoError(t, client.DeleteView(context.Background(), "v1"))
+}
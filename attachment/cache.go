@@ -0,0 +1,253 @@
+// Package attachment keeps a content-addressed cache of task attachments in the account data directory,
+// so attachments that were uploaded or opened once are available offline.
+// Files are stored by the SHA-256 of their content, which also deduplicates files attached to several tasks,
+// and image thumbnails are generated once and kept next to them.
+package attachment
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // Registers the decoders of the image formats thumbnails are made of
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"golang.org/x/image/draw"
+)
+
+// ErrTooLarge is returned by Put when the content is larger than allowed
+var ErrTooLarge = errors.New("file is too large")
+
+// ErrHashMismatch is returned by Put when the content does not have the expected hash
+var ErrHashMismatch = errors.New("content does not match its hash")
+
+// ErrTooManyPixels is returned by Thumbnail for images larger than MaxPixels
+var ErrTooManyPixels = errors.New("image has too many pixels")
+
+// MaxPixels is the largest number of pixels of images thumbnails are made of. A small file may declare
+// a huge image, which would take gigabytes of memory to decode, so their size is checked before decoding.
+const MaxPixels = 50_000_000
+
+// Cache is a content-addressed store of attachment files. It is safe for concurrent use,
+// as files are written to a temporary file first and renamed into place.
+type Cache struct {
+	dir string
+}
+
+// Open opens the cache in dir, creating it if needed.
+//
+// Parameters:
+//   - dir: The account data directory, see credentials.AccountDataDir
+//
+// Returns:
+//   - The opened Cache
+//   - An error if the cache directory could not be created
+func Open(dir string) (*Cache, error) {
+	c := &Cache{dir: filepath.Join(dir, "attachments")}
+	for _, sub := range []string{"blobs", "thumbnails", "named", "tmp"} {
+		if err := os.MkdirAll(filepath.Join(c.dir, sub), 0700); err != nil {
+			return nil, fmt.Errorf("failed to create attachment cache: %w", err)
+		}
+	}
+	return c, nil
+}
+
+// Put copies content into the cache and returns its hash and size.
+//
+// Parameters:
+//   - content: The content to store
+//   - expectedHash: The hash the content must have, or an empty string when it is not known yet
+//   - maxSize: The largest size accepted, ErrTooLarge is returned for larger content
+func (c *Cache) Put(content io.Reader, expectedHash string, maxSize int64) (string, int64, error) {
+	tmp, err := os.CreateTemp(filepath.Join(c.dir, "tmp"), "put-*")
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create cache file: %w", err)
+	}
+	defer func() {
+		// A no-op once the file was renamed into place
+		_ = os.Remove(tmp.Name())
+	}()
+
+	h := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, h), io.LimitReader(content, maxSize+1))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if size > maxSize {
+		return "", 0, ErrTooLarge
+	}
+	hash := hex.EncodeToString(h.Sum(nil))
+	if expectedHash != "" && hash != expectedHash {
+		return "", 0, ErrHashMismatch
+	}
+
+	path := c.Path(hash)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", 0, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", 0, fmt.Errorf("failed to save cache file: %w", err)
+	}
+	return hash, size, nil
+}
+
+// Has reports whether the content with the given hash is cached
+func (c *Cache) Has(hash string) bool {
+	if !validHash(hash) {
+		return false
+	}
+	_, err := os.Stat(c.Path(hash))
+	return err == nil
+}
+
+// Path returns where the content with the given hash is, or would be, cached
+func (c *Cache) Path(hash string) string {
+	if len(hash) < 2 {
+		return filepath.Join(c.dir, "blobs", hash)
+	}
+	return filepath.Join(c.dir, "blobs", hash[:2], hash)
+}
+
+// Named returns the path of a link to the cached content carrying the given file name,
+// so other applications can tell its type from the extension when it is opened
+func (c *Cache) Named(hash, name string) (string, error) {
+	if !c.Has(hash) {
+		return "", fmt.Errorf("attachment %s is not cached", hash)
+	}
+	name = filepath.Base(filepath.Clean("/" + name))
+	if name == "/" || name == "." {
+		name = hash
+	}
+	path := filepath.Join(c.dir, "named", hash, name)
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", fmt.Errorf("failed to create link directory: %w", err)
+	}
+	if err := os.Link(c.Path(hash), path); err != nil {
+		// Hard links are not supported everywhere, a copy does as well
+		if err := copyFile(c.Path(hash), path); err != nil {
+			return "", err
+		}
+	}
+	return path, nil
+}
+
+// Thumbnail returns the cached image with the given hash scaled down to fit a square of size pixels.
+// Thumbnails are generated on first use and cached as PNG files.
+func (c *Cache) Thumbnail(hash string, size int) (image.Image, error) {
+	if !c.Has(hash) {
+		return nil, fmt.Errorf("attachment %s is not cached", hash)
+	}
+	path := filepath.Join(c.dir, "thumbnails", hash+"-"+strconv.Itoa(size)+".png")
+	if img, err := decodeFile(path); err == nil {
+		return img, nil
+	}
+
+	src, err := decodeFile(c.Path(hash))
+	if err != nil {
+		return nil, err
+	}
+	thumb := Scale(src, size)
+	tmp, err := os.CreateTemp(filepath.Join(c.dir, "tmp"), "thumb-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create thumbnail: %w", err)
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+	err = png.Encode(tmp, thumb)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write thumbnail: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return nil, fmt.Errorf("failed to save thumbnail: %w", err)
+	}
+	return thumb, nil
+}
+
+// Scale returns img scaled down, keeping its aspect ratio, to fit a square of size pixels.
+// Images that already fit are returned unchanged.
+func Scale(img image.Image, size int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= size && h <= size {
+		return img
+	}
+	if w >= h {
+		w, h = size, max(1, h*size/w)
+	} else {
+		w, h = max(1, w*size/h), size
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+	return dst
+}
+
+// validHash reports whether hash is a hex encoded SHA-256, so it is safe to use in paths
+func validHash(hash string) bool {
+	b, err := hex.DecodeString(hash)
+	return err == nil && len(b) == sha256.Size
+}
+
+// decodeFile decodes the image in the file at path, unless it has more than MaxPixels pixels
+func decodeFile(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open image: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	config, _, err := image.DecodeConfig(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	if int64(config.Width)*int64(config.Height) > MaxPixels {
+		return nil, ErrTooManyPixels
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	return img, nil
+}
+
+// copyFile copies the file at src to dst
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open cache file: %w", err)
+	}
+	defer func() {
+		_ = in.Close()
+	}()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	_, err = io.Copy(out, in)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to copy file: %w", err)
+	}
+	return nil
+}
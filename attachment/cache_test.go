@@ -0,0 +1,131 @@
+package attachment
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sum(content string) string {
+	h := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(h[:])
+}
+
+func TestCachePut(t *testing.T) {
+	c, err := Open(t.TempDir())
+	require.NoError(t, err)
+
+	hash, size, err := c.Put(strings.NewReader("hello world"), "", 100)
+	require.NoError(t, err)
+	assert.Equal(t, sum("hello world"), hash)
+	assert.Equal(t, int64(11), size)
+	assert.True(t, c.Has(hash))
+	content, err := os.ReadFile(c.Path(hash))
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", string(content))
+
+	// The same content is stored once
+	again, _, err := c.Put(strings.NewReader("hello world"), hash, 100)
+	require.NoError(t, err)
+	assert.Equal(t, hash, again)
+
+	_, _, err = c.Put(strings.NewReader("hello world"), "", 10)
+	assert.ErrorIs(t, err, ErrTooLarge)
+	_, _, err = c.Put(strings.NewReader("tampered"), hash, 100)
+	assert.ErrorIs(t, err, ErrHashMismatch)
+	assert.False(t, c.Has(sum("tampered")))
+	assert.False(t, c.Has("../../etc/passwd"))
+
+	tmp, err := os.ReadDir(filepath.Join(c.dir, "tmp"))
+	require.NoError(t, err)
+	assert.Empty(t, tmp)
+}
+
+func TestCacheNamed(t *testing.T) {
+	c, err := Open(t.TempDir())
+	require.NoError(t, err)
+	hash, _, err := c.Put(strings.NewReader("%PDF"), "", 100)
+	require.NoError(t, err)
+
+	path, err := c.Named(hash, "../report.pdf")
+	require.NoError(t, err)
+	assert.Equal(t, "report.pdf", filepath.Base(path))
+	assert.Equal(t, filepath.Join(c.dir, "named", hash), filepath.Dir(path))
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "%PDF", string(content))
+
+	_, err = c.Named(sum("missing"), "missing.txt")
+	assert.Error(t, err)
+}
+
+func TestCacheThumbnail(t *testing.T) {
+	c, err := Open(t.TempDir())
+	require.NoError(t, err)
+	img := image.NewRGBA(image.Rect(0, 0, 400, 100))
+	for x := range 400 {
+		for y := range 100 {
+			img.Set(x, y, color.RGBA{R: 200, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	hash, _, err := c.Put(&buf, "", 1<<20)
+	require.NoError(t, err)
+
+	thumb, err := c.Thumbnail(hash, 64)
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 64, 16), thumb.Bounds())
+	r, _, _, _ := thumb.At(32, 8).RGBA()
+	assert.Equal(t, uint32(200), r>>8)
+
+	// Served from the thumbnail cache the second time
+	_, err = os.Stat(filepath.Join(c.dir, "thumbnails", hash+"-64.png"))
+	require.NoError(t, err)
+	thumb, err = c.Thumbnail(hash, 64)
+	require.NoError(t, err)
+	assert.Equal(t, 64, thumb.Bounds().Dx())
+
+	text, _, err := c.Put(strings.NewReader("not an image"), "", 100)
+	require.NoError(t, err)
+	_, err = c.Thumbnail(text, 64)
+	assert.Error(t, err)
+}
+
+func TestCacheThumbnailTooManyPixels(t *testing.T) {
+	c, err := Open(t.TempDir())
+	require.NoError(t, err)
+	// A PNG header declaring a 100000 x 100000 image, a few bytes on disk but 40 GB once decoded
+	ihdr := []byte("IHDR")
+	ihdr = binary.BigEndian.AppendUint32(ihdr, 100000)
+	ihdr = binary.BigEndian.AppendUint32(ihdr, 100000)
+	ihdr = append(ihdr, 8, 6, 0, 0, 0)
+	header := []byte("\x89PNG\r\n\x1a\n")
+	header = binary.BigEndian.AppendUint32(header, uint32(len(ihdr)-4))
+	header = append(header, ihdr...)
+	header = binary.BigEndian.AppendUint32(header, crc32.ChecksumIEEE(ihdr))
+	hash, _, err := c.Put(bytes.NewReader(header), "", 1<<20)
+	require.NoError(t, err)
+
+	_, err = c.Thumbnail(hash, 64)
+	assert.ErrorIs(t, err, ErrTooManyPixels)
+}
+
+func TestScale(t *testing.T) {
+	small := image.NewRGBA(image.Rect(0, 0, 10, 20))
+	assert.Same(t, small, Scale(small, 64))
+	assert.Equal(t, image.Rect(0, 0, 32, 64), Scale(image.NewRGBA(image.Rect(0, 0, 100, 200)), 64).Bounds())
+	assert.Equal(t, image.Rect(0, 0, 64, 1), Scale(image.NewRGBA(image.Rect(0, 0, 1000, 1)), 64).Bounds())
+}
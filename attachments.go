@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"eldar/attachment"
+	"eldar/model"
+	"eldar/store"
+	"eldar/ui"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+)
+
+// attachmentCache holds the attachment files of the signed-in account, it is nil while signed out
+var attachmentCache *attachment.Cache
+
+// transferTimeout bounds uploads and downloads of attachments, which may be large
+const transferTimeout = 10 * time.Minute
+
+// Sizes of the generated thumbnails in pixels
+const (
+	cardThumbnailSize  = 256
+	panelThumbnailSize = 96
+)
+
+// thumbnails keeps the decoded thumbnails in memory, as boards are reloaded on every change of the cache
+var thumbnails struct {
+	mu      sync.Mutex
+	images  map[string]image.Image
+	pending map[string]bool
+}
+
+// openAttachmentCache opens the attachment cache in the account data directory dir
+func openAttachmentCache(dir string) error {
+	cache, err := attachment.Open(dir)
+	if err != nil {
+		return err
+	}
+	attachmentCache = cache
+	thumbnails.mu.Lock()
+	thumbnails.images, thumbnails.pending = map[string]image.Image{}, map[string]bool{}
+	thumbnails.mu.Unlock()
+	return nil
+}
+
+// closeAttachmentCache forgets the attachment cache and the thumbnails of the signed-in account
+func closeAttachmentCache() {
+	attachmentCache = nil
+	thumbnails.mu.Lock()
+	thumbnails.images, thumbnails.pending = nil, nil
+	thumbnails.mu.Unlock()
+}
+
+// attachmentThumbnail returns the thumbnail of an image attachment of at most size pixels.
+// When the thumbnail is not in memory yet it returns nil, and downloads the image if needed and decodes it
+// in the background, calling loaded on the main goroutine once the thumbnail is available.
+func attachmentThumbnail(cache *attachment.Cache, a model.Attachment, size int, loaded func()) image.Image {
+	if !a.IsImage() {
+		return nil
+	}
+	key := a.Hash + "-" + strconv.Itoa(size)
+	thumbnails.mu.Lock()
+	defer thumbnails.mu.Unlock()
+	if img, ok := thumbnails.images[key]; ok {
+		return img
+	}
+	if thumbnails.pending == nil || thumbnails.pending[key] {
+		return nil
+	}
+
+	thumbnails.pending[key] = true
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), transferTimeout)
+		defer cancel()
+		err := fetchAttachment(ctx, cache, a, nil)
+		fetched := err == nil
+		var img image.Image
+		if fetched {
+			img, err = cache.Thumbnail(a.Hash, size)
+			if err != nil {
+				log.Printf("Error creating thumbnail of %s: %v", a.Name, err)
+			}
+		} else {
+			log.Printf("Error downloading %s: %v", a.Name, err)
+		}
+		thumbnails.mu.Lock()
+		if thumbnails.pending != nil {
+			delete(thumbnails.pending, key)
+			if fetched {
+				// Remembered even when nil, so a broken image is not decoded again on every reload
+				thumbnails.images[key] = img
+			}
+		}
+		thumbnails.mu.Unlock()
+		if img != nil {
+			fyne.Do(loaded)
+		}
+	}()
+	return nil
+}
+
+// fetchAttachment downloads an attachment into the cache unless it is cached already
+func fetchAttachment(ctx context.Context, cache *attachment.Cache, a model.Attachment, progress func(received int64)) error {
+	if cache.Has(a.Hash) {
+		return nil
+	}
+	r, w := io.Pipe()
+	go func() {
+		_ = w.CloseWithError(apiClient.DownloadAttachment(ctx, a.ID, w, progress))
+	}()
+	_, _, err := cache.Put(r, a.Hash, model.MaxAttachmentSize)
+	_ = r.Close()
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", a.Name, err)
+	}
+	return nil
+}
+
+// makeAttachmentsPanel creates the attachments panel of task. Files are copied into the cache before
+// they are uploaded, so they open offline right away, and opened files are downloaded first if needed.
+func makeAttachmentsPanel(st *store.Store, task model.Task) *ui.AttachmentsPanel {
+	cache := attachmentCache
+	var panel *ui.AttachmentsPanel
+	refresh := func() {
+		if current, ok := st.Task(task.ID); ok {
+			panel.SetAttachments(current.Attachments)
+		}
+	}
+	panel = ui.NewAttachmentsPanel(func() {
+		dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			if reader != nil {
+				uploadAttachment(st, cache, panel, task.ID, reader)
+			}
+		}, w)
+	}, func(a model.Attachment) {
+		openAttachment(cache, panel, a)
+	})
+	panel.OnDelete = func(a model.Attachment) {
+		dialog.ShowConfirm("Delete attachment", fmt.Sprintf("Remove %s from this task?", a.Name), func(ok bool) {
+			if !ok {
+				return
+			}
+			runTaskAction("deleting attachment", func(ctx context.Context) error {
+				return st.DeleteAttachment(ctx, task.ID, a.ID)
+			})
+		}, w)
+	}
+	panel.Thumbnail = func(a model.Attachment) image.Image {
+		return attachmentThumbnail(cache, a, panelThumbnailSize, refresh)
+	}
+	panel.SetAttachments(task.Attachments)
+	return panel
+}
+
+// uploadAttachment copies the file read by reader into the cache and uploads it to a task,
+// showing the progress in panel
+func uploadAttachment(st *store.Store, cache *attachment.Cache, panel *ui.AttachmentsPanel, taskID string, reader fyne.URIReadCloser) {
+	name := reader.URI().Name()
+	mediaType := reader.URI().MimeType()
+	if mediaType == "" {
+		mediaType = "application/octet-stream"
+	}
+	go func() {
+		defer fyne.Do(panel.HideProgress)
+		err := func() error {
+			hash, size, err := cache.Put(reader, "", model.MaxAttachmentSize)
+			_ = reader.Close()
+			if errors.Is(err, attachment.ErrTooLarge) {
+				return fmt.Errorf("%s is larger than %s", name, model.FormatSize(model.MaxAttachmentSize))
+			}
+			if err != nil {
+				return err
+			}
+			f, err := os.Open(cache.Path(hash))
+			if err != nil {
+				return fmt.Errorf("failed to open %s: %w", name, err)
+			}
+			defer func() {
+				_ = f.Close()
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), transferTimeout)
+			defer cancel()
+			_, err = st.UploadAttachment(ctx, taskID, name, mediaType, f, size, func(sent int64) {
+				fyne.Do(func() {
+					panel.SetProgress("Uploading "+name, sent, size)
+				})
+			})
+			return err
+		}()
+		if err != nil {
+			log.Printf("Error uploading attachment: %v", err)
+			fyne.Do(func() {
+				dialog.ShowError(err, w)
+			})
+		}
+	}()
+}
+
+// openAttachment opens an attachment with the default application, downloading it first unless it is cached
+func openAttachment(cache *attachment.Cache, panel *ui.AttachmentsPanel, a model.Attachment) {
+	go func() {
+		defer fyne.Do(panel.HideProgress)
+		ctx, cancel := context.WithTimeout(context.Background(), transferTimeout)
+		defer cancel()
+		err := fetchAttachment(ctx, cache, a, func(received int64) {
+			fyne.Do(func() {
+				panel.SetProgress("Downloading "+a.Name, received, a.Size)
+			})
+		})
+		var path string
+		if err == nil {
+			path, err = cache.Named(a.Hash, a.Name)
+		}
+		if err != nil {
+			log.Printf("Error opening attachment: %v", err)
+			fyne.Do(func() {
+				dialog.ShowError(err, w)
+			})
+			return
+		}
+		fyne.Do(func() {
+			if err := fyne.CurrentApp().OpenURL(&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}); err != nil {
+				dialog.ShowError(err, w)
+			}
+		})
+	}()
+}
@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"eldar/credentials"
+)
+
+// ErrRevocationFailed is wrapped by the error returned from Logout when the local sign-out
+// succeeded but at least one server could not revoke the session
+var ErrRevocationFailed = errors.New("session revocation failed")
+
+// Revoker revokes a session identified by its refresh token on a server
+type Revoker interface {
+	Revoke(ctx context.Context, refreshToken string, allSessions bool) error
+}
+
+// Logout signs the stored account out.
+// The refresh token is revoked first at the server that issued it, like Refresh chooses where to refresh,
+// then the local credentials and the cached data of the account are removed from storageDir.
+// Local data is removed even when revocation fails, in which case the returned error wraps ErrRevocationFailed.
+//
+// Parameters:
+//   - ctx: Context bounding the revocation request
+//   - storageDir: The storage directory holding the credentials database
+//   - allSessions: Whether to revoke every session of the account instead of only this one
+//   - server: Revokes sessions at the Eldar server
+//   - idp: Revokes sessions at the identity provider, nil when none is configured
+//
+// Returns:
+//   - An error if the local data could not be removed or wrapping ErrRevocationFailed
+func Logout(ctx context.Context, storageDir string, allSessions bool, server, idp Revoker) error {
+	creds, err := credentials.GetCredentialsWithDir(storageDir)
+	if err != nil {
+		return fmt.Errorf("failed to get credentials: %w", err)
+	}
+
+	var revokeErr error
+	if creds.RefreshToken != "" {
+		revoker := server
+		if creds.UsesOIDC(idp != nil) {
+			revoker = idp
+		}
+		if revoker == nil {
+			revokeErr = ErrNoIdentityProvider
+		} else {
+			revokeErr = revoker.Revoke(ctx, creds.RefreshToken, allSessions)
+		}
+	}
+
+	if err := credentials.ClearCredentialsWithDir(storageDir); err != nil {
+		return err
+	}
+	if err := credentials.ClearAccountDataWithDir(storageDir, creds.Username); err != nil {
+		return err
+	}
+
+	if revokeErr != nil {
+		return fmt.Errorf("%w: %w", ErrRevocationFailed, revokeErr)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"eldar/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRevoker records the revocations it receives
+type fakeRevoker struct {
+	tokens      []string
+	allSessions bool
+	err         error
+}
+
+// Revoke implements Revoker
+func (f *fakeRevoker) Revoke(ctx context.Context, refreshToken string, allSessions bool) error {
+	f.tokens = append(f.tokens, refreshToken)
+	f.allSessions = allSessions
+	return f.err
+}
+
+// setupLoggedIn stores credentials issued by issuer and some cached account data in a temporary directory
+func setupLoggedIn(t *testing.T, issuer string) (string, string) {
+	storageDir := t.TempDir()
+	require.NoError(t, credentials.SaveCredentialsWithDir(storageDir, &credentials.Credentials{
+		Username:     "eldar@ioluas.dev",
+		AccessToken:  "access-1",
+		RefreshToken: "refresh-1",
+		Issuer:       issuer,
+	}))
+	dataDir := credentials.AccountDataDir(storageDir, "eldar@ioluas.dev")
+	require.NoError(t, os.MkdirAll(dataDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dataDir, "cache.db"), []byte("cached"), 0600))
+	return storageDir, dataDir
+}
+
+func TestLogout(t *testing.T) {
+	storageDir, dataDir := setupLoggedIn(t, credentials.IssuerEldar)
+	server, idp := &fakeRevoker{}, &fakeRevoker{}
+
+	// A password session is only revoked at the Eldar server, even with SSO configured
+	require.NoError(t, Logout(context.Background(), storageDir, true, server, idp))
+	assert.Equal(t, []string{"refresh-1"}, server.tokens)
+	assert.True(t, server.allSessions)
+	assert.Empty(t, idp.tokens)
+
+	creds, err := credentials.GetCredentialsWithDir(storageDir)
+	require.NoError(t, err)
+	assert.Equal(t, credentials.Credentials{}, *creds)
+	assert.NoDirExists(t, dataDir)
+}
+
+func TestLogoutOIDC(t *testing.T) {
+	storageDir, dataDir := setupLoggedIn(t, credentials.IssuerOIDC)
+	server, idp := &fakeRevoker{}, &fakeRevoker{}
+
+	// The refresh token of the identity provider never reaches the Eldar server
+	require.NoError(t, Logout(context.Background(), storageDir, false, server, idp))
+	assert.Empty(t, server.tokens)
+	assert.Equal(t, []string{"refresh-1"}, idp.tokens)
+	assert.NoDirExists(t, dataDir)
+
+	// Without an identity provider the session cannot be revoked, but is still signed out locally
+	storageDir, dataDir = setupLoggedIn(t, credentials.IssuerOIDC)
+	err := Logout(context.Background(), storageDir, false, server, nil)
+	assert.ErrorIs(t, err, ErrRevocationFailed)
+	assert.ErrorIs(t, err, ErrNoIdentityProvider)
+	assert.Empty(t, server.tokens)
+	assert.NoDirExists(t, dataDir)
+}
+
+func TestLogoutRevocationFailure(t *testing.T) {
+	storageDir, dataDir := setupLoggedIn(t, credentials.IssuerEldar)
+	server := &fakeRevoker{err: errors.New("server unreachable")}
+
+	err := Logout(context.Background(), storageDir, false, server, nil)
+	assert.ErrorIs(t, err, ErrRevocationFailed)
+	assert.ErrorContains(t, err, "server unreachable")
+
+	creds, err := credentials.GetCredentialsWithDir(storageDir)
+	require.NoError(t, err)
+	assert.Empty(t, creds.RefreshToken)
+	assert.NoDirExists(t, dataDir)
+}
@@ -0,0 +1,370 @@
+// Package auth implements the sign-in flows used by the Eldar client.
+// It currently provides an OpenID Connect authorization code flow with PKCE
+// that receives the authorization response on a loopback redirect listener.
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"eldar/credentials"
+)
+
+// DefaultScopes are requested when the OIDCConfig does not specify any scopes
+var DefaultScopes = []string{"openid", "profile", "email", "offline_access"}
+
+// ProviderMetadata holds the subset of the OpenID Connect discovery document used by Eldar
+type ProviderMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	RevocationEndpoint    string `json:"revocation_endpoint,omitempty"`
+	EndSessionEndpoint    string `json:"end_session_endpoint,omitempty"`
+}
+
+// OIDCConfig describes the identity provider and the client registered with it
+type OIDCConfig struct {
+	// Issuer is the identity provider URL, used to locate the discovery document
+	Issuer string
+	// ClientID is the public client identifier registered with the identity provider
+	ClientID string
+	// Scopes requested during sign-in, DefaultScopes is used when empty
+	Scopes []string
+	// HTTPClient used to talk to the identity provider, http.DefaultClient is used when nil
+	HTTPClient *http.Client
+}
+
+// TokenResponse is the successful response of the identity provider token endpoint
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	IDToken      string `json:"id_token,omitempty"`
+	ExpiresIn    int64  `json:"expires_in,omitempty"`
+	// RefreshExpiresIn is a non-standard lifetime of the refresh token returned by some providers
+	RefreshExpiresIn int64  `json:"refresh_expires_in,omitempty"`
+	Scope            string `json:"scope,omitempty"`
+}
+
+// tokenError is the error response of the identity provider token endpoint
+type tokenError struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// OIDCClient performs sign-in and token refresh against an OpenID Connect identity provider
+type OIDCClient struct {
+	config     OIDCConfig
+	httpClient *http.Client
+
+	mu       sync.Mutex
+	metadata *ProviderMetadata
+}
+
+// NewOIDCClient creates a client for the identity provider described by config
+func NewOIDCClient(config OIDCConfig) *OIDCClient {
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	if len(config.Scopes) == 0 {
+		config.Scopes = DefaultScopes
+	}
+	return &OIDCClient{config: config, httpClient: httpClient}
+}
+
+// Discover fetches and caches the discovery document of the identity provider
+func (c *OIDCClient) Discover(ctx context.Context) (*ProviderMetadata, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.metadata != nil {
+		return c.metadata, nil
+	}
+
+	discoveryURL := strings.TrimSuffix(c.config.Issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discoveryURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch discovery document: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch discovery document: unexpected status %s", resp.Status)
+	}
+
+	var metadata ProviderMetadata
+	if err := json.NewDecoder(resp.Body).Decode(&metadata); err != nil {
+		return nil, fmt.Errorf("failed to decode discovery document: %w", err)
+	}
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" {
+		return nil, errors.New("discovery document is missing authorization or token endpoint")
+	}
+
+	c.metadata = &metadata
+	return c.metadata, nil
+}
+
+// Login runs the authorization code flow with PKCE.
+// A loopback listener is started on 127.0.0.1 to receive the redirect, and openURL is
+// called with the authorization URL so the user can sign in using the system browser.
+// Login blocks until the identity provider redirects back, or ctx is done.
+//
+// Parameters:
+//   - ctx: Context bounding the whole sign-in, including the time the user spends in the browser
+//   - openURL: A function opening the authorization URL, usually fyne.App.OpenURL
+//
+// Returns:
+//   - The tokens issued by the identity provider
+//   - An error if any step of the flow fails
+func (c *OIDCClient) Login(ctx context.Context, openURL func(*url.URL) error) (*TokenResponse, error) {
+	metadata, err := c.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	verifier, err := newCodeVerifier()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create code verifier: %w", err)
+	}
+	state, err := newState()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create state: %w", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to start redirect listener: %w", err)
+	}
+	redirectURI := fmt.Sprintf("http://%s/callback", listener.Addr().String())
+
+	type callbackResult struct {
+		code string
+		err  error
+	}
+	results := make(chan callbackResult, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		var result callbackResult
+		switch {
+		case query.Get("state") != state:
+			result.err = errors.New("authorization response state does not match")
+		case query.Get("error") != "":
+			result.err = fmt.Errorf("authorization failed: %s %s", query.Get("error"), query.Get("error_description"))
+		case query.Get("code") == "":
+			result.err = errors.New("authorization response is missing the code")
+		default:
+			result.code = query.Get("code")
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if result.err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = fmt.Fprint(w, "<html><body><p>Sign in to Eldar failed. You can close this window.</p></body></html>")
+		} else {
+			_, _ = fmt.Fprint(w, "<html><body><p>Signed in to Eldar. You can close this window.</p></body></html>")
+		}
+
+		select {
+		case results <- result:
+		default:
+		}
+	})
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer func() {
+		_ = server.Close()
+	}()
+
+	authURL, err := url.Parse(metadata.AuthorizationEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse authorization endpoint: %w", err)
+	}
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", c.config.ClientID)
+	query.Set("redirect_uri", redirectURI)
+	query.Set("scope", strings.Join(c.config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("code_challenge", codeChallenge(verifier))
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+
+	if err := openURL(authURL); err != nil {
+		return nil, fmt.Errorf("failed to open authorization URL: %w", err)
+	}
+
+	var result callbackResult
+	select {
+	case result = <-results:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("sign in was not completed: %w", ctx.Err())
+	}
+	if result.err != nil {
+		return nil, result.err
+	}
+
+	return c.requestToken(ctx, metadata.TokenEndpoint, url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {result.code},
+		"redirect_uri":  {redirectURI},
+		"client_id":     {c.config.ClientID},
+		"code_verifier": {verifier},
+	})
+}
+
+// Refresh exchanges a refresh token for a new set of tokens at the token endpoint
+func (c *OIDCClient) Refresh(ctx context.Context, refreshToken string) (*TokenResponse, error) {
+	if refreshToken == "" {
+		return nil, errors.New("refresh token is empty")
+	}
+	metadata, err := c.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.requestToken(ctx, metadata.TokenEndpoint, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+		"client_id":     {c.config.ClientID},
+	})
+}
+
+// Revoke revokes the refresh token at the identity provider revocation endpoint (RFC 7009).
+// Identity providers have no notion of Eldar sessions, so allSessions only affects the given token.
+// It does nothing when the provider does not advertise a revocation endpoint.
+func (c *OIDCClient) Revoke(ctx context.Context, refreshToken string, allSessions bool) error {
+	metadata, err := c.Discover(ctx)
+	if err != nil {
+		return err
+	}
+	if metadata.RevocationEndpoint == "" {
+		return nil
+	}
+
+	form := url.Values{
+		"token":           {refreshToken},
+		"token_type_hint": {"refresh_token"},
+		"client_id":       {c.config.ClientID},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, metadata.RevocationEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create revocation request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to revoke token: unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// requestToken posts a form to the token endpoint and decodes the response
+func (c *OIDCClient) requestToken(ctx context.Context, endpoint string, form url.Values) (*TokenResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request token: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		var tokenErr tokenError
+		if err := json.NewDecoder(resp.Body).Decode(&tokenErr); err == nil && tokenErr.Error != "" {
+			return nil, fmt.Errorf("token request failed: %s %s", tokenErr.Error, tokenErr.ErrorDescription)
+		}
+		return nil, fmt.Errorf("token request failed: unexpected status %s", resp.Status)
+	}
+
+	var tokens TokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tokens); err != nil {
+		return nil, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if tokens.AccessToken == "" {
+		return nil, errors.New("token response is missing the access token")
+	}
+	return &tokens, nil
+}
+
+// ApplyTo copies the tokens and their metadata into creds, computing expiry times from now.
+// The refresh token, its expiry, the scopes and the username are kept when the response does
+// not carry new ones, which is the case for most refresh grants.
+func (t *TokenResponse) ApplyTo(creds *credentials.Credentials, now time.Time) {
+	creds.AccessToken = t.AccessToken
+	creds.TokenType = t.TokenType
+	creds.AccessTokenExpiresAt = time.Time{}
+	if t.ExpiresIn > 0 {
+		creds.AccessTokenExpiresAt = now.Add(time.Duration(t.ExpiresIn) * time.Second)
+	}
+	if t.RefreshToken != "" {
+		creds.RefreshToken = t.RefreshToken
+		creds.RefreshTokenExpiresAt = time.Time{}
+	}
+	if t.RefreshExpiresIn > 0 {
+		creds.RefreshTokenExpiresAt = now.Add(time.Duration(t.RefreshExpiresIn) * time.Second)
+	}
+	if t.Scope != "" {
+		creds.Scopes = strings.Fields(t.Scope)
+	}
+	if username := t.username(); username != "" {
+		creds.Username = username
+	}
+}
+
+// username extracts a display username from the ID token claims.
+// The ID token was received directly from the token endpoint over TLS, so its
+// signature is not verified here (OpenID Connect Core section 3.1.3.7).
+func (t *TokenResponse) username() string {
+	parts := strings.Split(t.IDToken, ".")
+	if len(parts) != 3 {
+		return ""
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return ""
+	}
+	var claims struct {
+		Subject           string `json:"sub"`
+		Email             string `json:"email"`
+		PreferredUsername string `json:"preferred_username"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return ""
+	}
+	switch {
+	case claims.Email != "":
+		return claims.Email
+	case claims.PreferredUsername != "":
+		return claims.PreferredUsername
+	default:
+		return claims.Subject
+	}
+}
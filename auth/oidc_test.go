@@ -0,0 +1,187 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"eldar/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubIdP is a minimal OpenID Connect provider used to exercise the client
+type stubIdP struct {
+	server        *httptest.Server
+	challenge     string
+	redirectURI   string
+	refreshTokens map[string]bool
+}
+
+// newStubIdP starts a stub identity provider that issues tokens for a single code
+func newStubIdP(t *testing.T) *stubIdP {
+	idp := &stubIdP{refreshTokens: map[string]bool{"refresh-1": true}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(ProviderMetadata{
+			Issuer:                idp.server.URL,
+			AuthorizationEndpoint: idp.server.URL + "/authorize",
+			TokenEndpoint:         idp.server.URL + "/token",
+		})
+	})
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("code_challenge_method") != "S256" || query.Get("client_id") != "eldar" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		idp.challenge = query.Get("code_challenge")
+		idp.redirectURI = query.Get("redirect_uri")
+		redirect, _ := url.Parse(idp.redirectURI)
+		redirect.RawQuery = url.Values{"code": {"code-1"}, "state": {query.Get("state")}}.Encode()
+		http.Redirect(w, r, redirect.String(), http.StatusFound)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		w.Header().Set("Content-Type", "application/json")
+		switch r.PostForm.Get("grant_type") {
+		case "authorization_code":
+			if r.PostForm.Get("code") != "code-1" ||
+				r.PostForm.Get("redirect_uri") != idp.redirectURI ||
+				codeChallenge(r.PostForm.Get("code_verifier")) != idp.challenge {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"invalid_grant","error_description":"bad code or verifier"}`))
+				return
+			}
+			_ = json.NewEncoder(w).Encode(TokenResponse{
+				AccessToken:      "access-1",
+				TokenType:        "Bearer",
+				RefreshToken:     "refresh-1",
+				IDToken:          testIDToken(`{"sub":"42","email":"eldar@ioluas.dev"}`),
+				ExpiresIn:        300,
+				RefreshExpiresIn: 3600,
+				Scope:            "openid email",
+			})
+		case "refresh_token":
+			if !idp.refreshTokens[r.PostForm.Get("refresh_token")] {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+				return
+			}
+			_ = json.NewEncoder(w).Encode(TokenResponse{AccessToken: "access-2", TokenType: "Bearer", ExpiresIn: 300})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+	return idp
+}
+
+// testIDToken builds an unsigned JWT carrying the given claims
+func testIDToken(claims string) string {
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString([]byte(`{"alg":"none"}`)) + "." + enc.EncodeToString([]byte(claims)) + "."
+}
+
+// browser simulates the system browser by following the authorization redirects
+func browser(u *url.URL) error {
+	go func() {
+		resp, err := http.Get(u.String())
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+	}()
+	return nil
+}
+
+func TestCodeChallenge(t *testing.T) {
+	// Example from RFC 7636 appendix B
+	assert.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", codeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+
+	verifier, err := newCodeVerifier()
+	require.NoError(t, err)
+	assert.Len(t, verifier, 43)
+}
+
+func TestOIDCClientLogin(t *testing.T) {
+	idp := newStubIdP(t)
+	client := NewOIDCClient(OIDCConfig{Issuer: idp.server.URL, ClientID: "eldar"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	tokens, err := client.Login(ctx, browser)
+	require.NoError(t, err)
+	assert.Equal(t, "access-1", tokens.AccessToken)
+	assert.Equal(t, "refresh-1", tokens.RefreshToken)
+	assert.Equal(t, int64(300), tokens.ExpiresIn)
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	creds := &credentials.Credentials{}
+	tokens.ApplyTo(creds, now)
+	assert.Equal(t, "eldar@ioluas.dev", creds.Username)
+	assert.Equal(t, "access-1", creds.AccessToken)
+	assert.Equal(t, "refresh-1", creds.RefreshToken)
+	assert.Equal(t, "Bearer", creds.TokenType)
+	assert.Equal(t, []string{"openid", "email"}, creds.Scopes)
+	assert.Equal(t, now.Add(5*time.Minute), creds.AccessTokenExpiresAt)
+	assert.Equal(t, now.Add(time.Hour), creds.RefreshTokenExpiresAt)
+}
+
+func TestOIDCClientLoginTimeout(t *testing.T) {
+	idp := newStubIdP(t)
+	client := NewOIDCClient(OIDCConfig{Issuer: idp.server.URL, ClientID: "eldar"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := client.Login(ctx, func(*url.URL) error { return nil })
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestOIDCClientLoginStateMismatch(t *testing.T) {
+	idp := newStubIdP(t)
+	client := NewOIDCClient(OIDCConfig{Issuer: idp.server.URL, ClientID: "eldar"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := client.Login(ctx, func(u *url.URL) error {
+		redirect, _ := url.Parse(u.Query().Get("redirect_uri"))
+		redirect.RawQuery = url.Values{"code": {"code-1"}, "state": {"forged"}}.Encode()
+		return browser(redirect)
+	})
+	assert.ErrorContains(t, err, "state does not match")
+}
+
+func TestOIDCClientRefresh(t *testing.T) {
+	idp := newStubIdP(t)
+	client := NewOIDCClient(OIDCConfig{Issuer: idp.server.URL, ClientID: "eldar"})
+
+	tokens, err := client.Refresh(context.Background(), "refresh-1")
+	require.NoError(t, err)
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	creds := &credentials.Credentials{
+		Username:              "eldar@ioluas.dev",
+		AccessToken:           "access-1",
+		RefreshToken:          "refresh-1",
+		Scopes:                []string{"openid"},
+		RefreshTokenExpiresAt: now.Add(time.Hour),
+	}
+	tokens.ApplyTo(creds, now)
+	assert.Equal(t, "access-2", creds.AccessToken)
+	assert.Equal(t, now.Add(5*time.Minute), creds.AccessTokenExpiresAt)
+	assert.Equal(t, "refresh-1", creds.RefreshToken)
+	assert.Equal(t, now.Add(time.Hour), creds.RefreshTokenExpiresAt)
+	assert.Equal(t, []string{"openid"}, creds.Scopes)
+	assert.Equal(t, "eldar@ioluas.dev", creds.Username)
+
+	_, err = client.Refresh(context.Background(), "revoked")
+	assert.ErrorContains(t, err, "invalid_grant")
+	_, err = client.Refresh(context.Background(), "")
+	assert.Error(t, err)
+}
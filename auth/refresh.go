@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"eldar/credentials"
+)
+
+// ErrNoIdentityProvider is returned when a session started through SSO must be refreshed
+// but no identity provider is configured any more
+var ErrNoIdentityProvider = errors.New("identity provider is not configured")
+
+// Tokens are new tokens that can be stored in the credentials of a session
+type Tokens interface {
+	ApplyTo(creds *credentials.Credentials, now time.Time)
+}
+
+// RefreshFunc exchanges a refresh token for new tokens at a server
+type RefreshFunc func(ctx context.Context, refreshToken string) (Tokens, error)
+
+// Refresh renews the access token of the stored session when it is expired or about to expire.
+// The session is refreshed at the server that issued its tokens, see credentials.Credentials.UsesOIDC,
+// so the refresh token of an Eldar session never reaches the identity provider and the other way round.
+//
+// Parameters:
+//   - ctx: Context bounding the refresh request
+//   - storageDir: The storage directory holding the credentials database
+//   - now: The current time
+//   - server: Refreshes sessions at the Eldar server
+//   - idp: Refreshes sessions at the identity provider, nil when none is configured
+//
+// Returns:
+//   - The credentials, refreshed or not
+//   - An error if the credentials could not be read, refreshed or saved
+func Refresh(ctx context.Context, storageDir string, now time.Time, server, idp RefreshFunc) (*credentials.Credentials, error) {
+	creds, err := credentials.GetCredentialsWithDir(storageDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credentials: %w", err)
+	}
+	if !creds.NeedsRefresh(now) {
+		return creds, nil
+	}
+
+	refresh := server
+	if creds.UsesOIDC(idp != nil) {
+		if idp == nil {
+			return nil, ErrNoIdentityProvider
+		}
+		refresh = idp
+	}
+	tokens, err := refresh(ctx, creds.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+	tokens.ApplyTo(creds, now)
+	if err := credentials.SaveCredentialsWithDir(storageDir, creds); err != nil {
+		return nil, err
+	}
+	return creds, nil
+}
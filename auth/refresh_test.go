@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"eldar/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRefresher records the refresh tokens it receives and hands out new tokens
+type fakeRefresher struct {
+	tokens []string
+	issued string
+}
+
+// refresh implements RefreshFunc
+func (f *fakeRefresher) refresh(ctx context.Context, refreshToken string) (Tokens, error) {
+	f.tokens = append(f.tokens, refreshToken)
+	return &TokenResponse{AccessToken: f.issued, TokenType: "Bearer", ExpiresIn: 3600}, nil
+}
+
+// setupExpiring stores a session of the given issuer whose access token has expired
+func setupExpiring(t *testing.T, issuer string, now time.Time) string {
+	storageDir := t.TempDir()
+	require.NoError(t, credentials.SaveCredentialsWithDir(storageDir, &credentials.Credentials{
+		Username:             "eldar@ioluas.dev",
+		AccessToken:          "access-1",
+		RefreshToken:         "refresh-1",
+		Issuer:               issuer,
+		AccessTokenExpiresAt: now.Add(-time.Minute),
+	}))
+	return storageDir
+}
+
+func TestRefreshPasswordSessionWithOIDCConfigured(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	storageDir := setupExpiring(t, credentials.IssuerEldar, now)
+	server, idp := &fakeRefresher{issued: "server-access"}, &fakeRefresher{issued: "idp-access"}
+
+	creds, err := Refresh(context.Background(), storageDir, now, server.refresh, idp.refresh)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"refresh-1"}, server.tokens)
+	assert.Empty(t, idp.tokens)
+	assert.Equal(t, "server-access", creds.AccessToken)
+	assert.Equal(t, credentials.IssuerEldar, creds.Issuer)
+
+	stored, err := credentials.GetCredentialsWithDir(storageDir)
+	require.NoError(t, err)
+	assert.Equal(t, "server-access", stored.AccessToken)
+	assert.Equal(t, now.Add(time.Hour), stored.AccessTokenExpiresAt)
+}
+
+func TestRefreshSSOSession(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	storageDir := setupExpiring(t, credentials.IssuerOIDC, now)
+	server, idp := &fakeRefresher{issued: "server-access"}, &fakeRefresher{issued: "idp-access"}
+
+	creds, err := Refresh(context.Background(), storageDir, now, server.refresh, idp.refresh)
+	require.NoError(t, err)
+	assert.Empty(t, server.tokens)
+	assert.Equal(t, []string{"refresh-1"}, idp.tokens)
+	assert.Equal(t, "idp-access", creds.AccessToken)
+
+	// The refresh token is never sent to the Eldar server once the identity provider is gone
+	storageDir = setupExpiring(t, credentials.IssuerOIDC, now)
+	_, err = Refresh(context.Background(), storageDir, now, server.refresh, nil)
+	assert.ErrorIs(t, err, ErrNoIdentityProvider)
+	assert.Empty(t, server.tokens)
+}
+
+func TestRefreshNotNeeded(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	storageDir := setupExpiring(t, credentials.IssuerEldar, now.Add(time.Hour))
+	server := &fakeRefresher{issued: "server-access"}
+
+	creds, err := Refresh(context.Background(), storageDir, now, server.refresh, nil)
+	require.NoError(t, err)
+	assert.Empty(t, server.tokens)
+	assert.Equal(t, "access-1", creds.AccessToken)
+}
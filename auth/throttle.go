@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"eldar/api"
+)
+
+// ErrInvalidCredentials is returned by a login attempt rejected because of a wrong email or password
+var ErrInvalidCredentials = errors.New("invalid email or password")
+
+// ErrWrongPIN is returned by an unlock attempt rejected because of a wrong local PIN
+var ErrWrongPIN = errors.New("wrong PIN")
+
+// Throttle enforces an exponential backoff between failed login or unlock attempts.
+// Each consecutive failure doubles the wait, starting at the base delay and capped at the maximum.
+// A server Retry-After extends the wait when it is longer. It is safe for concurrent use.
+type Throttle struct {
+	mu       sync.Mutex
+	base     time.Duration
+	max      time.Duration
+	failures int
+	until    time.Time
+	now      func() time.Time
+}
+
+// NewThrottle creates a Throttle with the given base and maximum delay
+func NewThrottle(base, max time.Duration) *Throttle {
+	return &Throttle{base: base, max: max, now: time.Now}
+}
+
+// Record updates the throttle with the outcome of a login attempt.
+// A nil error resets it, ErrInvalidCredentials, ErrWrongPIN or a 401 response counts as a failed attempt,
+// and a response carrying Retry-After makes the client wait at least that long.
+// Other errors, such as network failures, do not count as attempts.
+func (t *Throttle) Record(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if err == nil {
+		t.failures = 0
+		t.until = time.Time{}
+		return
+	}
+
+	now := t.now()
+	var apiErr *api.Error
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		t.extend(now.Add(apiErr.RetryAfter))
+	}
+	if errors.Is(err, ErrInvalidCredentials) || errors.Is(err, ErrWrongPIN) || api.IsStatus(err, http.StatusUnauthorized) {
+		t.failures++
+		t.extend(now.Add(t.backoff()))
+	}
+}
+
+// Remaining returns how long to wait before the next login attempt is allowed
+func (t *Throttle) Remaining() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if remaining := t.until.Sub(t.now()); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// Failures returns the number of failed attempts since the last successful one
+func (t *Throttle) Failures() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.failures
+}
+
+// backoff returns the wait for the current number of failures.
+// The caller must hold t.mu.
+func (t *Throttle) backoff() time.Duration {
+	delay := t.base
+	for i := 1; i < t.failures && delay < t.max; i++ {
+		delay *= 2
+	}
+	return min(delay, t.max)
+}
+
+// extend moves the end of the wait to until if it is later than the current one.
+// The caller must hold t.mu.
+func (t *Throttle) extend(until time.Time) {
+	if until.After(t.until) {
+		t.until = until
+	}
+}
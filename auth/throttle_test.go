@@ -0,0 +1,55 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"eldar/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThrottle(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	throttle := NewThrottle(time.Second, 8*time.Second)
+	throttle.now = func() time.Time { return now }
+	assert.Equal(t, time.Duration(0), throttle.Remaining())
+
+	// Consecutive failures double the wait up to the maximum
+	for _, expected := range []time.Duration{1, 2, 4, 8, 8} {
+		throttle.Record(ErrInvalidCredentials)
+		assert.Equal(t, expected*time.Second, throttle.Remaining())
+		now = now.Add(throttle.Remaining())
+	}
+
+	// Network failures are not attempts
+	throttle.Record(errors.New("connection refused"))
+	assert.Equal(t, time.Duration(0), throttle.Remaining())
+
+	// The server Retry-After wins when longer than the local backoff
+	throttle.Record(&api.Error{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Minute})
+	assert.Equal(t, time.Minute, throttle.Remaining())
+	throttle.Record(&api.Error{StatusCode: http.StatusUnauthorized})
+	assert.Equal(t, time.Minute, throttle.Remaining())
+
+	// A successful login resets the backoff
+	throttle.Record(nil)
+	assert.Equal(t, time.Duration(0), throttle.Remaining())
+	throttle.Record(ErrInvalidCredentials)
+	assert.Equal(t, time.Second, throttle.Remaining())
+	assert.Equal(t, 1, throttle.Failures())
+}
+
+func TestThrottleWrongPIN(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	throttle := NewThrottle(time.Second, 8*time.Second)
+	throttle.now = func() time.Time { return now }
+	throttle.Record(fmt.Errorf("unlock: %w", ErrWrongPIN))
+	throttle.Record(ErrWrongPIN)
+	assert.Equal(t, 2, throttle.Failures())
+	assert.Equal(t, 2*time.Second, throttle.Remaining())
+	throttle.Record(nil)
+	assert.Zero(t, throttle.Failures())
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"eldar/model"
+	"eldar/store"
+	"eldar/ui"
+	"eldar/undo"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+)
+
+// showBoardSettings opens the editor of the column policies, labels and custom fields of board in a dialog.
+// Saving applies the edit to the cache immediately and rolls it back if the server rejects it.
+func showBoardSettings(st *store.Store, board model.Board) {
+	editor := ui.NewBoardSettingsEditor(board)
+	d := dialog.NewCustomConfirm(board.Name+" settings", "Save", "Cancel", editor, func(ok bool) {
+		if !ok {
+			return
+		}
+		edited, err := editor.Board()
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		runTaskCommand(board.ID, &undo.UpdateBoard{Store: st, Label: "Edit board settings", Before: board, After: edited})
+	}, w)
+	d.Resize(fyne.NewSize(640, 560))
+	d.Show()
+}
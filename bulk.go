@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"eldar/model"
+	"eldar/store"
+	"eldar/undo"
+	"fyne.io/fyne/v2/dialog"
+)
+
+// bulkEdit applies a change to the tasks selected on the Boards page as a single batch, after confirmation
+// when they are moved to the trash. The tasks that could not be changed are listed with the reason,
+// and the change of the others is recorded in the undo history.
+func bulkEdit(st *store.Store, e model.BulkEdit, tasks []model.Task) {
+	if len(tasks) == 0 {
+		return
+	}
+	ids := make([]string, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	run := func() {
+		history := undoHistory
+		runTaskAction("changing tasks", func(ctx context.Context) error {
+			before := map[string]model.Task{}
+			for _, id := range ids {
+				if task, ok := st.Task(id); ok {
+					before[id] = task
+				}
+			}
+			result, err := st.BulkEdit(ctx, e, ids)
+			if err != nil {
+				return err
+			}
+			if history != nil {
+				recordBulkEdit(st, history, e, before, result)
+			}
+			return result.Err()
+		})
+	}
+	if e.Action != model.BulkDelete {
+		run()
+		return
+	}
+	message := fmt.Sprintf("Move %d tasks to the trash? They can be restored from the Trash tab for %d days.",
+		len(tasks), int(model.TrashRetention.Hours()/24))
+	if len(tasks) == 1 {
+		message = fmt.Sprintf("Move %q to the trash? It can be restored from the Trash tab for %d days.",
+			tasks[0].Title, int(model.TrashRetention.Hours()/24))
+	}
+	dialog.ShowConfirm("Delete tasks", message, func(ok bool) {
+		if ok {
+			run()
+		}
+	}, w)
+}
+
+// bulkEditNames name the bulk edits in the undo history by action
+var bulkEditNames = map[model.BulkAction]string{
+	model.BulkMove:    "Move tasks",
+	model.BulkAssign:  "Assign tasks",
+	model.BulkLabel:   "Label tasks",
+	model.BulkDue:     "Set due dates",
+	model.BulkArchive: "Archive tasks",
+	model.BulkDelete:  "Delete tasks",
+}
+
+// recordBulkEdit adds the changes a bulk edit made to the undo history, given the tasks before the edit by ID.
+// The tasks of each board are undone together, replacing the earlier history of the board, and tasks that
+// could not be changed are left out.
+func recordBulkEdit(st *store.Store, history *undo.History, e model.BulkEdit, before map[string]model.Task, result model.BatchResult) {
+	name := bulkEditNames[e.Action]
+	commands := map[string][]undo.Command{}
+	for _, after := range result.Updated {
+		if task, ok := before[after.ID]; ok {
+			commands[task.BoardID] = append(commands[task.BoardID], undo.UpdatedTask(st, name, task, after))
+		}
+	}
+	for _, id := range result.Deleted {
+		if task, ok := before[id]; ok {
+			commands[task.BoardID] = append(commands[task.BoardID], &undo.DeleteTask{Store: st, Task: task})
+		}
+	}
+	for boardID, c := range commands {
+		// Earlier changes of the tasks could not be undone past the bulk edit, whose undo sends new versions
+		history.Clear(boardID)
+		history.Record(boardID, &undo.Batch{Label: name, Commands: c})
+	}
+}
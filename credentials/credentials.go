@@ -1,20 +1,91 @@
 package credentials
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
+	"strings"
+	"time"
 
 	"fyne.io/fyne/v2"
 	"go.etcd.io/bbolt"
 )
 
+// refreshLeeway is how long before the access token expires that it should be refreshed
+const refreshLeeway = time.Minute
+
+// Issuers of the tokens of a session
+const (
+	// IssuerEldar is the Eldar server, for sessions started with an email and password
+	IssuerEldar = "eldar"
+	// IssuerOIDC is the OpenID Connect identity provider, for sessions started through SSO
+	IssuerOIDC = "oidc"
+)
+
 // Credentials structure to hold the user credentials
 type Credentials struct {
 	Username     string
 	AccessToken  string
 	RefreshToken string
+	// Issuer is IssuerEldar or IssuerOIDC, telling where the tokens are refreshed and revoked.
+	// It is empty for credentials stored by older versions.
+	Issuer string
+	// TokenType is the access token type, usually "Bearer"
+	TokenType string
+	// Scopes granted to the access token
+	Scopes []string
+	// AccessTokenExpiresAt is when the access token expires, zero when unknown
+	AccessTokenExpiresAt time.Time
+	// RefreshTokenExpiresAt is when the refresh token expires, zero when unknown
+	RefreshTokenExpiresAt time.Time
+}
+
+// IsEmpty reports whether no account is stored
+func (c *Credentials) IsEmpty() bool {
+	return c.Username == "" && c.AccessToken == "" && c.RefreshToken == ""
+}
+
+// AccessTokenValid reports whether the access token can still be used at the given time.
+// A token without a known expiry is assumed valid.
+func (c *Credentials) AccessTokenValid(now time.Time) bool {
+	return c.AccessToken != "" && (c.AccessTokenExpiresAt.IsZero() || now.Before(c.AccessTokenExpiresAt))
+}
+
+// RefreshTokenValid reports whether the refresh token can still be used at the given time.
+// A token without a known expiry is assumed valid.
+func (c *Credentials) RefreshTokenValid(now time.Time) bool {
+	return c.RefreshToken != "" && (c.RefreshTokenExpiresAt.IsZero() || now.Before(c.RefreshTokenExpiresAt))
+}
+
+// IsValid reports whether the session can be used at the given time, either directly
+// or after refreshing the access token
+func (c *Credentials) IsValid(now time.Time) bool {
+	return c.AccessTokenValid(now) || c.RefreshTokenValid(now)
+}
+
+// NeedsRefresh reports whether the access token is expired or about to expire
+// and can be renewed with the refresh token
+func (c *Credentials) NeedsRefresh(now time.Time) bool {
+	return !c.AccessTokenValid(now.Add(refreshLeeway)) && c.RefreshTokenValid(now)
+}
+
+// UsesOIDC reports whether the tokens were issued by the identity provider, and so must be refreshed and
+// revoked there rather than at the Eldar server. Credentials stored before the issuer was recorded are
+// assumed to come from the identity provider when one is configured.
+func (c *Credentials) UsesOIDC(oidcConfigured bool) bool {
+	if c.Issuer == "" {
+		return oidcConfigured
+	}
+	return c.Issuer == IssuerOIDC
+}
+
+// HasScope reports whether the access token was granted the given scope
+func (c *Credentials) HasScope(scope string) bool {
+	return slices.Contains(c.Scopes, scope)
 }
 
 // GetStorageDir returns the appropriate storage directory based on the platform
@@ -132,6 +203,13 @@ func GetCredentials() (*Credentials, error) {
 		return nil, fmt.Errorf("failed to get storage directory: %w", err)
 	}
 
+	// Call the implementation with the storage directory
+	return GetCredentialsWithDir(storageDir)
+}
+
+// GetCredentialsWithDir initializes and checks the bbolt database for credentials
+// in the specified storage directory
+func GetCredentialsWithDir(storageDir string) (*Credentials, error) {
 	// Create the directory for our database if it doesn't exist
 	dbDir := filepath.Join(storageDir, ".eldar")
 	if err := os.MkdirAll(dbDir, 0755); err != nil {
@@ -179,6 +257,20 @@ func GetCredentials() (*Credentials, error) {
 			creds.RefreshToken = string(refreshToken)
 		}
 
+		// Token metadata is absent for credentials stored by older versions
+		creds.TokenType = string(b.Get([]byte("token_type")))
+		creds.Issuer = string(b.Get([]byte("issuer")))
+		if scopes := string(b.Get([]byte("scopes"))); scopes != "" {
+			creds.Scopes = strings.Fields(scopes)
+		}
+		var err error
+		if creds.AccessTokenExpiresAt, err = parseTime(b.Get([]byte("access_token_expires_at"))); err != nil {
+			return fmt.Errorf("invalid access token expiry: %w", err)
+		}
+		if creds.RefreshTokenExpiresAt, err = parseTime(b.Get([]byte("refresh_token_expires_at"))); err != nil {
+			return fmt.Errorf("invalid refresh token expiry: %w", err)
+		}
+
 		return nil
 	})
 
@@ -189,6 +281,89 @@ func GetCredentials() (*Credentials, error) {
 	return &creds, nil
 }
 
+// SaveCredentials stores the given credentials in the database, replacing any existing values
+func SaveCredentials(creds *Credentials) error {
+	// Get the appropriate storage directory for the platform
+	storageDir, err := GetStorageDir()
+	if err != nil {
+		return fmt.Errorf("failed to get storage directory: %w", err)
+	}
+
+	// Call the implementation with the storage directory
+	return SaveCredentialsWithDir(storageDir, creds)
+}
+
+// SaveCredentialsWithDir stores the given credentials in the database
+// in the specified storage directory
+func SaveCredentialsWithDir(storageDir string, creds *Credentials) error {
+	if creds == nil {
+		return fmt.Errorf("credentials must not be nil")
+	}
+
+	// Create the directory for our database if it doesn't exist
+	dbDir := filepath.Join(storageDir, ".eldar")
+	if err := os.MkdirAll(dbDir, 0755); err != nil {
+		return fmt.Errorf("failed to create database directory: %w", err)
+	}
+
+	// Open the bbolt database
+	dbPath := filepath.Join(dbDir, "credentials.db")
+	db, err := bbolt.Open(dbPath, 0600, nil)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer func(db *bbolt.DB) {
+		_ = db.Close()
+	}(db)
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte("credentials"))
+		if err != nil {
+			return fmt.Errorf("failed to create bucket: %w", err)
+		}
+
+		if err := b.Put([]byte("username"), []byte(creds.Username)); err != nil {
+			return fmt.Errorf("failed to set username: %w", err)
+		}
+
+		if err := b.Put([]byte("access_token"), []byte(creds.AccessToken)); err != nil {
+			return fmt.Errorf("failed to set access token: %w", err)
+		}
+
+		if err := b.Put([]byte("refresh_token"), []byte(creds.RefreshToken)); err != nil {
+			return fmt.Errorf("failed to set refresh token: %w", err)
+		}
+
+		if err := b.Put([]byte("token_type"), []byte(creds.TokenType)); err != nil {
+			return fmt.Errorf("failed to set token type: %w", err)
+		}
+
+		if err := b.Put([]byte("issuer"), []byte(creds.Issuer)); err != nil {
+			return fmt.Errorf("failed to set issuer: %w", err)
+		}
+
+		if err := b.Put([]byte("scopes"), []byte(strings.Join(creds.Scopes, " "))); err != nil {
+			return fmt.Errorf("failed to set scopes: %w", err)
+		}
+
+		if err := b.Put([]byte("access_token_expires_at"), formatTime(creds.AccessTokenExpiresAt)); err != nil {
+			return fmt.Errorf("failed to set access token expiry: %w", err)
+		}
+
+		if err := b.Put([]byte("refresh_token_expires_at"), formatTime(creds.RefreshTokenExpiresAt)); err != nil {
+			return fmt.Errorf("failed to set refresh token expiry: %w", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to save credentials: %w", err)
+	}
+
+	return nil
+}
+
 // ClearCredentials removes all stored credentials from the database
 func ClearCredentials() error {
 	// Get the appropriate storage directory for the platform
@@ -197,9 +372,16 @@ func ClearCredentials() error {
 		return fmt.Errorf("failed to get storage directory: %w", err)
 	}
 
+	// Call the implementation with the storage directory
+	return ClearCredentialsWithDir(storageDir)
+}
+
+// ClearCredentialsWithDir removes all stored credentials from the database
+// in the specified storage directory
+func ClearCredentialsWithDir(storageDir string) error {
 	// Check if the database file exists
 	dbPath := filepath.Join(storageDir, ".eldar", "credentials.db")
-	if _, err = os.Stat(dbPath); os.IsNotExist(err) {
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
 		fmt.Println("Database file does not exist. Nothing to clear.")
 		return nil
 	}
@@ -216,7 +398,7 @@ func ClearCredentials() error {
 	}(db)
 
 	// Clear the credentials
-	if err = db.Update(func(tx *bbolt.Tx) error {
+	if err := db.Update(func(tx *bbolt.Tx) error {
 		// Check if the bucket exists
 		b := tx.Bucket([]byte("credentials"))
 		if b == nil {
@@ -233,6 +415,14 @@ func ClearCredentials() error {
 		if err := b.Delete([]byte("refresh_token")); err != nil {
 			return fmt.Errorf("failed to delete refresh token: %w", err)
 		}
+		if err := b.Delete([]byte("pin")); err != nil {
+			return fmt.Errorf("failed to delete PIN: %w", err)
+		}
+		for _, key := range []string{"token_type", "issuer", "scopes", "access_token_expires_at", "refresh_token_expires_at"} {
+			if err := b.Delete([]byte(key)); err != nil {
+				return fmt.Errorf("failed to delete %s: %w", key, err)
+			}
+		}
 
 		return nil
 	}); err != nil {
@@ -242,3 +432,35 @@ func ClearCredentials() error {
 	fmt.Println("Credentials cleared successfully!")
 	return nil
 }
+
+// AccountDataDir returns the directory holding cached data of the given account
+// inside the specified storage directory
+func AccountDataDir(storageDir, username string) string {
+	sum := sha256.Sum256([]byte(username))
+	return filepath.Join(storageDir, ".eldar", "accounts", hex.EncodeToString(sum[:8]))
+}
+
+// ClearAccountDataWithDir removes all cached data of the given account
+// from the specified storage directory
+func ClearAccountDataWithDir(storageDir, username string) error {
+	if err := os.RemoveAll(AccountDataDir(storageDir, username)); err != nil {
+		return fmt.Errorf("failed to remove account data: %w", err)
+	}
+	return nil
+}
+
+// formatTime encodes t for storage, the zero time is stored as an empty value
+func formatTime(t time.Time) []byte {
+	if t.IsZero() {
+		return []byte{}
+	}
+	return []byte(t.UTC().Format(time.RFC3339))
+}
+
+// parseTime decodes a time stored by formatTime
+func parseTime(b []byte) (time.Time, error) {
+	if len(b) == 0 {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, string(b))
+}
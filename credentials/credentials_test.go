@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"go.etcd.io/bbolt"
 )
@@ -228,3 +229,143 @@ func TestClearCredentials(t *testing.T) {
 		t.Errorf("RefreshToken should be empty after clearing, got '%s'", creds.RefreshToken)
 	}
 }
+
+// TestSaveCredentials tests the SaveCredentialsWithDir function
+func TestSaveCredentials(t *testing.T) {
+	setupTestEnvironment(t)
+
+	err := SaveCredentialsWithDir(testStorageDir, &Credentials{
+		Username:     "eldar@ioluas.dev",
+		AccessToken:  "saved-access-token",
+		RefreshToken: "saved-refresh-token",
+	})
+	if err != nil {
+		t.Fatalf("SaveCredentialsWithDir failed: %v", err)
+	}
+
+	creds, err := GetCredentialsWithDir(testStorageDir)
+	if err != nil {
+		t.Fatalf("GetCredentialsWithDir failed: %v", err)
+	}
+	if creds.Username != "eldar@ioluas.dev" {
+		t.Errorf("Expected username 'eldar@ioluas.dev', got '%s'", creds.Username)
+	}
+	if creds.AccessToken != "saved-access-token" {
+		t.Errorf("Expected access token 'saved-access-token', got '%s'", creds.AccessToken)
+	}
+	if creds.RefreshToken != "saved-refresh-token" {
+		t.Errorf("Expected refresh token 'saved-refresh-token', got '%s'", creds.RefreshToken)
+	}
+
+	if err := SaveCredentialsWithDir(testStorageDir, nil); err == nil {
+		t.Errorf("Expected error when saving nil credentials")
+	}
+}
+
+// TestSaveCredentialsMetadata tests that token metadata survives a round trip through the database
+func TestSaveCredentialsMetadata(t *testing.T) {
+	setupTestEnvironment(t)
+
+	expiresAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	err := SaveCredentialsWithDir(testStorageDir, &Credentials{
+		Username:              "eldar@ioluas.dev",
+		AccessToken:           "saved-access-token",
+		RefreshToken:          "saved-refresh-token",
+		TokenType:             "Bearer",
+		Issuer:                IssuerOIDC,
+		Scopes:                []string{"openid", "email"},
+		AccessTokenExpiresAt:  expiresAt,
+		RefreshTokenExpiresAt: expiresAt.Add(24 * time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("SaveCredentialsWithDir failed: %v", err)
+	}
+
+	creds, err := GetCredentialsWithDir(testStorageDir)
+	if err != nil {
+		t.Fatalf("GetCredentialsWithDir failed: %v", err)
+	}
+	if creds.TokenType != "Bearer" {
+		t.Errorf("Expected token type 'Bearer', got '%s'", creds.TokenType)
+	}
+	if creds.Issuer != IssuerOIDC {
+		t.Errorf("Expected issuer '%s', got '%s'", IssuerOIDC, creds.Issuer)
+	}
+	if !creds.HasScope("email") || creds.HasScope("offline_access") {
+		t.Errorf("Unexpected scopes %v", creds.Scopes)
+	}
+	if !creds.AccessTokenExpiresAt.Equal(expiresAt) {
+		t.Errorf("Expected access token expiry %v, got %v", expiresAt, creds.AccessTokenExpiresAt)
+	}
+	if !creds.RefreshTokenExpiresAt.Equal(expiresAt.Add(24 * time.Hour)) {
+		t.Errorf("Expected refresh token expiry %v, got %v", expiresAt.Add(24*time.Hour), creds.RefreshTokenExpiresAt)
+	}
+
+	if err := ClearCredentialsWithDir(testStorageDir); err != nil {
+		t.Fatalf("ClearCredentialsWithDir failed: %v", err)
+	}
+	creds, err = GetCredentialsWithDir(testStorageDir)
+	if err != nil {
+		t.Fatalf("GetCredentialsWithDir failed after clearing: %v", err)
+	}
+	if creds.TokenType != "" || creds.Issuer != "" || creds.Scopes != nil || !creds.AccessTokenExpiresAt.IsZero() || !creds.RefreshTokenExpiresAt.IsZero() {
+		t.Errorf("Token metadata should be empty after clearing, got %+v", creds)
+	}
+}
+
+// TestCredentialsValidity tests the session validity checks
+func TestCredentialsValidity(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		creds        Credentials
+		valid        bool
+		needsRefresh bool
+	}{
+		{"empty", Credentials{}, false, false},
+		{"no expiry", Credentials{AccessToken: "a", RefreshToken: "r"}, true, false},
+		{"access valid", Credentials{AccessToken: "a", RefreshToken: "r", AccessTokenExpiresAt: now.Add(time.Hour)}, true, false},
+		{"access expiring", Credentials{AccessToken: "a", RefreshToken: "r", AccessTokenExpiresAt: now.Add(30 * time.Second)}, true, true},
+		{"access expired", Credentials{AccessToken: "a", RefreshToken: "r", AccessTokenExpiresAt: now.Add(-time.Hour)}, true, true},
+		{"access expired without refresh token", Credentials{AccessToken: "a", AccessTokenExpiresAt: now.Add(-time.Hour)}, false, false},
+		{"both expired", Credentials{
+			AccessToken: "a", RefreshToken: "r",
+			AccessTokenExpiresAt: now.Add(-time.Hour), RefreshTokenExpiresAt: now.Add(-time.Minute),
+		}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if valid := tt.creds.IsValid(now); valid != tt.valid {
+				t.Errorf("Expected IsValid %v, got %v", tt.valid, valid)
+			}
+			if needsRefresh := tt.creds.NeedsRefresh(now); needsRefresh != tt.needsRefresh {
+				t.Errorf("Expected NeedsRefresh %v, got %v", tt.needsRefresh, needsRefresh)
+			}
+		})
+	}
+}
+
+// TestCredentialsUsesOIDC tests where sessions are refreshed depending on how they started
+func TestCredentialsUsesOIDC(t *testing.T) {
+	tests := []struct {
+		name           string
+		issuer         string
+		oidcConfigured bool
+		usesOIDC       bool
+	}{
+		{"password session", IssuerEldar, false, false},
+		{"password session with OIDC configured", IssuerEldar, true, false},
+		{"SSO session", IssuerOIDC, true, true},
+		{"SSO session once OIDC is no longer configured", IssuerOIDC, false, true},
+		{"older session", "", false, false},
+		{"older session with OIDC configured", "", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			creds := Credentials{Issuer: tt.issuer}
+			if usesOIDC := creds.UsesOIDC(tt.oidcConfigured); usesOIDC != tt.usesOIDC {
+				t.Errorf("Expected UsesOIDC %v, got %v", tt.usesOIDC, usesOIDC)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package credentials
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"go.etcd.io/bbolt"
+)
+
+// pinIterations is the PBKDF2 iteration count used when hashing a new PIN
+const pinIterations = 200000
+
+// SetPIN stores a salted hash of the local unlock PIN, an empty pin removes it
+func SetPIN(pin string) error {
+	// Get the appropriate storage directory for the platform
+	storageDir, err := GetStorageDir()
+	if err != nil {
+		return fmt.Errorf("failed to get storage directory: %w", err)
+	}
+
+	// Call the implementation with the storage directory
+	return SetPINWithDir(storageDir, pin)
+}
+
+// SetPINWithDir stores a salted hash of the local unlock PIN in the specified storage directory,
+// an empty pin removes it
+func SetPINWithDir(storageDir, pin string) error {
+	db, err := openDB(storageDir)
+	if err != nil {
+		return err
+	}
+	defer func(db *bbolt.DB) {
+		_ = db.Close()
+	}(db)
+
+	if err := db.Update(func(tx *bbolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte("credentials"))
+		if err != nil {
+			return fmt.Errorf("failed to create bucket: %w", err)
+		}
+		if pin == "" {
+			return b.Delete([]byte("pin"))
+		}
+
+		salt := make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return fmt.Errorf("failed to create salt: %w", err)
+		}
+		hash := pbkdf2SHA256([]byte(pin), salt, pinIterations)
+		encoded := fmt.Sprintf("pbkdf2-sha256$%d$%s$%s", pinIterations, hex.EncodeToString(salt), hex.EncodeToString(hash))
+		return b.Put([]byte("pin"), []byte(encoded))
+	}); err != nil {
+		return fmt.Errorf("failed to set PIN: %w", err)
+	}
+
+	return nil
+}
+
+// HasPIN reports whether a local unlock PIN is set
+func HasPIN() (bool, error) {
+	// Get the appropriate storage directory for the platform
+	storageDir, err := GetStorageDir()
+	if err != nil {
+		return false, fmt.Errorf("failed to get storage directory: %w", err)
+	}
+
+	// Call the implementation with the storage directory
+	return HasPINWithDir(storageDir)
+}
+
+// HasPINWithDir reports whether a local unlock PIN is set in the specified storage directory
+func HasPINWithDir(storageDir string) (bool, error) {
+	encoded, err := readPIN(storageDir)
+	return encoded != "", err
+}
+
+// VerifyPIN reports whether pin matches the stored local unlock PIN
+func VerifyPIN(pin string) (bool, error) {
+	// Get the appropriate storage directory for the platform
+	storageDir, err := GetStorageDir()
+	if err != nil {
+		return false, fmt.Errorf("failed to get storage directory: %w", err)
+	}
+
+	// Call the implementation with the storage directory
+	return VerifyPINWithDir(storageDir, pin)
+}
+
+// VerifyPINWithDir reports whether pin matches the local unlock PIN stored in the specified storage directory
+func VerifyPINWithDir(storageDir, pin string) (bool, error) {
+	encoded, err := readPIN(storageDir)
+	if err != nil || encoded == "" {
+		return false, err
+	}
+
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 4 || parts[0] != "pbkdf2-sha256" {
+		return false, fmt.Errorf("unsupported PIN hash format")
+	}
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return false, fmt.Errorf("invalid PIN hash iterations: %w", err)
+	}
+	salt, err := hex.DecodeString(parts[2])
+	if err != nil {
+		return false, fmt.Errorf("invalid PIN hash salt: %w", err)
+	}
+	expected, err := hex.DecodeString(parts[3])
+	if err != nil {
+		return false, fmt.Errorf("invalid PIN hash: %w", err)
+	}
+
+	hash := pbkdf2SHA256([]byte(pin), salt, iterations)
+	return subtle.ConstantTimeCompare(hash, expected) == 1, nil
+}
+
+// readPIN returns the encoded PIN hash, or an empty string when no PIN is set
+func readPIN(storageDir string) (string, error) {
+	db, err := openDB(storageDir)
+	if err != nil {
+		return "", err
+	}
+	defer func(db *bbolt.DB) {
+		_ = db.Close()
+	}(db)
+
+	var encoded string
+	if err := db.View(func(tx *bbolt.Tx) error {
+		if b := tx.Bucket([]byte("credentials")); b != nil {
+			encoded = string(b.Get([]byte("pin")))
+		}
+		return nil
+	}); err != nil {
+		return "", fmt.Errorf("failed to read from database: %w", err)
+	}
+	return encoded, nil
+}
+
+// openDB creates the database directory if needed and opens the credentials database
+func openDB(storageDir string) (*bbolt.DB, error) {
+	dbDir := filepath.Join(storageDir, ".eldar")
+	if err := os.MkdirAll(dbDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create database directory: %w", err)
+	}
+
+	db, err := bbolt.Open(filepath.Join(dbDir, "credentials.db"), 0600, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	return db, nil
+}
+
+// pbkdf2SHA256 derives a 32 byte key from password and salt using PBKDF2-HMAC-SHA256 (RFC 8018)
+func pbkdf2SHA256(password, salt []byte, iterations int) []byte {
+	prf := hmac.New(sha256.New, password)
+	prf.Write(salt)
+	prf.Write(binary.BigEndian.AppendUint32(nil, 1))
+	u := prf.Sum(nil)
+
+	key := make([]byte, len(u))
+	copy(key, u)
+	for i := 1; i < iterations; i++ {
+		prf.Reset()
+		prf.Write(u)
+		u = prf.Sum(u[:0])
+		for j := range key {
+			key[j] ^= u[j]
+		}
+	}
+	return key
+}
@@ -0,0 +1,46 @@
+package credentials
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// TestPBKDF2SHA256 checks the key derivation against a published test vector
+func TestPBKDF2SHA256(t *testing.T) {
+	key := pbkdf2SHA256([]byte("password"), []byte("salt"), 4096)
+	expected := "c5e478d59288c841aa530db6845c4c8d962893a001ce4e11a4963873aa98134a"
+	if hex.EncodeToString(key) != expected {
+		t.Errorf("Expected key '%s', got '%x'", expected, key)
+	}
+}
+
+// TestPIN tests setting, verifying and removing the local unlock PIN
+func TestPIN(t *testing.T) {
+	setupTestEnvironment(t)
+
+	hasPIN, err := HasPINWithDir(testStorageDir)
+	if err != nil {
+		t.Fatalf("HasPINWithDir failed: %v", err)
+	}
+	if hasPIN {
+		t.Fatalf("Expected no PIN to be set")
+	}
+
+	if err := SetPINWithDir(testStorageDir, "2468"); err != nil {
+		t.Fatalf("SetPINWithDir failed: %v", err)
+	}
+	if ok, err := VerifyPINWithDir(testStorageDir, "2468"); err != nil || !ok {
+		t.Errorf("Expected PIN '2468' to verify, got %v, %v", ok, err)
+	}
+	if ok, err := VerifyPINWithDir(testStorageDir, "1357"); err != nil || ok {
+		t.Errorf("Expected PIN '1357' to be rejected, got %v, %v", ok, err)
+	}
+
+	// Logging out removes the PIN along with the tokens
+	if err := ClearCredentialsWithDir(testStorageDir); err != nil {
+		t.Fatalf("ClearCredentialsWithDir failed: %v", err)
+	}
+	if hasPIN, err = HasPINWithDir(testStorageDir); err != nil || hasPIN {
+		t.Errorf("Expected PIN to be removed, got %v, %v", hasPIN, err)
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+
+	"eldar/api"
+	"eldar/model"
+	"eldar/store"
+	"fyne.io/fyne/v2"
+)
+
+// stopEvents stops the goroutine receiving real-time events, it is nil while signed out
+var stopEvents context.CancelFunc
+
+// Delays between reconnects of the event stream, doubled after every failed attempt
+const (
+	minEventsBackoff = time.Second
+	maxEventsBackoff = time.Minute
+)
+
+// startEvents starts receiving the real-time events of the signed-in account into the task cache st
+func startEvents(st *store.Store, username string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stopEvents = cancel
+	go runEvents(ctx, st, username)
+}
+
+// closeEvents stops receiving real-time events, if started
+func closeEvents() {
+	if stopEvents == nil {
+		return
+	}
+	stopEvents()
+	stopEvents = nil
+}
+
+// runEvents streams events until ctx is done, reconnecting with an increasing delay when the connection is lost.
+// The ID of the last event handled is passed on reconnect so the server replays what was missed.
+func runEvents(ctx context.Context, st *store.Store, username string) {
+	lastEventID := ""
+	backoff := minEventsBackoff
+	for {
+		err := apiClient.Events(ctx, lastEventID, func(e api.Event) {
+			lastEventID = e.ID
+			backoff = minEventsBackoff
+			handleEvent(ctx, st, username, e)
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("Event stream disconnected, retrying in %v: %v", backoff, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxEventsBackoff)
+	}
+}
+
+// handleEvent applies an event to the task cache and notifies username when mentioned by someone else
+func handleEvent(ctx context.Context, st *store.Store, username string, e api.Event) {
+	switch e.Type {
+	case api.EventActivity:
+		var entry model.Activity
+		if err := json.Unmarshal(e.Data, &entry); err != nil {
+			log.Printf("Error decoding activity event: %v", err)
+			return
+		}
+		if err := st.ApplyActivity(ctx, entry); err != nil {
+			log.Printf("Error applying activity event: %v", err)
+		}
+		// Changes made elsewhere may conflict with what could be undone on the board
+		if h := undoHistory; h != nil && entry.Actor != username {
+			h.Clear(entry.BoardID)
+		}
+	case api.EventComment:
+		var comment model.Comment
+		if err := json.Unmarshal(e.Data, &comment); err != nil {
+			log.Printf("Error decoding comment event: %v", err)
+			return
+		}
+		if err := st.ApplyComment(comment); err != nil {
+			log.Printf("Error applying comment event: %v", err)
+		}
+	case api.EventMention:
+		var mention api.Mention
+		if err := json.Unmarshal(e.Data, &mention); err != nil {
+			log.Printf("Error decoding mention event: %v", err)
+			return
+		}
+		if err := st.ApplyComment(mention.Comment); err != nil {
+			log.Printf("Error applying mention event: %v", err)
+		}
+		if mention.Comment.Author == username || mention.Comment.Deleted {
+			return
+		}
+		author := mention.Comment.Author
+		if m, ok := st.Member(author); ok && m.Name != "" {
+			author = m.Name
+		}
+		fyne.CurrentApp().SendNotification(fyne.NewNotification(author+" mentioned you",
+			"On "+mention.TaskTitle+": "+excerpt(mention.Comment.Body, 120)))
+	}
+}
+
+// excerpt returns the first line of text, shortened to at most n runes
+func excerpt(text string, n int) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	if runes := []rune(line); len(runes) > n {
+		return string(runes[:n-1]) + "…"
+	}
+	return line
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"eldar/export"
+	"eldar/model"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+)
+
+// exportTasks writes the tasks on screen to a CSV file picked by the user: the search results while
+// the search bar holds a query, otherwise the tasks of the selected board
+func exportTasks() {
+	st, page := taskStore, boardsPage
+	if st == nil || page == nil {
+		return
+	}
+	name := "tasks.csv"
+	var tasks []model.Task
+	if query := strings.TrimSpace(page.Query()); query != "" {
+		results, err := searchTasks(query)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		tasks = results
+	} else {
+		board, ok := st.Board(page.SelectedBoard())
+		if !ok {
+			dialog.ShowInformation("Export tasks", "Pick a board or search for tasks to export.", w)
+			return
+		}
+		tasks = st.Tasks(board.ID)
+		name = board.Name + ".csv"
+	}
+
+	save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		if writer == nil {
+			return
+		}
+		err = export.TasksCSV(writer, tasks, st.Board)
+		if closeErr := writer.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			log.Printf("Error exporting tasks: %v", err)
+			dialog.ShowError(fmt.Errorf("failed to export tasks: %w", err), w)
+		}
+	}, w)
+	save.SetFileName(name)
+	save.Show()
+}
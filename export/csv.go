@@ -0,0 +1,88 @@
+// Package export writes tasks and time reports to files other tools can read, such as spreadsheets.
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"eldar/model"
+)
+
+// taskColumns are the columns written for every task, before those of the custom fields
+var taskColumns = []string{"ID", "Board", "Column", "Title", "Status", "Priority", "Due date", "Assignees", "Labels"}
+
+// TasksCSV writes tasks as CSV with a header row. Custom fields get a column each, named after
+// the field; fields of the same name on different boards share a column. Cells are escaped with
+// escapeCells, so spreadsheets do not run text written by other users as formulas.
+//
+// Parameters:
+//   - w: Where to write the CSV
+//   - tasks: The tasks to write, in order
+//   - board: A function returning the board of a task, used to name columns and custom fields
+//
+// Returns:
+//   - An error if writing failed
+func TasksCSV(w io.Writer, tasks []model.Task, board func(id string) (model.Board, bool)) error {
+	var fieldNames []string
+	fieldColumns := map[string]int{}
+	for _, task := range tasks {
+		b, _ := board(task.BoardID)
+		for _, f := range b.Fields {
+			key := strings.ToLower(f.Name)
+			if _, ok := fieldColumns[key]; !ok {
+				fieldColumns[key] = len(fieldNames)
+				fieldNames = append(fieldNames, f.Name)
+			}
+		}
+	}
+
+	out := csv.NewWriter(w)
+	if err := out.Write(escapeCells(append(append([]string{}, taskColumns...), fieldNames...))); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	for _, task := range tasks {
+		b, _ := board(task.BoardID)
+		column, _ := b.Column(task.ColumnID)
+		status := "Open"
+		if task.Done {
+			status = "Done"
+		}
+		due := ""
+		if task.DueDate != nil {
+			due = task.DueDate.Format(time.DateOnly)
+		}
+		record := []string{task.ID, b.Name, column.Name, task.Title, status, task.Priority.String(), due,
+			strings.Join(task.Assignees, ", "), strings.Join(task.Labels, ", ")}
+		fields := make([]string, len(fieldNames))
+		for _, f := range b.Fields {
+			fields[fieldColumns[strings.ToLower(f.Name)]] = f.Format(task.Fields[f.ID])
+		}
+		if err := out.Write(escapeCells(append(record, fields...))); err != nil {
+			return fmt.Errorf("failed to write task %s: %w", task.ID, err)
+		}
+	}
+	out.Flush()
+	if err := out.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// escapeCells prefixes the cells of record that a spreadsheet would read as a formula with a quote,
+// which makes it show them as text, and returns record. Numbers such as -3 are left as they are.
+func escapeCells(record []string) []string {
+	for i, cell := range record {
+		if cell == "" || !strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+			continue
+		}
+		if _, err := strconv.ParseFloat(cell, 64); err == nil {
+			continue
+		}
+		record[i] = "'" + cell
+	}
+	return record
+}
@@ -0,0 +1,66 @@
+package export
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"eldar/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTasksCSV(t *testing.T) {
+	boards := map[string]model.Board{
+		"b1": {ID: "b1", Name: "Sprint", Columns: []model.Column{{ID: "todo", Name: "To do"}}, Fields: []model.Field{
+			{ID: "f1", Name: "Estimate", Type: model.FieldNumber},
+			{ID: "f2", Name: "Platforms", Type: model.FieldMultiSelect, Options: []string{"iOS", "Web"}},
+		}},
+		"b2": {ID: "b2", Name: "Launch", Fields: []model.Field{{ID: "x", Name: "estimate", Type: model.FieldText}}},
+	}
+	board := func(id string) (model.Board, bool) {
+		b, ok := boards[id]
+		return b, ok
+	}
+	three := 3.0
+	due := time.Date(2025, 6, 30, 9, 0, 0, 0, time.UTC)
+	tasks := []model.Task{
+		{ID: "t1", BoardID: "b1", ColumnID: "todo", Title: "Write, review", Priority: model.PriorityHigh, DueDate: &due,
+			Assignees: []string{"ana@example.com"}, Labels: []string{"docs", "bug"},
+			Fields: map[string]model.FieldValue{"f1": {Number: &three}, "f2": {Choices: []string{"iOS", "Web"}}}},
+		{ID: "t2", BoardID: "b2", Title: "Ship", Done: true, Fields: map[string]model.FieldValue{"x": {Text: "Small"}}},
+	}
+
+	var b strings.Builder
+	require.NoError(t, TasksCSV(&b, tasks, board))
+	assert.Equal(t, "ID,Board,Column,Title,Status,Priority,Due date,Assignees,Labels,Estimate,Platforms\n"+
+		`t1,Sprint,To do,"Write, review",Open,High,2025-06-30,ana@example.com,"docs, bug",3,"iOS, Web"`+"\n"+
+		"t2,Launch,,Ship,Done,None,,,,Small,\n", b.String())
+
+	assert.Error(t, TasksCSV(failingWriter{}, tasks, board))
+}
+
+func TestTasksCSVFormulas(t *testing.T) {
+	board := func(id string) (model.Board, bool) {
+		return model.Board{ID: "b1", Name: "=HYPERLINK(\"http://example.com\")", Fields: []model.Field{
+			{ID: "f1", Name: "@Owner", Type: model.FieldText},
+			{ID: "f2", Name: "Delta", Type: model.FieldNumber},
+		}}, true
+	}
+	minus := -2.5
+	tasks := []model.Task{{ID: "t1", BoardID: "b1", Title: "+1 for this", Labels: []string{"-urgent"},
+		Fields: map[string]model.FieldValue{"f1": {Text: "\tcmd"}, "f2": {Number: &minus}}}}
+
+	var b strings.Builder
+	require.NoError(t, TasksCSV(&b, tasks, board))
+	assert.Equal(t, "ID,Board,Column,Title,Status,Priority,Due date,Assignees,Labels,'@Owner,Delta\n"+
+		`t1,"'=HYPERLINK(""http://example.com"")",,'+1 for this,Open,None,,,'-urgent,'`+"\tcmd,-2.5\n", b.String())
+}
+
+// failingWriter fails every write
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
@@ -0,0 +1,46 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"eldar/model"
+)
+
+// timeColumns are the columns of a time report
+var timeColumns = []string{"Week", "Board", "User", "Hours"}
+
+// TimeCSV writes the totals of a time report as CSV with a header row, one row per total.
+// Weeks are written as the date of their Monday and hours as a decimal number with up to two decimals, such as 1.5.
+// Board and user names are escaped like the cells of TasksCSV.
+//
+// Parameters:
+//   - w: Where to write the CSV
+//   - totals: The totals to write, in order, see model.SumTime
+//   - board: A function returning the board of a total, used to name it
+//   - user: A function returning the name of a user shown in the report
+//
+// Returns:
+//   - An error if writing failed
+func TimeCSV(w io.Writer, totals []model.TimeTotal, board func(id string) (model.Board, bool), user func(username string) string) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(timeColumns); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	for _, total := range totals {
+		b, _ := board(total.BoardID)
+		hours := strconv.FormatFloat(math.Round(total.Duration.Hours()*100)/100, 'f', -1, 64)
+		if err := out.Write(escapeCells([]string{total.Week.Format(time.DateOnly), b.Name, user(total.User), hours})); err != nil {
+			return fmt.Errorf("failed to write time total: %w", err)
+		}
+	}
+	out.Flush()
+	if err := out.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
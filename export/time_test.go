@@ -0,0 +1,45 @@
+package export
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"eldar/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeCSV(t *testing.T) {
+	board := func(id string) (model.Board, bool) {
+		return model.Board{ID: id, Name: "Sprint, June"}, id == "b1"
+	}
+	user := func(username string) string {
+		return strings.ToUpper(username)
+	}
+	week := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	totals := []model.TimeTotal{
+		{User: "ana", BoardID: "b1", Week: week, Duration: 90 * time.Minute},
+		{User: "ben", BoardID: "b1", Week: week, Duration: 20*time.Minute + 10*time.Second},
+	}
+
+	var out strings.Builder
+	require.NoError(t, TimeCSV(&out, totals, board, user))
+	assert.Equal(t, "Week,Board,User,Hours\n"+
+		"2025-06-02,\"Sprint, June\",ANA,1.5\n"+
+		"2025-06-02,\"Sprint, June\",BEN,0.34\n", out.String())
+}
+
+func TestTimeCSVFormulas(t *testing.T) {
+	board := func(id string) (model.Board, bool) {
+		return model.Board{ID: id, Name: "=1+1"}, true
+	}
+	user := func(username string) string {
+		return "@" + username
+	}
+	totals := []model.TimeTotal{{User: "ana", BoardID: "b1", Week: time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC), Duration: time.Hour}}
+
+	var out strings.Builder
+	require.NoError(t, TimeCSV(&out, totals, board, user))
+	assert.Equal(t, "Week,Board,User,Hours\n2025-06-02,'=1+1,'@ana,1\n", out.String())
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"eldar/undo"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+)
+
+// undoHistory holds the changes of the signed-in account that can be undone, it is nil while signed out
+var undoHistory *undo.History
+
+// contentTabs are the Board, Calendar, Sprints, Time, Archive and Trash tabs of the main window, nil while signed out
+var contentTabs *container.AppTabs
+
+// runTaskCommand makes a change of the task cache in the background, recording it in the undo history
+// of its board, and shows its error, if any
+func runTaskCommand(boardID string, c undo.Command) {
+	history := undoHistory
+	runTaskAction(c.Name(), func(ctx context.Context) error {
+		return history.Run(ctx, boardID, c)
+	})
+}
+
+// currentBoard returns the board undo and redo apply to: the board shown on the Board tab,
+// or the board changed most recently from the other tabs or when it was archived or deleted
+func currentBoard() string {
+	if boardsPage != nil && contentTabs != nil && contentTabs.SelectedIndex() == 0 {
+		// A board archived or deleted last is no longer on the Board tab, so that change comes first
+		if h, st := undoHistory, taskStore; h != nil && st != nil {
+			if id := h.Latest(); id != "" {
+				if board, ok := st.Board(id); !ok || board.Archived() {
+					return id
+				}
+			}
+		}
+		return boardsPage.SelectedBoard()
+	}
+	return ""
+}
+
+// undoLast undoes the last change of the current board
+func undoLast() {
+	stepHistory("undo", func(ctx context.Context, h *undo.History, boardID string) (string, error) {
+		return h.Undo(ctx, boardID)
+	})
+}
+
+// redoLast makes the last undone change of the current board again
+func redoLast() {
+	stepHistory("redo", func(ctx context.Context, h *undo.History, boardID string) (string, error) {
+		return h.Redo(ctx, boardID)
+	})
+}
+
+// stepHistory runs an undo or redo in the background. Nothing happens when there is nothing to undo,
+// and a conflict with a change made elsewhere is explained rather than reported as an error.
+func stepHistory(verb string, step func(ctx context.Context, h *undo.History, boardID string) (string, error)) {
+	h := undoHistory
+	if h == nil || locked {
+		return
+	}
+	boardID := currentBoard()
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		name, err := step(ctx, h, boardID)
+		switch {
+		case err == nil, errors.Is(err, undo.ErrEmpty):
+		case errors.Is(err, undo.ErrConflict):
+			fyne.Do(func() {
+				dialog.ShowInformation("Cannot "+verb+" "+name, err.Error()+".", w)
+			})
+		default:
+			log.Printf("Error trying to %s %s: %v", verb, name, err)
+			fyne.Do(func() {
+				dialog.ShowError(err, w)
+			})
+		}
+	}()
+}
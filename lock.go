@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"slices"
+	"time"
+
+	"eldar/api"
+	"eldar/auth"
+	"eldar/credentials"
+	"eldar/ui"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// defaultLockTimeoutMinutes is the idle time before the window locks when not configured
+const defaultLockTimeoutMinutes = 15
+
+// maxPINAttempts is the number of wrong PINs after which only the account password unlocks the window
+const maxPINAttempts = 5
+
+// typingCheckInterval is how often typing into the focused entry is looked for, see ui.WatchTyping
+const typingCheckInterval = 5 * time.Second
+
+// pinThrottle enforces the backoff between wrong PINs for the lifetime of the app
+var pinThrottle = auth.NewThrottle(time.Second, time.Minute)
+
+// idleMonitor locks the window once the user has been inactive for the configured time
+var idleMonitor *ui.IdleMonitor
+
+// signedIn is true while the window shows the content of a signed in account
+var signedIn bool
+
+// locked is true while the window is locked, lockedContent, lockedMenu and lockedOverlays hold what the
+// unlock screen replaced so that nothing, including unsaved edits in open dialogs, is lost
+var locked bool
+var lockedContent fyne.CanvasObject
+var lockedMenu *fyne.MainMenu
+var lockedOverlays []fyne.CanvasObject
+
+// lockTimeout returns the configured idle timeout, zero when auto-lock is disabled
+func lockTimeout(prefs fyne.Preferences) time.Duration {
+	return time.Duration(prefs.IntWithFallback("idle_timeout_minutes", defaultLockTimeoutMinutes)) * time.Minute
+}
+
+// lockWindow hides the window content behind the unlock screen.
+// Tokens are kept, so the session continues once the user unlocks.
+func lockWindow() {
+	if locked || !signedIn {
+		return
+	}
+	hasPIN, err := credentials.HasPIN()
+	if err != nil {
+		log.Printf("Error checking PIN: %v", err)
+	}
+	hasPIN = hasPIN && pinThrottle.Failures() < maxPINAttempts
+
+	locked = true
+	idleMonitor.Pause()
+	lockedContent = w.Content()
+	lockedMenu = w.MainMenu()
+	w.SetMainMenu(nil)
+	// Dialogs are overlays drawn above the content, hiding them takes them off the canvas until unlocked
+	lockedOverlays = slices.Clone(w.Canvas().Overlays().List())
+	for _, o := range lockedOverlays {
+		o.Hide()
+	}
+
+	title := widget.NewLabel("Eldar is locked")
+	title.Alignment = fyne.TextAlignCenter
+	w.SetContent(container.NewVBox(title, ui.MakeUnlockForm(hasPIN, unlock, unlockWindow, func(err error) {
+		dialog.ShowError(err, w)
+	})))
+}
+
+// unlock checks the PIN locally or the account password with the server.
+// Wrong PINs are throttled, and after maxPINAttempts of them only the password unlocks.
+// Passwords share loginThrottle with the login form, so locking does not reset its backoff.
+func unlock(secret string, usePIN bool) error {
+	if usePIN {
+		return unlockWithPIN(secret)
+	}
+
+	if remaining := loginThrottle.Remaining(); remaining > 0 {
+		return fmt.Errorf("too many failed attempts, try again in %ds", int(math.Ceil(remaining.Seconds())))
+	}
+	creds, err := credentials.GetCredentials()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = apiClient.VerifyPassword(ctx, creds.Username, secret)
+	loginThrottle.Record(err)
+	if err != nil {
+		if api.IsStatus(err, http.StatusUnauthorized) {
+			return errors.New("wrong password")
+		}
+		return fmt.Errorf("could not verify password: %w", err)
+	}
+	pinThrottle.Record(nil)
+	return nil
+}
+
+// unlockWithPIN checks the PIN locally, recording the attempt with pinThrottle
+func unlockWithPIN(pin string) error {
+	if pinThrottle.Failures() >= maxPINAttempts {
+		return ui.ErrUsePassword
+	}
+	if remaining := pinThrottle.Remaining(); remaining > 0 {
+		return fmt.Errorf("too many wrong PINs, try again in %ds", int(math.Ceil(remaining.Seconds())))
+	}
+	ok, err := credentials.VerifyPIN(pin)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		err = auth.ErrWrongPIN
+	}
+	pinThrottle.Record(err)
+	if err != nil && pinThrottle.Failures() >= maxPINAttempts {
+		return ui.ErrUsePassword
+	}
+	return err
+}
+
+// unlockWindow restores the content and menu that were shown when the window locked
+func unlockWindow() {
+	if !locked {
+		return
+	}
+	locked = false
+	w.SetMainMenu(lockedMenu)
+	w.SetContent(lockedContent)
+	for _, o := range lockedOverlays {
+		o.Show()
+	}
+	lockedContent, lockedMenu, lockedOverlays = nil, nil, nil
+	idleMonitor.Resume()
+}
+
+// showLockSettings shows a dialog to configure the idle timeout and local unlock PIN
+func showLockSettings() {
+	prefs := fyne.CurrentApp().Preferences()
+	hasPIN, err := credentials.HasPIN()
+	if err != nil {
+		log.Printf("Error checking PIN: %v", err)
+	}
+
+	var d dialog.Dialog
+	form := ui.MakeLockSettingsForm(int(lockTimeout(prefs)/time.Minute), hasPIN, func(settings ui.LockSettings) {
+		prefs.SetInt("idle_timeout_minutes", settings.TimeoutMinutes)
+		idleMonitor.SetTimeout(lockTimeout(prefs))
+		var err error
+		if settings.RemovePIN {
+			err = credentials.SetPIN("")
+		} else if settings.PIN != "" {
+			err = credentials.SetPIN(settings.PIN)
+		}
+		if err != nil {
+			log.Printf("Error saving PIN: %v", err)
+			dialog.ShowError(err, w)
+		}
+		d.Hide()
+	})
+	form.OnCancel = func() {
+		d.Hide()
+	}
+	d = dialog.NewCustomWithoutButtons("Lock settings", form, w)
+	d.Show()
+}
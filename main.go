@@ -2,12 +2,18 @@ package main
 
 import (
 	"log"
+	"os"
+	"time"
 
+	"eldar/api"
+	"eldar/auth"
 	"eldar/credentials"
 	"eldar/ui"
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 )
 
@@ -23,18 +29,41 @@ const (
 var appPage ui.AppPage
 var w fyne.Window
 
+// apiClient talks to the Eldar server
+var apiClient *api.Client
+
+// loginThrottle enforces the backoff between failed login attempts for the lifetime of the app
+var loginThrottle = auth.NewThrottle(time.Second, 5*time.Minute)
+
+// oidcClient is set when an identity provider is configured, enabling SSO sign-in
+var oidcClient *auth.OIDCClient
+
 // updateWindowContent updates the content of the window based on the current credentials
 func updateWindowContent() {
 	if appPage == Login {
 		noCredsLabel := widget.NewLabel("Login")
 		noCredsLabel.Alignment = fyne.TextAlignCenter
-		w.SetContent(container.NewVBox(noCredsLabel, ui.MakeLoginForm(&appPage, updateWindowContent)))
+		content := container.NewVBox(noCredsLabel, ui.MakeLoginForm(&appPage, updateWindowContent, login, loginThrottle, func(err error) {
+			log.Printf("Error logging in: %v", err)
+			dialog.ShowError(err, w)
+		}))
+		if oidcClient != nil {
+			content.Add(ui.MakeOIDCLoginButton(&appPage, updateWindowContent, oidcSignIn, func(err error) {
+				log.Printf("Error signing in with SSO: %v", err)
+				dialog.ShowError(err, w)
+			}))
+		}
+		signedIn = false
+		w.SetMainMenu(nil)
+		w.SetContent(content)
 		return
 	}
 
 	if appPage == Register {
 		noCredsLabel := widget.NewLabel("Register")
 		noCredsLabel.Alignment = fyne.TextAlignCenter
+		signedIn = false
+		w.SetMainMenu(nil)
 		w.SetContent(container.NewVBox(noCredsLabel, ui.MakeRegisterForm()))
 		return
 	}
@@ -45,21 +74,67 @@ func updateWindowContent() {
 		creds = &credentials.Credentials{}
 	}
 
-	// No creds exist, we need to ask user to login
-	if creds.Username == "" && creds.AccessToken == "" && creds.RefreshToken == "" {
+	// No creds exist or both tokens expired, we need to ask user to login
+	if creds.IsEmpty() || !creds.IsValid(time.Now()) {
 		appPage = Login
 		updateWindowContent()
 		return
 	}
 
-	// Credentials are not empty, display todo for now
-	w.SetContent(container.NewVBox(widget.NewLabel("TODO")))
+	signedIn = true
+	apiClient.SetAccessToken(creds.AccessToken)
+	w.SetMainMenu(fyne.NewMainMenu(ui.MakeAccountMenu(func() {
+		logout(false)
+	}, func() {
+		dialog.ShowConfirm("Sign out all sessions",
+			"This signs your account out on every device. Continue?", func(ok bool) {
+				if ok {
+					logout(true)
+				}
+			}, w)
+	}), ui.MakeSecurityMenu(lockWindow, showLockSettings), ui.MakeTasksMenu(showNewBoard, showNewTask, undoLast, redoLast, exportTasks, showReminderSettings)))
+
+	if err := openTaskStore(creds.Username); err != nil {
+		log.Printf("Error opening task store: %v", err)
+		dialog.ShowError(err, w)
+		return
+	}
+	appPage = Boards
+	contentTabs = container.NewAppTabs(
+		container.NewTabItemWithIcon("Board", theme.GridIcon(), container.NewBorder(nil, nil, viewsSidebar, nil, boardsPage)),
+		container.NewTabItemWithIcon("Calendar", theme.CalendarIcon(), calendarPage),
+		container.NewTabItemWithIcon("Sprints", theme.MediaFastForwardIcon(), sprintPage),
+		container.NewTabItemWithIcon("Time", theme.HistoryIcon(), timeReportPage),
+		container.NewTabItemWithIcon("Archive", theme.StorageIcon(), archivePage),
+		container.NewTabItemWithIcon("Trash", theme.DeleteIcon(), trashPage),
+	)
+	w.SetContent(ui.NewActivityTracker(container.NewBorder(nil, timerBar, nil, nil, contentTabs), idleMonitor.Touch))
 }
 
 func main() {
 	appPage = Unknown
 	a := app.NewWithID("dev.ioluas.eldar")
 	w = a.NewWindow("Eldar")
+	apiClient = api.NewClient(a.Preferences().StringWithFallback("api_url", os.Getenv("ELDAR_API_URL")), nil)
+	oidcClient = newOIDCClient(a.Preferences())
+	idleMonitor = ui.NewIdleMonitor(lockTimeout(a.Preferences()), func() {
+		fyne.Do(lockWindow)
+	})
+	w.Canvas().SetOnTypedKey(func(*fyne.KeyEvent) {
+		idleMonitor.Touch()
+	})
+	w.Canvas().SetOnTypedRune(func(rune) {
+		idleMonitor.Touch()
+	})
+	ui.WatchTyping(w.Canvas(), typingCheckInterval, idleMonitor.Touch)
+	w.Canvas().AddShortcut(ui.UndoShortcut, func(fyne.Shortcut) {
+		undoLast()
+	})
+	w.Canvas().AddShortcut(ui.RedoShortcut, func(fyne.Shortcut) {
+		redoLast()
+	})
+	go refreshLoop()
+	go tickTimers()
 	updateWindowContent()
 	w.ShowAndRun()
 }
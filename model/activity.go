@@ -0,0 +1,101 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ActivityKind is what happened in an Activity
+type ActivityKind string
+
+// Activity kinds
+const (
+	ActivityCreated    ActivityKind = "created"
+	ActivityMoved      ActivityKind = "moved"
+	ActivityEdited     ActivityKind = "edited"
+	ActivityAssigned   ActivityKind = "assigned"
+	ActivityUnassigned ActivityKind = "unassigned"
+	ActivityDeleted    ActivityKind = "deleted"
+	ActivityRestored   ActivityKind = "restored"
+)
+
+// Activity is an entry of the append-only event log of a board, recorded by the server on every change of a task
+type Activity struct {
+	// Seq numbers the entries of a board from 1 without gaps, so the log can be fetched incrementally
+	Seq     int64  `json:"seq"`
+	BoardID string `json:"board_id"`
+	TaskID  string `json:"task_id"`
+	// TaskTitle is the title of the task at the time, so entries still read well once it is renamed or deleted
+	TaskTitle string `json:"task_title"`
+	// Actor is the username of the account that made the change
+	Actor string       `json:"actor"`
+	Kind  ActivityKind `json:"kind"`
+	// Field is the JSON name of the changed task field, set on ActivityEdited entries
+	Field string `json:"field,omitempty"`
+	// From and To are the previous and new value: column IDs for ActivityMoved, the username for
+	// ActivityAssigned and ActivityUnassigned, and the field values formatted as text for ActivityEdited
+	From string    `json:"from,omitempty"`
+	To   string    `json:"to,omitempty"`
+	At   time.Time `json:"at"`
+}
+
+// Describe returns what happened as text following the name of the actor, as in
+// `moved "Write report" from To do to Done`.
+//
+// Parameters:
+//   - board: The board of the entry, used to name columns
+//   - name: A function returning the display name of a username
+func (a Activity) Describe(board Board, name func(username string) string) string {
+	task := fmt.Sprintf("%q", a.TaskTitle)
+	switch a.Kind {
+	case ActivityCreated:
+		return "created " + task + " in " + columnName(board, a.To)
+	case ActivityMoved:
+		return "moved " + task + " from " + columnName(board, a.From) + " to " + columnName(board, a.To)
+	case ActivityAssigned:
+		return "assigned " + task + " to " + name(a.To)
+	case ActivityUnassigned:
+		return "unassigned " + name(a.From) + " from " + task
+	case ActivityDeleted:
+		return "deleted " + task
+	case ActivityRestored:
+		return "restored " + task + " from the trash"
+	case ActivityEdited:
+		field := strings.ReplaceAll(a.Field, "_", " ")
+		from, to := a.From, a.To
+		if a.Field == "sprint_id" {
+			field, from, to = "sprint", sprintName(board, from), sprintName(board, to)
+		}
+		switch {
+		case from == "" && to == "":
+			return "edited the " + field + " of " + task
+		case from == "":
+			return fmt.Sprintf("set the %s of %s to %s", field, task, quoteValue(to))
+		case to == "":
+			return fmt.Sprintf("cleared the %s of %s", field, task)
+		default:
+			return fmt.Sprintf("changed the %s of %s from %s to %s", field, task, quoteValue(from), quoteValue(to))
+		}
+	default:
+		return string(a.Kind) + " " + task
+	}
+}
+
+// columnName returns the name of a column of board, or its ID when it no longer exists
+func columnName(board Board, id string) string {
+	if c, ok := board.Column(id); ok {
+		return c.Name
+	}
+	return id
+}
+
+// quoteValue shortens a field value for display, values such as descriptions can be long
+func quoteValue(value string) string {
+	const maxLength = 60
+	value = strings.Join(strings.Fields(value), " ")
+	if runes := []rune(value); len(runes) > maxLength {
+		value = string(runes[:maxLength-1]) + "…"
+	}
+	return value
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActivityDescribe(t *testing.T) {
+	board := Board{ID: "b1", Columns: []Column{{ID: "todo", Name: "To do"}, {ID: "done", Name: "Done"}}}
+	name := func(username string) string {
+		return strings.ToUpper(username[:1]) + username[1:]
+	}
+	entry := Activity{TaskTitle: "Write report"}
+	for kind, want := range map[ActivityKind]string{
+		ActivityCreated:    `created "Write report" in To do`,
+		ActivityMoved:      `moved "Write report" from To do to Done`,
+		ActivityAssigned:   `assigned "Write report" to Ben`,
+		ActivityUnassigned: `unassigned Ana from "Write report"`,
+		ActivityDeleted:    `deleted "Write report"`,
+		ActivityRestored:   `restored "Write report" from the trash`,
+	} {
+		entry.Kind, entry.From, entry.To = kind, "todo", "done"
+		if kind == ActivityAssigned || kind == ActivityUnassigned {
+			entry.From, entry.To = "ana", "ben"
+		}
+		if kind == ActivityCreated {
+			entry.From, entry.To = "", "todo"
+		}
+		assert.Equal(t, want, entry.Describe(board, name), kind)
+	}
+
+	// Columns that are gone are shown by ID
+	entry = Activity{TaskTitle: "Write report", Kind: ActivityMoved, From: "review", To: "done"}
+	assert.Equal(t, `moved "Write report" from review to Done`, entry.Describe(board, name))
+
+	entry = Activity{TaskTitle: "Write report", Kind: ActivityEdited, Field: "due_date"}
+	entry.From, entry.To = "Low", "High"
+	assert.Equal(t, `changed the due date of "Write report" from Low to High`, entry.Describe(board, name))
+	entry.From = ""
+	assert.Equal(t, `set the due date of "Write report" to High`, entry.Describe(board, name))
+	entry.From, entry.To = "Low", ""
+	assert.Equal(t, `cleared the due date of "Write report"`, entry.Describe(board, name))
+	entry.Field, entry.From, entry.To = "description", "", strings.Repeat("word ", 20)
+	assert.Equal(t, `set the description of "Write report" to `+strings.Repeat("word ", 11)+"word…", entry.Describe(board, name))
+
+	// Sprints are named, those that are gone are shown by ID
+	board.Sprints = []Sprint{{ID: "s1", Name: "Sprint 1"}}
+	entry.Field, entry.From, entry.To = "sprint_id", "s0", "s1"
+	assert.Equal(t, `changed the sprint of "Write report" from s0 to Sprint 1`, entry.Describe(board, name))
+}
@@ -0,0 +1,79 @@
+package model
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaxAttachmentSize is the largest file the server accepts as an attachment, in bytes
+const MaxAttachmentSize = 25 << 20
+
+// Attachment is a file attached to a task. Its content is stored by the server and downloaded
+// on demand into a local cache keyed by Hash, see package attachment.
+type Attachment struct {
+	ID     string `json:"id"`
+	TaskID string `json:"task_id"`
+	// Name is the file name the attachment was uploaded with
+	Name      string `json:"name"`
+	MediaType string `json:"media_type"`
+	Size      int64  `json:"size"`
+	// Hash is the hex encoded SHA-256 of the content
+	Hash string `json:"hash"`
+	// Uploader is the username of the account that attached the file
+	Uploader  string    `json:"uploader"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Validate checks that the attachment can be uploaded
+func (a *Attachment) Validate() error {
+	if strings.TrimSpace(a.Name) == "" {
+		return errors.New("attachment name must not be empty")
+	}
+	if a.Size > MaxAttachmentSize {
+		return fmt.Errorf("attachment must be at most %s", FormatSize(MaxAttachmentSize))
+	}
+	if a.Hash != "" {
+		if b, err := hex.DecodeString(a.Hash); err != nil || len(b) != 32 {
+			return fmt.Errorf("invalid attachment hash %q", a.Hash)
+		}
+	}
+	return nil
+}
+
+// IsImage reports whether the attachment is an image a thumbnail can be made of
+func (a *Attachment) IsImage() bool {
+	switch strings.ToLower(a.MediaType) {
+	case "image/png", "image/jpeg", "image/gif":
+		return true
+	}
+	return false
+}
+
+// Cover returns the most recently attached image of the task, which is shown on its card
+func (t *Task) Cover() (Attachment, bool) {
+	for i := len(t.Attachments) - 1; i >= 0; i-- {
+		if t.Attachments[i].IsImage() {
+			return t.Attachments[i], true
+		}
+	}
+	return Attachment{}, false
+}
+
+// FormatSize formats a number of bytes for display, as in "512 B", "1.5 KB" or "25.0 MB"
+func FormatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	value, suffix := float64(size)/unit, "KB"
+	for _, s := range []string{"MB", "GB"} {
+		if value < unit {
+			break
+		}
+		value, suffix = value/unit, s
+	}
+	return fmt.Sprintf("%.1f %s", value, suffix)
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttachmentValidate(t *testing.T) {
+	valid := Attachment{Name: "plan.pdf", Size: 1024, Hash: strings.Repeat("ab", 32)}
+	assert.NoError(t, valid.Validate())
+
+	for name, a := range map[string]Attachment{
+		"no name":  {Name: " ", Size: 1},
+		"too big":  {Name: "video.mp4", Size: MaxAttachmentSize + 1},
+		"bad hash": {Name: "plan.pdf", Hash: "abc"},
+	} {
+		assert.Error(t, a.Validate(), name)
+	}
+}
+
+func TestTaskCover(t *testing.T) {
+	task := Task{Attachments: []Attachment{
+		{ID: "a", MediaType: "image/png"},
+		{ID: "b", MediaType: "image/JPEG"},
+		{ID: "c", MediaType: "application/pdf"},
+		{ID: "d", MediaType: "image/svg+xml"},
+	}}
+	cover, ok := task.Cover()
+	assert.True(t, ok)
+	assert.Equal(t, "b", cover.ID)
+
+	_, ok = (&Task{}).Cover()
+	assert.False(t, ok)
+}
+
+func TestFormatSize(t *testing.T) {
+	assert.Equal(t, "512 B", FormatSize(512))
+	assert.Equal(t, "1.5 KB", FormatSize(1536))
+	assert.Equal(t, "25.0 MB", FormatSize(MaxAttachmentSize))
+	assert.Equal(t, "2.0 GB", FormatSize(2<<30))
+}
@@ -0,0 +1,165 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Column is a list of tasks on a board, such as "To do" or "Done"
+type Column struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// WIPLimit is the number of tasks the column should hold at most, 0 for no limit
+	WIPLimit int `json:"wip_limit,omitempty"`
+	// WIPPolicy is what happens when a task is moved into the column at its limit, WIPWarn when empty
+	WIPPolicy WIPPolicy `json:"wip_policy,omitempty"`
+	// DefinitionOfDone lists the checklist items a task must have completed before it is moved into the column
+	DefinitionOfDone []string `json:"definition_of_done,omitempty"`
+}
+
+// Board groups tasks into columns
+type Board struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Columns []Column `json:"columns"`
+	// Labels is the set of labels offered for the tasks of the board
+	Labels []Label `json:"labels,omitempty"`
+	// Fields are the custom fields of the tasks of the board, in display order
+	Fields []Field `json:"fields,omitempty"`
+	// Swimlanes is the task field the columns are split into horizontal lanes by, SwimlanesNone for a single row
+	Swimlanes Swimlanes `json:"swimlanes,omitempty"`
+	// Sprints are the sprints and milestones tasks of the board are planned into, in display order
+	Sprints []Sprint `json:"sprints,omitempty"`
+	// ArchivedAt is set on finished boards, which are kept read-only out of the way of the active ones
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+	// DeletedAt is set on boards in the trash, see TrashRetention
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// Version is incremented by the server on every change and used to detect conflicting edits
+	Version int64 `json:"version"`
+}
+
+// Column returns the column with the given ID
+func (b *Board) Column(id string) (Column, bool) {
+	for _, c := range b.Columns {
+		if c.ID == id {
+			return c, true
+		}
+	}
+	return Column{}, false
+}
+
+// ColumnNamed returns the column of the board with the given name, ignoring case
+func (b *Board) ColumnNamed(name string) (Column, bool) {
+	for _, c := range b.Columns {
+		if strings.EqualFold(c.Name, name) {
+			return c, true
+		}
+	}
+	return Column{}, false
+}
+
+// Archived reports whether the board is archived
+func (b *Board) Archived() bool {
+	return b.ArchivedAt != nil
+}
+
+// Label returns the label of the board with the given name, ignoring case
+func (b *Board) Label(name string) (Label, bool) {
+	for _, l := range b.Labels {
+		if strings.EqualFold(l.Name, name) {
+			return l, true
+		}
+	}
+	return Label{}, false
+}
+
+// TaskLabels returns the labels of a task with their colour from the label set of the board.
+// Labels that are not in the set have no colour.
+func (b *Board) TaskLabels(task *Task) []Label {
+	labels := make([]Label, 0, len(task.Labels))
+	for _, name := range task.Labels {
+		l, ok := b.Label(name)
+		if !ok {
+			l = Label{Name: name}
+		}
+		labels = append(labels, l)
+	}
+	return labels
+}
+
+// Field returns the custom field of the board with the given ID
+func (b *Board) Field(id string) (Field, bool) {
+	for _, f := range b.Fields {
+		if f.ID == id {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+// FieldNamed returns the custom field of the board with the given name, ignoring case
+func (b *Board) FieldNamed(name string) (Field, bool) {
+	for _, f := range b.Fields {
+		if strings.EqualFold(f.Name, name) {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+// Validate checks that the column policies, labels, custom fields, swimlanes and sprints of the board can be saved
+func (b *Board) Validate() error {
+	if strings.TrimSpace(b.Name) == "" {
+		return errors.New("board name must not be empty")
+	}
+	if err := b.Swimlanes.validate(); err != nil {
+		return err
+	}
+	for _, c := range b.Columns {
+		if err := c.validate(); err != nil {
+			return err
+		}
+	}
+	labels := map[string]bool{}
+	for _, l := range b.Labels {
+		if err := l.Validate(); err != nil {
+			return err
+		}
+		key := strings.ToLower(l.Name)
+		if labels[key] {
+			return fmt.Errorf("label %s is defined twice", l.Name)
+		}
+		labels[key] = true
+	}
+	ids, names := map[string]bool{}, map[string]bool{}
+	for _, f := range b.Fields {
+		if err := f.Validate(); err != nil {
+			return err
+		}
+		if f.ID == "" || ids[f.ID] {
+			return fmt.Errorf("field %s needs a unique ID", f.Name)
+		}
+		key := strings.ToLower(f.Name)
+		if names[key] {
+			return fmt.Errorf("field %s is defined twice", f.Name)
+		}
+		ids[f.ID], names[key] = true, true
+	}
+	return b.validateSprints()
+}
+
+// CheckFields checks that every custom field value of a task belongs to a field of the board and fits it
+func (b *Board) CheckFields(task *Task) error {
+	for id, v := range task.Fields {
+		f, ok := b.Field(id)
+		if !ok {
+			return fmt.Errorf("unknown field %s", id)
+		}
+		if err := f.CheckValue(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// BulkAction is a change made to every task of a selection at once
+type BulkAction string
+
+// Bulk actions
+const (
+	BulkMove    BulkAction = "move"
+	BulkAssign  BulkAction = "assign"
+	BulkLabel   BulkAction = "label"
+	BulkDue     BulkAction = "due"
+	BulkArchive BulkAction = "archive"
+	BulkDelete  BulkAction = "delete"
+)
+
+// BulkEdit describes a change made to every task of a selection
+type BulkEdit struct {
+	Action BulkAction
+	// Column is the name of the column BulkMove moves tasks to, looked up on the board of each task
+	// so that tasks of several boards can be moved together
+	Column string
+	// Assignee is the username BulkAssign adds to the assignees of each task
+	Assignee string
+	// Label is the label BulkLabel adds to each task
+	Label string
+	// DueDate is the due date BulkDue sets, nil to clear it
+	DueDate *time.Time
+}
+
+// Apply returns a copy of task changed by the edit. board is the board of the task and now the time
+// tasks are archived at. BulkDelete returns the task unchanged, as it is moved to the trash instead.
+func (e BulkEdit) Apply(task Task, board *Board, now time.Time) (Task, error) {
+	edited := task.Clone()
+	switch e.Action {
+	case BulkMove:
+		column, ok := board.ColumnNamed(e.Column)
+		if !ok {
+			return Task{}, fmt.Errorf("board %s has no column %s", board.Name, e.Column)
+		}
+		edited.ColumnID = column.ID
+	case BulkAssign:
+		if !slices.Contains(edited.Assignees, e.Assignee) {
+			edited.Assignees = append(edited.Assignees, e.Assignee)
+		}
+	case BulkLabel:
+		name := e.Label
+		// Board labels keep the spelling of the board
+		if l, ok := board.Label(name); ok {
+			name = l.Name
+		}
+		if !slices.ContainsFunc(edited.Labels, func(l string) bool {
+			return strings.EqualFold(l, name)
+		}) {
+			edited.Labels = append(edited.Labels, name)
+		}
+	case BulkDue:
+		edited.DueDate = nil
+		if e.DueDate != nil {
+			due := *e.DueDate
+			edited.DueDate = &due
+		}
+	case BulkArchive:
+		edited.ArchivedAt = &now
+	case BulkDelete:
+	default:
+		return Task{}, fmt.Errorf("unknown bulk action %q", e.Action)
+	}
+	return edited, nil
+}
+
+// TaskBatch is a set of task changes the server applies in a single transaction. Changes that cannot be
+// made, for example because the task was edited by someone else since, are reported in the BatchResult
+// while the others are still made.
+type TaskBatch struct {
+	// Update holds the edited tasks, with the version they were edited from
+	Update []Task `json:"update,omitempty"`
+	// Delete holds the IDs of the tasks to move to the trash
+	Delete []string `json:"delete,omitempty"`
+}
+
+// BatchResult is the outcome of a TaskBatch
+type BatchResult struct {
+	// Updated holds the server copies of the tasks that were updated
+	Updated []Task `json:"updated,omitempty"`
+	// Deleted holds the IDs of the tasks that were moved to the trash
+	Deleted []string       `json:"deleted,omitempty"`
+	Failed  []BatchFailure `json:"failed,omitempty"`
+}
+
+// BatchFailure is a change of a TaskBatch that could not be made
+type BatchFailure struct {
+	TaskID string `json:"task_id"`
+	// Title is the title of the task, filled in from the cache
+	Title string `json:"title,omitempty"`
+	Error string `json:"error"`
+}
+
+// Changed returns the number of tasks that were changed
+func (r *BatchResult) Changed() int {
+	return len(r.Updated) + len(r.Deleted)
+}
+
+// Err returns an error listing the changes that failed, or nil when all of them were made
+func (r *BatchResult) Err() error {
+	if len(r.Failed) == 0 {
+		return nil
+	}
+	reasons := make([]string, len(r.Failed))
+	for i, f := range r.Failed {
+		name := f.Title
+		if name == "" {
+			name = f.TaskID
+		}
+		reasons[i] = name + ": " + f.Error
+	}
+	return fmt.Errorf("%d of %d tasks could not be changed:\n%s", len(r.Failed), len(r.Failed)+r.Changed(), strings.Join(reasons, "\n"))
+}
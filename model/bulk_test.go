@@ -0,0 +1,63 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkEditApply(t *testing.T) {
+	now := time.Date(2025, 6, 3, 10, 0, 0, 0, time.UTC)
+	board := Board{Name: "Sprint", Columns: []Column{{ID: "todo", Name: "To do"}, {ID: "done", Name: "Done"}},
+		Labels: []Label{{Name: "Bug"}}}
+	task := Task{ID: "t1", ColumnID: "todo", Title: "Fix login", Assignees: []string{"ana"}, Labels: []string{"ui"}}
+
+	moved, err := BulkEdit{Action: BulkMove, Column: "done"}.Apply(task, &board, now)
+	require.NoError(t, err)
+	assert.Equal(t, "done", moved.ColumnID)
+	_, err = BulkEdit{Action: BulkMove, Column: "Review"}.Apply(task, &board, now)
+	assert.EqualError(t, err, "board Sprint has no column Review")
+
+	assigned, err := BulkEdit{Action: BulkAssign, Assignee: "bo"}.Apply(task, &board, now)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ana", "bo"}, assigned.Assignees)
+	assert.Equal(t, []string{"ana"}, task.Assignees)
+	assigned, _ = BulkEdit{Action: BulkAssign, Assignee: "ana"}.Apply(task, &board, now)
+	assert.Equal(t, []string{"ana"}, assigned.Assignees)
+
+	labelled, err := BulkEdit{Action: BulkLabel, Label: "bug"}.Apply(task, &board, now)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ui", "Bug"}, labelled.Labels)
+	labelled, _ = BulkEdit{Action: BulkLabel, Label: "UI"}.Apply(task, &board, now)
+	assert.Equal(t, []string{"ui"}, labelled.Labels)
+
+	due := time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC)
+	scheduled, err := BulkEdit{Action: BulkDue, DueDate: &due}.Apply(task, &board, now)
+	require.NoError(t, err)
+	assert.Equal(t, due, *scheduled.DueDate)
+	cleared, _ := BulkEdit{Action: BulkDue}.Apply(scheduled, &board, now)
+	assert.Nil(t, cleared.DueDate)
+
+	archived, err := BulkEdit{Action: BulkArchive}.Apply(task, &board, now)
+	require.NoError(t, err)
+	assert.True(t, archived.Archived())
+	assert.Equal(t, now, *archived.Clone().ArchivedAt)
+	assert.False(t, task.Archived())
+
+	unchanged, err := BulkEdit{Action: BulkDelete}.Apply(task, &board, now)
+	require.NoError(t, err)
+	assert.Equal(t, task, unchanged)
+	_, err = BulkEdit{Action: "rename"}.Apply(task, &board, now)
+	assert.Error(t, err)
+}
+
+func TestBatchResultErr(t *testing.T) {
+	result := BatchResult{Updated: []Task{{ID: "t1"}}, Deleted: []string{"t2"}}
+	assert.Equal(t, 2, result.Changed())
+	assert.NoError(t, result.Err())
+
+	result.Failed = []BatchFailure{{TaskID: "t3", Title: "Fix login", Error: "version conflict"}, {TaskID: "t4", Error: "not found"}}
+	assert.EqualError(t, result.Err(), "2 of 4 tasks could not be changed:\nFix login: version conflict\nt4: not found")
+}
@@ -0,0 +1,146 @@
+package model
+
+import (
+	"strconv"
+	"time"
+)
+
+// BurnUnit is what the progress of a sprint is measured in
+type BurnUnit string
+
+// Burn units
+const (
+	// BurnPoints sums the estimates of the tasks
+	BurnPoints BurnUnit = "points"
+	// BurnTasks counts the tasks, for sprints whose tasks are not estimated
+	BurnTasks BurnUnit = "tasks"
+)
+
+// BurnDay is the state of a sprint at the end of one of its days
+type BurnDay struct {
+	Day time.Time
+	// Scope is the work planned into the sprint and Completed the part of it that is done
+	Scope     float64
+	Completed float64
+}
+
+// Remaining returns the work of the sprint left to do
+func (d BurnDay) Remaining() float64 {
+	return d.Scope - d.Completed
+}
+
+// Burn is the day by day progress of a sprint, the data of its burndown and burnup charts
+type Burn struct {
+	Sprint Sprint
+	Unit   BurnUnit
+	// Days holds the state at the end of every day from the start of the sprint until its end or today,
+	// whichever comes first
+	Days []BurnDay
+}
+
+// Ideal returns the work left at the end of day i of the sprint when burning down at a steady pace
+// from the scope of its first day to nothing on its last day
+func (b *Burn) Ideal(i int) float64 {
+	if len(b.Days) == 0 {
+		return 0
+	}
+	days := b.Sprint.Days()
+	if days <= 1 {
+		return 0
+	}
+	return b.Days[0].Scope * float64(days-1-i) / float64(days-1)
+}
+
+// burnTask is the state of a task at some point of the history of its board
+type burnTask struct {
+	exists   bool
+	done     bool
+	sprintID string
+	estimate float64
+}
+
+// ComputeBurn works out the progress of a sprint from the current state of the tasks of its board and
+// the activity log of the board, oldest entry first. The state at the end of each day is found by undoing
+// the changes made after it, so tasks must include those in the trash for their work to be counted
+// until they were deleted. Subtasks are left out, their work is part of the estimate of their parent.
+// Progress is measured in points, or in tasks when none of the tasks planned into the sprint is estimated.
+// Days end at midnight in the time zone of now.
+func ComputeBurn(sprint Sprint, tasks []Task, activity []Activity, now time.Time) Burn {
+	states := map[string]*burnTask{}
+	for _, t := range tasks {
+		if t.ParentID == "" {
+			states[t.ID] = &burnTask{exists: t.DeletedAt == nil, done: t.Done, sprintID: t.SprintID, estimate: t.Estimate}
+		}
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	last := sprint.Days() - 1
+	if today.Before(sprint.End) {
+		last = int(today.Sub(sprint.Start).Hours() / 24)
+	}
+	type totals struct {
+		points, pointsDone float64
+		tasks, tasksDone   float64
+	}
+	snapshots := make([]totals, max(last+1, 0))
+	next := len(activity) - 1
+	for i := last; i >= 0; i-- {
+		day := sprint.Day(i)
+		end := time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, now.Location())
+		for ; next >= 0 && !activity[next].At.Before(end); next-- {
+			undoBurn(states, activity[next])
+		}
+		var s totals
+		for _, t := range states {
+			if !t.exists || t.sprintID != sprint.ID {
+				continue
+			}
+			s.points += t.estimate
+			s.tasks++
+			if t.done {
+				s.pointsDone += t.estimate
+				s.tasksDone++
+			}
+		}
+		snapshots[i] = s
+	}
+
+	burn := Burn{Sprint: sprint, Unit: BurnTasks}
+	for _, s := range snapshots {
+		if s.points > 0 {
+			burn.Unit = BurnPoints
+		}
+	}
+	for i, s := range snapshots {
+		day := BurnDay{Day: sprint.Day(i), Scope: s.tasks, Completed: s.tasksDone}
+		if burn.Unit == BurnPoints {
+			day.Scope, day.Completed = s.points, s.pointsDone
+		}
+		burn.Days = append(burn.Days, day)
+	}
+	return burn
+}
+
+// undoBurn reverts the state of a task to what it was before an activity entry.
+// Entries of tasks that are gone for good are ignored.
+func undoBurn(states map[string]*burnTask, a Activity) {
+	t, ok := states[a.TaskID]
+	if !ok {
+		return
+	}
+	switch a.Kind {
+	case ActivityCreated, ActivityRestored:
+		t.exists = false
+	case ActivityDeleted:
+		t.exists = true
+	case ActivityEdited:
+		switch a.Field {
+		case "done":
+			t.done = a.From == "true"
+		case "sprint_id":
+			t.sprintID = a.From
+		case "estimate":
+			t.estimate, _ = strconv.ParseFloat(a.From, 64)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeBurn(t *testing.T) {
+	start := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	sprint := Sprint{ID: "s1", Name: "Sprint 1", Start: start, End: start.AddDate(0, 0, 4)}
+	at := func(day, hour int) time.Time {
+		return time.Date(2025, 6, 2+day, hour, 0, 0, 0, time.UTC)
+	}
+	deleted := at(2, 9)
+	tasks := []Task{
+		{ID: "t1", SprintID: "s1", Estimate: 3, Done: true},
+		{ID: "t2", SprintID: "s1", Estimate: 5},
+		{ID: "t3", SprintID: "s1", Estimate: 2, DeletedAt: &deleted},
+		{ID: "t4", SprintID: "s1", Estimate: 8, ParentID: "t2"},
+		{ID: "t5", Estimate: 1},
+	}
+	activity := []Activity{
+		{TaskID: "t1", Kind: ActivityCreated, At: at(-1, 10)},
+		{TaskID: "t1", Kind: ActivityEdited, Field: "sprint_id", To: "s1", At: at(-1, 11)},
+		{TaskID: "t2", Kind: ActivityEdited, Field: "sprint_id", To: "s1", At: at(-1, 12)},
+		{TaskID: "t3", Kind: ActivityEdited, Field: "sprint_id", To: "s1", At: at(-1, 12)},
+		// t2 is re-estimated on day 1, t1 is done on day 2, when t3 is deleted
+		{TaskID: "t2", Kind: ActivityEdited, Field: "estimate", From: "4", To: "5", At: at(1, 15)},
+		{TaskID: "t1", Kind: ActivityEdited, Field: "done", From: "false", To: "true", At: at(2, 8)},
+		{TaskID: "t3", Kind: ActivityDeleted, At: at(2, 9)},
+		{TaskID: "t5", Kind: ActivityEdited, Field: "sprint_id", From: "s1", At: at(3, 9)},
+		{TaskID: "gone", Kind: ActivityDeleted, At: at(3, 10)},
+	}
+
+	burn := ComputeBurn(sprint, tasks, activity, at(3, 18).In(time.UTC))
+	assert.Equal(t, BurnPoints, burn.Unit)
+	require.Len(t, burn.Days, 4, "days after today are left out")
+	scope := []float64{10, 11, 9, 8}
+	completed := []float64{0, 0, 3, 3}
+	for i, day := range burn.Days {
+		assert.Equal(t, sprint.Day(i), day.Day)
+		assert.Equal(t, scope[i], day.Scope, "scope of day %d", i)
+		assert.Equal(t, completed[i], day.Completed, "completed on day %d", i)
+	}
+	assert.Equal(t, 5.0, burn.Days[3].Remaining())
+	assert.Equal(t, 10.0, burn.Ideal(0))
+	assert.Equal(t, 5.0, burn.Ideal(2))
+	assert.Equal(t, 0.0, burn.Ideal(4))
+
+	// Unestimated sprints are counted in tasks
+	for i := range tasks {
+		tasks[i].Estimate = 0
+	}
+	activity = activity[:4]
+	burn = ComputeBurn(sprint, tasks, activity, at(10, 0))
+	assert.Equal(t, BurnTasks, burn.Unit)
+	require.Len(t, burn.Days, 5)
+	assert.Equal(t, BurnDay{Day: sprint.End, Scope: 2, Completed: 1}, burn.Days[4])
+
+	// Sprints that have not started have no days yet
+	assert.Empty(t, ComputeBurn(sprint, tasks, activity, at(-1, 0)).Days)
+}
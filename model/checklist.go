@@ -0,0 +1,141 @@
+package model
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ChecklistItem is a step of a task that is too small to be a task of its own
+type ChecklistItem struct {
+	ID   string `json:"id"`
+	Text string `json:"text"`
+	Done bool   `json:"done"`
+}
+
+// NewChecklistItem creates an unchecked checklist item with a random ID
+func NewChecklistItem(text string) (ChecklistItem, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return ChecklistItem{}, fmt.Errorf("failed to create checklist item ID: %w", err)
+	}
+	return ChecklistItem{ID: hex.EncodeToString(b), Text: strings.TrimSpace(text)}, nil
+}
+
+// Progress counts the completed steps of a task
+type Progress struct {
+	Done  int
+	Total int
+}
+
+// String returns the progress as "done/total", such as "3/7"
+func (p Progress) String() string {
+	return fmt.Sprintf("%d/%d", p.Done, p.Total)
+}
+
+// Complete reports whether the progress has steps and all of them are done
+func (p Progress) Complete() bool {
+	return p.Total > 0 && p.Done == p.Total
+}
+
+// Fraction returns the share of completed steps between 0 and 1, or 0 when there are none
+func (p Progress) Fraction() float64 {
+	if p.Total == 0 {
+		return 0
+	}
+	return float64(p.Done) / float64(p.Total)
+}
+
+// ChecklistIndex returns the position of the checklist item with the given ID, or -1
+func (t *Task) ChecklistIndex(itemID string) int {
+	for i, item := range t.Checklist {
+		if item.ID == itemID {
+			return i
+		}
+	}
+	return -1
+}
+
+// MoveChecklistItem moves the checklist item at index from to index to, shifting the items in between
+func (t *Task) MoveChecklistItem(from, to int) error {
+	if from < 0 || from >= len(t.Checklist) || to < 0 || to >= len(t.Checklist) {
+		return fmt.Errorf("checklist index out of range: %d to %d of %d", from, to, len(t.Checklist))
+	}
+	// Build a new slice so tasks sharing the backing array are not affected
+	item := t.Checklist[from]
+	rest := removeChecklistItem(t.Checklist, from)
+	checklist := make([]ChecklistItem, 0, len(t.Checklist))
+	checklist = append(checklist, rest[:to]...)
+	checklist = append(checklist, item)
+	t.Checklist = append(checklist, rest[to:]...)
+	return nil
+}
+
+// ConvertChecklistItem removes a checklist item from the task and returns a subtask in its place.
+// The subtask lives in the same board and column, and keeps the completion of the item.
+func (t *Task) ConvertChecklistItem(itemID string) (Task, error) {
+	index := t.ChecklistIndex(itemID)
+	if index < 0 {
+		return Task{}, errors.New("checklist item not found")
+	}
+	item := t.Checklist[index]
+	t.Checklist = removeChecklistItem(t.Checklist, index)
+	return Task{
+		BoardID:  t.BoardID,
+		ColumnID: t.ColumnID,
+		ParentID: t.ID,
+		Title:    item.Text,
+		Done:     item.Done,
+	}, nil
+}
+
+// removeChecklistItem returns a copy of items without the item at index
+func removeChecklistItem(items []ChecklistItem, index int) []ChecklistItem {
+	removed := make([]ChecklistItem, 0, len(items)-1)
+	removed = append(removed, items[:index]...)
+	return append(removed, items[index+1:]...)
+}
+
+// RollUp computes the progress of the task with the given ID from its checklist and its subtasks.
+// A subtask counts as one step, completed when it is marked done or when all its own steps are done,
+// so completion rolls up through any depth of nesting.
+//
+// Parameters:
+//   - id: The ID of the task to compute the progress of
+//   - tasks: The tasks by ID, including the task and all its descendants
+//
+// Returns:
+//   - The progress of the task, with a zero Total when it has no checklist nor subtasks
+func RollUp(id string, tasks map[string]Task) Progress {
+	children := map[string][]string{}
+	for _, t := range tasks {
+		if t.ParentID != "" {
+			children[t.ParentID] = append(children[t.ParentID], t.ID)
+		}
+	}
+	return rollUp(id, tasks, children, map[string]bool{})
+}
+
+// rollUp computes the progress of a task, visited guards against parent cycles in bad data
+func rollUp(id string, tasks map[string]Task, children map[string][]string, visited map[string]bool) Progress {
+	visited[id] = true
+	var p Progress
+	for _, item := range tasks[id].Checklist {
+		p.Total++
+		if item.Done {
+			p.Done++
+		}
+	}
+	for _, childID := range children[id] {
+		if visited[childID] {
+			continue
+		}
+		p.Total++
+		if tasks[childID].Done || rollUp(childID, tasks, children, visited).Complete() {
+			p.Done++
+		}
+	}
+	return p
+}
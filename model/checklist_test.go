@@ -0,0 +1,81 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func checklistTexts(items []ChecklistItem) []string {
+	texts := make([]string, len(items))
+	for i, item := range items {
+		texts[i] = item.Text
+	}
+	return texts
+}
+
+func TestMoveChecklistItem(t *testing.T) {
+	task := Task{Checklist: []ChecklistItem{{ID: "a", Text: "A"}, {ID: "b", Text: "B"}, {ID: "c", Text: "C"}}}
+	original := task.Checklist
+
+	require.NoError(t, task.MoveChecklistItem(0, 2))
+	assert.Equal(t, []string{"B", "C", "A"}, checklistTexts(task.Checklist))
+	require.NoError(t, task.MoveChecklistItem(2, 1))
+	assert.Equal(t, []string{"B", "A", "C"}, checklistTexts(task.Checklist))
+	assert.Error(t, task.MoveChecklistItem(0, 3))
+
+	// The slice the task started with is left untouched
+	assert.Equal(t, []string{"A", "B", "C"}, checklistTexts(original))
+}
+
+func TestConvertChecklistItem(t *testing.T) {
+	task := Task{ID: "t1", BoardID: "b1", ColumnID: "todo", Checklist: []ChecklistItem{
+		{ID: "a", Text: "Draft"},
+		{ID: "b", Text: "Review", Done: true},
+	}}
+
+	subtask, err := task.ConvertChecklistItem("b")
+	require.NoError(t, err)
+	assert.Equal(t, Task{BoardID: "b1", ColumnID: "todo", ParentID: "t1", Title: "Review", Done: true}, subtask)
+	assert.Equal(t, []string{"Draft"}, checklistTexts(task.Checklist))
+
+	_, err = task.ConvertChecklistItem("b")
+	assert.Error(t, err)
+}
+
+func TestRollUp(t *testing.T) {
+	tasks := map[string]Task{
+		"parent": {ID: "parent", Checklist: []ChecklistItem{{Text: "A", Done: true}, {Text: "B"}}},
+		// Done through its own checklist
+		"child1": {ID: "child1", ParentID: "parent", Checklist: []ChecklistItem{{Text: "C", Done: true}}},
+		// Marked done explicitly
+		"child2": {ID: "child2", ParentID: "parent", Done: true},
+		// Not done because its own subtask is open
+		"child3":      {ID: "child3", ParentID: "parent"},
+		"grandchild1": {ID: "grandchild1", ParentID: "child3"},
+		"other":       {ID: "other"},
+	}
+	assert.Equal(t, Progress{Done: 3, Total: 5}, RollUp("parent", tasks))
+	assert.Equal(t, "3/5", RollUp("parent", tasks).String())
+	assert.Equal(t, Progress{Done: 0, Total: 1}, RollUp("child3", tasks))
+	assert.Equal(t, Progress{}, RollUp("other", tasks))
+
+	// Completing the grandchild completes child3 and rolls up to the parent
+	grandchild := tasks["grandchild1"]
+	grandchild.Done = true
+	tasks["grandchild1"] = grandchild
+	assert.Equal(t, Progress{Done: 4, Total: 5}, RollUp("parent", tasks))
+
+	// Cycles in the parent links do not loop forever
+	tasks["a"] = Task{ID: "a", ParentID: "b"}
+	tasks["b"] = Task{ID: "b", ParentID: "a"}
+	assert.Equal(t, Progress{Done: 0, Total: 1}, RollUp("a", tasks))
+}
+
+func TestProgress(t *testing.T) {
+	assert.False(t, Progress{}.Complete())
+	assert.True(t, Progress{Done: 2, Total: 2}.Complete())
+	assert.Equal(t, 0.0, Progress{}.Fraction())
+	assert.Equal(t, 0.25, Progress{Done: 1, Total: 4}.Fraction())
+}
@@ -0,0 +1,161 @@
+package model
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// maxCommentLength is the longest comment body the server accepts, in bytes
+const maxCommentLength = 10000
+
+// Comment is a Markdown message on a task, optionally in reply to another comment
+type Comment struct {
+	ID     string `json:"id"`
+	TaskID string `json:"task_id"`
+	// ParentID is set on replies to the ID of the comment they answer
+	ParentID string `json:"parent_id,omitempty"`
+	// Author is the username of the account that wrote the comment
+	Author string `json:"author"`
+	// Body is Markdown text, it is empty once the comment was deleted
+	Body string `json:"body"`
+	// Mentions lists the usernames of the members mentioned in the body, see ParseMentions
+	Mentions []string `json:"mentions,omitempty"`
+	// History holds the earlier bodies of an edited comment, oldest first
+	History []CommentRevision `json:"history,omitempty"`
+	// Deleted comments keep their place in the thread so replies to them still make sense
+	Deleted bool `json:"deleted,omitempty"`
+	// Version is incremented by the server on every change and used to detect conflicting edits
+	Version   int64     `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CommentRevision is an earlier body of an edited comment
+type CommentRevision struct {
+	Body     string    `json:"body"`
+	EditedAt time.Time `json:"edited_at"`
+}
+
+// Validate checks that the comment can be saved
+func (c *Comment) Validate() error {
+	if strings.TrimSpace(c.Body) == "" {
+		return errors.New("comment must not be empty")
+	}
+	if len(c.Body) > maxCommentLength {
+		return errors.New("comment must be at most 10000 characters")
+	}
+	if c.ParentID != "" && c.ParentID == c.ID {
+		return errors.New("comment cannot reply to itself")
+	}
+	return nil
+}
+
+// Edited reports whether the body of the comment was changed after it was posted
+func (c *Comment) Edited() bool {
+	return len(c.History) > 0
+}
+
+// Edit replaces the body of the comment, keeping the previous body in the history
+func (c *Comment) Edit(body string, now time.Time) {
+	c.History = append(c.History, CommentRevision{Body: c.Body, EditedAt: now})
+	c.Body = body
+	c.UpdatedAt = now
+}
+
+// Clone returns a deep copy of the comment, so edits do not affect the original
+func (c Comment) Clone() Comment {
+	c.Mentions = cloneStrings(c.Mentions)
+	if c.History != nil {
+		c.History = append([]CommentRevision{}, c.History...)
+	}
+	return c
+}
+
+// ThreadEntry is a comment and how deeply it is nested in its thread
+type ThreadEntry struct {
+	Comment Comment
+	// Depth is 0 for top-level comments, 1 for replies to them and so on
+	Depth int
+}
+
+// Thread orders comments as threads: top-level comments oldest first, each followed by its replies,
+// also oldest first. Replies to comments that are not in the list are shown as top-level comments.
+func Thread(comments []Comment) []ThreadEntry {
+	byID := make(map[string]bool, len(comments))
+	for _, c := range comments {
+		byID[c.ID] = true
+	}
+	replies := map[string][]Comment{}
+	for _, c := range comments {
+		parent := c.ParentID
+		if !byID[parent] || parent == c.ID {
+			parent = ""
+		}
+		replies[parent] = append(replies[parent], c)
+	}
+	for _, list := range replies {
+		sort.SliceStable(list, func(i, j int) bool {
+			if !list[i].CreatedAt.Equal(list[j].CreatedAt) {
+				return list[i].CreatedAt.Before(list[j].CreatedAt)
+			}
+			return list[i].ID < list[j].ID
+		})
+	}
+
+	entries := make([]ThreadEntry, 0, len(comments))
+	visited := map[string]bool{}
+	var walk func(parent string, depth int)
+	walk = func(parent string, depth int) {
+		for _, c := range replies[parent] {
+			// Guards against reply cycles sent by a misbehaving server
+			if visited[c.ID] {
+				continue
+			}
+			visited[c.ID] = true
+			entries = append(entries, ThreadEntry{Comment: c, Depth: depth})
+			walk(c.ID, depth+1)
+		}
+	}
+	walk("", 0)
+	return entries
+}
+
+// ParseMentions returns the usernames of the members mentioned in a comment body by their handle,
+// as in "@ana", in the order they are first mentioned. Handles are matched ignoring case.
+func ParseMentions(body string, members []Member) []string {
+	byHandle := make(map[string]string, len(members))
+	for _, m := range members {
+		if m.Handle != "" {
+			byHandle[strings.ToLower(m.Handle)] = m.Username
+		}
+	}
+	var mentions []string
+	seen := map[string]bool{}
+	runes := []rune(body)
+	for i := 0; i < len(runes); i++ {
+		// A mention starts a word, so e-mail addresses are not mistaken for mentions
+		if runes[i] != '@' || (i > 0 && IsHandleRune(runes[i-1])) {
+			continue
+		}
+		j := i + 1
+		for j < len(runes) && IsHandleRune(runes[j]) {
+			j++
+		}
+		// Handles may contain dots, but a sentence may also end right after a mention
+		handle := strings.TrimRight(string(runes[i+1:j]), ".")
+		if username, ok := byHandle[strings.ToLower(handle)]; ok && !seen[username] {
+			seen[username] = true
+			mentions = append(mentions, username)
+		}
+		i = j - 1
+	}
+	return mentions
+}
+
+// IsHandleRune reports whether r can be part of a member handle
+func IsHandleRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.'
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommentValidate(t *testing.T) {
+	assert.NoError(t, (&Comment{Body: "Looks good"}).Validate())
+	assert.Error(t, (&Comment{Body: " \n"}).Validate())
+	assert.Error(t, (&Comment{Body: strings.Repeat("a", 10001)}).Validate())
+	assert.Error(t, (&Comment{ID: "c1", ParentID: "c1", Body: "Me too"}).Validate())
+}
+
+func TestCommentEdit(t *testing.T) {
+	posted := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	edited := posted.Add(time.Hour)
+	c := Comment{Body: "Frist draft", CreatedAt: posted}
+	assert.False(t, c.Edited())
+
+	original := c.Clone()
+	c.Edit("First draft", edited)
+	assert.True(t, c.Edited())
+	assert.Equal(t, "First draft", c.Body)
+	assert.Equal(t, []CommentRevision{{Body: "Frist draft", EditedAt: edited}}, c.History)
+	assert.Equal(t, edited, c.UpdatedAt)
+	assert.False(t, original.Edited())
+}
+
+func TestThread(t *testing.T) {
+	at := func(minutes int) time.Time {
+		return time.Date(2025, 6, 2, 9, minutes, 0, 0, time.UTC)
+	}
+	comments := []Comment{
+		{ID: "c3", ParentID: "c1", CreatedAt: at(3)},
+		{ID: "c2", CreatedAt: at(2)},
+		{ID: "c1", CreatedAt: at(1)},
+		{ID: "c4", ParentID: "c3", CreatedAt: at(4)},
+		{ID: "c5", ParentID: "gone", CreatedAt: at(5)},
+	}
+	var got []string
+	for _, e := range Thread(comments) {
+		got = append(got, strings.Repeat(">", e.Depth)+e.Comment.ID)
+	}
+	assert.Equal(t, []string{"c1", ">c3", ">>c4", "c2", "c5"}, got)
+}
+
+func TestParseMentions(t *testing.T) {
+	members := []Member{
+		{Username: "ana@example.com", Handle: "ana"},
+		{Username: "ben@example.com", Handle: "ben.k"},
+	}
+	assert.Equal(t, []string{"ben@example.com", "ana@example.com"},
+		ParseMentions("@Ben.K can you check this with @ana? Thanks @ana.", members))
+	assert.Empty(t, ParseMentions("Mail ana@example.com or ping @carol", members))
+	assert.Empty(t, ParseMentions("", members))
+}
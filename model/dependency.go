@@ -0,0 +1,104 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CycleError is returned when a "blocked by" link would make a task wait on itself
+type CycleError struct {
+	// Path lists the task IDs of the cycle, starting and ending with the same task
+	Path []string
+	// Titles lists the task titles of the cycle, in the same order as Path
+	Titles []string
+}
+
+// Error implements the error interface
+func (e *CycleError) Error() string {
+	return "dependency cycle: " + strings.Join(e.Titles, " → ")
+}
+
+// ValidateDependencies checks that the task with the given ID can be blocked by the tasks in blockedBy.
+// Every blocker must exist and must not depend on the task, directly or through other tasks.
+//
+// Parameters:
+//   - taskID: The ID of the task whose blockers are being set
+//   - blockedBy: The IDs of the proposed blockers, replacing the current ones of the task
+//   - tasks: The tasks by ID, the dependency graph is followed through their BlockedBy links
+//
+// Returns:
+//   - A *CycleError describing the cycle when one would be created, or another error for an invalid link
+func ValidateDependencies(taskID string, blockedBy []string, tasks map[string]Task) error {
+	title := func(id string) string {
+		if t, ok := tasks[id]; ok {
+			return t.Title
+		}
+		return id
+	}
+
+	seen := map[string]bool{}
+	for _, blockerID := range blockedBy {
+		if blockerID == taskID {
+			return fmt.Errorf("%q cannot be blocked by itself", title(taskID))
+		}
+		if _, ok := tasks[blockerID]; !ok {
+			return fmt.Errorf("blocking task %s does not exist", blockerID)
+		}
+		if seen[blockerID] {
+			return fmt.Errorf("%q is already blocked by %q", title(taskID), title(blockerID))
+		}
+		seen[blockerID] = true
+	}
+
+	for _, blockerID := range blockedBy {
+		if path := dependencyPath(blockerID, taskID, tasks); path != nil {
+			path = append([]string{taskID}, path...)
+			titles := make([]string, len(path))
+			for i, id := range path {
+				titles[i] = title(id)
+			}
+			return &CycleError{Path: path, Titles: titles}
+		}
+	}
+	return nil
+}
+
+// dependencyPath returns the IDs of a chain of "blocked by" links leading from one task to another,
+// including both ends, or nil when there is none
+func dependencyPath(from, to string, tasks map[string]Task) []string {
+	previous := map[string]string{from: ""}
+	queue := []string{from}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if id == to {
+			var path []string
+			for ; id != ""; id = previous[id] {
+				path = append([]string{id}, path...)
+			}
+			return path
+		}
+		for _, next := range tasks[id].BlockedBy {
+			if _, visited := previous[next]; !visited {
+				previous[next] = id
+				queue = append(queue, next)
+			}
+		}
+	}
+	return nil
+}
+
+// OpenBlockers returns the tasks blocking the task with the given ID that are not completed yet.
+// A blocker is completed when it is marked done or when all its checklist items and subtasks are done.
+// Blockers missing from tasks, e.g. deleted ones, are ignored.
+func OpenBlockers(id string, tasks map[string]Task) []Task {
+	var open []Task
+	for _, blockerID := range tasks[id].BlockedBy {
+		blocker, ok := tasks[blockerID]
+		if !ok || blocker.Done || RollUp(blockerID, tasks).Complete() {
+			continue
+		}
+		open = append(open, blocker)
+	}
+	return open
+}
@@ -0,0 +1,78 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dependencyGraph builds tasks titled after their IDs from a map of "blocked by" links
+func dependencyGraph(links map[string][]string) map[string]Task {
+	tasks := map[string]Task{}
+	for id, blockedBy := range links {
+		tasks[id] = Task{ID: id, Title: id, BlockedBy: blockedBy}
+	}
+	return tasks
+}
+
+func TestValidateDependencies(t *testing.T) {
+	// d is blocked by b and c, which are both blocked by a
+	tasks := dependencyGraph(map[string][]string{
+		"a": nil,
+		"b": {"a"},
+		"c": {"a"},
+		"d": {"b", "c"},
+		"e": nil,
+	})
+
+	// Diamonds and independent tasks are not cycles
+	assert.NoError(t, ValidateDependencies("d", []string{"b", "c", "e"}, tasks))
+	assert.NoError(t, ValidateDependencies("e", []string{"d"}, tasks))
+	assert.NoError(t, ValidateDependencies("a", nil, tasks))
+
+	err := ValidateDependencies("a", []string{"d"}, tasks)
+	var cycle *CycleError
+	require.ErrorAs(t, err, &cycle)
+	assert.Equal(t, "a", cycle.Path[0])
+	assert.Equal(t, "a", cycle.Path[len(cycle.Path)-1])
+	assert.Len(t, cycle.Path, 4)
+	assert.Contains(t, []string{"dependency cycle: a → d → b → a", "dependency cycle: a → d → c → a"}, err.Error())
+
+	// A direct cycle between two tasks
+	err = ValidateDependencies("a", []string{"b"}, tasks)
+	require.ErrorAs(t, err, &cycle)
+	assert.Equal(t, []string{"a", "b", "a"}, cycle.Path)
+
+	assert.ErrorContains(t, ValidateDependencies("a", []string{"a"}, tasks), "cannot be blocked by itself")
+	assert.ErrorContains(t, ValidateDependencies("a", []string{"missing"}, tasks), "does not exist")
+	assert.ErrorContains(t, ValidateDependencies("d", []string{"b", "b"}, tasks), "already blocked")
+}
+
+func TestValidateDependenciesLongChain(t *testing.T) {
+	links := map[string][]string{}
+	ids := []string{"t0", "t1", "t2", "t3", "t4", "t5", "t6", "t7"}
+	for i := 1; i < len(ids); i++ {
+		links[ids[i]] = []string{ids[i-1]}
+	}
+	tasks := dependencyGraph(links)
+	tasks["t0"] = Task{ID: "t0", Title: "t0"}
+
+	err := ValidateDependencies("t0", []string{"t7"}, tasks)
+	var cycle *CycleError
+	require.ErrorAs(t, err, &cycle)
+	assert.Equal(t, []string{"t0", "t7", "t6", "t5", "t4", "t3", "t2", "t1", "t0"}, cycle.Path)
+}
+
+func TestOpenBlockers(t *testing.T) {
+	tasks := map[string]Task{
+		"task":   {ID: "task", BlockedBy: []string{"open", "done", "ticked", "deleted"}},
+		"open":   {ID: "open", Checklist: []ChecklistItem{{Text: "A", Done: true}, {Text: "B"}}},
+		"done":   {ID: "done", Done: true},
+		"ticked": {ID: "ticked", Checklist: []ChecklistItem{{Text: "A", Done: true}}},
+	}
+	blockers := OpenBlockers("task", tasks)
+	require.Len(t, blockers, 1)
+	assert.Equal(t, "open", blockers[0].ID)
+	assert.Empty(t, OpenBlockers("open", tasks))
+}
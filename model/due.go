@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// DateOnly reports whether due is a date without a time of day. Due dates picked as a date,
+// like those of widget.DateEntry, are stored at midnight UTC.
+func DateOnly(due time.Time) bool {
+	due = due.UTC()
+	return due.Hour() == 0 && due.Minute() == 0 && due.Second() == 0 && due.Nanosecond() == 0
+}
+
+// DueIn returns due as a time in loc for comparisons with local dates. Date-only due dates fall on
+// their UTC date at midnight in loc, so they do not move a day earlier west of UTC. Due dates with
+// a time of day are returned as the same instant in loc.
+func DueIn(due time.Time, loc *time.Location) time.Time {
+	if !DateOnly(due) {
+		return due.In(loc)
+	}
+	y, m, d := due.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, loc)
+}
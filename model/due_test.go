@@ -0,0 +1,25 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDateOnly(t *testing.T) {
+	assert.True(t, DateOnly(time.Date(2025, 6, 3, 0, 0, 0, 0, time.UTC)))
+	// Midnight UTC read in another zone is still date-only, local midnight is not
+	assert.True(t, DateOnly(time.Date(2025, 6, 2, 19, 0, 0, 0, time.FixedZone("UTC-5", -5*60*60))))
+	assert.False(t, DateOnly(time.Date(2025, 6, 3, 0, 0, 0, 0, time.FixedZone("UTC-5", -5*60*60))))
+	assert.False(t, DateOnly(time.Date(2025, 6, 3, 9, 30, 0, 0, time.UTC)))
+}
+
+func TestDueIn(t *testing.T) {
+	west := time.FixedZone("UTC-5", -5*60*60)
+	// A date-only due date stays on its date, a due date with a time of day falls on its local date
+	assert.Equal(t, time.Date(2025, 6, 3, 0, 0, 0, 0, west), DueIn(time.Date(2025, 6, 3, 0, 0, 0, 0, time.UTC), west))
+	timed := time.Date(2025, 6, 3, 2, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 6, 2, 21, 0, 0, 0, west), DueIn(timed, west))
+	assert.True(t, DueIn(timed, west).Equal(timed))
+}
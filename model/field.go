@@ -0,0 +1,286 @@
+package model
+
+import (
+	"cmp"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Label is a coloured tag of a board that can be put on its tasks
+type Label struct {
+	Name string `json:"name"`
+	// Color is the hex RGB colour of the label, such as "#e53935"
+	Color string `json:"color"`
+}
+
+// labelColorPattern matches the hex RGB colours of labels
+var labelColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// Validate checks that the label can be saved
+func (l *Label) Validate() error {
+	if strings.TrimSpace(l.Name) == "" {
+		return errors.New("label name must not be empty")
+	}
+	if len(l.Name) > 50 {
+		return errors.New("label name must be at most 50 characters")
+	}
+	if !labelColorPattern.MatchString(l.Color) {
+		return fmt.Errorf("invalid colour %q of label %s, use #rrggbb", l.Color, l.Name)
+	}
+	return nil
+}
+
+// FieldType is the kind of value a custom field holds
+type FieldType string
+
+// Custom field types
+const (
+	FieldText        FieldType = "text"
+	FieldNumber      FieldType = "number"
+	FieldDate        FieldType = "date"
+	FieldSelect      FieldType = "select"
+	FieldMultiSelect FieldType = "multi_select"
+)
+
+// FieldTypes lists every custom field type
+var FieldTypes = []FieldType{FieldText, FieldNumber, FieldDate, FieldSelect, FieldMultiSelect}
+
+// String returns the name of a FieldType as shown to users
+func (t FieldType) String() string {
+	switch t {
+	case FieldText:
+		return "Text"
+	case FieldNumber:
+		return "Number"
+	case FieldDate:
+		return "Date"
+	case FieldSelect:
+		return "Single select"
+	case FieldMultiSelect:
+		return "Multi select"
+	default:
+		return string(t)
+	}
+}
+
+// fieldDateLayout is how dates of custom fields are written in filters and exports
+const fieldDateLayout = "2006-01-02"
+
+// Field is a custom field defined on a board, such as "Estimate" or "Customer", whose value is
+// kept in the Fields of every task of the board
+type Field struct {
+	ID   string    `json:"id"`
+	Name string    `json:"name"`
+	Type FieldType `json:"type"`
+	// Options are the choices of FieldSelect and FieldMultiSelect fields, in display order
+	Options []string `json:"options,omitempty"`
+}
+
+// NewField creates a custom field with a random ID
+func NewField(name string, fieldType FieldType, options []string) (Field, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return Field{}, fmt.Errorf("failed to create field ID: %w", err)
+	}
+	return Field{ID: hex.EncodeToString(b), Name: strings.TrimSpace(name), Type: fieldType, Options: options}, nil
+}
+
+// Validate checks that the field can be saved
+func (f *Field) Validate() error {
+	if strings.TrimSpace(f.Name) == "" {
+		return errors.New("field name must not be empty")
+	}
+	if len(f.Name) > 50 {
+		return errors.New("field name must be at most 50 characters")
+	}
+	switch f.Type {
+	case FieldText, FieldNumber, FieldDate:
+		if len(f.Options) > 0 {
+			return fmt.Errorf("field %s of type %s cannot have options", f.Name, f.Type)
+		}
+	case FieldSelect, FieldMultiSelect:
+		if len(f.Options) == 0 {
+			return fmt.Errorf("field %s needs at least one option", f.Name)
+		}
+		seen := map[string]bool{}
+		for _, option := range f.Options {
+			key := strings.ToLower(strings.TrimSpace(option))
+			if key == "" {
+				return fmt.Errorf("options of field %s must not be empty", f.Name)
+			}
+			if seen[key] {
+				return fmt.Errorf("field %s lists option %q twice", f.Name, option)
+			}
+			seen[key] = true
+		}
+	default:
+		return fmt.Errorf("unknown field type %q", f.Type)
+	}
+	return nil
+}
+
+// FieldValue is the value of a custom field on a task. Which member is used depends on the type of
+// the field: Text for FieldText, Number for FieldNumber, Date for FieldDate and Choices for
+// FieldSelect, with at most one choice, and FieldMultiSelect.
+type FieldValue struct {
+	Text    string     `json:"text,omitempty"`
+	Number  *float64   `json:"number,omitempty"`
+	Date    *time.Time `json:"date,omitempty"`
+	Choices []string   `json:"choices,omitempty"`
+}
+
+// Empty reports whether the value is unset
+func (v FieldValue) Empty() bool {
+	return v.Text == "" && v.Number == nil && v.Date == nil && len(v.Choices) == 0
+}
+
+// Clone returns a deep copy of the value
+func (v FieldValue) Clone() FieldValue {
+	if v.Number != nil {
+		n := *v.Number
+		v.Number = &n
+	}
+	if v.Date != nil {
+		d := *v.Date
+		v.Date = &d
+	}
+	v.Choices = cloneStrings(v.Choices)
+	return v
+}
+
+// CheckValue checks that a value fits the field
+func (f *Field) CheckValue(v FieldValue) error {
+	other := v
+	switch f.Type {
+	case FieldText:
+		other.Text = ""
+		if len(v.Text) > 1000 {
+			return fmt.Errorf("%s must be at most 1000 characters", f.Name)
+		}
+	case FieldNumber:
+		other.Number = nil
+	case FieldDate:
+		other.Date = nil
+	case FieldSelect, FieldMultiSelect:
+		other.Choices = nil
+		if f.Type == FieldSelect && len(v.Choices) > 1 {
+			return fmt.Errorf("%s takes a single choice", f.Name)
+		}
+		for _, choice := range v.Choices {
+			if f.option(choice) == "" {
+				return fmt.Errorf("%q is not an option of %s", choice, f.Name)
+			}
+		}
+	}
+	if !other.Empty() {
+		return fmt.Errorf("value of %s does not match its type %s", f.Name, f.Type)
+	}
+	return nil
+}
+
+// option returns the option of the field matching s, ignoring case, or an empty string
+func (f *Field) option(s string) string {
+	for _, option := range f.Options {
+		if strings.EqualFold(option, strings.TrimSpace(s)) {
+			return option
+		}
+	}
+	return ""
+}
+
+// Format returns a value of the field as text, as shown on task cards and written in exports
+func (f *Field) Format(v FieldValue) string {
+	switch f.Type {
+	case FieldNumber:
+		if v.Number == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*v.Number, 'f', -1, 64)
+	case FieldDate:
+		if v.Date == nil {
+			return ""
+		}
+		return v.Date.Format(fieldDateLayout)
+	case FieldSelect, FieldMultiSelect:
+		return strings.Join(v.Choices, ", ")
+	default:
+		return v.Text
+	}
+}
+
+// Parse reads a value of the field from text written as by Format. Choices are matched ignoring case,
+// and an empty string is the empty value.
+func (f *Field) Parse(s string) (FieldValue, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return FieldValue{}, nil
+	}
+	switch f.Type {
+	case FieldNumber:
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return FieldValue{}, fmt.Errorf("%s must be a number", f.Name)
+		}
+		return FieldValue{Number: &n}, nil
+	case FieldDate:
+		d, err := time.ParseInLocation(fieldDateLayout, s, time.Local)
+		if err != nil {
+			return FieldValue{}, fmt.Errorf("%s must be a date such as 2025-06-30", f.Name)
+		}
+		return FieldValue{Date: &d}, nil
+	case FieldSelect, FieldMultiSelect:
+		var v FieldValue
+		for _, part := range strings.Split(s, ",") {
+			option := f.option(part)
+			if option == "" {
+				return FieldValue{}, fmt.Errorf("%q is not an option of %s", strings.TrimSpace(part), f.Name)
+			}
+			v.Choices = append(v.Choices, option)
+		}
+		return v, f.CheckValue(v)
+	default:
+		return FieldValue{Text: s}, nil
+	}
+}
+
+// Compare orders two values of the field: numbers and dates by value, choices by the order of
+// the options and text alphabetically, ignoring case. Empty values come last.
+// It returns a negative number when a comes first, a positive number when b does, and 0 otherwise.
+func (f *Field) Compare(a, b FieldValue) int {
+	if a.Empty() || b.Empty() {
+		switch {
+		case a.Empty() && b.Empty():
+			return 0
+		case a.Empty():
+			return 1
+		default:
+			return -1
+		}
+	}
+	switch f.Type {
+	case FieldNumber:
+		return cmp.Compare(*a.Number, *b.Number)
+	case FieldDate:
+		return a.Date.Compare(*b.Date)
+	case FieldSelect, FieldMultiSelect:
+		return cmp.Compare(f.optionIndex(a.Choices[0]), f.optionIndex(b.Choices[0]))
+	default:
+		return strings.Compare(strings.ToLower(a.Text), strings.ToLower(b.Text))
+	}
+}
+
+// optionIndex returns the position of an option, or the number of options when it is not one
+func (f *Field) optionIndex(choice string) int {
+	for i, option := range f.Options {
+		if strings.EqualFold(option, choice) {
+			return i
+		}
+	}
+	return len(f.Options)
+}
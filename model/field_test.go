@@ -0,0 +1,110 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabelValidate(t *testing.T) {
+	assert.NoError(t, (&Label{Name: "bug", Color: "#E53935"}).Validate())
+	assert.Error(t, (&Label{Name: " ", Color: "#e53935"}).Validate())
+	assert.Error(t, (&Label{Name: "bug", Color: "red"}).Validate())
+}
+
+func TestFieldParseAndFormat(t *testing.T) {
+	estimate := Field{Name: "Estimate", Type: FieldNumber}
+	v, err := estimate.Parse(" 2.5 ")
+	require.NoError(t, err)
+	assert.Equal(t, "2.5", estimate.Format(v))
+	_, err = estimate.Parse("two")
+	assert.EqualError(t, err, "Estimate must be a number")
+
+	launch := Field{Name: "Launch", Type: FieldDate}
+	v, err = launch.Parse("2025-06-30")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 6, 30, 0, 0, 0, 0, time.Local), *v.Date)
+	assert.Equal(t, "2025-06-30", launch.Format(v))
+
+	platforms := Field{Name: "Platforms", Type: FieldMultiSelect, Options: []string{"iOS", "Android", "Web"}}
+	v, err = platforms.Parse("web, ios")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Web", "iOS"}, v.Choices)
+	assert.Equal(t, "Web, iOS", platforms.Format(v))
+	_, err = platforms.Parse("desktop")
+	assert.Error(t, err)
+
+	stage := Field{Name: "Stage", Type: FieldSelect, Options: []string{"Idea", "Build"}}
+	_, err = stage.Parse("idea, build")
+	assert.EqualError(t, err, "Stage takes a single choice")
+
+	v, err = estimate.Parse("")
+	require.NoError(t, err)
+	assert.True(t, v.Empty())
+}
+
+func TestFieldCheckValue(t *testing.T) {
+	n := 3.0
+	estimate := Field{Name: "Estimate", Type: FieldNumber}
+	assert.NoError(t, estimate.CheckValue(FieldValue{Number: &n}))
+	assert.Error(t, estimate.CheckValue(FieldValue{Text: "3"}))
+
+	stage := Field{Name: "Stage", Type: FieldSelect, Options: []string{"Idea", "Build"}}
+	assert.NoError(t, stage.CheckValue(FieldValue{Choices: []string{"Build"}}))
+	assert.Error(t, stage.CheckValue(FieldValue{Choices: []string{"Ship"}}))
+
+	assert.Error(t, (&Field{Name: "Stage", Type: FieldSelect}).Validate())
+	assert.Error(t, (&Field{Name: "Stage", Type: FieldSelect, Options: []string{"Idea", "idea"}}).Validate())
+	assert.Error(t, (&Field{Name: "Notes", Type: FieldText, Options: []string{"a"}}).Validate())
+	assert.Error(t, (&Field{Name: "Notes", Type: "rich"}).Validate())
+}
+
+func TestFieldCompare(t *testing.T) {
+	one, two := 1.0, 2.0
+	estimate := Field{Type: FieldNumber}
+	assert.Negative(t, estimate.Compare(FieldValue{Number: &one}, FieldValue{Number: &two}))
+	// Empty values sort last
+	assert.Negative(t, estimate.Compare(FieldValue{Number: &two}, FieldValue{}))
+	assert.Zero(t, estimate.Compare(FieldValue{}, FieldValue{}))
+
+	stage := Field{Type: FieldSelect, Options: []string{"Idea", "Build", "Ship"}}
+	assert.Positive(t, stage.Compare(FieldValue{Choices: []string{"Ship"}}, FieldValue{Choices: []string{"Build"}}))
+
+	customer := Field{Type: FieldText}
+	assert.Negative(t, customer.Compare(FieldValue{Text: "acme"}, FieldValue{Text: "Globex"}))
+}
+
+func TestBoardValidate(t *testing.T) {
+	estimate, err := NewField("Estimate", FieldNumber, nil)
+	require.NoError(t, err)
+	board := Board{
+		Name:   "Sprint",
+		Labels: []Label{{Name: "bug", Color: "#e53935"}, {Name: "docs", Color: "#1e88e5"}},
+		Fields: []Field{estimate},
+	}
+	require.NoError(t, board.Validate())
+	label, ok := board.Label("BUG")
+	assert.True(t, ok)
+	assert.Equal(t, "#e53935", label.Color)
+	field, ok := board.FieldNamed("estimate")
+	assert.True(t, ok)
+	assert.Equal(t, estimate.ID, field.ID)
+
+	assert.Equal(t, []Label{{Name: "docs", Color: "#1e88e5"}, {Name: "wontfix"}},
+		board.TaskLabels(&Task{Labels: []string{"docs", "wontfix"}}))
+
+	board.Labels = append(board.Labels, Label{Name: "Bug", Color: "#000000"})
+	assert.EqualError(t, board.Validate(), "label Bug is defined twice")
+	board.Labels = board.Labels[:2]
+	board.Fields = append(board.Fields, Field{ID: "f2", Name: "ESTIMATE", Type: FieldText})
+	assert.EqualError(t, board.Validate(), "field ESTIMATE is defined twice")
+	board.Fields = board.Fields[:1]
+
+	n := 5.0
+	task := Task{Fields: map[string]FieldValue{estimate.ID: {Number: &n}}}
+	assert.NoError(t, board.CheckFields(&task))
+	task.Fields["missing"] = FieldValue{Text: "x"}
+	assert.Error(t, board.CheckFields(&task))
+}
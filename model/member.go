@@ -0,0 +1,10 @@
+package model
+
+// Member is an account of the group the signed in account belongs to
+type Member struct {
+	// Username identifies the account, as in Task.Assignees
+	Username string `json:"username"`
+	Name     string `json:"name"`
+	// Handle is the short name the member is mentioned by, as in @ana
+	Handle string `json:"handle"`
+}
@@ -0,0 +1,119 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// WIPPolicy is what happens when a task is moved into a column that is at its WIP limit
+type WIPPolicy string
+
+// WIP policies
+const (
+	// WIPWarn lets the move through after a warning, it is the policy of columns without one
+	WIPWarn WIPPolicy = "warn"
+	// WIPBlock rejects the move
+	WIPBlock WIPPolicy = "block"
+)
+
+// Blocks reports whether the policy rejects moves into a full column
+func (p WIPPolicy) Blocks() bool {
+	return p == WIPBlock
+}
+
+// validate checks the work in progress limit and definition of done of the column
+func (c *Column) validate() error {
+	if c.WIPLimit < 0 {
+		return fmt.Errorf("WIP limit of column %s must not be negative", c.Name)
+	}
+	switch c.WIPPolicy {
+	case "", WIPWarn, WIPBlock:
+	default:
+		return fmt.Errorf("unknown WIP policy %q", c.WIPPolicy)
+	}
+	for _, item := range c.DefinitionOfDone {
+		if strings.TrimSpace(item) == "" {
+			return fmt.Errorf("definition of done of column %s must not have empty items", c.Name)
+		}
+	}
+	return nil
+}
+
+// ErrPolicy is wrapped by PolicyViolation errors that reject a move
+var ErrPolicy = errors.New("column policy not met")
+
+// PolicyViolation describes how moving a task into a column breaks the policies of the column
+type PolicyViolation struct {
+	Column Column
+	// Count is the number of tasks in the column before the move
+	Count int
+	// Missing are the items of the definition of done of the column that are not completed on the task
+	Missing []string
+}
+
+// OverLimit reports whether the move takes the column over its WIP limit
+func (v *PolicyViolation) OverLimit() bool {
+	return v.Column.WIPLimit > 0 && v.Count >= v.Column.WIPLimit
+}
+
+// Blocking reports whether the move is rejected, rather than only warned about
+func (v *PolicyViolation) Blocking() bool {
+	return len(v.Missing) > 0 || v.OverLimit() && v.Column.WIPPolicy.Blocks()
+}
+
+// Error implements error
+func (v *PolicyViolation) Error() string {
+	var reasons []string
+	if v.OverLimit() {
+		reasons = append(reasons, fmt.Sprintf("%s is at its limit of %d tasks", v.Column.Name, v.Column.WIPLimit))
+	}
+	if len(v.Missing) > 0 {
+		reasons = append(reasons, fmt.Sprintf("the definition of done of %s is not met: %s", v.Column.Name, strings.Join(v.Missing, ", ")))
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// Unwrap makes blocking violations match ErrPolicy
+func (v *PolicyViolation) Unwrap() error {
+	if v.Blocking() {
+		return ErrPolicy
+	}
+	return nil
+}
+
+// CheckMove checks moving task into the column with the given ID, which holds count other tasks.
+// It returns nil when the move meets the policies of the column.
+func (b *Board) CheckMove(task *Task, columnID string, count int) *PolicyViolation {
+	column, ok := b.Column(columnID)
+	if !ok {
+		return nil
+	}
+	v := &PolicyViolation{Column: column, Count: count, Missing: column.MissingDone(task)}
+	if !v.OverLimit() && len(v.Missing) == 0 {
+		return nil
+	}
+	return v
+}
+
+// MissingDone returns the items of the definition of done of the column that are not completed
+// on the checklist of task, matching their text regardless of case
+func (c *Column) MissingDone(task *Task) []string {
+	var missing []string
+	for _, item := range c.DefinitionOfDone {
+		if !task.checkedItem(item) {
+			missing = append(missing, item)
+		}
+	}
+	return missing
+}
+
+// checkedItem reports whether the checklist of the task has a completed item with the given text
+func (t *Task) checkedItem(text string) bool {
+	for _, item := range t.Checklist {
+		if item.Done && strings.EqualFold(strings.TrimSpace(item.Text), strings.TrimSpace(text)) {
+			return true
+		}
+	}
+	return false
+}
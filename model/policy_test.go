@@ -0,0 +1,56 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoardCheckMove(t *testing.T) {
+	board := Board{Name: "Sprint", Columns: []Column{
+		{ID: "todo", Name: "To do"},
+		{ID: "doing", Name: "Doing", WIPLimit: 2},
+		{ID: "review", Name: "Review", WIPLimit: 1, WIPPolicy: WIPBlock},
+		{ID: "done", Name: "Done", DefinitionOfDone: []string{"Tests pass", "Docs updated"}},
+	}}
+	task := Task{Checklist: []ChecklistItem{{Text: "tests pass", Done: true}, {Text: "Docs updated"}}}
+
+	assert.Nil(t, board.CheckMove(&task, "todo", 10))
+	assert.Nil(t, board.CheckMove(&task, "doing", 1))
+	assert.Nil(t, board.CheckMove(&task, "unknown", 10))
+
+	// Full columns warn unless their policy blocks
+	v := board.CheckMove(&task, "doing", 2)
+	require.NotNil(t, v)
+	assert.True(t, v.OverLimit())
+	assert.False(t, v.Blocking())
+	assert.NotErrorIs(t, v, ErrPolicy)
+	assert.Equal(t, "Doing is at its limit of 2 tasks", v.Error())
+	v = board.CheckMove(&task, "review", 1)
+	require.NotNil(t, v)
+	assert.True(t, v.Blocking())
+	assert.ErrorIs(t, v, ErrPolicy)
+
+	// The definition of done must be completed on the checklist
+	v = board.CheckMove(&task, "done", 0)
+	require.NotNil(t, v)
+	assert.Equal(t, []string{"Docs updated"}, v.Missing)
+	assert.True(t, v.Blocking())
+	assert.Equal(t, "the definition of done of Done is not met: Docs updated", v.Error())
+	task.Checklist[1].Done = true
+	assert.Nil(t, board.CheckMove(&task, "done", 0))
+}
+
+func TestBoardValidateColumns(t *testing.T) {
+	board := Board{Name: "Sprint", Columns: []Column{{ID: "doing", Name: "Doing", WIPLimit: 3, WIPPolicy: WIPWarn}}}
+	assert.NoError(t, board.Validate())
+	board.Columns[0].WIPLimit = -1
+	assert.Error(t, board.Validate())
+	board.Columns[0].WIPLimit = 3
+	board.Columns[0].WIPPolicy = "stop"
+	assert.Error(t, board.Validate())
+	board.Columns[0].WIPPolicy = WIPBlock
+	board.Columns[0].DefinitionOfDone = []string{"Reviewed", " "}
+	assert.Error(t, board.Validate())
+}
@@ -0,0 +1,77 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"eldar/rrule"
+)
+
+// Recurrence makes a task repeat on an RFC 5545 schedule.
+// Only the latest occurrence of a series carries it, completed occurrences leave the series.
+type Recurrence struct {
+	// Rule is an RRULE value such as "FREQ=WEEKLY;BYDAY=MO"
+	Rule string `json:"rule"`
+	// Start is the due date of the first occurrence, COUNT is counted from it
+	Start time.Time `json:"start"`
+	// TimeZone is the IANA time zone the rule is expanded in, keeping the wall clock time across DST changes.
+	// Series of date-only due dates use UTC, as does an empty zone.
+	TimeZone string `json:"time_zone,omitempty"`
+}
+
+// Validate checks that the rule and time zone can be used
+func (r *Recurrence) Validate() error {
+	if _, err := rrule.Parse(r.Rule); err != nil {
+		return fmt.Errorf("invalid recurrence: %w", err)
+	}
+	if _, err := time.LoadLocation(r.TimeZone); err != nil {
+		return fmt.Errorf("invalid recurrence time zone: %w", err)
+	}
+	return nil
+}
+
+// Next returns the first occurrence strictly after after, or false when the series has ended
+func (r *Recurrence) Next(after time.Time) (time.Time, bool, error) {
+	rule, err := rrule.Parse(r.Rule)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid recurrence: %w", err)
+	}
+	loc, err := time.LoadLocation(r.TimeZone)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid recurrence time zone: %w", err)
+	}
+	next, ok := rule.Next(r.Start.In(loc), after)
+	return next, ok, nil
+}
+
+// NextOccurrence returns the task to create when a recurring task is completed.
+// The occurrence is due on the next date of the rule after the completed one and starts afresh:
+// it is not done, its checklist is unchecked and it has no ID, version nor dependencies yet.
+// It returns false when the series has ended.
+func (t Task) NextOccurrence() (Task, bool, error) {
+	if t.Recurrence == nil {
+		return Task{}, false, errors.New("task does not recur")
+	}
+	if t.DueDate == nil {
+		return Task{}, false, errors.New("recurring task has no due date")
+	}
+	due, ok, err := t.Recurrence.Next(*t.DueDate)
+	if err != nil || !ok {
+		return Task{}, false, err
+	}
+
+	next := t.Clone()
+	next.ID = ""
+	next.Version = 0
+	next.CreatedAt = time.Time{}
+	next.UpdatedAt = time.Time{}
+	next.Done = false
+	next.BlockedBy = nil
+	next.Attachments = nil
+	next.DueDate = &due
+	for i := range next.Checklist {
+		next.Checklist[i].Done = false
+	}
+	return next, true, nil
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextOccurrence(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	start := time.Date(2025, 3, 24, 9, 0, 0, 0, berlin)
+	due := start.Add(7 * 24 * time.Hour).Add(-time.Hour).UTC()
+	task := Task{
+		ID:         "t1",
+		Title:      "Water the plants",
+		DueDate:    &due,
+		Done:       true,
+		Version:    4,
+		BlockedBy:  []string{"t0"},
+		Checklist:  []ChecklistItem{{ID: "a", Text: "Ficus", Done: true}},
+		Recurrence: &Recurrence{Rule: "FREQ=WEEKLY;COUNT=3", Start: start.UTC(), TimeZone: "Europe/Berlin"},
+	}
+	require.NoError(t, task.Validate())
+
+	// The second occurrence is completed, the third keeps 09:00 in Berlin
+	next, ok, err := task.NextOccurrence()
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2025, 4, 7, 9, 0, 0, 0, berlin), next.DueDate.In(berlin))
+	assert.Empty(t, next.ID)
+	assert.Zero(t, next.Version)
+	assert.False(t, next.Done)
+	assert.Empty(t, next.BlockedBy)
+	assert.False(t, next.Checklist[0].Done)
+	assert.True(t, task.Checklist[0].Done)
+	assert.Equal(t, task.Recurrence, next.Recurrence)
+
+	// COUNT=3 ends the series after the third occurrence
+	_, ok, err = next.NextOccurrence()
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, _, err = Task{Title: "Once"}.NextOccurrence()
+	assert.Error(t, err)
+}
+
+func TestRecurrenceValidate(t *testing.T) {
+	due := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	assert.NoError(t, (&Task{Title: "Report", DueDate: &due, Recurrence: &Recurrence{Rule: "FREQ=MONTHLY", Start: due}}).Validate())
+	assert.ErrorContains(t, (&Task{Title: "Report", Recurrence: &Recurrence{Rule: "FREQ=MONTHLY", Start: due}}).Validate(), "due date")
+	assert.ErrorContains(t, (&Task{Title: "Report", DueDate: &due, Recurrence: &Recurrence{Rule: "FREQ=HOURLY", Start: due}}).Validate(), "invalid recurrence")
+	assert.ErrorContains(t, (&Task{Title: "Report", DueDate: &due, Recurrence: &Recurrence{Rule: "FREQ=DAILY", Start: due, TimeZone: "Mars/Olympus"}}).Validate(), "time zone")
+}
@@ -0,0 +1,92 @@
+package model
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxSprintDays is the longest a sprint or milestone can last
+const maxSprintDays = 366
+
+// Sprint is a time-boxed sprint or milestone of a board, tasks are planned into it with estimates
+type Sprint struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Start and End are the first and last day of the sprint, at midnight UTC like due dates
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// NewSprint returns a sprint with a new random ID running from start to end
+func NewSprint(name string, start, end time.Time) (Sprint, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return Sprint{}, fmt.Errorf("failed to create sprint ID: %w", err)
+	}
+	return Sprint{ID: hex.EncodeToString(b), Name: strings.TrimSpace(name), Start: start, End: end}, nil
+}
+
+// Validate checks that the sprint can be saved
+func (s *Sprint) Validate() error {
+	if strings.TrimSpace(s.Name) == "" {
+		return errors.New("sprint name must not be empty")
+	}
+	if s.End.Before(s.Start) {
+		return fmt.Errorf("sprint %s must not end before it starts", s.Name)
+	}
+	if s.Days() > maxSprintDays {
+		return fmt.Errorf("sprint %s must be at most %d days long", s.Name, maxSprintDays)
+	}
+	return nil
+}
+
+// Days returns the number of days of the sprint, counting its first and last day
+func (s *Sprint) Days() int {
+	return int(s.End.Sub(s.Start).Hours()/24) + 1
+}
+
+// Day returns the date of day i of the sprint, the first day being 0
+func (s *Sprint) Day(i int) time.Time {
+	return s.Start.AddDate(0, 0, i)
+}
+
+// Sprint returns the sprint of the board with the given ID
+func (b *Board) Sprint(id string) (Sprint, bool) {
+	for _, s := range b.Sprints {
+		if s.ID == id {
+			return s, true
+		}
+	}
+	return Sprint{}, false
+}
+
+// validateSprints checks the sprints of the board and that their IDs and names are unique
+func (b *Board) validateSprints() error {
+	ids, names := map[string]bool{}, map[string]bool{}
+	for _, s := range b.Sprints {
+		if err := s.Validate(); err != nil {
+			return err
+		}
+		if s.ID == "" || ids[s.ID] {
+			return fmt.Errorf("sprint %s needs a unique ID", s.Name)
+		}
+		key := strings.ToLower(strings.TrimSpace(s.Name))
+		if names[key] {
+			return fmt.Errorf("sprint %s is defined twice", s.Name)
+		}
+		ids[s.ID], names[key] = true, true
+	}
+	return nil
+}
+
+// sprintName returns the name of a sprint of board, or its ID when it no longer exists
+func sprintName(board Board, id string) string {
+	if s, ok := board.Sprint(id); ok {
+		return s.Name
+	}
+	return id
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSprint(t *testing.T) {
+	start := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	sprint, err := NewSprint(" Sprint 1 ", start, start.AddDate(0, 0, 13))
+	require.NoError(t, err)
+	assert.Len(t, sprint.ID, 16)
+	assert.Equal(t, "Sprint 1", sprint.Name)
+	require.NoError(t, sprint.Validate())
+	assert.Equal(t, 14, sprint.Days())
+	assert.Equal(t, time.Date(2025, 6, 4, 0, 0, 0, 0, time.UTC), sprint.Day(2))
+
+	board := Board{Name: "Team", Sprints: []Sprint{sprint}}
+	require.NoError(t, board.Validate())
+	found, ok := board.Sprint(sprint.ID)
+	assert.True(t, ok)
+	assert.Equal(t, sprint, found)
+
+	board.Sprints = append(board.Sprints, Sprint{ID: "s2", Name: "sprint 1", Start: start, End: start})
+	assert.EqualError(t, board.Validate(), "sprint sprint 1 is defined twice")
+	board.Sprints[1] = Sprint{ID: sprint.ID, Name: "Sprint 2", Start: start, End: start}
+	assert.EqualError(t, board.Validate(), "sprint Sprint 2 needs a unique ID")
+	board.Sprints[1] = Sprint{ID: "s2", Name: "Sprint 2", Start: start, End: start.AddDate(0, 0, -1)}
+	assert.EqualError(t, board.Validate(), "sprint Sprint 2 must not end before it starts")
+	board.Sprints[1].End = start.AddDate(2, 0, 0)
+	assert.EqualError(t, board.Validate(), "sprint Sprint 2 must be at most 366 days long")
+	board.Sprints[1].Name = " "
+	assert.EqualError(t, board.Validate(), "sprint name must not be empty")
+
+	task := Task{Title: "Estimate", Estimate: -1}
+	assert.EqualError(t, task.Validate(), "estimate must be a positive number of points")
+}
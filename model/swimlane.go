@@ -0,0 +1,186 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Swimlanes is the task field the columns of a board are split into horizontal lanes by
+type Swimlanes string
+
+// Swimlane groupings
+const (
+	// SwimlanesNone shows the columns as a single row, it is the grouping of boards without one
+	SwimlanesNone Swimlanes = ""
+	// SwimlanesAssignee gives every assignee a lane
+	SwimlanesAssignee Swimlanes = "assignee"
+	// SwimlanesPriority gives every priority a lane, the most urgent first
+	SwimlanesPriority Swimlanes = "priority"
+	// SwimlanesLabel gives every label a lane
+	SwimlanesLabel Swimlanes = "label"
+)
+
+// SwimlaneGroupings lists the ways a board can be split into swimlanes, in display order
+var SwimlaneGroupings = []Swimlanes{SwimlanesNone, SwimlanesAssignee, SwimlanesPriority, SwimlanesLabel}
+
+// String returns the name of the grouping
+func (s Swimlanes) String() string {
+	switch s {
+	case SwimlanesNone:
+		return "None"
+	case SwimlanesAssignee:
+		return "Assignee"
+	case SwimlanesPriority:
+		return "Priority"
+	case SwimlanesLabel:
+		return "Label"
+	default:
+		return fmt.Sprintf("Swimlanes(%q)", string(s))
+	}
+}
+
+// validate checks that the grouping is known
+func (s Swimlanes) validate() error {
+	if !slices.Contains(SwimlaneGroupings, s) {
+		return fmt.Errorf("unknown swimlane grouping %q", string(s))
+	}
+	return nil
+}
+
+// Lane is a horizontal lane of a board split into swimlanes
+type Lane struct {
+	// Key is the value the tasks of the lane have: a username, a priority or a label name.
+	// It is empty for the lane of the tasks without one.
+	Key  string
+	Name string
+}
+
+// Lanes returns the swimlanes of the board in display order, or nil when it is not split into any.
+// Assignees get a lane when they are members of the group or assigned to one of tasks, labels when they
+// are in the label set of the board or on one of tasks; the lane of the tasks without either comes last.
+func (b *Board) Lanes(tasks []Task, members []Member) []Lane {
+	var lanes []Lane
+	switch b.Swimlanes {
+	case SwimlanesAssignee:
+		names := map[string]string{}
+		for _, m := range members {
+			names[m.Username] = m.Name
+		}
+		for _, t := range tasks {
+			for _, username := range t.Assignees {
+				if _, ok := names[username]; !ok {
+					names[username] = ""
+				}
+			}
+		}
+		for username, name := range names {
+			if name == "" {
+				name = username
+			}
+			lanes = append(lanes, Lane{Key: username, Name: name})
+		}
+		slices.SortFunc(lanes, func(a, b Lane) int {
+			return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+		})
+		lanes = append(lanes, Lane{Name: "Unassigned"})
+	case SwimlanesPriority:
+		for i := len(Priorities) - 1; i > 0; i-- {
+			lanes = append(lanes, Lane{Key: Priorities[i].String(), Name: Priorities[i].String()})
+		}
+		lanes = append(lanes, Lane{Key: PriorityNone.String(), Name: "No priority"})
+	case SwimlanesLabel:
+		for _, l := range b.Labels {
+			lanes = append(lanes, Lane{Key: l.Name, Name: l.Name})
+		}
+		var others []string
+		for _, t := range tasks {
+			for _, name := range t.Labels {
+				if _, ok := b.Label(name); !ok && !slices.ContainsFunc(others, func(o string) bool {
+					return strings.EqualFold(o, name)
+				}) {
+					others = append(others, name)
+				}
+			}
+		}
+		slices.SortFunc(others, func(a, b string) int {
+			return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+		})
+		for _, name := range others {
+			lanes = append(lanes, Lane{Key: name, Name: name})
+		}
+		lanes = append(lanes, Lane{Name: "No label"})
+	}
+	return lanes
+}
+
+// LaneKeys returns the keys of the swimlanes a task is shown in. A task with several assignees or labels
+// is shown in the lane of each.
+func (b *Board) LaneKeys(task *Task) []string {
+	switch b.Swimlanes {
+	case SwimlanesAssignee:
+		if len(task.Assignees) > 0 {
+			return slices.Clone(task.Assignees)
+		}
+	case SwimlanesPriority:
+		return []string{task.Priority.String()}
+	case SwimlanesLabel:
+		keys := make([]string, 0, len(task.Labels))
+		for _, name := range task.Labels {
+			// Lanes of labels in the set are named as in the set
+			if l, ok := b.Label(name); ok {
+				name = l.Name
+			}
+			if !slices.ContainsFunc(keys, func(k string) bool {
+				return strings.EqualFold(k, name)
+			}) {
+				keys = append(keys, name)
+			}
+		}
+		if len(keys) > 0 {
+			return keys
+		}
+	}
+	return []string{""}
+}
+
+// MoveToLane moves a task from the swimlane with key from to the one with key to, by replacing the value
+// of from with that of to. The other assignees and labels of the task are kept, unless to is the lane
+// of tasks without any, which clears them all.
+func (b *Board) MoveToLane(task *Task, from, to string) error {
+	switch b.Swimlanes {
+	case SwimlanesAssignee:
+		task.Assignees = moveValue(task.Assignees, from, to)
+	case SwimlanesPriority:
+		p, err := ParsePriority(to)
+		if err != nil {
+			return err
+		}
+		task.Priority = p
+	case SwimlanesLabel:
+		task.Labels = moveValue(task.Labels, from, to)
+	default:
+		return fmt.Errorf("board %s has no swimlanes", b.Name)
+	}
+	return nil
+}
+
+// moveValue removes from from values and adds to when it is not there yet, ignoring case.
+// Empty values stand for none: an empty from removes nothing and an empty to removes every value.
+func moveValue(values []string, from, to string) []string {
+	if to == "" {
+		return nil
+	}
+	moved := slices.DeleteFunc(slices.Clone(values), func(v string) bool {
+		return from != "" && strings.EqualFold(v, from)
+	})
+	if !slices.ContainsFunc(moved, func(v string) bool {
+		return strings.EqualFold(v, to)
+	}) {
+		moved = append(moved, to)
+	}
+	if len(moved) == 0 {
+		return nil
+	}
+	return moved
+}
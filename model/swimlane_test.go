@@ -0,0 +1,89 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoardLanes(t *testing.T) {
+	board := Board{Name: "Sprint", Labels: []Label{{Name: "Bug"}, {Name: "Feature"}}}
+	tasks := []Task{
+		{Assignees: []string{"zoe", "ana"}, Labels: []string{"bug", "docs"}},
+		{Labels: []string{"Chore"}},
+	}
+	members := []Member{{Username: "ana", Name: "Ana"}, {Username: "bo", Name: "Bo"}}
+	keys := func(lanes []Lane) []string {
+		var keys []string
+		for _, l := range lanes {
+			keys = append(keys, l.Key)
+		}
+		return keys
+	}
+
+	assert.Nil(t, board.Lanes(tasks, members))
+	assert.Equal(t, []string{""}, board.LaneKeys(&tasks[0]))
+
+	board.Swimlanes = SwimlanesAssignee
+	lanes := board.Lanes(tasks, members)
+	assert.Equal(t, []string{"ana", "bo", "zoe", ""}, keys(lanes))
+	assert.Equal(t, "Ana", lanes[0].Name)
+	assert.Equal(t, "zoe", lanes[2].Name)
+	assert.Equal(t, "Unassigned", lanes[3].Name)
+	assert.Equal(t, []string{"zoe", "ana"}, board.LaneKeys(&tasks[0]))
+	assert.Equal(t, []string{""}, board.LaneKeys(&tasks[1]))
+
+	board.Swimlanes = SwimlanesPriority
+	lanes = board.Lanes(tasks, members)
+	assert.Equal(t, []string{"Urgent", "High", "Medium", "Low", "None"}, keys(lanes))
+	assert.Equal(t, "No priority", lanes[4].Name)
+	assert.Equal(t, []string{"None"}, board.LaneKeys(&tasks[0]))
+
+	// Labels outside the set come after those in it
+	board.Swimlanes = SwimlanesLabel
+	assert.Equal(t, []string{"Bug", "Feature", "Chore", "docs", ""}, keys(board.Lanes(tasks, members)))
+	assert.Equal(t, []string{"Bug", "docs"}, board.LaneKeys(&tasks[0]))
+	assert.Equal(t, []string{""}, board.LaneKeys(&Task{}))
+}
+
+func TestBoardMoveToLane(t *testing.T) {
+	board := Board{Name: "Sprint"}
+	task := Task{Assignees: []string{"ana", "bo"}, Labels: []string{"bug"}}
+	assert.Error(t, board.MoveToLane(&task, "ana", "zoe"))
+
+	board.Swimlanes = SwimlanesAssignee
+	require.NoError(t, board.MoveToLane(&task, "ana", "zoe"))
+	assert.Equal(t, []string{"bo", "zoe"}, task.Assignees)
+	require.NoError(t, board.MoveToLane(&task, "zoe", "bo"))
+	assert.Equal(t, []string{"bo"}, task.Assignees)
+	require.NoError(t, board.MoveToLane(&task, "bo", ""))
+	assert.Nil(t, task.Assignees)
+	require.NoError(t, board.MoveToLane(&task, "", "ana"))
+	assert.Equal(t, []string{"ana"}, task.Assignees)
+
+	// Dropping a task with several assignees on the lane of unassigned tasks unassigns all of them
+	task.Assignees = []string{"ana", "bo"}
+	require.NoError(t, board.MoveToLane(&task, "ana", ""))
+	assert.Nil(t, task.Assignees)
+	task.Assignees = []string{"ana"}
+
+	board.Swimlanes = SwimlanesLabel
+	require.NoError(t, board.MoveToLane(&task, "Bug", "Feature"))
+	assert.Equal(t, []string{"Feature"}, task.Labels)
+	task.Labels = []string{"Feature", "docs"}
+	require.NoError(t, board.MoveToLane(&task, "docs", ""))
+	assert.Nil(t, task.Labels)
+
+	board.Swimlanes = SwimlanesPriority
+	require.NoError(t, board.MoveToLane(&task, "None", "High"))
+	assert.Equal(t, PriorityHigh, task.Priority)
+	assert.Error(t, board.MoveToLane(&task, "High", "Soon"))
+}
+
+func TestBoardValidateSwimlanes(t *testing.T) {
+	board := Board{Name: "Sprint", Swimlanes: SwimlanesLabel}
+	assert.NoError(t, board.Validate())
+	board.Swimlanes = "column"
+	assert.EqualError(t, board.Validate(), `unknown swimlane grouping "column"`)
+}
@@ -0,0 +1,194 @@
+// Package model defines the domain types shared by the Eldar client packages,
+// such as boards, columns and tasks, along with their validation rules.
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Priority represents how urgent a task is
+type Priority int
+
+// Task priority constants
+const (
+	PriorityNone   Priority = iota // No priority set
+	PriorityLow                    // Low priority
+	PriorityMedium                 // Medium priority
+	PriorityHigh                   // High priority
+	PriorityUrgent                 // Urgent priority
+)
+
+// Priorities lists every priority in ascending order
+var Priorities = []Priority{PriorityNone, PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent}
+
+// String returns the string representation of a Priority
+func (p Priority) String() string {
+	switch p {
+	case PriorityNone:
+		return "None"
+	case PriorityLow:
+		return "Low"
+	case PriorityMedium:
+		return "Medium"
+	case PriorityHigh:
+		return "High"
+	case PriorityUrgent:
+		return "Urgent"
+	default:
+		return fmt.Sprintf("Priority(%d)", int(p))
+	}
+}
+
+// ParsePriority returns the Priority whose String matches s, ignoring case
+func ParsePriority(s string) (Priority, error) {
+	for _, p := range Priorities {
+		if strings.EqualFold(p.String(), s) {
+			return p, nil
+		}
+	}
+	return PriorityNone, fmt.Errorf("unknown priority %q", s)
+}
+
+// MarshalText implements encoding.TextMarshaler so priorities are readable in JSON
+func (p Priority) MarshalText() ([]byte, error) {
+	return []byte(strings.ToLower(p.String())), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (p *Priority) UnmarshalText(text []byte) error {
+	parsed, err := ParsePriority(string(text))
+	if err != nil {
+		return err
+	}
+	*p = parsed
+	return nil
+}
+
+// Task is a card on a board
+type Task struct {
+	ID       string `json:"id"`
+	BoardID  string `json:"board_id"`
+	ColumnID string `json:"column_id"`
+	// ParentID is set on subtasks to the ID of the task they belong to
+	ParentID string `json:"parent_id,omitempty"`
+	// Position orders tasks within a column, lower first
+	Position int    `json:"position"`
+	Title    string `json:"title"`
+	// Description is Markdown text
+	Description string     `json:"description,omitempty"`
+	Assignees   []string   `json:"assignees,omitempty"`
+	Labels      []string   `json:"labels,omitempty"`
+	Priority    Priority   `json:"priority"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	// Checklist is the ordered list of small steps of the task
+	Checklist []ChecklistItem `json:"checklist,omitempty"`
+	Done      bool            `json:"done,omitempty"`
+	// BlockedBy lists the IDs of the tasks that must be completed before this one can start
+	BlockedBy []string `json:"blocked_by,omitempty"`
+	// Recurrence is set when the task repeats, see NextOccurrence
+	Recurrence *Recurrence `json:"recurrence,omitempty"`
+	// Attachments are the files attached to the task, oldest first. They are managed by the server
+	// through their own endpoints, so edits of the task leave them unchanged.
+	Attachments []Attachment `json:"attachments,omitempty"`
+	// Fields holds the values of the custom fields of the board by field ID, unset fields are left out
+	Fields map[string]FieldValue `json:"fields,omitempty"`
+	// SprintID is the ID of the sprint of the board the task is planned into, if any
+	SprintID string `json:"sprint_id,omitempty"`
+	// Estimate is the size of the task in points, 0 when it is not estimated
+	Estimate float64 `json:"estimate,omitempty"`
+	// ArchivedAt is set on tasks put away from the board, search and calendar until they are unarchived
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+	// DeletedAt is set on tasks in the trash, see TrashRetention
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// Version is incremented by the server on every change and used to detect conflicting edits
+	Version   int64     `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks that the task can be saved
+func (t *Task) Validate() error {
+	if strings.TrimSpace(t.Title) == "" {
+		return errors.New("task title must not be empty")
+	}
+	if len(t.Title) > 255 {
+		return errors.New("task title must be at most 255 characters")
+	}
+	if t.Priority < PriorityNone || t.Priority > PriorityUrgent {
+		return fmt.Errorf("invalid priority %d", t.Priority)
+	}
+	if t.ParentID != "" && t.ParentID == t.ID {
+		return errors.New("task cannot be its own subtask")
+	}
+	if t.Estimate < 0 || math.IsNaN(t.Estimate) || math.IsInf(t.Estimate, 0) {
+		return errors.New("estimate must be a positive number of points")
+	}
+	for _, item := range t.Checklist {
+		if strings.TrimSpace(item.Text) == "" {
+			return errors.New("checklist item text must not be empty")
+		}
+	}
+	if t.Recurrence != nil {
+		if t.DueDate == nil {
+			return errors.New("recurring task must have a due date")
+		}
+		if err := t.Recurrence.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Clone returns a deep copy of the task, so edits do not affect the original
+func (t Task) Clone() Task {
+	t.Assignees = cloneStrings(t.Assignees)
+	t.Labels = cloneStrings(t.Labels)
+	t.BlockedBy = cloneStrings(t.BlockedBy)
+	if t.Checklist != nil {
+		t.Checklist = append([]ChecklistItem{}, t.Checklist...)
+	}
+	if t.DueDate != nil {
+		due := *t.DueDate
+		t.DueDate = &due
+	}
+	if t.Recurrence != nil {
+		recurrence := *t.Recurrence
+		t.Recurrence = &recurrence
+	}
+	if t.Attachments != nil {
+		t.Attachments = append([]Attachment{}, t.Attachments...)
+	}
+	if t.Fields != nil {
+		fields := make(map[string]FieldValue, len(t.Fields))
+		for id, v := range t.Fields {
+			fields[id] = v.Clone()
+		}
+		t.Fields = fields
+	}
+	if t.ArchivedAt != nil {
+		archived := *t.ArchivedAt
+		t.ArchivedAt = &archived
+	}
+	if t.DeletedAt != nil {
+		deleted := *t.DeletedAt
+		t.DeletedAt = &deleted
+	}
+	return t
+}
+
+// Archived reports whether the task is archived
+func (t *Task) Archived() bool {
+	return t.ArchivedAt != nil
+}
+
+// cloneStrings copies a string slice, keeping nil as nil
+func cloneStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	return append([]string{}, s...)
+}
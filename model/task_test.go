@@ -0,0 +1,53 @@
+package model
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriority(t *testing.T) {
+	for _, p := range Priorities {
+		parsed, err := ParsePriority(strings.ToUpper(p.String()))
+		require.NoError(t, err)
+		assert.Equal(t, p, parsed)
+	}
+	_, err := ParsePriority("critical")
+	assert.Error(t, err)
+
+	payload, err := json.Marshal(Task{Title: "Write report", Priority: PriorityHigh})
+	require.NoError(t, err)
+	assert.Contains(t, string(payload), `"priority":"high"`)
+
+	var task Task
+	require.NoError(t, json.Unmarshal(payload, &task))
+	assert.Equal(t, PriorityHigh, task.Priority)
+}
+
+func TestTaskValidate(t *testing.T) {
+	assert.NoError(t, (&Task{Title: "Write report"}).Validate())
+	assert.Error(t, (&Task{Title: "  "}).Validate())
+	assert.Error(t, (&Task{Title: strings.Repeat("a", 256)}).Validate())
+	assert.Error(t, (&Task{Title: "Write report", Priority: Priority(9)}).Validate())
+}
+
+func TestTaskClone(t *testing.T) {
+	due := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	task := Task{Title: "Write report", Labels: []string{"docs"}, DueDate: &due}
+
+	clone := task.Clone()
+	clone.Labels[0] = "bug"
+	*clone.DueDate = due.Add(24 * time.Hour)
+	assert.Equal(t, []string{"docs"}, task.Labels)
+	assert.Equal(t, due, *task.DueDate)
+
+	task.Fields = map[string]FieldValue{"f1": {Choices: []string{"iOS"}}}
+	clone = task.Clone()
+	clone.Fields["f1"].Choices[0] = "Web"
+	clone.Fields["f2"] = FieldValue{Text: "Acme"}
+	assert.Equal(t, map[string]FieldValue{"f1": {Choices: []string{"iOS"}}}, task.Fields)
+}
@@ -0,0 +1,228 @@
+package model
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strings"
+)
+
+// TemplateKind is what a template creates
+type TemplateKind string
+
+// Template kinds
+const (
+	// TemplateBoard creates a board with its columns, labels, custom fields and optionally tasks
+	TemplateBoard TemplateKind = "board"
+	// TemplateTask creates a task with its description and checklist
+	TemplateTask TemplateKind = "task"
+)
+
+// Template is a board or task saved to create more like it, such as the board of a sprint
+type Template struct {
+	ID          string       `json:"id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description,omitempty"`
+	Kind        TemplateKind `json:"kind"`
+	// Board holds the columns, labels, custom fields and swimlanes of board templates
+	Board *Board `json:"board,omitempty"`
+	// Tasks are created along with the board of a board template, in the columns of Board.
+	// Task templates hold a single task.
+	Tasks []Task `json:"tasks,omitempty"`
+	// Owner is the username of the account that saved the template, only the owner can delete it.
+	// Templates are shared with the group of the owner.
+	Owner string `json:"owner"`
+	// Version is incremented by the server on every change and used to detect conflicting edits
+	Version int64 `json:"version"`
+}
+
+// builtinTemplatePrefix marks the IDs of the BuiltinTemplates
+const builtinTemplatePrefix = "builtin:"
+
+//go:embed templates/*.json
+var builtinTemplateFiles embed.FS
+
+// BuiltinTemplates are the templates available to every account without saving them first,
+// read from the templates folder embedded in the binary
+var BuiltinTemplates = mustLoadTemplates(builtinTemplateFiles, "templates")
+
+// mustLoadTemplates reads the templates of a folder, one per JSON file, named after the file.
+// It panics when one cannot be read, as the built-in templates are checked by the tests.
+func mustLoadTemplates(fsys fs.FS, dir string) []Template {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		panic(fmt.Sprintf("failed to list templates: %v", err))
+	}
+	templates := make([]Template, 0, len(entries))
+	for _, entry := range entries {
+		payload, err := fs.ReadFile(fsys, dir+"/"+entry.Name())
+		if err != nil {
+			panic(fmt.Sprintf("failed to read template %s: %v", entry.Name(), err))
+		}
+		var t Template
+		if err := json.Unmarshal(payload, &t); err != nil {
+			panic(fmt.Sprintf("failed to decode template %s: %v", entry.Name(), err))
+		}
+		t.ID = builtinTemplatePrefix + strings.TrimSuffix(entry.Name(), ".json")
+		templates = append(templates, t)
+	}
+	return templates
+}
+
+// NewBoardTemplate creates a template of the columns, labels, custom fields and swimlanes of board.
+// The tasks are saved along with it, without their assignees, due dates and progress; pass none to leave them out.
+func NewBoardTemplate(name string, board Board, tasks []Task) Template {
+	t := Template{Name: strings.TrimSpace(name), Kind: TemplateBoard, Board: &Board{
+		Name:      board.Name,
+		Columns:   cloneColumns(board.Columns),
+		Labels:    slices.Clone(board.Labels),
+		Fields:    cloneFields(board.Fields),
+		Swimlanes: board.Swimlanes,
+	}}
+	for _, task := range tasks {
+		t.Tasks = append(t.Tasks, templateTask(task))
+	}
+	return t
+}
+
+// NewTaskTemplate creates a template of the title, description, checklist, labels, priority and estimate of task
+func NewTaskTemplate(name string, task Task) Template {
+	return Template{Name: strings.TrimSpace(name), Kind: TemplateTask, Tasks: []Task{templateTask(task)}}
+}
+
+// templateTask returns the parts of a task that are kept in templates, with its checklist unchecked
+func templateTask(task Task) Task {
+	task = task.Clone()
+	t := Task{
+		ColumnID:    task.ColumnID,
+		Title:       task.Title,
+		Description: task.Description,
+		Labels:      task.Labels,
+		Priority:    task.Priority,
+		Checklist:   task.Checklist,
+		Fields:      task.Fields,
+		Estimate:    task.Estimate,
+	}
+	for i := range t.Checklist {
+		t.Checklist[i].Done = false
+	}
+	return t
+}
+
+// Builtin reports whether the template is one of the BuiltinTemplates, which cannot be changed
+func (t *Template) Builtin() bool {
+	return strings.HasPrefix(t.ID, builtinTemplatePrefix)
+}
+
+// Validate checks that the template can be saved
+func (t *Template) Validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return errors.New("template name must not be empty")
+	}
+	if len(t.Name) > 100 {
+		return errors.New("template name must be at most 100 characters")
+	}
+	switch t.Kind {
+	case TemplateBoard:
+		if t.Board == nil {
+			return errors.New("board template has no board")
+		}
+		if err := t.Board.Validate(); err != nil {
+			return err
+		}
+		for _, task := range t.Tasks {
+			if _, ok := t.Board.Column(task.ColumnID); !ok {
+				return fmt.Errorf("task %s of the template is not in one of its columns", task.Title)
+			}
+		}
+	case TemplateTask:
+		if len(t.Tasks) != 1 {
+			return errors.New("task template must hold exactly one task")
+		}
+	default:
+		return fmt.Errorf("unknown template kind %q", t.Kind)
+	}
+	for _, task := range t.Tasks {
+		if err := task.Validate(); err != nil {
+			return err
+		}
+	}
+	if t.Builtin() {
+		return errors.New("built-in templates cannot be changed")
+	}
+	return nil
+}
+
+// NewBoard creates a board named name from a board template, along with the tasks of the template.
+// The tasks get the ID of the board once it is created.
+func (t *Template) NewBoard(name string) (Board, []Task, error) {
+	if t.Kind != TemplateBoard || t.Board == nil {
+		return Board{}, nil, fmt.Errorf("%s is not a board template", t.Name)
+	}
+	board := Board{
+		Name:      strings.TrimSpace(name),
+		Columns:   cloneColumns(t.Board.Columns),
+		Labels:    slices.Clone(t.Board.Labels),
+		Fields:    cloneFields(t.Board.Fields),
+		Swimlanes: t.Board.Swimlanes,
+	}
+	if err := board.Validate(); err != nil {
+		return Board{}, nil, err
+	}
+	tasks := make([]Task, len(t.Tasks))
+	for i, task := range t.Tasks {
+		var err error
+		if tasks[i], err = instantiateTask(task); err != nil {
+			return Board{}, nil, err
+		}
+		tasks[i].Position = i
+	}
+	return board, tasks, nil
+}
+
+// NewTask creates a task in a column of a board from a task template
+func (t *Template) NewTask(boardID, columnID string) (Task, error) {
+	if t.Kind != TemplateTask || len(t.Tasks) != 1 {
+		return Task{}, fmt.Errorf("%s is not a task template", t.Name)
+	}
+	task, err := instantiateTask(t.Tasks[0])
+	if err != nil {
+		return Task{}, err
+	}
+	task.BoardID, task.ColumnID = boardID, columnID
+	return task, nil
+}
+
+// instantiateTask copies a task of a template, giving its checklist items new IDs
+func instantiateTask(task Task) (Task, error) {
+	task = templateTask(task)
+	for i, item := range task.Checklist {
+		fresh, err := NewChecklistItem(item.Text)
+		if err != nil {
+			return Task{}, err
+		}
+		task.Checklist[i] = fresh
+	}
+	return task, nil
+}
+
+// cloneColumns copies columns along with their definitions of done
+func cloneColumns(columns []Column) []Column {
+	cloned := slices.Clone(columns)
+	for i := range cloned {
+		cloned[i].DefinitionOfDone = slices.Clone(cloned[i].DefinitionOfDone)
+	}
+	return cloned
+}
+
+// cloneFields copies custom fields along with their options
+func cloneFields(fields []Field) []Field {
+	cloned := slices.Clone(fields)
+	for i := range cloned {
+		cloned[i].Options = slices.Clone(cloned[i].Options)
+	}
+	return cloned
+}
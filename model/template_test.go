@@ -0,0 +1,109 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltinTemplates(t *testing.T) {
+	require.NotEmpty(t, BuiltinTemplates)
+	kinds := map[TemplateKind]int{}
+	for _, template := range BuiltinTemplates {
+		assert.True(t, template.Builtin(), template.ID)
+		assert.NotEmpty(t, template.Description, template.ID)
+		kinds[template.Kind]++
+
+		// Built-ins are valid apart from being built in
+		saved := template
+		saved.ID = ""
+		assert.NoError(t, saved.Validate(), template.ID)
+		assert.EqualError(t, template.Validate(), "built-in templates cannot be changed")
+	}
+	assert.Positive(t, kinds[TemplateBoard])
+	assert.Positive(t, kinds[TemplateTask])
+}
+
+func TestNewBoardTemplate(t *testing.T) {
+	due := time.Date(2025, 6, 3, 0, 0, 0, 0, time.UTC)
+	board := Board{
+		ID: "b1", Name: "Sprint 12", Version: 4, ArchivedAt: &due, Swimlanes: SwimlanesLabel,
+		Columns: []Column{{ID: "todo", Name: "To do"}, {ID: "done", Name: "Done", DefinitionOfDone: []string{"Reviewed"}}},
+		Labels:  []Label{{Name: "Bug", Color: "#e53935"}},
+		Fields:  []Field{{ID: "f1", Name: "Size", Type: FieldSelect, Options: []string{"S", "L"}}},
+	}
+	task := Task{
+		ID: "t1", BoardID: "b1", ColumnID: "done", Title: "Ship it", Description: "Notes", Done: true,
+		Assignees: []string{"ana"}, Labels: []string{"Bug"}, Priority: PriorityHigh, DueDate: &due, Version: 2,
+		Checklist: []ChecklistItem{{ID: "c1", Text: "Reviewed", Done: true}},
+		Fields:    map[string]FieldValue{"f1": {Text: "S"}},
+	}
+
+	template := NewBoardTemplate(" Sprint ", board, []Task{task})
+	require.NoError(t, template.Validate())
+	assert.Equal(t, "Sprint", template.Name)
+	assert.Equal(t, TemplateBoard, template.Kind)
+	assert.Empty(t, template.Board.ID)
+	assert.Nil(t, template.Board.ArchivedAt)
+	assert.Zero(t, template.Board.Version)
+	assert.Equal(t, board.Columns, template.Board.Columns)
+	require.Len(t, template.Tasks, 1)
+	saved := template.Tasks[0]
+	assert.Equal(t, Task{ColumnID: "done", Title: "Ship it", Description: "Notes", Labels: []string{"Bug"}, Priority: PriorityHigh,
+		Checklist: []ChecklistItem{{ID: "c1", Text: "Reviewed"}}, Fields: map[string]FieldValue{"f1": {Text: "S"}}}, saved)
+
+	// The template does not share slices with the board
+	template.Board.Columns[1].DefinitionOfDone[0] = "Tested"
+	template.Board.Fields[0].Options[0] = "XS"
+	assert.Equal(t, "Reviewed", board.Columns[1].DefinitionOfDone[0])
+	assert.Equal(t, "S", board.Fields[0].Options[0])
+	assert.True(t, task.Checklist[0].Done)
+
+	created, tasks, err := template.NewBoard("Sprint 13")
+	require.NoError(t, err)
+	assert.Equal(t, "Sprint 13", created.Name)
+	assert.Equal(t, SwimlanesLabel, created.Swimlanes)
+	require.Len(t, tasks, 1)
+	assert.NotEqual(t, "c1", tasks[0].Checklist[0].ID)
+	assert.Equal(t, "Reviewed", tasks[0].Checklist[0].Text)
+
+	_, _, err = template.NewBoard(" ")
+	assert.EqualError(t, err, "board name must not be empty")
+	_, err = template.NewTask("b1", "todo")
+	assert.EqualError(t, err, "Sprint is not a task template")
+
+	// Tasks must be in a column of the template
+	template.Tasks[0].ColumnID = "gone"
+	assert.EqualError(t, template.Validate(), "task Ship it of the template is not in one of its columns")
+}
+
+func TestNewTaskTemplate(t *testing.T) {
+	task := Task{ID: "t1", BoardID: "b1", ColumnID: "todo", Title: "Release", Assignees: []string{"ana"},
+		Checklist: []ChecklistItem{{ID: "c1", Text: "Tag", Done: true}, {ID: "c2", Text: "Announce"}}}
+	template := NewTaskTemplate("Release", task)
+	require.NoError(t, template.Validate())
+	assert.Equal(t, TemplateTask, template.Kind)
+	assert.Nil(t, template.Board)
+
+	created, err := template.NewTask("b2", "doing")
+	require.NoError(t, err)
+	assert.Equal(t, "b2", created.BoardID)
+	assert.Equal(t, "doing", created.ColumnID)
+	assert.Equal(t, "Release", created.Title)
+	assert.Nil(t, created.Assignees)
+	require.Len(t, created.Checklist, 2)
+	assert.False(t, created.Checklist[0].Done)
+	assert.NotEqual(t, "c1", created.Checklist[0].ID)
+
+	_, _, err = template.NewBoard("Release")
+	assert.EqualError(t, err, "Release is not a board template")
+
+	template.Tasks = nil
+	assert.EqualError(t, template.Validate(), "task template must hold exactly one task")
+	template.Kind = "column"
+	assert.EqualError(t, template.Validate(), `unknown template kind "column"`)
+	template.Name = ""
+	assert.EqualError(t, template.Validate(), "template name must not be empty")
+}
@@ -0,0 +1,133 @@
+package model
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxTimeEntry is the longest time entry accepted, longer ones are most likely a forgotten timer
+const maxTimeEntry = 24 * time.Hour
+
+// TimeEntry is time spent on a task, tracked with a timer or entered by hand
+type TimeEntry struct {
+	ID      string `json:"id"`
+	TaskID  string `json:"task_id"`
+	BoardID string `json:"board_id"`
+	// User is the username of the account that spent the time
+	User  string    `json:"user"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Note  string    `json:"note,omitempty"`
+	// Manual is set on entries entered by hand rather than tracked with a timer
+	Manual bool `json:"manual,omitempty"`
+}
+
+// Duration returns the time spent
+func (e *TimeEntry) Duration() time.Duration {
+	return e.End.Sub(e.Start)
+}
+
+// Validate checks that the time entry can be saved
+func (e *TimeEntry) Validate() error {
+	if e.TaskID == "" {
+		return errors.New("time entry needs a task")
+	}
+	if !e.End.After(e.Start) {
+		return errors.New("time entry must end after it starts")
+	}
+	if e.Duration() > maxTimeEntry {
+		return errors.New("time entry must be at most 24 hours")
+	}
+	if len(e.Note) > 500 {
+		return errors.New("time entry note must be at most 500 characters")
+	}
+	return nil
+}
+
+// Timer is a running timer of a task. Only one timer runs at a time.
+type Timer struct {
+	TaskID  string    `json:"task_id"`
+	BoardID string    `json:"board_id"`
+	Start   time.Time `json:"start"`
+}
+
+// Elapsed returns the time the timer has been running at now
+func (t *Timer) Elapsed(now time.Time) time.Duration {
+	return max(now.Sub(t.Start), 0)
+}
+
+// Stop returns the time entry of user tracked by the timer until now
+func (t *Timer) Stop(user string, now time.Time) TimeEntry {
+	return TimeEntry{TaskID: t.TaskID, BoardID: t.BoardID, User: user, Start: t.Start, End: now}
+}
+
+// ParseDuration parses a time spent such as "1h30m", "45m", "2h" or "1.5", a number being hours
+func ParseDuration(s string) (time.Duration, error) {
+	s = strings.ReplaceAll(strings.TrimSpace(s), " ", "")
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		s += "h"
+	}
+	d, err := time.ParseDuration(s)
+	if d = d.Round(time.Minute); err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid time %q, use hours and minutes such as 1h30m", s)
+	}
+	return d, nil
+}
+
+// FormatDuration returns a time spent in hours and minutes, such as "1h 30m" or "45m"
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	hours, minutes := int(d/time.Hour), int(d%time.Hour/time.Minute)
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes == 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	}
+}
+
+// WeekStart returns midnight of the Monday of the week of t, in the time zone of t
+func WeekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, t.Location())
+}
+
+// TimeTotal is the time a user spent on the tasks of a board in a week
+type TimeTotal struct {
+	User    string
+	BoardID string
+	// Week is the start of the week, see WeekStart
+	Week     time.Time
+	Duration time.Duration
+}
+
+// SumTime adds time entries up by user, board and week, the week of an entry being that of its start in loc.
+// Totals are sorted by week, most recent first, then board and user.
+func SumTime(entries []TimeEntry, loc *time.Location) []TimeTotal {
+	type key struct {
+		user, boardID string
+		week          time.Time
+	}
+	sums := map[key]time.Duration{}
+	for _, e := range entries {
+		sums[key{e.User, e.BoardID, WeekStart(e.Start.In(loc))}] += e.Duration()
+	}
+	totals := make([]TimeTotal, 0, len(sums))
+	for k, d := range sums {
+		totals = append(totals, TimeTotal{User: k.user, BoardID: k.boardID, Week: k.week, Duration: d})
+	}
+	slices.SortFunc(totals, func(a, b TimeTotal) int {
+		if c := b.Week.Compare(a.Week); c != 0 {
+			return c
+		}
+		return cmp.Or(cmp.Compare(a.BoardID, b.BoardID), cmp.Compare(a.User, b.User))
+	})
+	return totals
+}
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeEntryValidate(t *testing.T) {
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	entry := TimeEntry{TaskID: "t1", Start: start, End: start.Add(90 * time.Minute)}
+	assert.NoError(t, entry.Validate())
+	assert.Equal(t, 90*time.Minute, entry.Duration())
+
+	entry.End = start
+	assert.Error(t, entry.Validate())
+	entry.End = start.Add(25 * time.Hour)
+	assert.Error(t, entry.Validate())
+	entry.End, entry.TaskID = start.Add(time.Hour), ""
+	assert.Error(t, entry.Validate())
+}
+
+func TestTimer(t *testing.T) {
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	timer := Timer{TaskID: "t1", BoardID: "b1", Start: start}
+	assert.Equal(t, 5*time.Minute, timer.Elapsed(start.Add(5*time.Minute)))
+	assert.Zero(t, timer.Elapsed(start.Add(-time.Minute)))
+	assert.Equal(t, TimeEntry{TaskID: "t1", BoardID: "b1", User: "ana", Start: start, End: start.Add(time.Hour)},
+		timer.Stop("ana", start.Add(time.Hour)))
+}
+
+func TestParseDuration(t *testing.T) {
+	for input, want := range map[string]time.Duration{
+		"1h30m":  90 * time.Minute,
+		"1h 30m": 90 * time.Minute,
+		"45m":    45 * time.Minute,
+		"2":      2 * time.Hour,
+		"1.5":    90 * time.Minute,
+		"20s":    0,
+	} {
+		d, err := ParseDuration(input)
+		if want == 0 {
+			assert.Error(t, err, input)
+			continue
+		}
+		require.NoError(t, err, input)
+		assert.Equal(t, want, d, input)
+	}
+	for _, input := range []string{"", "soon", "-1h", "0"} {
+		_, err := ParseDuration(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	assert.Equal(t, "45m", FormatDuration(45*time.Minute))
+	assert.Equal(t, "2h", FormatDuration(2*time.Hour))
+	assert.Equal(t, "1h 30m", FormatDuration(90*time.Minute+10*time.Second))
+	assert.Equal(t, "26h 5m", FormatDuration(26*time.Hour+5*time.Minute))
+}
+
+func TestSumTime(t *testing.T) {
+	// Monday 2 June and Sunday 8 June 2025 are in the same week
+	monday := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	sunday := time.Date(2025, 6, 8, 23, 0, 0, 0, time.UTC)
+	nextWeek := time.Date(2025, 6, 9, 9, 0, 0, 0, time.UTC)
+	entries := []TimeEntry{
+		{User: "ana", BoardID: "b1", Start: monday, End: monday.Add(time.Hour)},
+		{User: "ana", BoardID: "b1", Start: sunday, End: sunday.Add(30 * time.Minute)},
+		{User: "ben", BoardID: "b1", Start: monday, End: monday.Add(2 * time.Hour)},
+		{User: "ana", BoardID: "b2", Start: nextWeek, End: nextWeek.Add(time.Hour)},
+	}
+	assert.Equal(t, []TimeTotal{
+		{User: "ana", BoardID: "b2", Week: nextWeek.Add(-9 * time.Hour), Duration: time.Hour},
+		{User: "ana", BoardID: "b1", Week: monday.Add(-9 * time.Hour), Duration: 90 * time.Minute},
+		{User: "ben", BoardID: "b1", Week: monday.Add(-9 * time.Hour), Duration: 2 * time.Hour},
+	}, SumTime(entries, time.UTC))
+
+	// Sunday 23:00 UTC is already Monday in Berlin
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	totals := SumTime(entries[1:2], berlin)
+	require.Len(t, totals, 1)
+	assert.Equal(t, time.Date(2025, 6, 9, 0, 0, 0, 0, berlin), totals[0].Week)
+}
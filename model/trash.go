@@ -0,0 +1,52 @@
+package model
+
+import (
+	"sort"
+	"time"
+)
+
+// TrashRetention is how long the server keeps deleted boards and tasks in the trash before purging them
+const TrashRetention = 30 * 24 * time.Hour
+
+// Trash holds the deleted boards and tasks that can still be restored.
+// The tasks of a deleted board are restored with it, so they are not listed separately.
+type Trash struct {
+	Boards []Board `json:"boards"`
+	Tasks  []Task  `json:"tasks"`
+}
+
+// PurgeAt returns when an item deleted at deletedAt is removed from the trash for good
+func PurgeAt(deletedAt time.Time) time.Time {
+	return deletedAt.Add(TrashRetention)
+}
+
+// Restorable returns the items of the trash that are not purged by now, most recently deleted first.
+// The server purges expired items on its own schedule, so a cached trash may still hold a few.
+func (t Trash) Restorable(now time.Time) Trash {
+	var kept Trash
+	for _, b := range t.Boards {
+		if b.DeletedAt == nil || now.Before(PurgeAt(*b.DeletedAt)) {
+			kept.Boards = append(kept.Boards, b)
+		}
+	}
+	for _, task := range t.Tasks {
+		if task.DeletedAt == nil || now.Before(PurgeAt(*task.DeletedAt)) {
+			kept.Tasks = append(kept.Tasks, task)
+		}
+	}
+	sort.SliceStable(kept.Boards, func(i, j int) bool {
+		return deletedAt(kept.Boards[i].DeletedAt).After(deletedAt(kept.Boards[j].DeletedAt))
+	})
+	sort.SliceStable(kept.Tasks, func(i, j int) bool {
+		return deletedAt(kept.Tasks[i].DeletedAt).After(deletedAt(kept.Tasks[j].DeletedAt))
+	})
+	return kept
+}
+
+// deletedAt returns the time an item was deleted, or the zero time when it is not in the trash
+func deletedAt(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrashRestorable(t *testing.T) {
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+	at := func(days int) *time.Time {
+		deleted := now.AddDate(0, 0, -days)
+		return &deleted
+	}
+	trash := Trash{
+		Boards: []Board{{ID: "b1", DeletedAt: at(31)}, {ID: "b2", DeletedAt: at(2)}},
+		Tasks:  []Task{{ID: "t1", DeletedAt: at(5)}, {ID: "t2", DeletedAt: at(1)}, {ID: "t3", DeletedAt: at(30)}},
+	}
+
+	restorable := trash.Restorable(now)
+	assert.Equal(t, []Board{trash.Boards[1]}, restorable.Boards)
+	if assert.Len(t, restorable.Tasks, 2) {
+		assert.Equal(t, "t2", restorable.Tasks[0].ID)
+		assert.Equal(t, "t1", restorable.Tasks[1].ID)
+	}
+	assert.Equal(t, now, PurgeAt(*at(30)))
+}
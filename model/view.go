@@ -0,0 +1,54 @@
+package model
+
+import (
+	"errors"
+	"strings"
+)
+
+// View is a saved search, such as "My open bugs", listed in the sidebar of the Boards page.
+// Its query is evaluated against the local task cache, so views work offline.
+type View struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Query is a search query such as "label:bug assignee:me is:open", see search.Parse
+	Query string `json:"query"`
+	// Owner is the username of the account that created the view, only the owner can change it
+	Owner string `json:"owner"`
+	// Shared views are listed for every member of the owner's group
+	Shared bool `json:"shared,omitempty"`
+	// Version is incremented by the server on every change and used to detect conflicting edits
+	Version int64 `json:"version"`
+}
+
+// builtinViewPrefix marks the IDs of the BuiltinViews
+const builtinViewPrefix = "builtin:"
+
+// BuiltinViews are the smart filters available to every account without saving them first
+var BuiltinViews = []View{
+	{ID: builtinViewPrefix + "mine", Name: "My open tasks", Query: "assignee:me is:open"},
+	{ID: builtinViewPrefix + "week", Name: "Due this week", Query: "is:open due:<7d"},
+	{ID: builtinViewPrefix + "overdue", Name: "Overdue", Query: "due:overdue"},
+	{ID: builtinViewPrefix + "blocked", Name: "Blocked", Query: "is:open is:blocked"},
+}
+
+// Builtin reports whether the view is one of the BuiltinViews, which cannot be changed
+func (v *View) Builtin() bool {
+	return strings.HasPrefix(v.ID, builtinViewPrefix)
+}
+
+// Validate checks that the view can be saved. The query itself is checked with search.Parse.
+func (v *View) Validate() error {
+	if strings.TrimSpace(v.Name) == "" {
+		return errors.New("view name must not be empty")
+	}
+	if len(v.Name) > 100 {
+		return errors.New("view name must be at most 100 characters")
+	}
+	if strings.TrimSpace(v.Query) == "" {
+		return errors.New("view query must not be empty")
+	}
+	if v.Builtin() {
+		return errors.New("built-in views cannot be changed")
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestViewValidate(t *testing.T) {
+	assert.NoError(t, (&View{Name: "My open bugs", Query: "label:bug assignee:me is:open"}).Validate())
+	assert.Error(t, (&View{Name: " ", Query: "is:open"}).Validate())
+	assert.Error(t, (&View{Name: strings.Repeat("a", 101), Query: "is:open"}).Validate())
+	assert.Error(t, (&View{Name: "Open", Query: " "}).Validate())
+
+	for _, v := range BuiltinViews {
+		assert.True(t, v.Builtin(), v.Name)
+		assert.ErrorContains(t, v.Validate(), "built-in", v.Name)
+	}
+}
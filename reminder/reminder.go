@@ -0,0 +1,350 @@
+// Package reminder schedules desktop notifications ahead of task due dates.
+// Pending reminders are persisted in a bbolt database so they survive restarts, and each one is
+// claimed on the server before it is shown so that only one device of the account notifies.
+package reminder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"eldar/api"
+	"eldar/model"
+	"go.etcd.io/bbolt"
+)
+
+// remindersBucket holds the pending and sent reminders by ID
+var remindersBucket = []byte("reminders")
+
+// DefaultLeadTimes are used when the user has not chosen any: a day and an hour before the due date
+var DefaultLeadTimes = []time.Duration{24 * time.Hour, time.Hour}
+
+// missedGrace is how long after a due date a reminder that could not be shown, e.g. because the app
+// was not running, is still worth showing
+const missedGrace = 12 * time.Hour
+
+// dateOnlyDueHour is the local hour date-only due dates are reminded of as due at, the start of a working day.
+// They are stored at midnight UTC, which would put their reminders at a different time of day in every zone.
+const dateOnlyDueHour = 9
+
+// Reminder is a notification to show before a task is due
+type Reminder struct {
+	ID     string        `json:"id"`
+	TaskID string        `json:"task_id"`
+	Title  string        `json:"title"`
+	Due    time.Time     `json:"due"`
+	Lead   time.Duration `json:"lead"`
+	// FireAt is when the notification should be shown
+	FireAt time.Time `json:"fire_at"`
+	// Sent is set once the reminder was shown here or claimed by another device
+	Sent bool `json:"sent"`
+}
+
+// Message describes the reminder for a notification body
+func (r Reminder) Message() string {
+	return fmt.Sprintf("%q is due in %s", r.Title, FormatLeadTime(r.Lead))
+}
+
+// reminderID identifies the reminder of a task for a due date and lead time.
+// It is the same on every device, so the server can tell a reminder was already claimed.
+func reminderID(taskID string, due time.Time, lead time.Duration) string {
+	return fmt.Sprintf("%s:%d:%d", taskID, due.Unix(), int64(lead/time.Minute))
+}
+
+// dueAt returns when a task due at due is due in loc. Date-only due dates are due at dateOnlyDueHour
+// on their date in loc, due dates with a time of day at that time.
+func dueAt(due time.Time, loc *time.Location) time.Time {
+	if !model.DateOnly(due) {
+		return due
+	}
+	y, m, d := due.UTC().Date()
+	return time.Date(y, m, d, dateOnlyDueHour, 0, 0, 0, loc)
+}
+
+// Claimer claims a reminder on the server.
+// It returns an *api.Error with status 409 when another device already claimed it.
+type Claimer interface {
+	ClaimReminder(ctx context.Context, id string) error
+}
+
+// Scheduler keeps the reminders of the tasks of an account and shows them when they are due.
+// It is safe for concurrent use.
+type Scheduler struct {
+	db      *bbolt.DB
+	claimer Claimer
+	notify  func(Reminder)
+	now     func() time.Time
+
+	mu        sync.Mutex
+	leadTimes []time.Duration
+	reminders map[string]Reminder
+}
+
+// Open opens the reminder database in dir, creating it if needed, and loads the pending reminders.
+//
+// Parameters:
+//   - dir: The account data directory, see credentials.AccountDataDir
+//   - claimer: The server reminders are claimed on before they are shown
+//   - notify: A function showing a reminder, called from the goroutine running Fire
+//
+// Returns:
+//   - The opened Scheduler, which must be closed with Close
+//   - An error if the database could not be opened or read
+func Open(dir string, claimer Claimer, notify func(Reminder)) (*Scheduler, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create reminder directory: %w", err)
+	}
+	db, err := bbolt.Open(filepath.Join(dir, "reminders.db"), 0600, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open reminder database: %w", err)
+	}
+
+	s := &Scheduler{
+		db:        db,
+		claimer:   claimer,
+		notify:    notify,
+		now:       time.Now,
+		leadTimes: DefaultLeadTimes,
+		reminders: map[string]Reminder{},
+	}
+	if err := db.Update(func(tx *bbolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(remindersBucket)
+		if err != nil {
+			return fmt.Errorf("failed to create bucket %s: %w", remindersBucket, err)
+		}
+		return b.ForEach(func(k, v []byte) error {
+			var r Reminder
+			if err := json.Unmarshal(v, &r); err != nil {
+				return fmt.Errorf("failed to decode %s: %w", k, err)
+			}
+			s.reminders[r.ID] = r
+			return nil
+		})
+	}); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to load reminders: %w", err)
+	}
+	return s, nil
+}
+
+// Close closes the reminder database
+func (s *Scheduler) Close() error {
+	return s.db.Close()
+}
+
+// SetLeadTimes changes how long before due dates reminders are shown.
+// It takes effect on the next call to Update.
+func (s *Scheduler) SetLeadTimes(leadTimes []time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.leadTimes = append([]time.Duration{}, leadTimes...)
+}
+
+// Pending returns the reminders that have not been shown yet, soonest first
+func (s *Scheduler) Pending() []Reminder {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var pending []Reminder
+	for _, r := range s.reminders {
+		if !r.Sent {
+			pending = append(pending, r)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].FireAt.Before(pending[j].FireAt)
+	})
+	return pending
+}
+
+// Update replaces the reminders with those needed for tasks.
+// Open tasks with a due date get a reminder for every lead time, reminders of completed, deleted
+// or rescheduled tasks are dropped, and reminders already shown are remembered until the due date
+// has passed so they are not shown twice.
+func (s *Scheduler) Update(tasks []model.Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+
+	wanted := map[string]Reminder{}
+	for _, t := range tasks {
+		if t.Done || t.DueDate == nil {
+			continue
+		}
+		due := dueAt(*t.DueDate, now.Location())
+		if now.After(due.Add(missedGrace)) {
+			continue
+		}
+		for _, lead := range s.leadTimes {
+			// Identified by the stored due date, which is the same on devices in other zones
+			id := reminderID(t.ID, *t.DueDate, lead)
+			// Reminders not shown yet are rescheduled, for the time zone may have changed since
+			r := s.reminders[id]
+			if !r.Sent {
+				r = Reminder{ID: id, TaskID: t.ID, Due: due, Lead: lead, FireAt: due.Add(-lead)}
+			}
+			r.Title = t.Title
+			wanted[id] = r
+		}
+	}
+
+	if err := s.db.Update(func(tx *bbolt.Tx) error {
+		if err := tx.DeleteBucket(remindersBucket); err != nil && !errors.Is(err, bbolt.ErrBucketNotFound) {
+			return err
+		}
+		b, err := tx.CreateBucket(remindersBucket)
+		if err != nil {
+			return err
+		}
+		for id, r := range wanted {
+			payload, err := json.Marshal(r)
+			if err != nil {
+				return err
+			}
+			if err := b.Put([]byte(id), payload); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to save reminders: %w", err)
+	}
+	s.reminders = wanted
+	return nil
+}
+
+// Fire shows every pending reminder whose time has come.
+// Each reminder is claimed on the server first; one claimed by another device is marked as sent
+// without being shown. When the server cannot be reached the reminder is shown anyway, as a
+// duplicate notification is better than a missed one.
+func (s *Scheduler) Fire(ctx context.Context) error {
+	now := s.now()
+	var due []Reminder
+	for _, r := range s.Pending() {
+		if !r.FireAt.After(now) {
+			due = append(due, r)
+		}
+	}
+
+	// When several reminders of a task are due at once, e.g. after the app was closed for a while,
+	// only the latest one is shown
+	latest := map[string]string{}
+	for _, r := range due {
+		latest[r.TaskID] = r.ID
+	}
+
+	var errs []error
+	for _, r := range due {
+		if latest[r.TaskID] != r.ID {
+			if err := s.markSent(r.ID); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		err := s.claimer.ClaimReminder(ctx, r.ID)
+		if err != nil && !api.IsStatus(err, http.StatusConflict) {
+			errs = append(errs, fmt.Errorf("failed to claim reminder %s: %w", r.ID, err))
+		}
+		if !api.IsStatus(err, http.StatusConflict) {
+			s.notify(r)
+		}
+		if err := s.markSent(r.ID); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Run calls Fire every interval until ctx is done, logging errors through onError
+func (s *Scheduler) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := s.Fire(ctx); err != nil && onError != nil {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// markSent records that a reminder was shown or claimed elsewhere
+func (s *Scheduler) markSent(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.reminders[id]
+	if !ok {
+		// The task changed while the reminder was being shown
+		return nil
+	}
+	r.Sent = true
+	payload, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to encode reminder: %w", err)
+	}
+	if err := s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(remindersBucket).Put([]byte(id), payload)
+	}); err != nil {
+		return fmt.Errorf("failed to save reminder: %w", err)
+	}
+	s.reminders[id] = r
+	return nil
+}
+
+// ParseLeadTimes parses a comma separated list of lead times in minutes, such as "60,1440".
+// An empty string means no reminders.
+func ParseLeadTimes(s string) ([]time.Duration, error) {
+	var leadTimes []time.Duration
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		minutes, err := strconv.Atoi(item)
+		if err != nil || minutes < 0 {
+			return nil, fmt.Errorf("invalid reminder lead time %q", item)
+		}
+		leadTimes = append(leadTimes, time.Duration(minutes)*time.Minute)
+	}
+	return leadTimes, nil
+}
+
+// FormatLeadTimes formats lead times for ParseLeadTimes
+func FormatLeadTimes(leadTimes []time.Duration) string {
+	items := make([]string, len(leadTimes))
+	for i, lead := range leadTimes {
+		items[i] = strconv.FormatInt(int64(lead/time.Minute), 10)
+	}
+	return strings.Join(items, ",")
+}
+
+// FormatLeadTime describes a lead time in the largest whole unit, such as "1 day" or "90 minutes"
+func FormatLeadTime(lead time.Duration) string {
+	plural := func(n int64, unit string) string {
+		if n == 1 {
+			return "1 " + unit
+		}
+		return fmt.Sprintf("%d %ss", n, unit)
+	}
+	switch {
+	case lead >= 7*24*time.Hour && lead%(7*24*time.Hour) == 0:
+		return plural(int64(lead/(7*24*time.Hour)), "week")
+	case lead >= 24*time.Hour && lead%(24*time.Hour) == 0:
+		return plural(int64(lead/(24*time.Hour)), "day")
+	case lead >= time.Hour && lead%time.Hour == 0:
+		return plural(int64(lead/time.Hour), "hour")
+	default:
+		return plural(int64(lead/time.Minute), "minute")
+	}
+}
@@ -0,0 +1,204 @@
+package reminder
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"eldar/api"
+	"eldar/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClaimer stands in for the server, remembering which reminders were claimed
+type fakeClaimer struct {
+	mu      sync.Mutex
+	claimed map[string]bool
+	err     error
+}
+
+func (f *fakeClaimer) ClaimReminder(ctx context.Context, id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	if f.claimed[id] {
+		return &api.Error{StatusCode: http.StatusConflict}
+	}
+	f.claimed[id] = true
+	return nil
+}
+
+// openScheduler opens a scheduler in dir whose clock reads *now and that records shown reminders
+func openScheduler(t *testing.T, dir string, claimer Claimer, now *time.Time, shown *[]Reminder) *Scheduler {
+	s, err := Open(dir, claimer, func(r Reminder) {
+		*shown = append(*shown, r)
+	})
+	require.NoError(t, err)
+	s.now = func() time.Time {
+		return *now
+	}
+	return s
+}
+
+func TestSchedulerFire(t *testing.T) {
+	now := time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)
+	due := time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)
+	tasks := []model.Task{
+		{ID: "t1", Title: "Write report", DueDate: &due},
+		{ID: "t2", Title: "Done already", DueDate: &due, Done: true},
+		{ID: "t3", Title: "No due date"},
+	}
+	var shown []Reminder
+	s := openScheduler(t, t.TempDir(), &fakeClaimer{claimed: map[string]bool{}}, &now, &shown)
+	defer func() {
+		_ = s.Close()
+	}()
+
+	require.NoError(t, s.Update(tasks))
+	pending := s.Pending()
+	require.Len(t, pending, 2)
+	assert.Equal(t, due.Add(-24*time.Hour), pending[0].FireAt)
+	assert.Equal(t, due.Add(-time.Hour), pending[1].FireAt)
+
+	require.NoError(t, s.Fire(context.Background()))
+	assert.Empty(t, shown)
+
+	now = due.Add(-23 * time.Hour)
+	require.NoError(t, s.Fire(context.Background()))
+	require.Len(t, shown, 1)
+	assert.Equal(t, `"Write report" is due in 1 day`, shown[0].Message())
+
+	// A reminder is shown once, even when the tasks are updated again
+	require.NoError(t, s.Update(tasks))
+	require.NoError(t, s.Fire(context.Background()))
+	assert.Len(t, shown, 1)
+
+	// Rescheduling the task replaces its reminders
+	later := due.Add(48 * time.Hour)
+	tasks[0].DueDate = &later
+	require.NoError(t, s.Update(tasks))
+	require.Len(t, s.Pending(), 2)
+	assert.Equal(t, later.Add(-24*time.Hour), s.Pending()[0].FireAt)
+
+	// Completing it drops them
+	tasks[0].Done = true
+	require.NoError(t, s.Update(tasks))
+	assert.Empty(t, s.Pending())
+}
+
+func TestSchedulerSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2025, 6, 1, 8, 0, 0, 0, time.UTC)
+	due := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	var shown []Reminder
+	s := openScheduler(t, dir, &fakeClaimer{claimed: map[string]bool{}}, &now, &shown)
+	s.SetLeadTimes([]time.Duration{2 * time.Hour, time.Hour})
+	require.NoError(t, s.Update([]model.Task{{ID: "t1", Title: "Write report", DueDate: &due}}))
+	require.NoError(t, s.Close())
+
+	// The app was closed while the reminders were due, the latest is shown on the next start
+	now = due.Add(-30 * time.Minute)
+	s = openScheduler(t, dir, &fakeClaimer{claimed: map[string]bool{}}, &now, &shown)
+	defer func() {
+		_ = s.Close()
+	}()
+	require.Len(t, s.Pending(), 2)
+	require.NoError(t, s.Fire(context.Background()))
+	require.Len(t, shown, 1)
+	assert.Equal(t, "t1", shown[0].TaskID)
+	assert.Equal(t, time.Hour, shown[0].Lead)
+	assert.Empty(t, s.Pending())
+}
+
+func TestSchedulerDeduplicatesAcrossDevices(t *testing.T) {
+	now := time.Date(2025, 6, 1, 11, 30, 0, 0, time.UTC)
+	due := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tasks := []model.Task{{ID: "t1", Title: "Write report", DueDate: &due}}
+	server := &fakeClaimer{claimed: map[string]bool{}}
+
+	var laptopShown, desktopShown []Reminder
+	laptop := openScheduler(t, t.TempDir(), server, &now, &laptopShown)
+	desktop := openScheduler(t, t.TempDir(), server, &now, &desktopShown)
+	defer func() {
+		_ = laptop.Close()
+		_ = desktop.Close()
+	}()
+	for _, s := range []*Scheduler{laptop, desktop} {
+		s.SetLeadTimes([]time.Duration{time.Hour})
+		require.NoError(t, s.Update(tasks))
+	}
+
+	require.NoError(t, laptop.Fire(context.Background()))
+	require.NoError(t, desktop.Fire(context.Background()))
+	assert.Len(t, laptopShown, 1)
+	assert.Empty(t, desktopShown)
+	assert.Empty(t, desktop.Pending())
+}
+
+func TestSchedulerOffline(t *testing.T) {
+	now := time.Date(2025, 6, 1, 11, 30, 0, 0, time.UTC)
+	due := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	var shown []Reminder
+	s := openScheduler(t, t.TempDir(), &fakeClaimer{err: errors.New("offline")}, &now, &shown)
+	defer func() {
+		_ = s.Close()
+	}()
+	s.SetLeadTimes([]time.Duration{time.Hour})
+	require.NoError(t, s.Update([]model.Task{{ID: "t1", Title: "Write report", DueDate: &due}}))
+
+	// The reminder is shown even though it could not be claimed
+	assert.ErrorContains(t, s.Fire(context.Background()), "offline")
+	assert.Len(t, shown, 1)
+	assert.Empty(t, s.Pending())
+}
+
+func TestSchedulerDateOnlyDueDates(t *testing.T) {
+	// Date-only due dates are stored at midnight UTC, reminders come at the same local time in every zone
+	due := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	tasks := []model.Task{{ID: "t1", Title: "Write report", DueDate: &due}}
+	for _, loc := range []*time.Location{time.FixedZone("UTC-5", -5*60*60), time.FixedZone("UTC+10", 10*60*60)} {
+		now := time.Date(2025, 6, 1, 8, 0, 0, 0, loc)
+		var shown []Reminder
+		s := openScheduler(t, t.TempDir(), &fakeClaimer{claimed: map[string]bool{}}, &now, &shown)
+		require.NoError(t, s.Update(tasks))
+		pending := s.Pending()
+		require.Len(t, pending, 2, loc)
+		assert.True(t, time.Date(2025, 6, 1, 9, 0, 0, 0, loc).Equal(pending[0].FireAt), loc)
+		assert.True(t, time.Date(2025, 6, 2, 8, 0, 0, 0, loc).Equal(pending[1].FireAt), loc)
+		// The reminders are the same on devices in other zones, so only one of them shows each
+		assert.Equal(t, reminderID("t1", due, time.Hour), pending[1].ID)
+
+		// They are kept until the local due day is well under way
+		now = time.Date(2025, 6, 2, 20, 0, 0, 0, loc)
+		require.NoError(t, s.Update(tasks))
+		assert.Len(t, s.Pending(), 2, loc)
+		now = time.Date(2025, 6, 2, 21, 30, 0, 0, loc)
+		require.NoError(t, s.Update(tasks))
+		assert.Empty(t, s.Pending(), loc)
+		require.NoError(t, s.Close())
+	}
+}
+
+func TestLeadTimes(t *testing.T) {
+	leadTimes, err := ParseLeadTimes(" 60, 1440,,15")
+	require.NoError(t, err)
+	assert.Equal(t, []time.Duration{time.Hour, 24 * time.Hour, 15 * time.Minute}, leadTimes)
+	assert.Equal(t, "60,1440,15", FormatLeadTimes(leadTimes))
+
+	_, err = ParseLeadTimes("soon")
+	assert.Error(t, err)
+	leadTimes, err = ParseLeadTimes("")
+	require.NoError(t, err)
+	assert.Empty(t, leadTimes)
+
+	assert.Equal(t, "1 week", FormatLeadTime(7*24*time.Hour))
+	assert.Equal(t, "2 days", FormatLeadTime(48*time.Hour))
+	assert.Equal(t, "1 hour", FormatLeadTime(time.Hour))
+	assert.Equal(t, "90 minutes", FormatLeadTime(90*time.Minute))
+}
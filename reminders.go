@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"eldar/reminder"
+	"eldar/store"
+	"eldar/ui"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+)
+
+// reminders shows due-date notifications for the signed-in account, it is nil while signed out
+var reminders *reminder.Scheduler
+
+// stopReminders stops the goroutine firing reminders
+var stopReminders context.CancelFunc
+
+// reminderLeadTimes reads how long before due dates reminders are shown from the preferences
+func reminderLeadTimes(prefs fyne.Preferences) []time.Duration {
+	leadTimes, err := reminder.ParseLeadTimes(prefs.StringWithFallback("reminder_lead_minutes", reminder.FormatLeadTimes(reminder.DefaultLeadTimes)))
+	if err != nil {
+		log.Printf("Error reading reminder lead times: %v", err)
+		return reminder.DefaultLeadTimes
+	}
+	return leadTimes
+}
+
+// openReminders opens the reminder scheduler in the account data directory dir,
+// keeps it in step with the task cache st and starts firing reminders in the background
+func openReminders(dir string, st *store.Store) error {
+	scheduler, err := reminder.Open(dir, apiClient, func(r reminder.Reminder) {
+		fyne.CurrentApp().SendNotification(fyne.NewNotification("Task due soon", r.Message()))
+	})
+	if err != nil {
+		return err
+	}
+	scheduler.SetLeadTimes(reminderLeadTimes(fyne.CurrentApp().Preferences()))
+	update := func() {
+		if err := scheduler.Update(st.AllTasks()); err != nil {
+			log.Printf("Error updating reminders: %v", err)
+		}
+	}
+	update()
+	st.OnChange(update)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	reminders, stopReminders = scheduler, cancel
+	go scheduler.Run(ctx, time.Minute, func(err error) {
+		log.Printf("Error firing reminders: %v", err)
+	})
+	return nil
+}
+
+// closeReminders stops and closes the reminder scheduler, if any
+func closeReminders() {
+	if reminders == nil {
+		return
+	}
+	stopReminders()
+	if err := reminders.Close(); err != nil {
+		log.Printf("Error closing reminders: %v", err)
+	}
+	reminders, stopReminders = nil, nil
+}
+
+// showReminderSettings opens a dialog to choose how long before due dates reminders are shown
+func showReminderSettings() {
+	prefs := fyne.CurrentApp().Preferences()
+	var d dialog.Dialog
+	form := ui.MakeReminderSettingsForm(reminderLeadTimes(prefs), func(leadTimes []time.Duration) {
+		prefs.SetString("reminder_lead_minutes", reminder.FormatLeadTimes(leadTimes))
+		if reminders != nil && taskStore != nil {
+			reminders.SetLeadTimes(leadTimes)
+			if err := reminders.Update(taskStore.AllTasks()); err != nil {
+				log.Printf("Error updating reminders: %v", err)
+				dialog.ShowError(err, w)
+			}
+		}
+		d.Hide()
+	})
+	form.OnCancel = func() {
+		d.Hide()
+	}
+	d = dialog.NewCustomWithoutButtons("Reminder settings", form, w)
+	d.Show()
+}
@@ -0,0 +1,457 @@
+// Package rrule parses and expands the recurrence rules of RFC 5545 section 3.3.10.
+//
+// The supported subset covers the schedules offered by the client: FREQ (DAILY, WEEKLY, MONTHLY
+// or YEARLY), INTERVAL, COUNT, UNTIL, BYDAY, BYMONTHDAY, BYMONTH and WKST. Other rule parts are
+// rejected rather than silently ignored. Occurrences keep the wall clock time of the start in its
+// location, so a 09:00 meeting stays at 09:00 across daylight saving time changes.
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxPeriods bounds the expansion of rules whose filters never or rarely match, e.g. the 30th of February
+const maxPeriods = 100000
+
+// Frequency is the period a rule repeats over
+type Frequency int
+
+// Rule frequencies
+const (
+	Daily Frequency = iota
+	Weekly
+	Monthly
+	Yearly
+)
+
+// String returns the FREQ value of a Frequency
+func (f Frequency) String() string {
+	switch f {
+	case Daily:
+		return "DAILY"
+	case Weekly:
+		return "WEEKLY"
+	case Monthly:
+		return "MONTHLY"
+	case Yearly:
+		return "YEARLY"
+	default:
+		return fmt.Sprintf("Frequency(%d)", int(f))
+	}
+}
+
+// WeekdayNum is a BYDAY value, such as MO, or 1MO for the first Monday and -1FR for the last Friday
+type WeekdayNum struct {
+	// N is the ordinal within the month, 0 meaning every such weekday
+	N   int
+	Day time.Weekday
+}
+
+// weekdayCodes are the two letter weekday codes of RFC 5545, indexed by time.Weekday
+var weekdayCodes = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// String returns the BYDAY representation of a WeekdayNum
+func (w WeekdayNum) String() string {
+	if w.N == 0 {
+		return weekdayCodes[w.Day]
+	}
+	return strconv.Itoa(w.N) + weekdayCodes[w.Day]
+}
+
+// Rule is a parsed recurrence rule
+type Rule struct {
+	Freq     Frequency
+	Interval int
+	// Count limits the number of occurrences, 0 meaning no limit
+	Count int
+	// Until is the last allowed occurrence, the zero time meaning no limit
+	Until time.Time
+	// UntilFloating is set when Until was given without a time zone and applies in the start location
+	UntilFloating bool
+	ByDay         []WeekdayNum
+	ByMonthDay    []int
+	ByMonth       []time.Month
+	WeekStart     time.Weekday
+}
+
+// Parse parses a recurrence rule such as "FREQ=WEEKLY;BYDAY=MO,WE". An "RRULE:" prefix is accepted.
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return nil, errors.New("empty recurrence rule")
+	}
+
+	r := &Rule{Interval: 1, WeekStart: time.Monday}
+	hasFreq := false
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("invalid rule part %q", part)
+		}
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			hasFreq = true
+			r.Freq, err = parseFrequency(value)
+		case "INTERVAL":
+			r.Interval, err = parsePositive(value)
+		case "COUNT":
+			r.Count, err = parsePositive(value)
+		case "UNTIL":
+			r.Until, r.UntilFloating, err = parseUntil(value)
+		case "BYDAY":
+			r.ByDay, err = parseList(value, parseWeekdayNum)
+		case "BYMONTHDAY":
+			r.ByMonthDay, err = parseList(value, parseMonthDay)
+		case "BYMONTH":
+			r.ByMonth, err = parseList(value, parseMonth)
+		case "WKST":
+			var w WeekdayNum
+			w, err = parseWeekdayNum(value)
+			if err == nil && w.N != 0 {
+				err = fmt.Errorf("invalid week start %q", value)
+			}
+			r.WeekStart = w.Day
+		default:
+			err = fmt.Errorf("unsupported rule part %s", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !hasFreq {
+		return nil, errors.New("recurrence rule has no FREQ")
+	}
+	if r.Count > 0 && !r.Until.IsZero() {
+		return nil, errors.New("recurrence rule cannot have both COUNT and UNTIL")
+	}
+	for _, w := range r.ByDay {
+		if w.N == 0 {
+			continue
+		}
+		if r.Freq != Monthly && r.Freq != Yearly {
+			return nil, fmt.Errorf("BYDAY %s needs a MONTHLY or YEARLY rule", w)
+		}
+		if r.Freq == Yearly && len(r.ByMonth) == 0 {
+			return nil, fmt.Errorf("BYDAY %s needs BYMONTH in a YEARLY rule", w)
+		}
+	}
+	if r.Freq == Weekly && len(r.ByMonthDay) > 0 {
+		return nil, errors.New("BYMONTHDAY cannot be used in a WEEKLY rule")
+	}
+	return r, nil
+}
+
+// String returns the rule in RFC 5545 form, without the "RRULE:" prefix
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq.String()}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		if r.UntilFloating {
+			parts = append(parts, "UNTIL="+r.Until.Format("20060102T150405"))
+		} else {
+			parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+		}
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, w := range r.ByDay {
+			days[i] = w.String()
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, d := range r.ByMonthDay {
+			days[i] = strconv.Itoa(d)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonth) > 0 {
+		months := make([]string, len(r.ByMonth))
+		for i, m := range r.ByMonth {
+			months[i] = strconv.Itoa(int(m))
+		}
+		parts = append(parts, "BYMONTH="+strings.Join(months, ","))
+	}
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+weekdayCodes[r.WeekStart])
+	}
+	return strings.Join(parts, ";")
+}
+
+// Occurrences returns up to limit occurrences of the rule starting at start, which is the first one
+// when it matches the rule
+func (r *Rule) Occurrences(start time.Time, limit int) []time.Time {
+	var occurrences []time.Time
+	r.each(start, func(t time.Time) bool {
+		occurrences = append(occurrences, t)
+		return len(occurrences) < limit
+	})
+	return occurrences
+}
+
+// Next returns the first occurrence of the rule starting at start that is strictly after after.
+// It returns false when the rule has no further occurrence because of COUNT or UNTIL.
+func (r *Rule) Next(start, after time.Time) (time.Time, bool) {
+	var next time.Time
+	found := false
+	r.each(start, func(t time.Time) bool {
+		if t.After(after) {
+			next, found = t, true
+			return false
+		}
+		return true
+	})
+	return next, found
+}
+
+// each calls fn with every occurrence in order until fn returns false or the rule ends
+func (r *Rule) each(start time.Time, fn func(time.Time) bool) {
+	until := r.Until
+	if r.UntilFloating {
+		until = time.Date(until.Year(), until.Month(), until.Day(), until.Hour(), until.Minute(), until.Second(), 0, start.Location())
+	}
+
+	count := 0
+	for period := 0; period < maxPeriods; period++ {
+		for _, t := range r.candidates(start, period) {
+			if t.Before(start) {
+				continue
+			}
+			if !until.IsZero() && t.After(until) {
+				return
+			}
+			count++
+			if !fn(t) || (r.Count > 0 && count >= r.Count) {
+				return
+			}
+		}
+	}
+}
+
+// candidates returns the sorted occurrences within the given period after the start period
+func (r *Rule) candidates(start time.Time, period int) []time.Time {
+	year, month, day := start.Date()
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), start.Location())
+	}
+
+	var days []time.Time
+	switch r.Freq {
+	case Daily:
+		t := at(year, month, day+period*r.Interval)
+		if r.matchesDay(t) {
+			days = append(days, t)
+		}
+	case Weekly:
+		offset := (int(start.Weekday()) - int(r.WeekStart) + 7) % 7
+		weekStart := day - offset + period*r.Interval*7
+		for i := 0; i < 7; i++ {
+			t := at(year, month, weekStart+i)
+			if len(r.ByDay) == 0 && t.Weekday() != start.Weekday() {
+				continue
+			}
+			if r.matchesDay(t) {
+				days = append(days, t)
+			}
+		}
+	case Monthly:
+		first := time.Date(year, month+time.Month(period*r.Interval), 1, 0, 0, 0, 0, time.UTC)
+		if len(r.ByMonth) == 0 || slices.Contains(r.ByMonth, first.Month()) {
+			for _, d := range r.monthDays(first.Year(), first.Month(), day) {
+				days = append(days, at(first.Year(), first.Month(), d))
+			}
+		}
+	case Yearly:
+		y := year + period*r.Interval
+		months := r.ByMonth
+		if len(months) == 0 {
+			months = []time.Month{month}
+			if len(r.ByDay) > 0 && len(r.ByMonthDay) == 0 {
+				months = []time.Month{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+			}
+		}
+		for _, m := range slices.Sorted(slices.Values(months)) {
+			for _, d := range r.monthDays(y, m, day) {
+				days = append(days, at(y, m, d))
+			}
+		}
+	}
+	return days
+}
+
+// matchesDay applies the BYMONTH, BYMONTHDAY and plain BYDAY filters to a daily or weekly candidate
+func (r *Rule) matchesDay(t time.Time) bool {
+	if len(r.ByMonth) > 0 && !slices.Contains(r.ByMonth, t.Month()) {
+		return false
+	}
+	if len(r.ByMonthDay) > 0 {
+		n := daysIn(t.Year(), t.Month())
+		if !slices.ContainsFunc(r.ByMonthDay, func(d int) bool {
+			return d == t.Day() || n+1+d == t.Day()
+		}) {
+			return false
+		}
+	}
+	if len(r.ByDay) > 0 && !slices.ContainsFunc(r.ByDay, func(w WeekdayNum) bool {
+		return w.Day == t.Weekday()
+	}) {
+		return false
+	}
+	return true
+}
+
+// monthDays returns the sorted days of a month selected by BYMONTHDAY and BYDAY, defaulting to
+// startDay. Days that do not exist in the month, such as the 31st of April, are skipped.
+func (r *Rule) monthDays(year int, month time.Month, startDay int) []int {
+	n := daysIn(year, month)
+	var byMonthDay, byDay map[int]bool
+	if len(r.ByMonthDay) > 0 {
+		byMonthDay = map[int]bool{}
+		for _, d := range r.ByMonthDay {
+			if d < 0 {
+				d = n + 1 + d
+			}
+			if d >= 1 && d <= n {
+				byMonthDay[d] = true
+			}
+		}
+	}
+	if len(r.ByDay) > 0 {
+		byDay = map[int]bool{}
+		firstWeekday := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC).Weekday()
+		for _, w := range r.ByDay {
+			first := 1 + (int(w.Day)-int(firstWeekday)+7)%7
+			var matches []int
+			for d := first; d <= n; d += 7 {
+				matches = append(matches, d)
+			}
+			switch {
+			case w.N == 0:
+				for _, d := range matches {
+					byDay[d] = true
+				}
+			case w.N > 0 && w.N <= len(matches):
+				byDay[matches[w.N-1]] = true
+			case w.N < 0 && -w.N <= len(matches):
+				byDay[matches[len(matches)+w.N]] = true
+			}
+		}
+	}
+
+	var days []int
+	for d := 1; d <= n; d++ {
+		switch {
+		case byMonthDay == nil && byDay == nil:
+			if d == startDay {
+				days = append(days, d)
+			}
+		case (byMonthDay == nil || byMonthDay[d]) && (byDay == nil || byDay[d]):
+			days = append(days, d)
+		}
+	}
+	return days
+}
+
+// daysIn returns the number of days of a month
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// parseFrequency parses a FREQ value
+func parseFrequency(s string) (Frequency, error) {
+	for f := Daily; f <= Yearly; f++ {
+		if strings.EqualFold(f.String(), s) {
+			return f, nil
+		}
+	}
+	return 0, fmt.Errorf("unsupported frequency %q", s)
+}
+
+// parsePositive parses a strictly positive integer
+func parsePositive(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid positive number %q", s)
+	}
+	return n, nil
+}
+
+// parseUntil parses an UNTIL value given as a UTC date-time, a floating date-time or a date
+func parseUntil(s string) (time.Time, bool, error) {
+	if t, err := time.Parse("20060102T150405Z", s); err == nil {
+		return t, false, nil
+	}
+	if t, err := time.Parse("20060102T150405", s); err == nil {
+		return t, true, nil
+	}
+	if t, err := time.Parse("20060102", s); err == nil {
+		// A date includes the whole day
+		return t.Add(24*time.Hour - time.Second), true, nil
+	}
+	return time.Time{}, false, fmt.Errorf("invalid UNTIL %q", s)
+}
+
+// parseList parses a comma separated list of values
+func parseList[T any](s string, parse func(string) (T, error)) ([]T, error) {
+	var values []T
+	for _, item := range strings.Split(s, ",") {
+		value, err := parse(item)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// parseWeekdayNum parses a BYDAY value such as MO, 2TU or -1FR
+func parseWeekdayNum(s string) (WeekdayNum, error) {
+	if len(s) < 2 {
+		return WeekdayNum{}, fmt.Errorf("invalid weekday %q", s)
+	}
+	code := strings.ToUpper(s[len(s)-2:])
+	day := slices.Index(weekdayCodes, code)
+	if day < 0 {
+		return WeekdayNum{}, fmt.Errorf("invalid weekday %q", s)
+	}
+	w := WeekdayNum{Day: time.Weekday(day)}
+	if ordinal := s[:len(s)-2]; ordinal != "" {
+		n, err := strconv.Atoi(ordinal)
+		if err != nil || n == 0 || n < -5 || n > 5 {
+			return WeekdayNum{}, fmt.Errorf("invalid weekday ordinal %q", s)
+		}
+		w.N = n
+	}
+	return w, nil
+}
+
+// parseMonthDay parses a BYMONTHDAY value between 1 and 31, or -31 and -1 counting from the month end
+func parseMonthDay(s string) (int, error) {
+	d, err := strconv.Atoi(s)
+	if err != nil || d == 0 || d < -31 || d > 31 {
+		return 0, fmt.Errorf("invalid month day %q", s)
+	}
+	return d, nil
+}
+
+// parseMonth parses a BYMONTH value between 1 and 12
+func parseMonth(s string) (time.Month, error) {
+	m, err := strconv.Atoi(s)
+	if err != nil || m < 1 || m > 12 {
+		return 0, fmt.Errorf("invalid month %q", s)
+	}
+	return time.Month(m), nil
+}
@@ -0,0 +1,172 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mustLocation loads a time zone, failing the test when the zone database is unavailable
+func mustLocation(t *testing.T, name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	require.NoError(t, err)
+	return loc
+}
+
+// format renders occurrences compactly with their UTC offset
+func format(times []time.Time) []string {
+	formatted := make([]string, len(times))
+	for i, t := range times {
+		formatted[i] = t.Format("2006-01-02 15:04 -0700")
+	}
+	return formatted
+}
+
+func TestParse(t *testing.T) {
+	for _, rule := range []string{
+		"FREQ=DAILY",
+		"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE,FR",
+		"FREQ=MONTHLY;COUNT=12;BYDAY=-1FR",
+		"FREQ=MONTHLY;BYMONTHDAY=1,15,-1",
+		"FREQ=YEARLY;UNTIL=20300101T000000Z;BYDAY=2SU;BYMONTH=3",
+		"FREQ=WEEKLY;BYDAY=SU;WKST=SU",
+	} {
+		r, err := Parse(rule)
+		require.NoError(t, err, rule)
+		assert.Equal(t, rule, r.String())
+	}
+
+	r, err := Parse("RRULE:freq=weekly;byday=mo")
+	require.NoError(t, err)
+	assert.Equal(t, "FREQ=WEEKLY;BYDAY=MO", r.String())
+
+	for rule, msg := range map[string]string{
+		"":                                  "empty",
+		"INTERVAL=2":                        "no FREQ",
+		"FREQ=HOURLY":                       "unsupported frequency",
+		"FREQ=DAILY;BYHOUR=9":               "unsupported rule part",
+		"FREQ=DAILY;INTERVAL=0":             "invalid positive number",
+		"FREQ=DAILY;COUNT=2;UNTIL=203001":   "invalid UNTIL",
+		"FREQ=DAILY;COUNT=2;UNTIL=20300101": "both COUNT and UNTIL",
+		"FREQ=WEEKLY;BYDAY=1MO":             "needs a MONTHLY or YEARLY rule",
+		"FREQ=YEARLY;BYDAY=1MO":             "needs BYMONTH",
+		"FREQ=MONTHLY;BYDAY=XX":             "invalid weekday",
+		"FREQ=MONTHLY;BYMONTHDAY=32":        "invalid month day",
+		"FREQ=YEARLY;BYMONTH=13":            "invalid month",
+		"FREQ=WEEKLY;BYMONTHDAY=1":          "cannot be used in a WEEKLY rule",
+	} {
+		_, err := Parse(rule)
+		assert.ErrorContains(t, err, msg, rule)
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	start := time.Date(2025, 1, 31, 10, 0, 0, 0, time.UTC)
+	for rule, want := range map[string][]string{
+		// Months without a 31st are skipped
+		"FREQ=MONTHLY;COUNT=4":                        {"2025-01-31", "2025-03-31", "2025-05-31", "2025-07-31"},
+		"FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=3":          {"2025-01-31", "2025-02-28", "2025-03-31"},
+		"FREQ=MONTHLY;BYDAY=-1FR;COUNT=3":             {"2025-01-31", "2025-02-28", "2025-03-28"},
+		"FREQ=MONTHLY;BYDAY=2TU;COUNT=2":              {"2025-02-11", "2025-03-11"},
+		"FREQ=WEEKLY;BYDAY=MO,FR;COUNT=4":             {"2025-01-31", "2025-02-03", "2025-02-07", "2025-02-10"},
+		"FREQ=WEEKLY;INTERVAL=2;COUNT=3":              {"2025-01-31", "2025-02-14", "2025-02-28"},
+		"FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR;COUNT=3":     {"2025-01-31", "2025-02-03", "2025-02-04"},
+		"FREQ=DAILY;UNTIL=20250202":                   {"2025-01-31", "2025-02-01", "2025-02-02"},
+		"FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=29;COUNT=2": {"2028-02-29", "2032-02-29"},
+		"FREQ=YEARLY;COUNT=2":                         {"2025-01-31", "2026-01-31"},
+	} {
+		r, err := Parse(rule)
+		require.NoError(t, err, rule)
+		occurrences := r.Occurrences(start, 10)
+		days := make([]string, len(occurrences))
+		for i, o := range occurrences {
+			days[i] = o.Format("2006-01-02")
+			assert.Equal(t, 10, o.Hour(), rule)
+		}
+		assert.Equal(t, want, days, rule)
+	}
+
+	// A rule that never matches ends instead of looping forever
+	r, err := Parse("FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30")
+	require.NoError(t, err)
+	assert.Empty(t, r.Occurrences(start, 1))
+}
+
+func TestNext(t *testing.T) {
+	r, err := Parse("FREQ=WEEKLY;BYDAY=MO;COUNT=3")
+	require.NoError(t, err)
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+
+	next, ok := r.Next(start, start)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2025, 6, 9, 9, 0, 0, 0, time.UTC), next)
+
+	// Completing an occurrence late still moves to the following one
+	next, ok = r.Next(start, time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC))
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2025, 6, 16, 9, 0, 0, 0, time.UTC), next)
+
+	// COUNT=3 ends the series after the third Monday
+	_, ok = r.Next(start, time.Date(2025, 6, 16, 9, 0, 0, 0, time.UTC))
+	assert.False(t, ok)
+}
+
+func TestOccurrencesAcrossDST(t *testing.T) {
+	berlin := mustLocation(t, "Europe/Berlin")
+	newYork := mustLocation(t, "America/New_York")
+
+	tests := []struct {
+		name  string
+		rule  string
+		start time.Time
+		want  []string
+	}{
+		{
+			name:  "weekly across spring forward in Berlin",
+			rule:  "FREQ=WEEKLY;COUNT=3",
+			start: time.Date(2025, 3, 24, 9, 0, 0, 0, berlin),
+			want:  []string{"2025-03-24 09:00 +0100", "2025-03-31 09:00 +0200", "2025-04-07 09:00 +0200"},
+		},
+		{
+			name:  "daily across fall back in Berlin",
+			rule:  "FREQ=DAILY;COUNT=3",
+			start: time.Date(2025, 10, 25, 9, 0, 0, 0, berlin),
+			want:  []string{"2025-10-25 09:00 +0200", "2025-10-26 09:00 +0100", "2025-10-27 09:00 +0100"},
+		},
+		{
+			name:  "monthly across both changes in New York",
+			rule:  "FREQ=MONTHLY;BYDAY=1MO;COUNT=4",
+			start: time.Date(2025, 2, 3, 8, 30, 0, 0, newYork),
+			want:  []string{"2025-02-03 08:30 -0500", "2025-03-03 08:30 -0500", "2025-04-07 08:30 -0400", "2025-05-05 08:30 -0400"},
+		},
+		{
+			name:  "daily on the day of the change in New York",
+			rule:  "FREQ=DAILY;COUNT=3",
+			start: time.Date(2025, 3, 8, 12, 0, 0, 0, newYork),
+			want:  []string{"2025-03-08 12:00 -0500", "2025-03-09 12:00 -0400", "2025-03-10 12:00 -0400"},
+		},
+		{
+			name:  "floating until applies in the start location",
+			rule:  "FREQ=DAILY;UNTIL=20251026T090000",
+			start: time.Date(2025, 10, 25, 9, 0, 0, 0, berlin),
+			want:  []string{"2025-10-25 09:00 +0200", "2025-10-26 09:00 +0100"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := Parse(tt.rule)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, format(r.Occurrences(tt.start, 10)))
+		})
+	}
+
+	// The absolute gap between weekly occurrences shrinks by the hour lost to DST
+	r, err := Parse("FREQ=WEEKLY")
+	require.NoError(t, err)
+	start := time.Date(2025, 3, 24, 9, 0, 0, 0, berlin)
+	next, ok := r.Next(start, start)
+	require.True(t, ok)
+	assert.Equal(t, 7*24*time.Hour-time.Hour, next.Sub(start))
+}
@@ -0,0 +1,331 @@
+// Package search provides offline full-text search over the cached tasks.
+// An inverted index of the task text is kept in a bbolt database and updated incrementally,
+// and queries combine free text with field filters such as assignee:me or due:<7d.
+package search
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"eldar/model"
+	"go.etcd.io/bbolt"
+)
+
+// Bucket names of the index database
+var (
+	// termsBucket holds a nested bucket per term whose keys are the IDs of the tasks containing it
+	termsBucket = []byte("terms")
+	// docsBucket holds the indexed state of every task by ID, see document
+	docsBucket = []byte("docs")
+)
+
+// document is what the index remembers about an indexed task
+type document struct {
+	// Hash identifies the indexed text, so unchanged tasks are skipped
+	Hash  string   `json:"hash"`
+	Terms []string `json:"terms"`
+}
+
+// Index is an inverted index of the task text. It is safe for concurrent use.
+type Index struct {
+	db *bbolt.DB
+}
+
+// Open opens the index database in dir, creating it if needed.
+//
+// Parameters:
+//   - dir: The account data directory, see credentials.AccountDataDir
+//
+// Returns:
+//   - The opened Index, which must be closed with Close
+//   - An error if the database could not be opened
+func Open(dir string) (*Index, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create index directory: %w", err)
+	}
+	db, err := bbolt.Open(filepath.Join(dir, "search.db"), 0600, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open index database: %w", err)
+	}
+	if err := db.Update(func(tx *bbolt.Tx) error {
+		for _, name := range [][]byte{termsBucket, docsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return fmt.Errorf("failed to create bucket %s: %w", name, err)
+			}
+		}
+		return nil
+	}); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return &Index{db: db}, nil
+}
+
+// Close closes the index database
+func (ix *Index) Close() error {
+	return ix.db.Close()
+}
+
+// Update brings the index in line with tasks, the complete list of tasks to search, and their comments.
+// Only tasks whose text changed are reindexed and tasks that are gone are removed,
+// so it is cheap to call after every sync or edit.
+//
+// Returns:
+//   - The number of tasks added, reindexed or removed
+//   - An error if the index could not be saved
+func (ix *Index) Update(tasks []model.Task, comments []model.Comment) (int, error) {
+	commentsByTask := map[string][]model.Comment{}
+	for _, c := range comments {
+		commentsByTask[c.TaskID] = append(commentsByTask[c.TaskID], c)
+	}
+	changed := 0
+	err := ix.db.Update(func(tx *bbolt.Tx) error {
+		terms, docs := tx.Bucket(termsBucket), tx.Bucket(docsBucket)
+		seen := map[string]bool{}
+		for _, task := range tasks {
+			seen[task.ID] = true
+			text := taskText(task, commentsByTask[task.ID])
+			hash := sha256.Sum256([]byte(text))
+			doc := document{Hash: hex.EncodeToString(hash[:]), Terms: uniqueTerms(text)}
+
+			previous, err := loadDocument(docs, task.ID)
+			if err != nil {
+				return err
+			}
+			if previous != nil && previous.Hash == doc.Hash {
+				continue
+			}
+			if previous != nil {
+				if err := removePostings(terms, task.ID, previous.Terms); err != nil {
+					return err
+				}
+			}
+			for _, term := range doc.Terms {
+				b, err := terms.CreateBucketIfNotExists([]byte(term))
+				if err != nil {
+					return fmt.Errorf("failed to index term %q: %w", term, err)
+				}
+				if err := b.Put([]byte(task.ID), nil); err != nil {
+					return fmt.Errorf("failed to index term %q: %w", term, err)
+				}
+			}
+			payload, err := json.Marshal(doc)
+			if err != nil {
+				return fmt.Errorf("failed to encode document: %w", err)
+			}
+			if err := docs.Put([]byte(task.ID), payload); err != nil {
+				return fmt.Errorf("failed to save document: %w", err)
+			}
+			changed++
+		}
+
+		// Collect first, bbolt cursors must not be used while deleting
+		var removed []string
+		if err := docs.ForEach(func(k, _ []byte) error {
+			if !seen[string(k)] {
+				removed = append(removed, string(k))
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		for _, id := range removed {
+			previous, err := loadDocument(docs, id)
+			if err != nil {
+				return err
+			}
+			if err := removePostings(terms, id, previous.Terms); err != nil {
+				return err
+			}
+			if err := docs.Delete([]byte(id)); err != nil {
+				return fmt.Errorf("failed to remove document: %w", err)
+			}
+			changed++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to update index: %w", err)
+	}
+	return changed, nil
+}
+
+// Lookup returns the IDs of the tasks containing every term.
+// A term also matches the words it is a prefix of, so results follow the query as it is typed.
+func (ix *Index) Lookup(terms []string) (map[string]bool, error) {
+	var matches map[string]bool
+	err := ix.db.View(func(tx *bbolt.Tx) error {
+		for _, term := range terms {
+			ids := map[string]bool{}
+			prefix := []byte(term)
+			c := tx.Bucket(termsBucket).Cursor()
+			for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+				if err := tx.Bucket(termsBucket).Bucket(k).ForEach(func(id, _ []byte) error {
+					if matches == nil || matches[string(id)] {
+						ids[string(id)] = true
+					}
+					return nil
+				}); err != nil {
+					return err
+				}
+			}
+			matches = ids
+			if len(matches) == 0 {
+				return nil
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search index: %w", err)
+	}
+	return matches, nil
+}
+
+// Search runs a query over tasks, the complete list of tasks to search.
+// Results are ordered by the sort: keys of the query, then with title matches first,
+// then by due date, then by title.
+func (ix *Index) Search(query string, tasks []model.Task, env Env) ([]model.Task, error) {
+	q, err := Parse(query)
+	if err != nil {
+		return nil, err
+	}
+	if q.Empty() {
+		return nil, nil
+	}
+
+	var ids map[string]bool
+	if len(q.Terms) > 0 {
+		if ids, err = ix.Lookup(q.Terms); err != nil {
+			return nil, err
+		}
+	}
+	if env.Blocked == nil {
+		byID := make(map[string]model.Task, len(tasks))
+		for _, t := range tasks {
+			byID[t.ID] = t
+		}
+		env.Blocked = func(id string) bool {
+			return len(model.OpenBlockers(id, byID)) > 0
+		}
+	}
+
+	var results []model.Task
+	for _, task := range tasks {
+		if (ids == nil || ids[task.ID]) && q.Match(task, env) {
+			results = append(results, task)
+		}
+	}
+	inTitle := func(task model.Task) bool {
+		words := uniqueTerms(task.Title)
+		for _, term := range q.Terms {
+			if !containsPrefix(words, term) {
+				return false
+			}
+		}
+		return true
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if ta, tb := inTitle(a), inTitle(b); ta != tb {
+			return ta
+		}
+		if (a.DueDate == nil) != (b.DueDate == nil) {
+			return a.DueDate != nil
+		}
+		if a.DueDate != nil {
+			if da, db := model.DueIn(*a.DueDate, env.Now.Location()), model.DueIn(*b.DueDate, env.Now.Location()); !da.Equal(db) {
+				return da.Before(db)
+			}
+		}
+		return a.Title < b.Title
+	})
+	sortTasks(results, q.sorts, env)
+	return results, nil
+}
+
+// Tokenize splits text into lower case words of letters and digits
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// taskText returns the searchable text of a task and its comments
+func taskText(task model.Task, comments []model.Comment) string {
+	parts := []string{task.Title, task.Description}
+	for _, item := range task.Checklist {
+		parts = append(parts, item.Text)
+	}
+	// Sorted so the text, and with it the hash, does not depend on the order of the comments
+	sort.Slice(comments, func(i, j int) bool {
+		return comments[i].ID < comments[j].ID
+	})
+	for _, c := range comments {
+		parts = append(parts, c.Body)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// uniqueTerms returns the sorted distinct words of text
+func uniqueTerms(text string) []string {
+	terms := Tokenize(text)
+	sort.Strings(terms)
+	unique := terms[:0]
+	for i, term := range terms {
+		if i == 0 || term != terms[i-1] {
+			unique = append(unique, term)
+		}
+	}
+	return unique
+}
+
+// containsPrefix reports whether one of words starts with prefix
+func containsPrefix(words []string, prefix string) bool {
+	for _, w := range words {
+		if strings.HasPrefix(w, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadDocument returns the indexed state of a task, or nil when it is not indexed
+func loadDocument(docs *bbolt.Bucket, id string) (*document, error) {
+	payload := docs.Get([]byte(id))
+	if payload == nil {
+		return nil, nil
+	}
+	var doc document
+	if err := json.Unmarshal(payload, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode document %s: %w", id, err)
+	}
+	return &doc, nil
+}
+
+// removePostings removes a task from the postings of terms, dropping terms no task contains any more
+func removePostings(terms *bbolt.Bucket, id string, docTerms []string) error {
+	for _, term := range docTerms {
+		b := terms.Bucket([]byte(term))
+		if b == nil {
+			continue
+		}
+		if err := b.Delete([]byte(id)); err != nil {
+			return fmt.Errorf("failed to unindex term %q: %w", term, err)
+		}
+		if k, _ := b.Cursor().First(); k == nil {
+			if err := terms.DeleteBucket([]byte(term)); err != nil {
+				return fmt.Errorf("failed to drop term %q: %w", term, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package search
+
+import (
+	"testing"
+	"time"
+
+	"eldar/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// titles returns the titles of tasks in order
+func titles(tasks []model.Task) []string {
+	titles := make([]string, len(tasks))
+	for i, t := range tasks {
+		titles[i] = t.Title
+	}
+	return titles
+}
+
+func TestTokenize(t *testing.T) {
+	assert.Equal(t, []string{"fix", "crash", "in", "größe", "v2", "parser"}, Tokenize("Fix crash in Größe-v2 (parser)!"))
+	assert.Empty(t, Tokenize(" -- "))
+}
+
+func TestIndexSearch(t *testing.T) {
+	dir := t.TempDir()
+	ix, err := Open(dir)
+	require.NoError(t, err)
+
+	now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	soon := now.Add(24 * time.Hour)
+	tasks := []model.Task{
+		{ID: "t1", Title: "Write report", Description: "Quarterly numbers"},
+		{ID: "t2", Title: "Review budget", Description: "Check the report draft", DueDate: &soon, Labels: []string{"finance"}},
+		{ID: "t3", Title: "Plan offsite", Checklist: []model.ChecklistItem{{ID: "c1", Text: "Book the venue"}}},
+	}
+	comments := []model.Comment{{ID: "c1", TaskID: "t3", Body: "Catering is **confirmed**"}}
+	changed, err := ix.Update(tasks, comments)
+	require.NoError(t, err)
+	assert.Equal(t, 3, changed)
+
+	env := Env{Now: now}
+	search := func(query string) []string {
+		results, err := ix.Search(query, tasks, env)
+		require.NoError(t, err, query)
+		return titles(results)
+	}
+	// Title matches come first, partial words match as typed
+	assert.Equal(t, []string{"Write report", "Review budget"}, search("repo"))
+	assert.Equal(t, []string{"Plan offsite"}, search("venue"))
+	assert.Equal(t, []string{"Plan offsite"}, search("catering confirmed"))
+	assert.Equal(t, []string{"Review budget"}, search("report label:finance"))
+	assert.Equal(t, []string{"Review budget"}, search("due:<7d"))
+	assert.Empty(t, search("report venue"))
+	assert.Empty(t, search(""))
+
+	_, err = ix.Search(`"report`, tasks, env)
+	assert.Error(t, err)
+
+	// Unchanged tasks are not reindexed, edited and deleted ones are
+	tasks = []model.Task{tasks[0], tasks[1]}
+	tasks[1].Description = "Check the draft"
+	changed, err = ix.Update(tasks, comments)
+	require.NoError(t, err)
+	assert.Equal(t, 2, changed)
+	assert.Equal(t, []string{"Write report"}, search("report"))
+	assert.Empty(t, search("venue"))
+	require.NoError(t, ix.Close())
+
+	// The index survives a restart
+	ix, err = Open(dir)
+	require.NoError(t, err)
+	defer func() {
+		_ = ix.Close()
+	}()
+	changed, err = ix.Update(tasks, nil)
+	require.NoError(t, err)
+	assert.Zero(t, changed)
+
+	// A new comment reindexes its task
+	changed, err = ix.Update(tasks, []model.Comment{{ID: "c2", TaskID: "t1", Body: "Numbers from finance"}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, changed)
+	assert.Equal(t, []string{"Write report"}, search("financ"))
+	ids, err := ix.Lookup([]string{"draft"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"t2": true}, ids)
+}
@@ -0,0 +1,297 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"eldar/model"
+)
+
+// Env is what a query is evaluated against besides the tasks
+type Env struct {
+	// Me is the username of the signed in account, matched by assignee:me
+	Me string
+	// Now is the reference time of relative due dates
+	Now time.Time
+	// Blocked reports whether a task waits on open blockers, for is:blocked.
+	// When nil any task with blockers counts as blocked.
+	Blocked func(taskID string) bool
+	// Board returns the board of a task, whose custom fields are used by field: filters and sorts.
+	// When nil no task has custom fields.
+	Board func(boardID string) (model.Board, bool)
+}
+
+// Query is a parsed search query: free text terms that must all appear in a task,
+// filters on its fields and the order of the results
+type Query struct {
+	// Terms are the normalised words to look up in the index
+	Terms   []string
+	filters []filter
+	sorts   []sortKey
+}
+
+// filter reports whether a task matches one field condition of a query
+type filter func(task model.Task, env Env) bool
+
+// Parse parses a search query such as `report assignee:me label:bug due:<7d`.
+//
+// Supported filters are:
+//   - assignee:<email> or assignee:me
+//   - label:<name>, quoted when it contains spaces as in label:"needs review"
+//   - priority:<none|low|medium|high|urgent>
+//   - due:<Nd, due:>Nd (also with w for weeks), due:today, due:overdue or due:none
+//   - is:open, is:done or is:blocked
+//   - field:<name>=<value>, field:<name><value or field:<name>>value on a custom field, compared
+//     as its type, or field:<name> for tasks where it is set. Text fields match on part of their value.
+//
+// Results are ordered with sort:<key>, or sort:-<key> for descending order, where key is title, due,
+// priority, created, updated or the name of a custom field. Several sorts apply in the order given.
+//
+// Any other word is a free text term matched against titles, descriptions, checklists and comments.
+func Parse(query string) (*Query, error) {
+	tokens, err := tokenizeQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	q := &Query{}
+	for _, token := range tokens {
+		key, value, ok := strings.Cut(token, ":")
+		if !ok || value == "" {
+			q.Terms = append(q.Terms, Tokenize(token)...)
+			continue
+		}
+		if strings.EqualFold(key, "sort") {
+			q.sorts = append(q.sorts, parseSortKey(value))
+			continue
+		}
+		f, err := parseFilter(strings.ToLower(key), value)
+		if err != nil {
+			return nil, err
+		}
+		if f == nil {
+			// Not a known filter, e.g. a time such as 10:30, so search for it as text
+			q.Terms = append(q.Terms, Tokenize(token)...)
+			continue
+		}
+		q.filters = append(q.filters, f)
+	}
+	return q, nil
+}
+
+// Empty reports whether the query has neither terms nor filters. A query that only sorts is empty.
+func (q *Query) Empty() bool {
+	return len(q.Terms) == 0 && len(q.filters) == 0
+}
+
+// Match reports whether a task passes every filter of the query. Terms are matched by the index.
+func (q *Query) Match(task model.Task, env Env) bool {
+	for _, f := range q.filters {
+		if !f(task, env) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseFilter returns the filter for a key:value token, or nil when key is not a filter
+func parseFilter(key, value string) (filter, error) {
+	switch key {
+	case "assignee":
+		return func(task model.Task, env Env) bool {
+			want := value
+			if strings.EqualFold(value, "me") {
+				want = env.Me
+			}
+			return containsFold(task.Assignees, want)
+		}, nil
+	case "label":
+		return func(task model.Task, env Env) bool {
+			return containsFold(task.Labels, value)
+		}, nil
+	case "priority":
+		priority, err := model.ParsePriority(value)
+		if err != nil {
+			return nil, err
+		}
+		return func(task model.Task, env Env) bool {
+			return task.Priority == priority
+		}, nil
+	case "due":
+		return parseDueFilter(value)
+	case "field":
+		return parseFieldFilter(value)
+	case "is":
+		switch strings.ToLower(value) {
+		case "open":
+			return func(task model.Task, env Env) bool { return !task.Done }, nil
+		case "done":
+			return func(task model.Task, env Env) bool { return task.Done }, nil
+		case "blocked":
+			return func(task model.Task, env Env) bool {
+				if env.Blocked == nil {
+					return len(task.BlockedBy) > 0
+				}
+				return env.Blocked(task.ID)
+			}, nil
+		}
+		return nil, fmt.Errorf("unknown filter is:%s, use is:open, is:done or is:blocked", value)
+	}
+	return nil, nil
+}
+
+// parseDueFilter parses the value of a due: filter
+func parseDueFilter(value string) (filter, error) {
+	switch strings.ToLower(value) {
+	case "none":
+		return func(task model.Task, env Env) bool { return task.DueDate == nil }, nil
+	case "overdue":
+		return func(task model.Task, env Env) bool {
+			if task.Done || task.DueDate == nil {
+				return false
+			}
+			// Date-only due dates are overdue once their day is over
+			if model.DateOnly(*task.DueDate) {
+				return !model.DueIn(*task.DueDate, env.Now.Location()).AddDate(0, 0, 1).After(env.Now)
+			}
+			return task.DueDate.Before(env.Now)
+		}, nil
+	case "today":
+		return func(task model.Task, env Env) bool {
+			if task.DueDate == nil {
+				return false
+			}
+			y, m, d := model.DueIn(*task.DueDate, env.Now.Location()).Date()
+			ny, nm, nd := env.Now.Date()
+			return y == ny && m == nm && d == nd
+		}, nil
+	}
+
+	if len(value) < 3 || (value[0] != '<' && value[0] != '>') {
+		return nil, fmt.Errorf("invalid due filter %q, use due:<7d, due:>2w, due:today, due:overdue or due:none", value)
+	}
+	n, err := strconv.Atoi(value[1 : len(value)-1])
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("invalid due filter %q", value)
+	}
+	var span time.Duration
+	switch unicode.ToLower(rune(value[len(value)-1])) {
+	case 'd':
+		span = time.Duration(n) * 24 * time.Hour
+	case 'w':
+		span = time.Duration(n) * 7 * 24 * time.Hour
+	default:
+		return nil, fmt.Errorf("invalid due filter %q, use d for days or w for weeks", value)
+	}
+	before := value[0] == '<'
+	return func(task model.Task, env Env) bool {
+		if task.DueDate == nil {
+			return false
+		}
+		due, limit := model.DueIn(*task.DueDate, env.Now.Location()), env.Now.Add(span)
+		if before {
+			return due.Before(limit)
+		}
+		return due.After(limit)
+	}, nil
+}
+
+// parseFieldFilter parses the value of a field: filter
+func parseFieldFilter(value string) (filter, error) {
+	name, operand, op := value, "", byte(0)
+	if i := strings.IndexAny(value, "=<>"); i >= 0 {
+		name, operand, op = value[:i], value[i+1:], value[i]
+	}
+	if strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("invalid field filter %q, use field:<name>=<value>", value)
+	}
+	return func(task model.Task, env Env) bool {
+		field, v, ok := fieldValue(task, name, env)
+		if !ok || v.Empty() {
+			return false
+		}
+		if op == 0 {
+			return true
+		}
+		// The type of the field is only known per board, so values that do not parse simply match nothing
+		want, err := field.Parse(operand)
+		if err != nil || want.Empty() {
+			return false
+		}
+		switch op {
+		case '<':
+			return field.Compare(v, want) < 0
+		case '>':
+			return field.Compare(v, want) > 0
+		}
+		switch field.Type {
+		case model.FieldText:
+			return strings.Contains(strings.ToLower(v.Text), strings.ToLower(want.Text))
+		case model.FieldSelect, model.FieldMultiSelect:
+			for _, choice := range want.Choices {
+				if !containsFold(v.Choices, choice) {
+					return false
+				}
+			}
+			return true
+		default:
+			return field.Compare(v, want) == 0
+		}
+	}, nil
+}
+
+// fieldValue returns the custom field of the board of a task with the given name, and its value on the task
+func fieldValue(task model.Task, name string, env Env) (model.Field, model.FieldValue, bool) {
+	if env.Board == nil {
+		return model.Field{}, model.FieldValue{}, false
+	}
+	board, ok := env.Board(task.BoardID)
+	if !ok {
+		return model.Field{}, model.FieldValue{}, false
+	}
+	field, ok := board.FieldNamed(name)
+	if !ok {
+		return model.Field{}, model.FieldValue{}, false
+	}
+	return field, task.Fields[field.ID], true
+}
+
+// tokenizeQuery splits a query on spaces, keeping double quoted parts together and dropping the quotes
+func tokenizeQuery(query string) ([]string, error) {
+	var tokens []string
+	var current strings.Builder
+	quoted := false
+	for _, r := range query {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case unicode.IsSpace(r) && !quoted:
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if quoted {
+		return nil, errors.New("unterminated quote in search query")
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+	return tokens, nil
+}
+
+// containsFold reports whether values contains value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,166 @@
+package search
+
+import (
+	"testing"
+	"time"
+
+	"eldar/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	q, err := Parse(`Quarterly report assignee:me label:"needs review" 10:30`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"quarterly", "report", "10", "30"}, q.Terms)
+	assert.False(t, q.Empty())
+
+	q, err = Parse("   ")
+	require.NoError(t, err)
+	assert.True(t, q.Empty())
+
+	for query, msg := range map[string]string{
+		`label:"bug`:      "unterminated quote",
+		"priority:severe": "priority",
+		"is:closed":       "unknown filter",
+		"due:soon":        "invalid due filter",
+		"due:<7m":         "d for days or w for weeks",
+		"due:<xd":         "invalid due filter",
+	} {
+		_, err := Parse(query)
+		assert.ErrorContains(t, err, msg, query)
+	}
+}
+
+func TestQueryMatch(t *testing.T) {
+	now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	inThreeDays := now.Add(3 * 24 * time.Hour)
+	inTwoWeeks := now.Add(14 * 24 * time.Hour)
+	yesterday := now.Add(-24 * time.Hour)
+	laterToday := now.Add(2 * time.Hour)
+	env := Env{Me: "ana@example.com", Now: now}
+
+	tasks := map[string]model.Task{
+		"mine":    {ID: "mine", Assignees: []string{"Ana@example.com"}, Labels: []string{"bug"}, Priority: model.PriorityHigh, DueDate: &inThreeDays},
+		"later":   {ID: "later", Labels: []string{"needs review"}, DueDate: &inTwoWeeks},
+		"overdue": {ID: "overdue", DueDate: &yesterday},
+		"today":   {ID: "today", DueDate: &laterToday, Done: true},
+		"blocked": {ID: "blocked", BlockedBy: []string{"mine"}},
+	}
+	for query, want := range map[string][]string{
+		"assignee:me":              {"mine"},
+		"assignee:ANA@example.com": {"mine"},
+		"label:BUG":                {"mine"},
+		`label:"needs review"`:     {"later"},
+		"priority:high":            {"mine"},
+		"due:<7d":                  {"mine", "overdue", "today"},
+		"due:>1w":                  {"later"},
+		"due:today":                {"today"},
+		"due:overdue":              {"overdue"},
+		"due:none":                 {"blocked"},
+		"is:done":                  {"today"},
+		"is:blocked":               {"blocked"},
+		"is:open due:<1d":          {"overdue"},
+	} {
+		q, err := Parse(query)
+		require.NoError(t, err, query)
+		var got []string
+		for _, id := range []string{"mine", "later", "overdue", "today", "blocked"} {
+			if q.Match(tasks[id], env) {
+				got = append(got, id)
+			}
+		}
+		assert.Equal(t, want, got, query)
+	}
+
+	// A custom Blocked lets a task whose blockers are all done count as unblocked
+	q, err := Parse("is:blocked")
+	require.NoError(t, err)
+	env.Blocked = func(string) bool { return false }
+	assert.False(t, q.Match(tasks["blocked"], env))
+}
+
+func TestQueryMatchWestOfUTC(t *testing.T) {
+	west := time.FixedZone("UTC-5", -5*60*60)
+	now := time.Date(2025, 6, 2, 20, 0, 0, 0, west)
+	env := Env{Now: now}
+	// Date-only due dates are stored at midnight UTC, 7pm the day before in this zone
+	today := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	tomorrow := time.Date(2025, 6, 3, 0, 0, 0, 0, time.UTC)
+	yesterday := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	// 9pm on 2 June in this zone
+	tonight := time.Date(2025, 6, 3, 2, 0, 0, 0, time.UTC)
+
+	tasks := map[string]model.Task{
+		"today":     {ID: "today", DueDate: &today},
+		"tomorrow":  {ID: "tomorrow", DueDate: &tomorrow},
+		"yesterday": {ID: "yesterday", DueDate: &yesterday},
+		"tonight":   {ID: "tonight", DueDate: &tonight},
+	}
+	for query, want := range map[string][]string{
+		"due:today":   {"today", "tonight"},
+		"due:overdue": {"yesterday"},
+		"due:<1d":     {"today", "tomorrow", "yesterday", "tonight"},
+		"due:<0d":     {"today", "yesterday"},
+		"due:>0d":     {"tomorrow", "tonight"},
+	} {
+		q, err := Parse(query)
+		require.NoError(t, err, query)
+		var got []string
+		for _, id := range []string{"today", "tomorrow", "yesterday", "tonight"} {
+			if q.Match(tasks[id], env) {
+				got = append(got, id)
+			}
+		}
+		assert.Equal(t, want, got, query)
+	}
+}
+
+func TestQueryMatchFields(t *testing.T) {
+	boards := map[string]model.Board{"b1": {ID: "b1", Fields: []model.Field{
+		{ID: "f1", Name: "Estimate", Type: model.FieldNumber},
+		{ID: "f2", Name: "Platforms", Type: model.FieldMultiSelect, Options: []string{"iOS", "Android", "Web"}},
+		{ID: "f3", Name: "Customer", Type: model.FieldText},
+	}}}
+	env := Env{Board: func(id string) (model.Board, bool) {
+		b, ok := boards[id]
+		return b, ok
+	}}
+	two, eight := 2.0, 8.0
+	tasks := map[string]model.Task{
+		"small": {ID: "small", BoardID: "b1", Fields: map[string]model.FieldValue{
+			"f1": {Number: &two}, "f2": {Choices: []string{"iOS", "Web"}}, "f3": {Text: "Acme Ltd"}}},
+		"large": {ID: "large", BoardID: "b1", Fields: map[string]model.FieldValue{"f1": {Number: &eight}, "f2": {Choices: []string{"Web"}}}},
+		"unset": {ID: "unset", BoardID: "b1"},
+		"other": {ID: "other", BoardID: "b2"},
+	}
+	for query, want := range map[string][]string{
+		"field:estimate":              {"small", "large"},
+		"field:Estimate<5":            {"small"},
+		"field:estimate>5":            {"large"},
+		"field:estimate=8":            {"large"},
+		"field:estimate=lots":         nil,
+		"field:platforms=web":         {"small", "large"},
+		`field:"platforms=ios, web"`:  {"small"},
+		"field:customer=acme":         {"small"},
+		"field:unknown":               nil,
+		"field:estimate>1 sort:title": {"small", "large"},
+	} {
+		q, err := Parse(query)
+		require.NoError(t, err, query)
+		var got []string
+		for _, id := range []string{"small", "large", "unset", "other"} {
+			if q.Match(tasks[id], env) {
+				got = append(got, id)
+			}
+		}
+		assert.Equal(t, want, got, query)
+	}
+
+	_, err := Parse("field:=3")
+	assert.ErrorContains(t, err, "invalid field filter")
+	// Without boards no task has custom fields
+	q, err := Parse("field:estimate")
+	require.NoError(t, err)
+	assert.False(t, q.Match(tasks["small"], Env{}))
+}
@@ -0,0 +1,87 @@
+package search
+
+import (
+	"cmp"
+	"sort"
+	"strings"
+
+	"eldar/model"
+)
+
+// sortKey is an ordering of a query, see Parse
+type sortKey struct {
+	// name is a built-in key such as "due", or the name of a custom field
+	name string
+	desc bool
+}
+
+// parseSortKey parses the value of a sort: token
+func parseSortKey(value string) sortKey {
+	if rest, ok := strings.CutPrefix(value, "-"); ok {
+		return sortKey{name: strings.ToLower(rest), desc: true}
+	}
+	return sortKey{name: strings.ToLower(value)}
+}
+
+// sortTasks orders tasks by the keys, in turn. Tasks without a value for a key, such as no due date,
+// come last whatever the direction. The order of tasks that compare equal is kept.
+func sortTasks(tasks []model.Task, keys []sortKey, env Env) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		for _, key := range keys {
+			if c := key.compare(tasks[i], tasks[j], env); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+}
+
+// compare orders two tasks by the key
+func (k sortKey) compare(a, b model.Task, env Env) int {
+	switch k.name {
+	case "title":
+		return k.direct(strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title)))
+	case "priority":
+		return k.direct(cmp.Compare(a.Priority, b.Priority))
+	case "created":
+		return k.direct(a.CreatedAt.Compare(b.CreatedAt))
+	case "updated":
+		return k.direct(a.UpdatedAt.Compare(b.UpdatedAt))
+	case "due":
+		if a.DueDate == nil || b.DueDate == nil {
+			return emptyLast(a.DueDate == nil, b.DueDate == nil)
+		}
+		return k.direct(model.DueIn(*a.DueDate, env.Now.Location()).Compare(model.DueIn(*b.DueDate, env.Now.Location())))
+	}
+
+	fa, va, _ := fieldValue(a, k.name, env)
+	fb, vb, _ := fieldValue(b, k.name, env)
+	if va.Empty() || vb.Empty() {
+		return emptyLast(va.Empty(), vb.Empty())
+	}
+	if fa.Type != fb.Type {
+		// Boards may define fields of the same name differently, fall back to their text
+		return k.direct(strings.Compare(strings.ToLower(fa.Format(va)), strings.ToLower(fb.Format(vb))))
+	}
+	return k.direct(fa.Compare(va, vb))
+}
+
+// direct applies the direction of the key to an ascending comparison
+func (k sortKey) direct(c int) int {
+	if k.desc {
+		return -c
+	}
+	return c
+}
+
+// emptyLast orders a missing value after a present one
+func emptyLast(aEmpty, bEmpty bool) int {
+	switch {
+	case aEmpty == bEmpty:
+		return 0
+	case aEmpty:
+		return 1
+	default:
+		return -1
+	}
+}
@@ -0,0 +1,48 @@
+package search
+
+import (
+	"testing"
+	"time"
+
+	"eldar/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortTasks(t *testing.T) {
+	boards := map[string]model.Board{
+		"b1": {ID: "b1", Fields: []model.Field{{ID: "f1", Name: "Estimate", Type: model.FieldNumber}}},
+		"b2": {ID: "b2", Fields: []model.Field{{ID: "e", Name: "estimate", Type: model.FieldNumber}}},
+	}
+	env := Env{Board: func(id string) (model.Board, bool) {
+		b, ok := boards[id]
+		return b, ok
+	}}
+	one, three, five := 1.0, 3.0, 5.0
+	due := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	tasks := []model.Task{
+		{ID: "a", BoardID: "b1", Title: "Alpha", Priority: model.PriorityLow, Fields: map[string]model.FieldValue{"f1": {Number: &three}}},
+		{ID: "b", BoardID: "b2", Title: "beta", Priority: model.PriorityUrgent, DueDate: &due, Fields: map[string]model.FieldValue{"e": {Number: &one}}},
+		{ID: "c", BoardID: "b1", Title: "Gamma", Priority: model.PriorityLow},
+		{ID: "d", BoardID: "b1", Title: "delta", Priority: model.PriorityHigh, Fields: map[string]model.FieldValue{"f1": {Number: &five}}},
+	}
+	ids := func(query string) []string {
+		q, err := Parse(query)
+		assert.NoError(t, err)
+		sorted := append([]model.Task{}, tasks...)
+		sortTasks(sorted, q.sorts, env)
+		var ids []string
+		for _, task := range sorted {
+			ids = append(ids, task.ID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []string{"a", "b", "d", "c"}, ids("sort:title"))
+	assert.Equal(t, []string{"b", "d", "a", "c"}, ids("sort:-priority"))
+	assert.Equal(t, []string{"c", "a", "d", "b"}, ids("sort:priority sort:-title"))
+	// Tasks without a value come last in both directions
+	assert.Equal(t, []string{"b", "a", "d", "c"}, ids("sort:estimate"))
+	assert.Equal(t, []string{"d", "a", "b", "c"}, ids("sort:-estimate"))
+	assert.Equal(t, []string{"b", "a", "c", "d"}, ids("sort:-due"))
+	assert.Equal(t, []string{"a", "b", "c", "d"}, ids("sort:nothing"))
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"eldar/model"
+	"eldar/search"
+	"eldar/store"
+)
+
+// taskIndex is the full-text index of the tasks of the signed-in account, it is nil while signed out
+var taskIndex *search.Index
+
+// openTaskIndex opens the search index in the account data directory dir
+// and keeps it in step with the task cache st, reindexing only the tasks that changed
+func openTaskIndex(dir string, st *store.Store) error {
+	ix, err := search.Open(dir)
+	if err != nil {
+		return err
+	}
+	update := func() {
+		if _, err := ix.Update(st.AllTasks(), st.AllComments()); err != nil {
+			log.Printf("Error updating search index: %v", err)
+		}
+	}
+	update()
+	st.OnChange(update)
+	taskIndex = ix
+	return nil
+}
+
+// closeTaskIndex closes the search index, if any
+func closeTaskIndex() {
+	if taskIndex == nil {
+		return
+	}
+	if err := taskIndex.Close(); err != nil {
+		log.Printf("Error closing search index: %v", err)
+	}
+	taskIndex = nil
+}
+
+// searchTasks returns the cached tasks of the signed-in account matching query
+func searchTasks(query string) ([]model.Task, error) {
+	return taskIndex.Search(query, taskStore.AllTasks(), search.Env{
+		Me:      taskStoreUser,
+		Now:     time.Now(),
+		Blocked: func(taskID string) bool { return len(taskStore.OpenBlockers(taskID)) > 0 },
+		Board:   taskStore.Board,
+	})
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"eldar/api"
+	"eldar/auth"
+	"eldar/credentials"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+)
+
+// login signs in with an email and password on the Eldar server and stores the tokens
+func login(ctx context.Context, email, password string) error {
+	resp, err := apiClient.Login(ctx, email, password)
+	if err != nil {
+		if api.IsStatus(err, http.StatusUnauthorized) {
+			// Keep the server error so that its Retry-After is honoured by the throttle
+			return fmt.Errorf("%w: %w", auth.ErrInvalidCredentials, err)
+		}
+		if api.IsStatus(err, http.StatusTooManyRequests) {
+			return fmt.Errorf("too many login attempts, try again later: %w", err)
+		}
+		return err
+	}
+	creds := &credentials.Credentials{Username: email, Issuer: credentials.IssuerEldar}
+	resp.ApplyTo(creds, time.Now())
+	return credentials.SaveCredentials(creds)
+}
+
+// newOIDCClient creates the OpenID Connect client from the app preferences, falling back to
+// the ELDAR_OIDC_ISSUER and ELDAR_OIDC_CLIENT_ID environment variables. It returns nil when
+// no identity provider is configured.
+func newOIDCClient(prefs fyne.Preferences) *auth.OIDCClient {
+	issuer := prefs.StringWithFallback("oidc_issuer", os.Getenv("ELDAR_OIDC_ISSUER"))
+	clientID := prefs.StringWithFallback("oidc_client_id", os.Getenv("ELDAR_OIDC_CLIENT_ID"))
+	if issuer == "" || clientID == "" {
+		return nil
+	}
+	return auth.NewOIDCClient(auth.OIDCConfig{Issuer: issuer, ClientID: clientID})
+}
+
+// oidcSignIn runs the OpenID Connect sign-in in the system browser and stores the tokens
+func oidcSignIn(ctx context.Context) error {
+	tokens, err := oidcClient.Login(ctx, fyne.CurrentApp().OpenURL)
+	if err != nil {
+		return err
+	}
+	creds := &credentials.Credentials{Issuer: credentials.IssuerOIDC}
+	tokens.ApplyTo(creds, time.Now())
+	return credentials.SaveCredentials(creds)
+}
+
+// refreshCredentials renews the access token when it is expired or about to expire.
+// Sessions started through SSO are refreshed at the identity provider, others at the Eldar server.
+func refreshCredentials(ctx context.Context) error {
+	storageDir, err := credentials.GetStorageDir()
+	if err != nil {
+		return err
+	}
+	server := func(ctx context.Context, refreshToken string) (auth.Tokens, error) {
+		return apiClient.Refresh(ctx, refreshToken)
+	}
+	var idp auth.RefreshFunc
+	if oidcClient != nil {
+		idp = func(ctx context.Context, refreshToken string) (auth.Tokens, error) {
+			return oidcClient.Refresh(ctx, refreshToken)
+		}
+	}
+	creds, err := auth.Refresh(ctx, storageDir, time.Now(), server, idp)
+	if err != nil {
+		return err
+	}
+	apiClient.SetAccessToken(creds.AccessToken)
+	return nil
+}
+
+// refreshLoop keeps the access token fresh while the app runs and routes to the Login page
+// once the session can no longer be used
+func refreshLoop() {
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		if err := refreshCredentials(ctx); err != nil {
+			log.Printf("Error refreshing credentials: %v", err)
+		}
+		cancel()
+
+		if creds, err := credentials.GetCredentials(); err == nil && !creds.IsEmpty() && !creds.IsValid(time.Now()) {
+			fyne.Do(func() {
+				if signedIn && !locked {
+					appPage = Login
+					updateWindowContent()
+				}
+			})
+		}
+		<-ticker.C
+	}
+}
+
+// logout revokes the session on the server and identity provider, clears the local
+// credentials and cached data, then routes back to the Login page
+func logout(allSessions bool) {
+	storageDir, err := credentials.GetStorageDir()
+	if err != nil {
+		log.Printf("Error getting storage directory: %v", err)
+		dialog.ShowError(err, w)
+		return
+	}
+
+	// Like refreshes, sessions are revoked at the server that issued them and nowhere else
+	var idp auth.Revoker
+	if oidcClient != nil {
+		idp = oidcClient
+	}
+	// The cache lives in the account data directory which is removed on logout
+	closeTaskStore()
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		err := auth.Logout(ctx, storageDir, allSessions, apiClient, idp)
+		fyne.Do(func() {
+			if err != nil {
+				log.Printf("Error logging out: %v", err)
+				dialog.ShowError(err, w)
+				if !errors.Is(err, auth.ErrRevocationFailed) {
+					return
+				}
+			}
+			apiClient.SetAccessToken("")
+			appPage = Login
+			updateWindowContent()
+		})
+	}()
+}
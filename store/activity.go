@@ -0,0 +1,171 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"eldar/model"
+	"go.etcd.io/bbolt"
+)
+
+// Activity returns the cached event log of a board, newest first.
+// At most limit entries are returned, or all of them when limit is 0.
+func (s *Store) Activity(boardID string, limit int) []model.Activity {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	log := s.activity[boardID]
+	if limit <= 0 || limit > len(log) {
+		limit = len(log)
+	}
+	entries := make([]model.Activity, 0, limit)
+	for i := len(log) - 1; i >= len(log)-limit; i-- {
+		entries = append(entries, log[i])
+	}
+	return entries
+}
+
+// TaskActivity returns the cached history of a task, newest first
+func (s *Store) TaskActivity(taskID string) []model.Activity {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var entries []model.Activity
+	for _, log := range s.activity {
+		for _, a := range log {
+			if a.TaskID == taskID {
+				entries = append(entries, a)
+			}
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].At.Equal(entries[j].At) {
+			return entries[i].At.After(entries[j].At)
+		}
+		return entries[i].Seq > entries[j].Seq
+	})
+	return entries
+}
+
+// SyncActivity fetches the entries of the event log of a board that are not cached yet
+func (s *Store) SyncActivity(ctx context.Context, boardID string) error {
+	entries, err := s.remote.ListActivity(ctx, boardID, s.lastActivity(boardID))
+	if err != nil {
+		return fmt.Errorf("failed to list activity: %w", err)
+	}
+	return s.appendActivity(boardID, entries)
+}
+
+// ApplyActivity adds an entry received from the event stream to the cached log of its board.
+// When entries were missed, for example while offline, the missing part of the log is fetched first.
+func (s *Store) ApplyActivity(ctx context.Context, entry model.Activity) error {
+	last := s.lastActivity(entry.BoardID)
+	switch {
+	case entry.Seq <= last:
+		return nil
+	case entry.Seq > last+1:
+		return s.SyncActivity(ctx, entry.BoardID)
+	}
+	return s.appendActivity(entry.BoardID, []model.Activity{entry})
+}
+
+// lastActivity returns the sequence number of the newest cached entry of the log of a board, or 0
+func (s *Store) lastActivity(boardID string) int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	log := s.activity[boardID]
+	if len(log) == 0 {
+		return 0
+	}
+	return log[len(log)-1].Seq
+}
+
+// appendActivity adds entries to the cached log of a board in memory and in the database, then notifies listeners
+func (s *Store) appendActivity(boardID string, entries []model.Activity) error {
+	s.mu.Lock()
+	entries = s.newActivity(boardID, entries)
+	if len(entries) == 0 {
+		s.mu.Unlock()
+		return nil
+	}
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		return putActivity(tx.Bucket(activityBucket), entries)
+	})
+	if err == nil {
+		s.activity[boardID] = append(s.activity[boardID], entries...)
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to save activity: %w", err)
+	}
+
+	s.notify()
+	return nil
+}
+
+// newActivity returns the entries of a board that follow its cached log, in order.
+// The caller must hold s.mu.
+func (s *Store) newActivity(boardID string, entries []model.Activity) []model.Activity {
+	var last int64
+	if log := s.activity[boardID]; len(log) > 0 {
+		last = log[len(log)-1].Seq
+	}
+	var fresh []model.Activity
+	for _, a := range entries {
+		if a.BoardID == boardID && a.Seq > last {
+			fresh = append(fresh, a)
+		}
+	}
+	sort.Slice(fresh, func(i, j int) bool {
+		return fresh[i].Seq < fresh[j].Seq
+	})
+	return fresh
+}
+
+// activityKey returns the database key of an entry, ordering the entries of a board by sequence number
+func activityKey(boardID string, seq int64) []byte {
+	return fmt.Appendf(nil, "%s/%016x", boardID, seq)
+}
+
+// putActivity saves entries of event logs
+func putActivity(b *bbolt.Bucket, entries []model.Activity) error {
+	for _, a := range entries {
+		payload, err := json.Marshal(a)
+		if err != nil {
+			return fmt.Errorf("failed to encode activity %d: %w", a.Seq, err)
+		}
+		if err := b.Put(activityKey(a.BoardID, a.Seq), payload); err != nil {
+			return fmt.Errorf("failed to save activity %d: %w", a.Seq, err)
+		}
+	}
+	return nil
+}
+
+// deleteActivity removes the event log of a board
+func deleteActivity(b *bbolt.Bucket, boardID string) error {
+	prefix := []byte(boardID + "/")
+	var keys [][]byte
+	c := b.Cursor()
+	for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+		keys = append(keys, append([]byte{}, k...))
+	}
+	for _, k := range keys {
+		if err := b.Delete(k); err != nil {
+			return fmt.Errorf("failed to delete activity: %w", err)
+		}
+	}
+	return nil
+}
+
+// loadActivity reads the event logs of every board, which the keys keep in order
+func loadActivity(b *bbolt.Bucket, logs map[string][]model.Activity) error {
+	return b.ForEach(func(k, v []byte) error {
+		var a model.Activity
+		if err := json.Unmarshal(v, &a); err != nil {
+			return fmt.Errorf("failed to decode %s: %w", k, err)
+		}
+		logs[a.BoardID] = append(logs[a.BoardID], a)
+		return nil
+	})
+}
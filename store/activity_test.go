@@ -0,0 +1,64 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"eldar/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// activitySeqs returns the sequence numbers of entries in order
+func activitySeqs(entries []model.Activity) []int64 {
+	seqs := make([]int64, len(entries))
+	for i, a := range entries {
+		seqs[i] = a.Seq
+	}
+	return seqs
+}
+
+func TestStoreActivity(t *testing.T) {
+	remote := newFakeRemote()
+	remote.record("b1", "t1", model.ActivityCreated)
+	remote.record("b1", "t1", model.ActivityMoved)
+	dir := t.TempDir()
+	s, err := Open(dir, remote)
+	require.NoError(t, err)
+	require.NoError(t, s.Sync(context.Background()))
+	assert.Equal(t, []int64{2, 1}, activitySeqs(s.Activity("b1", 0)))
+
+	// Only new entries are fetched, and the log is kept in order
+	remote.record("b1", "t2", model.ActivityCreated)
+	remote.record("b1", "t1", model.ActivityAssigned)
+	require.NoError(t, s.Sync(context.Background()))
+	assert.Equal(t, []int64{4, 3}, activitySeqs(s.Activity("b1", 2)))
+	assert.Equal(t, []int64{4, 2, 1}, activitySeqs(s.TaskActivity("t1")))
+
+	// Entries from the event stream are appended, a gap fetches what was missed
+	next := remote.record("b1", "t1", model.ActivityEdited)
+	require.NoError(t, s.ApplyActivity(context.Background(), next))
+	require.NoError(t, s.ApplyActivity(context.Background(), next))
+	remote.record("b1", "t2", model.ActivityMoved)
+	latest := remote.record("b1", "t2", model.ActivityDeleted)
+	require.NoError(t, s.ApplyActivity(context.Background(), latest))
+	assert.Equal(t, []int64{7, 6, 5, 4, 3, 2, 1}, activitySeqs(s.Activity("b1", 0)))
+
+	// The log survives a restart
+	require.NoError(t, s.Close())
+	s, err = Open(dir, remote)
+	require.NoError(t, err)
+	assert.Len(t, s.Activity("b1", 0), 7)
+
+	// and is dropped with its board
+	remote.boards = nil
+	require.NoError(t, s.Sync(context.Background()))
+	assert.Empty(t, s.Activity("b1", 0))
+	require.NoError(t, s.Close())
+	s, err = Open(dir, remote)
+	require.NoError(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+	assert.Empty(t, s.Activity("b1", 0))
+}
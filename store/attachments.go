@@ -0,0 +1,87 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"eldar/model"
+)
+
+// UploadAttachment uploads a file and adds it to the attachments of the cached task once the server stored it.
+// Unlike other edits the upload is not applied to the cache first, as there is nothing to show until it is stored.
+//
+// Parameters:
+//   - taskID: The task to attach the file to
+//   - name: The file name
+//   - mediaType: The media type of the content
+//   - content: The content, exactly size bytes long
+//   - progress: A function called with the number of bytes sent so far, or nil
+//
+// Returns:
+//   - The attachment as stored by the server
+//   - An error if the file is too large, the task is not cached or the upload failed
+func (s *Store) UploadAttachment(ctx context.Context, taskID, name, mediaType string, content io.Reader, size int64, progress func(sent int64)) (model.Attachment, error) {
+	attachment := model.Attachment{TaskID: taskID, Name: name, MediaType: mediaType, Size: size}
+	if err := attachment.Validate(); err != nil {
+		return model.Attachment{}, err
+	}
+	if _, ok := s.Task(taskID); !ok {
+		return model.Attachment{}, fmt.Errorf("task %s: %w", taskID, ErrNotFound)
+	}
+
+	uploaded, err := s.remote.UploadAttachment(ctx, taskID, name, mediaType, content, size, progress)
+	if err != nil {
+		return model.Attachment{}, fmt.Errorf("failed to upload attachment: %w", err)
+	}
+	// The task may have changed during the upload
+	task, ok := s.Task(taskID)
+	if !ok {
+		return *uploaded, nil
+	}
+	if attachmentIndex(task.Attachments, uploaded.ID) < 0 {
+		task.Attachments = append(task.Attachments, *uploaded)
+		if err := s.putTask(task); err != nil {
+			return model.Attachment{}, err
+		}
+	}
+	return *uploaded, nil
+}
+
+// DeleteAttachment removes an attachment from the cached task and deletes it on the server.
+// The attachment is restored when the server rejects the deletion.
+func (s *Store) DeleteAttachment(ctx context.Context, taskID, attachmentID string) error {
+	task, ok := s.Task(taskID)
+	if !ok {
+		return fmt.Errorf("task %s: %w", taskID, ErrNotFound)
+	}
+	i := attachmentIndex(task.Attachments, attachmentID)
+	if i < 0 {
+		return fmt.Errorf("attachment %s: %w", attachmentID, ErrNotFound)
+	}
+	removed := task.Attachments[i]
+	task.Attachments = append(task.Attachments[:i], task.Attachments[i+1:]...)
+	if err := s.putTask(task); err != nil {
+		return err
+	}
+
+	if err := s.remote.DeleteAttachment(ctx, attachmentID); err != nil {
+		if current, ok := s.Task(taskID); ok && attachmentIndex(current.Attachments, attachmentID) < 0 {
+			i = min(i, len(current.Attachments))
+			current.Attachments = append(current.Attachments[:i], append([]model.Attachment{removed}, current.Attachments[i:]...)...)
+			_ = s.putTask(current)
+		}
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+	return nil
+}
+
+// attachmentIndex returns the index of the attachment with the given ID, or -1
+func attachmentIndex(attachments []model.Attachment, id string) int {
+	for i, a := range attachments {
+		if a.ID == id {
+			return i
+		}
+	}
+	return -1
+}
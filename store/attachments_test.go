@@ -0,0 +1,69 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"eldar/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreUploadAttachment(t *testing.T) {
+	remote := newFakeRemote()
+	s := openSynced(t, remote)
+
+	var sent int64
+	attachment, err := s.UploadAttachment(context.Background(), "t1", "plan.txt", "text/plain",
+		strings.NewReader("step one"), 8, func(n int64) { sent = n })
+	require.NoError(t, err)
+	assert.Equal(t, "a1", attachment.ID)
+	assert.Equal(t, int64(8), sent)
+	task, _ := s.Task("t1")
+	require.Len(t, task.Attachments, 1)
+	assert.Equal(t, "plan.txt", task.Attachments[0].Name)
+
+	// Edits of the task made from a copy taken before the upload keep the attachment
+	stale := task.Clone()
+	stale.Attachments = nil
+	stale.Title = "Write final report"
+	require.NoError(t, s.UpdateTask(context.Background(), stale))
+	task, _ = s.Task("t1")
+	assert.Len(t, task.Attachments, 1)
+
+	_, err = s.UploadAttachment(context.Background(), "t1", "video.mp4", "video/mp4",
+		strings.NewReader(""), model.MaxAttachmentSize+1, nil)
+	assert.Error(t, err)
+	_, err = s.UploadAttachment(context.Background(), "missing", "plan.txt", "text/plain", strings.NewReader(""), 0, nil)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	remote.fail(errors.New("offline"))
+	_, err = s.UploadAttachment(context.Background(), "t1", "notes.txt", "text/plain", strings.NewReader("x"), 1, nil)
+	assert.Error(t, err)
+	task, _ = s.Task("t1")
+	assert.Len(t, task.Attachments, 1)
+}
+
+func TestStoreDeleteAttachment(t *testing.T) {
+	remote := newFakeRemote()
+	remote.tasks["t1"] = model.Task{ID: "t1", BoardID: "b1", ColumnID: "todo", Title: "Write report", Version: 1,
+		Attachments: []model.Attachment{{ID: "a1", Name: "one.png"}, {ID: "a2", Name: "two.png"}}}
+	s := openSynced(t, remote)
+
+	remote.fail(errors.New("offline"))
+	assert.Error(t, s.DeleteAttachment(context.Background(), "t1", "a1"))
+	task, _ := s.Task("t1")
+	require.Len(t, task.Attachments, 2)
+	assert.Equal(t, "a1", task.Attachments[0].ID)
+
+	remote.fail(nil)
+	require.NoError(t, s.DeleteAttachment(context.Background(), "t1", "a1"))
+	task, _ = s.Task("t1")
+	require.Len(t, task.Attachments, 1)
+	assert.Equal(t, "a2", task.Attachments[0].ID)
+	assert.Len(t, remote.tasks["t1"].Attachments, 1)
+
+	assert.ErrorIs(t, s.DeleteAttachment(context.Background(), "t1", "a1"), ErrNotFound)
+}
@@ -0,0 +1,164 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"eldar/model"
+	"go.etcd.io/bbolt"
+)
+
+// CreateBoard adds a board to the cache and creates it on the server, then creates tasks on it.
+// The board is given a temporary ID until the server returns its copy, and is removed from the cache
+// again when the server rejects it. Tasks the server rejects are left out and reported in the error,
+// the board is kept.
+func (s *Store) CreateBoard(ctx context.Context, board model.Board, tasks []model.Task) (model.Board, error) {
+	if err := board.Validate(); err != nil {
+		return model.Board{}, err
+	}
+	id, err := newID()
+	if err != nil {
+		return model.Board{}, err
+	}
+	board.ID = id
+	if err := s.putBoard(board); err != nil {
+		return model.Board{}, err
+	}
+
+	created, err := s.remote.CreateBoard(ctx, board)
+	if err != nil {
+		_ = s.deleteBoard(board.ID)
+		return model.Board{}, fmt.Errorf("failed to create board: %w", err)
+	}
+	if err := s.deleteBoard(board.ID); err != nil {
+		return model.Board{}, err
+	}
+	if err := s.putBoard(*created); err != nil {
+		return model.Board{}, err
+	}
+
+	var errs []error
+	for _, task := range tasks {
+		task.ID, task.BoardID = "", created.ID
+		if _, err := s.CreateTask(ctx, task); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", task.Title, err))
+		}
+	}
+	if len(errs) > 0 {
+		return *created, fmt.Errorf("failed to add %d of %d tasks to board %s: %w", len(errs), len(tasks), created.Name, errors.Join(errs...))
+	}
+	return *created, nil
+}
+
+// UpdateBoard applies an edit of the name, columns, labels or custom fields of a cached board and
+// sends it to the server. The previous version is restored when the server rejects the edit,
+// unless the board was changed again in the meantime.
+func (s *Store) UpdateBoard(ctx context.Context, board model.Board) error {
+	if err := board.Validate(); err != nil {
+		return err
+	}
+	previous, ok := s.Board(board.ID)
+	if !ok {
+		return fmt.Errorf("board %s: %w", board.ID, ErrNotFound)
+	}
+	if err := s.putBoard(board); err != nil {
+		return err
+	}
+
+	updated, err := s.remote.UpdateBoard(ctx, board)
+	if err != nil {
+		if current, ok := s.Board(board.ID); ok && reflect.DeepEqual(current, board) {
+			_ = s.putBoard(previous)
+		}
+		return fmt.Errorf("failed to update board: %w", err)
+	}
+	return s.putBoard(*updated)
+}
+
+// CheckMove checks moving task into the column with the given ID against the WIP limit and definition
+// of done of the column. It returns nil when the move meets them.
+func (s *Store) CheckMove(task model.Task, columnID string) *model.PolicyViolation {
+	board, ok := s.Board(task.BoardID)
+	if !ok {
+		return nil
+	}
+	return board.CheckMove(&task, columnID, s.ColumnCount(task.BoardID, columnID, task.ID))
+}
+
+// ColumnCount returns the number of cards in a column, that is its tasks other than subtasks and
+// archived tasks, leaving out the task with ID exceptID
+func (s *Store) ColumnCount(boardID, columnID, exceptID string) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	count := 0
+	for _, t := range s.tasks {
+		if t.BoardID == boardID && t.ColumnID == columnID && t.ParentID == "" && !t.Archived() && t.ID != exceptID {
+			count++
+		}
+	}
+	return count
+}
+
+// checkFields drops the empty values and those of custom fields that were removed from the board of a task,
+// then checks that the remaining values fit their fields
+func (s *Store) checkFields(task *model.Task) error {
+	board, ok := s.Board(task.BoardID)
+	if !ok {
+		return fmt.Errorf("board %s: %w", task.BoardID, ErrNotFound)
+	}
+	var fields map[string]model.FieldValue
+	for id, v := range task.Fields {
+		if _, ok := board.Field(id); ok && !v.Empty() {
+			if fields == nil {
+				fields = map[string]model.FieldValue{}
+			}
+			fields[id] = v
+		}
+	}
+	task.Fields = fields
+	return board.CheckFields(task)
+}
+
+// putBoard stores a board in memory and in the database, then notifies listeners
+func (s *Store) putBoard(board model.Board) error {
+	payload, err := json.Marshal(board)
+	if err != nil {
+		return fmt.Errorf("failed to encode board: %w", err)
+	}
+
+	s.mu.Lock()
+	err = s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(boardsBucket).Put([]byte(board.ID), payload)
+	})
+	if err == nil {
+		s.boards[board.ID] = board
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to save board: %w", err)
+	}
+
+	s.notify()
+	return nil
+}
+
+// deleteBoard removes a board from memory and from the database, then notifies listeners
+func (s *Store) deleteBoard(id string) error {
+	s.mu.Lock()
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(boardsBucket).Delete([]byte(id))
+	})
+	if err == nil {
+		delete(s.boards, id)
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to delete board: %w", err)
+	}
+
+	s.notify()
+	return nil
+}
@@ -0,0 +1,155 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"eldar/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreUpdateBoard(t *testing.T) {
+	remote := newFakeRemote()
+	s := openSynced(t, remote)
+	ctx := context.Background()
+
+	board, _ := s.Board("b1")
+	board.Labels = []model.Label{{Name: "bug", Color: "#e53935"}}
+	board.Fields = []model.Field{{ID: "f1", Name: "Estimate", Type: model.FieldNumber}}
+	require.NoError(t, s.UpdateBoard(ctx, board))
+	board, _ = s.Board("b1")
+	assert.Equal(t, int64(1), board.Version)
+	assert.Len(t, board.Fields, 1)
+
+	invalid := board
+	invalid.Labels = []model.Label{{Name: "bug", Color: "red"}}
+	assert.Error(t, s.UpdateBoard(ctx, invalid))
+
+	remote.fail(errors.New("server unreachable"))
+	renamed := board
+	renamed.Name = "Lost edit"
+	assert.Error(t, s.UpdateBoard(ctx, renamed))
+	board, _ = s.Board("b1")
+	assert.Equal(t, "Sprint", board.Name)
+	assert.ErrorIs(t, s.UpdateBoard(ctx, model.Board{ID: "missing", Name: "x"}), ErrNotFound)
+}
+
+func TestStoreTaskFields(t *testing.T) {
+	remote := newFakeRemote()
+	remote.boards[0].Fields = []model.Field{
+		{ID: "f1", Name: "Estimate", Type: model.FieldNumber},
+		{ID: "f2", Name: "Stage", Type: model.FieldSelect, Options: []string{"Idea", "Build"}},
+	}
+	s := openSynced(t, remote)
+	ctx := context.Background()
+
+	n := 3.0
+	task, _ := s.Task("t1")
+	task.Fields = map[string]model.FieldValue{"f1": {Number: &n}, "f2": {}}
+	require.NoError(t, s.UpdateTask(ctx, task))
+	task, _ = s.Task("t1")
+	// Empty values are dropped
+	assert.Equal(t, map[string]model.FieldValue{"f1": {Number: &n}}, task.Fields)
+
+	task.Fields["f2"] = model.FieldValue{Choices: []string{"Ship"}}
+	assert.EqualError(t, s.UpdateTask(ctx, task), `"Ship" is not an option of Stage`)
+	delete(task.Fields, "f2")
+
+	// Values of fields removed from the board are dropped on the next save
+	board, _ := s.Board("b1")
+	board.Fields = board.Fields[1:]
+	require.NoError(t, s.UpdateBoard(ctx, board))
+	task, _ = s.Task("t1")
+	task.Title = "Write the report"
+	require.NoError(t, s.UpdateTask(ctx, task))
+	task, _ = s.Task("t1")
+	assert.Nil(t, task.Fields)
+
+	_, err := s.CreateTask(ctx, model.Task{BoardID: "b1", Title: "Review", Fields: map[string]model.FieldValue{"f2": {Text: "x"}}})
+	assert.Error(t, err)
+}
+
+func TestStoreColumnPolicies(t *testing.T) {
+	remote := newFakeRemote()
+	remote.boards[0].Columns = []model.Column{
+		{ID: "todo", Name: "To do"},
+		{ID: "doing", Name: "Doing", WIPLimit: 1, WIPPolicy: model.WIPBlock},
+		{ID: "done", Name: "Done", WIPLimit: 1, DefinitionOfDone: []string{"Reviewed"}},
+	}
+	remote.tasks["t2"] = model.Task{ID: "t2", BoardID: "b1", ColumnID: "doing", Title: "Collect numbers", Version: 1}
+	remote.tasks["t3"] = model.Task{ID: "t3", BoardID: "b1", ColumnID: "doing", ParentID: "t2", Title: "Ask finance", Version: 1}
+	s := openSynced(t, remote)
+	ctx := context.Background()
+
+	// Subtasks are not counted against the limit
+	assert.Equal(t, 1, s.ColumnCount("b1", "doing", ""))
+	assert.Equal(t, 0, s.ColumnCount("b1", "doing", "t2"))
+
+	task, _ := s.Task("t1")
+	task.ColumnID = "doing"
+	err := s.UpdateTask(ctx, task)
+	assert.ErrorIs(t, err, model.ErrPolicy)
+	var v *model.PolicyViolation
+	require.ErrorAs(t, err, &v)
+	assert.True(t, v.OverLimit())
+	task, _ = s.Task("t1")
+	assert.Equal(t, "todo", task.ColumnID)
+
+	// The definition of done has to be completed first, the limit of Done only warns
+	task.ColumnID = "done"
+	assert.ErrorIs(t, s.UpdateTask(ctx, task), model.ErrPolicy)
+	task.Checklist = []model.ChecklistItem{{ID: "c1", Text: "Reviewed", Done: true}}
+	require.NoError(t, s.UpdateTask(ctx, task))
+	moved, _ := s.Task("t2")
+	moved.ColumnID = "done"
+	moved.Checklist = task.Checklist
+	v = s.CheckMove(moved, "done")
+	require.NotNil(t, v)
+	assert.False(t, v.Blocking())
+	require.NoError(t, s.UpdateTask(ctx, moved))
+
+	// Edits within a column are not checked
+	task, _ = s.Task("t1")
+	task.Checklist[0].Done = false
+	task.Title = "Write the report"
+	require.NoError(t, s.UpdateTask(ctx, task))
+}
+
+func TestStoreColumnPoliciesAddingTasks(t *testing.T) {
+	remote := newFakeRemote()
+	remote.boards[0].Columns = []model.Column{
+		{ID: "todo", Name: "To do"},
+		{ID: "doing", Name: "Doing", WIPLimit: 1, WIPPolicy: model.WIPBlock},
+		{ID: "done", Name: "Done", DefinitionOfDone: []string{"Reviewed"}},
+	}
+	remote.tasks["t2"] = model.Task{ID: "t2", BoardID: "b1", ColumnID: "doing", Title: "Collect numbers", Version: 1}
+	s := openSynced(t, remote)
+	ctx := context.Background()
+
+	// New tasks, from the dialog or a template, are held to the policies of their column
+	_, err := s.CreateTask(ctx, model.Task{BoardID: "b1", ColumnID: "doing", Title: "Plan launch"})
+	assert.ErrorIs(t, err, model.ErrPolicy)
+	_, err = s.CreateTask(ctx, model.Task{BoardID: "b1", ColumnID: "done", Title: "Ship"})
+	assert.ErrorIs(t, err, model.ErrPolicy)
+	assert.Len(t, s.Tasks("b1"), 2)
+
+	// Archived cards leave room in their column, and unarchiving them is checked like a move back in
+	archived, _ := s.Task("t2")
+	now := time.Now()
+	archived.ArchivedAt = &now
+	require.NoError(t, s.UpdateTask(ctx, archived))
+	plan, err := s.CreateTask(ctx, model.Task{BoardID: "b1", ColumnID: "doing", Title: "Plan launch"})
+	require.NoError(t, err)
+	archived, _ = s.Task("t2")
+	archived.ArchivedAt = nil
+	assert.ErrorIs(t, s.UpdateTask(ctx, archived), model.ErrPolicy)
+
+	// So is restoring a task from the trash
+	require.NoError(t, s.DeleteTask(ctx, plan.ID))
+	require.NoError(t, s.UpdateTask(ctx, archived))
+	assert.ErrorIs(t, s.RestoreTask(ctx, plan.ID), model.ErrPolicy)
+	assert.Len(t, s.Trash().Tasks, 1)
+}
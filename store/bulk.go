@@ -0,0 +1,221 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"eldar/model"
+	"go.etcd.io/bbolt"
+)
+
+// ArchivedTasks returns the cached archived tasks of boards that are not archived, most recently archived first
+func (s *Store) ArchivedTasks() []model.Task {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var tasks []model.Task
+	for _, t := range s.tasks {
+		if b, ok := s.boards[t.BoardID]; t.Archived() && ok && !b.Archived() {
+			tasks = append(tasks, t.Clone())
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].ArchivedAt.After(*tasks[j].ArchivedAt)
+	})
+	return tasks
+}
+
+// BulkEdit applies an edit to the cached tasks with the given IDs and sends the changes to the server
+// as a single batch. Tasks the edit cannot be applied to, such as moves that break a blocking column
+// policy, are left out of the batch, and changes the server rejects are rolled back one by one unless
+// the task was changed again in the meantime; both are listed in the Failed field of the result.
+// An error is only returned when the whole batch failed, in which case every change is rolled back.
+func (s *Store) BulkEdit(ctx context.Context, edit model.BulkEdit, ids []string) (model.BatchResult, error) {
+	now := time.Now()
+	var result model.BatchResult
+	var batch model.TaskBatch
+	// optimistic holds the changed copies of the tasks in the batch, previous their cached versions
+	var optimistic, previous, trashed []model.Task
+	titles := map[string]string{}
+	// moved counts the cards the batch moves into each column of a board, so WIP limits hold for the batch as a whole
+	moved := map[[2]string]int{}
+	for _, id := range ids {
+		task, ok := s.Task(id)
+		if !ok {
+			result.Failed = append(result.Failed, model.BatchFailure{TaskID: id, Error: "task not found"})
+			continue
+		}
+		titles[id] = task.Title
+		board, ok := s.Board(task.BoardID)
+		if !ok {
+			result.Failed = append(result.Failed, model.BatchFailure{TaskID: id, Error: "board not found"})
+			continue
+		}
+		edited, err := edit.Apply(task, &board, now)
+		if err != nil {
+			result.Failed = append(result.Failed, model.BatchFailure{TaskID: id, Error: err.Error()})
+			continue
+		}
+		if edit.Action == model.BulkDelete {
+			edited.DeletedAt = &now
+			trashed = append(trashed, edited)
+			batch.Delete = append(batch.Delete, id)
+			optimistic, previous = append(optimistic, edited), append(previous, task)
+			continue
+		}
+		if edited.ColumnID != task.ColumnID {
+			column := [2]string{board.ID, edited.ColumnID}
+			count := s.ColumnCount(board.ID, edited.ColumnID, id) + moved[column]
+			if v := board.CheckMove(&edited, edited.ColumnID, count); v != nil && v.Blocking() {
+				result.Failed = append(result.Failed, model.BatchFailure{TaskID: id, Error: v.Error()})
+				continue
+			}
+			if edited.ParentID == "" {
+				moved[column]++
+			}
+		}
+		batch.Update = append(batch.Update, edited)
+		optimistic, previous = append(optimistic, edited), append(previous, task)
+	}
+	if len(previous) == 0 {
+		fillTitles(result.Failed, titles)
+		return result, nil
+	}
+	if err := s.putBatch(batch.Update, trashed, nil); err != nil {
+		return model.BatchResult{}, err
+	}
+
+	sent, err := s.remote.BatchTasks(ctx, batch)
+	if err != nil {
+		s.rollbackBatch(optimistic, previous)
+		return model.BatchResult{}, fmt.Errorf("failed to change %d tasks: %w", len(previous), err)
+	}
+	if err := s.putBatch(sent.Updated, nil, nil); err != nil {
+		return model.BatchResult{}, err
+	}
+	failed := map[string]bool{}
+	for _, f := range sent.Failed {
+		failed[f.TaskID] = true
+	}
+	var failedOptimistic, failedPrevious []model.Task
+	for i, task := range previous {
+		if failed[task.ID] {
+			failedOptimistic = append(failedOptimistic, optimistic[i])
+			failedPrevious = append(failedPrevious, task)
+		}
+	}
+	s.rollbackBatch(failedOptimistic, failedPrevious)
+
+	result.Updated, result.Deleted = sent.Updated, sent.Deleted
+	result.Failed = append(result.Failed, sent.Failed...)
+	fillTitles(result.Failed, titles)
+	return result, nil
+}
+
+// fillTitles sets the titles of failures from the titles of the tasks by ID
+func fillTitles(failures []model.BatchFailure, titles map[string]string) {
+	for i := range failures {
+		if failures[i].Title == "" {
+			failures[i].Title = titles[failures[i].TaskID]
+		}
+	}
+}
+
+// rollbackBatch restores the previous versions of tasks of a batch whose cached copy is still the optimistic
+// value that was sent to the server. Tasks that were moved to the trash are taken out of it again, unless they
+// were restored in the meantime.
+func (s *Store) rollbackBatch(optimistic, previous []model.Task) {
+	var put, untrash []model.Task
+	for i, task := range optimistic {
+		if task.DeletedAt != nil {
+			if _, restored := s.Task(task.ID); !restored {
+				untrash = append(untrash, previous[i])
+			}
+			continue
+		}
+		if current, ok := s.Task(task.ID); ok && reflect.DeepEqual(current, task) {
+			put = append(put, previous[i])
+		}
+	}
+	if len(put) > 0 || len(untrash) > 0 {
+		_ = s.putBatch(put, nil, untrash)
+	}
+}
+
+// putBatch stores tasks, moves the tasks of trash to the trash and takes those of untrash out of it,
+// in memory and in a single database transaction, then notifies listeners once
+func (s *Store) putBatch(put, trash, untrash []model.Task) error {
+	encode := func(tasks []model.Task) ([][]byte, error) {
+		payloads := make([][]byte, len(tasks))
+		for i, t := range tasks {
+			payload, err := json.Marshal(t)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode task: %w", err)
+			}
+			payloads[i] = payload
+		}
+		return payloads, nil
+	}
+	putPayloads, err := encode(put)
+	if err != nil {
+		return err
+	}
+	trashPayloads, err := encode(trash)
+	if err != nil {
+		return err
+	}
+	untrashPayloads, err := encode(untrash)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	err = s.db.Update(func(tx *bbolt.Tx) error {
+		tasks, trashed := tx.Bucket(tasksBucket), tx.Bucket(trashedTasksBucket)
+		for i, t := range put {
+			if err := tasks.Put([]byte(t.ID), putPayloads[i]); err != nil {
+				return err
+			}
+		}
+		for i, t := range trash {
+			if err := tasks.Delete([]byte(t.ID)); err != nil {
+				return err
+			}
+			if err := trashed.Put([]byte(t.ID), trashPayloads[i]); err != nil {
+				return err
+			}
+		}
+		for i, t := range untrash {
+			if err := trashed.Delete([]byte(t.ID)); err != nil {
+				return err
+			}
+			if err := tasks.Put([]byte(t.ID), untrashPayloads[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		for _, t := range put {
+			s.tasks[t.ID] = t.Clone()
+		}
+		for _, t := range trash {
+			delete(s.tasks, t.ID)
+			s.trashedTasks[t.ID] = t.Clone()
+		}
+		for _, t := range untrash {
+			delete(s.trashedTasks, t.ID)
+			s.tasks[t.ID] = t.Clone()
+		}
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to save tasks: %w", err)
+	}
+
+	s.notify()
+	return nil
+}
@@ -0,0 +1,97 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"eldar/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreBulkEdit(t *testing.T) {
+	remote := newFakeRemote()
+	remote.boards[0].Columns[1].WIPLimit = 2
+	remote.boards[0].Columns[1].WIPPolicy = model.WIPBlock
+	remote.tasks["t2"] = model.Task{ID: "t2", BoardID: "b1", ColumnID: "todo", Title: "Review budget", Version: 1}
+	remote.tasks["t3"] = model.Task{ID: "t3", BoardID: "b1", ColumnID: "todo", Title: "Book venue", Version: 1}
+	s := openSynced(t, remote)
+	notified := 0
+	s.OnChange(func() {
+		notified++
+	})
+
+	// The third card would take Done over its blocking WIP limit, and unknown tasks are reported
+	result, err := s.BulkEdit(context.Background(), model.BulkEdit{Action: model.BulkMove, Column: "Done"}, []string{"t1", "t2", "t3", "gone"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Changed())
+	assert.Equal(t, []model.BatchFailure{
+		{TaskID: "t3", Title: "Book venue", Error: "Done is at its limit of 2 tasks"},
+		{TaskID: "gone", Error: "task not found"},
+	}, result.Failed)
+	assert.Equal(t, 2, notified, "the optimistic changes and the server copies are each saved at once")
+	moved, _ := s.Task("t2")
+	assert.Equal(t, "done", moved.ColumnID)
+	assert.Equal(t, int64(2), moved.Version)
+	left, _ := s.Task("t3")
+	assert.Equal(t, "todo", left.ColumnID)
+
+	// Changes the server rejects are rolled back, the others are kept
+	remote.mu.Lock()
+	stale := remote.tasks["t2"]
+	stale.Version = 5
+	remote.tasks["t2"] = stale
+	remote.mu.Unlock()
+	due := time.Date(2025, 6, 10, 0, 0, 0, 0, time.UTC)
+	result, err = s.BulkEdit(context.Background(), model.BulkEdit{Action: model.BulkDue, DueDate: &due}, []string{"t1", "t2"})
+	require.NoError(t, err)
+	assert.Equal(t, []model.BatchFailure{{TaskID: "t2", Title: "Review budget", Error: "version conflict"}}, result.Failed)
+	scheduled, _ := s.Task("t1")
+	assert.Equal(t, due, *scheduled.DueDate)
+	conflicted, _ := s.Task("t2")
+	assert.Nil(t, conflicted.DueDate)
+	assert.EqualError(t, result.Err(), "1 of 2 tasks could not be changed:\nReview budget: version conflict")
+
+	// Archived tasks leave the board, search and column counts
+	_, err = s.BulkEdit(context.Background(), model.BulkEdit{Action: model.BulkArchive}, []string{"t1"})
+	require.NoError(t, err)
+	assert.Len(t, s.Tasks("b1"), 2)
+	assert.Len(t, s.AllTasks(), 2)
+	assert.Equal(t, 1, s.ColumnCount("b1", "done", ""))
+	require.Len(t, s.ArchivedTasks(), 1)
+	assert.Equal(t, "t1", s.ArchivedTasks()[0].ID)
+
+	// Deleted tasks go to the trash, archived ones included
+	result, err = s.BulkEdit(context.Background(), model.BulkEdit{Action: model.BulkDelete}, []string{"t1", "t3"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"t1", "t3"}, result.Deleted)
+	assert.Len(t, s.Trash().Tasks, 2)
+	assert.Empty(t, s.ArchivedTasks())
+	_, ok := s.Task("t3")
+	assert.False(t, ok)
+}
+
+func TestStoreBulkEditOffline(t *testing.T) {
+	remote := newFakeRemote()
+	remote.tasks["t2"] = model.Task{ID: "t2", BoardID: "b1", ColumnID: "todo", Title: "Review budget", Version: 1}
+	s := openSynced(t, remote)
+	remote.fail(errors.New("offline"))
+
+	_, err := s.BulkEdit(context.Background(), model.BulkEdit{Action: model.BulkAssign, Assignee: "ana@example.com"}, []string{"t1", "t2"})
+	assert.EqualError(t, err, "failed to change 2 tasks: offline")
+	for _, task := range s.Tasks("b1") {
+		assert.Empty(t, task.Assignees, task.ID)
+	}
+
+	_, err = s.BulkEdit(context.Background(), model.BulkEdit{Action: model.BulkDelete}, []string{"t1", "t2"})
+	assert.Error(t, err)
+	assert.Len(t, s.Tasks("b1"), 2)
+	assert.Empty(t, s.Trash().Tasks)
+
+	// Nothing is sent when no task can be changed
+	result, err := s.BulkEdit(context.Background(), model.BulkEdit{Action: model.BulkMove, Column: "Review"}, []string{"t1"})
+	require.NoError(t, err)
+	assert.Equal(t, []model.BatchFailure{{TaskID: "t1", Title: "Write report", Error: "board Sprint has no column Review"}}, result.Failed)
+}
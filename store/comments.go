@@ -0,0 +1,273 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"eldar/model"
+	"go.etcd.io/bbolt"
+)
+
+// Members returns the cached members of the group sorted by handle
+func (s *Store) Members() []model.Member {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	members := make([]model.Member, 0, len(s.members))
+	for _, m := range s.members {
+		members = append(members, m)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return strings.ToLower(members[i].Handle) < strings.ToLower(members[j].Handle)
+	})
+	return members
+}
+
+// Member returns the cached group member with the given username
+func (s *Store) Member(username string) (model.Member, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	m, ok := s.members[username]
+	return m, ok
+}
+
+// Comments returns the cached comments of a task ordered as threads, see model.Thread
+func (s *Store) Comments(taskID string) []model.ThreadEntry {
+	s.mu.RLock()
+	var comments []model.Comment
+	for _, c := range s.comments {
+		if c.TaskID == taskID {
+			comments = append(comments, c.Clone())
+		}
+	}
+	s.mu.RUnlock()
+	return model.Thread(comments)
+}
+
+// AllComments returns the cached comments of every task, in no particular order
+func (s *Store) AllComments() []model.Comment {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	comments := make([]model.Comment, 0, len(s.comments))
+	for _, c := range s.comments {
+		comments = append(comments, c.Clone())
+	}
+	return comments
+}
+
+// Comment returns the cached comment with the given ID
+func (s *Store) Comment(id string) (model.Comment, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	c, ok := s.comments[id]
+	return c.Clone(), ok
+}
+
+// SyncComments replaces the cached comments of a task with those currently on the server
+func (s *Store) SyncComments(ctx context.Context, taskID string) error {
+	comments, err := s.remote.ListComments(ctx, taskID)
+	if err != nil {
+		return fmt.Errorf("failed to list comments: %w", err)
+	}
+
+	s.mu.Lock()
+	err = s.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(commentsBucket)
+		for id, c := range s.comments {
+			if c.TaskID == taskID {
+				if err := b.Delete([]byte(id)); err != nil {
+					return err
+				}
+			}
+		}
+		for _, c := range comments {
+			payload, err := json.Marshal(c)
+			if err != nil {
+				return fmt.Errorf("failed to encode %s: %w", c.ID, err)
+			}
+			if err := b.Put([]byte(c.ID), payload); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		for id, c := range s.comments {
+			if c.TaskID == taskID {
+				delete(s.comments, id)
+			}
+		}
+		for _, c := range comments {
+			s.comments[c.ID] = c.Clone()
+		}
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to save comments: %w", err)
+	}
+
+	s.notify()
+	return nil
+}
+
+// ApplyComment stores a comment received from the event stream, unless the cache already has
+// the same or a newer version of it
+func (s *Store) ApplyComment(comment model.Comment) error {
+	if cached, ok := s.Comment(comment.ID); ok && cached.Version >= comment.Version {
+		return nil
+	}
+	return s.putComment(comment)
+}
+
+// CreateComment adds a comment to the cache and posts it on the server.
+// The members mentioned in the body are looked up first, so the server can notify them.
+// The comment is replaced by the server copy once posted, and removed again when the server rejects it.
+func (s *Store) CreateComment(ctx context.Context, comment model.Comment) (model.Comment, error) {
+	if err := comment.Validate(); err != nil {
+		return model.Comment{}, err
+	}
+	if _, ok := s.Task(comment.TaskID); !ok {
+		return model.Comment{}, fmt.Errorf("task %s: %w", comment.TaskID, ErrNotFound)
+	}
+	if comment.ID == "" {
+		id, err := newID()
+		if err != nil {
+			return model.Comment{}, err
+		}
+		comment.ID = id
+	}
+	comment.Mentions = model.ParseMentions(comment.Body, s.Members())
+	if comment.CreatedAt.IsZero() {
+		comment.CreatedAt = time.Now()
+		comment.UpdatedAt = comment.CreatedAt
+	}
+	if err := s.putComment(comment); err != nil {
+		return model.Comment{}, err
+	}
+
+	created, err := s.remote.CreateComment(ctx, comment)
+	if err != nil {
+		s.rollbackComment(comment.ID, comment, nil)
+		return model.Comment{}, fmt.Errorf("failed to post comment: %w", err)
+	}
+	if created.ID != comment.ID {
+		if err := s.deleteComment(comment.ID); err != nil {
+			return model.Comment{}, err
+		}
+	}
+	if err := s.putComment(*created); err != nil {
+		return model.Comment{}, err
+	}
+	return *created, nil
+}
+
+// UpdateComment replaces the body of a cached comment, keeping the previous body in its history,
+// and sends the edit to the server. The previous version is restored when the server rejects the edit,
+// unless the comment was changed again in the meantime.
+func (s *Store) UpdateComment(ctx context.Context, id, body string) error {
+	previous, ok := s.Comment(id)
+	if !ok {
+		return fmt.Errorf("comment %s: %w", id, ErrNotFound)
+	}
+	if previous.Deleted {
+		return fmt.Errorf("comment %s was deleted", id)
+	}
+	edited := previous.Clone()
+	edited.Edit(body, time.Now())
+	if err := edited.Validate(); err != nil {
+		return err
+	}
+	edited.Mentions = model.ParseMentions(body, s.Members())
+	if err := s.putComment(edited); err != nil {
+		return err
+	}
+
+	updated, err := s.remote.UpdateComment(ctx, edited)
+	if err != nil {
+		s.rollbackComment(id, edited, &previous)
+		return fmt.Errorf("failed to edit comment: %w", err)
+	}
+	return s.putComment(*updated)
+}
+
+// DeleteComment marks a cached comment as deleted, moving its body to its history, and deletes it
+// on the server. The comment is restored when the server rejects the deletion.
+func (s *Store) DeleteComment(ctx context.Context, id string) error {
+	previous, ok := s.Comment(id)
+	if !ok {
+		return fmt.Errorf("comment %s: %w", id, ErrNotFound)
+	}
+	deleted := previous.Clone()
+	deleted.Edit("", time.Now())
+	deleted.Deleted = true
+	deleted.Mentions = nil
+	if err := s.putComment(deleted); err != nil {
+		return err
+	}
+
+	stored, err := s.remote.DeleteComment(ctx, id)
+	if err != nil {
+		s.rollbackComment(id, deleted, &previous)
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+	return s.putComment(*stored)
+}
+
+// rollbackComment restores previous, or removes the comment when previous is nil, if the cached
+// comment is still the optimistic value that was sent to the server
+func (s *Store) rollbackComment(id string, optimistic model.Comment, previous *model.Comment) {
+	current, ok := s.Comment(id)
+	if !ok || !reflect.DeepEqual(current, optimistic) {
+		return
+	}
+	if previous == nil {
+		_ = s.deleteComment(id)
+		return
+	}
+	_ = s.putComment(*previous)
+}
+
+// putComment stores a comment in memory and in the database, then notifies listeners
+func (s *Store) putComment(comment model.Comment) error {
+	payload, err := json.Marshal(comment)
+	if err != nil {
+		return fmt.Errorf("failed to encode comment: %w", err)
+	}
+
+	s.mu.Lock()
+	err = s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(commentsBucket).Put([]byte(comment.ID), payload)
+	})
+	if err == nil {
+		s.comments[comment.ID] = comment.Clone()
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to save comment: %w", err)
+	}
+
+	s.notify()
+	return nil
+}
+
+// deleteComment removes a comment from memory and from the database, then notifies listeners
+func (s *Store) deleteComment(id string) error {
+	s.mu.Lock()
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(commentsBucket).Delete([]byte(id))
+	})
+	if err == nil {
+		delete(s.comments, id)
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+
+	s.notify()
+	return nil
+}
@@ -0,0 +1,110 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"eldar/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// threadIDs returns the IDs of thread entries in order, indented by depth
+func threadIDs(entries []model.ThreadEntry) []string {
+	ids := make([]string, len(entries))
+	for i, e := range entries {
+		ids[i] = e.Comment.ID
+		for range e.Depth {
+			ids[i] = ">" + ids[i]
+		}
+	}
+	return ids
+}
+
+func TestStoreMembers(t *testing.T) {
+	s := openSynced(t, newFakeRemote())
+	members := s.Members()
+	require.Len(t, members, 2)
+	assert.Equal(t, "ana", members[0].Handle)
+	m, ok := s.Member("ben@example.com")
+	require.True(t, ok)
+	assert.Equal(t, "Ben Kay", m.Name)
+}
+
+func TestStoreComments(t *testing.T) {
+	remote := newFakeRemote()
+	remote.comments["c1"] = model.Comment{ID: "c1", TaskID: "t1", Author: "ben@example.com", Body: "Draft is up", Version: 1}
+	s := openSynced(t, remote)
+	assert.Empty(t, s.Comments("t1"))
+	require.NoError(t, s.SyncComments(context.Background(), "t1"))
+	assert.Equal(t, []string{"c1"}, threadIDs(s.Comments("t1")))
+
+	// Mentions are looked up from the body before posting
+	reply, err := s.CreateComment(context.Background(), model.Comment{ID: "r1", TaskID: "t1", ParentID: "c1", Author: "ana@example.com", Body: "Thanks @ben!"})
+	require.NoError(t, err)
+	assert.Equal(t, "server-r1", reply.ID)
+	assert.Equal(t, []string{"ben@example.com"}, reply.Mentions)
+	assert.False(t, reply.CreatedAt.IsZero())
+	assert.Equal(t, []string{"c1", ">server-r1"}, threadIDs(s.Comments("t1")))
+
+	require.NoError(t, s.UpdateComment(context.Background(), "server-r1", "Thanks @ana and @ben"))
+	edited, _ := s.Comment("server-r1")
+	assert.Equal(t, "Thanks @ana and @ben", edited.Body)
+	assert.Equal(t, "Thanks @ben!", edited.History[0].Body)
+	assert.Equal(t, []string{"ana@example.com", "ben@example.com"}, edited.Mentions)
+	assert.Equal(t, int64(2), edited.Version)
+
+	require.NoError(t, s.DeleteComment(context.Background(), "c1"))
+	deleted, _ := s.Comment("c1")
+	assert.True(t, deleted.Deleted)
+	assert.Empty(t, deleted.Body)
+	// The reply keeps its place under the deleted comment
+	assert.Equal(t, []string{"c1", ">server-r1"}, threadIDs(s.Comments("t1")))
+	assert.Error(t, s.UpdateComment(context.Background(), "c1", "Back again"))
+
+	_, err = s.CreateComment(context.Background(), model.Comment{TaskID: "missing", Body: "Hello"})
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = s.CreateComment(context.Background(), model.Comment{TaskID: "t1", Body: " "})
+	assert.Error(t, err)
+}
+
+func TestStoreCommentsRollback(t *testing.T) {
+	remote := newFakeRemote()
+	remote.comments["c1"] = model.Comment{ID: "c1", TaskID: "t1", Body: "Draft is up", Version: 1}
+	s := openSynced(t, remote)
+	require.NoError(t, s.SyncComments(context.Background(), "t1"))
+	remote.fail(errors.New("offline"))
+
+	_, err := s.CreateComment(context.Background(), model.Comment{TaskID: "t1", Body: "Hello"})
+	assert.Error(t, err)
+	assert.Len(t, s.Comments("t1"), 1)
+
+	original, _ := s.Comment("c1")
+	assert.Error(t, s.UpdateComment(context.Background(), "c1", "Final is up"))
+	assert.Error(t, s.DeleteComment(context.Background(), "c1"))
+	current, _ := s.Comment("c1")
+	assert.Equal(t, original, current)
+
+	// The cache keeps the comments while offline
+	assert.Error(t, s.SyncComments(context.Background(), "t1"))
+	assert.Len(t, s.Comments("t1"), 1)
+}
+
+func TestStoreApplyComment(t *testing.T) {
+	s := openSynced(t, newFakeRemote())
+	now := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	require.NoError(t, s.ApplyComment(model.Comment{ID: "c1", TaskID: "t1", Body: "Second", Version: 2, CreatedAt: now}))
+	// An older version arriving late is ignored
+	require.NoError(t, s.ApplyComment(model.Comment{ID: "c1", TaskID: "t1", Body: "First", Version: 1, CreatedAt: now}))
+	c, _ := s.Comment("c1")
+	assert.Equal(t, "Second", c.Body)
+
+	// Comments of tasks that are gone are dropped on sync
+	require.NoError(t, s.ApplyComment(model.Comment{ID: "c2", TaskID: "gone", Body: "Orphan", Version: 1}))
+	require.NoError(t, s.Sync(context.Background()))
+	_, ok := s.Comment("c2")
+	assert.False(t, ok)
+	assert.Len(t, s.AllComments(), 1)
+}
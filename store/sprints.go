@@ -0,0 +1,36 @@
+package store
+
+import (
+	"fmt"
+	"time"
+
+	"eldar/model"
+)
+
+// Burn returns the progress of a sprint of a board, worked out from the cached tasks of the board, those in
+// the trash included, and its cached activity log, see model.ComputeBurn
+func (s *Store) Burn(boardID, sprintID string, now time.Time) (model.Burn, error) {
+	board, ok := s.Board(boardID)
+	if !ok {
+		return model.Burn{}, fmt.Errorf("board %s: %w", boardID, ErrNotFound)
+	}
+	sprint, ok := board.Sprint(sprintID)
+	if !ok {
+		return model.Burn{}, fmt.Errorf("sprint %s: %w", sprintID, ErrNotFound)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var tasks []model.Task
+	for _, t := range s.tasks {
+		if t.BoardID == boardID {
+			tasks = append(tasks, t)
+		}
+	}
+	for _, t := range s.trashedTasks {
+		if t.BoardID == boardID {
+			tasks = append(tasks, t)
+		}
+	}
+	return model.ComputeBurn(sprint, tasks, s.activity[boardID], now), nil
+}
@@ -0,0 +1,36 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"eldar/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreBurn(t *testing.T) {
+	start := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	remote := newFakeRemote()
+	remote.boards[0].Sprints = []model.Sprint{{ID: "s1", Name: "Sprint 1", Start: start, End: start.AddDate(0, 0, 4)}}
+	remote.tasks["t1"] = model.Task{ID: "t1", BoardID: "b1", Title: "Write report", SprintID: "s1", Estimate: 3, Done: true, Version: 2}
+	deleted := start.Add(30 * time.Hour)
+	remote.trashedTasks["t2"] = model.Task{ID: "t2", BoardID: "b1", Title: "Book venue", SprintID: "s1", Estimate: 5, DeletedAt: &deleted}
+	remote.activity = []model.Activity{
+		{Seq: 1, BoardID: "b1", TaskID: "t1", Kind: model.ActivityEdited, Field: "done", From: "false", To: "true", At: start.Add(26 * time.Hour)},
+		{Seq: 2, BoardID: "b1", TaskID: "t2", Kind: model.ActivityDeleted, At: deleted},
+	}
+	s := openSynced(t, remote)
+
+	burn, err := s.Burn("b1", "s1", start.AddDate(0, 0, 2).In(time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, model.BurnPoints, burn.Unit)
+	require.Len(t, burn.Days, 3)
+	assert.Equal(t, model.BurnDay{Day: start, Scope: 8}, burn.Days[0])
+	assert.Equal(t, model.BurnDay{Day: start.AddDate(0, 0, 1), Scope: 3, Completed: 3}, burn.Days[1])
+
+	_, err = s.Burn("b1", "s2", start)
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = s.Burn("b2", "s1", start)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,785 @@
+// Package store caches the boards, tasks, comments, saved views, templates, group members, trash and time entries
+// of the signed in account in a local bbolt database, along with its running timer.
+// Edits are applied to the cache first so the UI updates immediately, then sent to the server,
+// and rolled back when the server rejects them.
+package store
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"eldar/model"
+	"go.etcd.io/bbolt"
+)
+
+// Bucket names of the cache database
+var (
+	boardsBucket    = []byte("boards")
+	tasksBucket     = []byte("tasks")
+	viewsBucket     = []byte("views")
+	templatesBucket = []byte("templates")
+	commentsBucket  = []byte("comments")
+	membersBucket   = []byte("members")
+	// activityBucket holds the event logs of the boards, keyed by board ID and sequence number, see activityKey
+	activityBucket = []byte("activity")
+	// trashedBoardsBucket and trashedTasksBucket hold the boards and tasks in the trash, keyed by ID
+	trashedBoardsBucket = []byte("trashed_boards")
+	trashedTasksBucket  = []byte("trashed_tasks")
+	timeEntriesBucket   = []byte("time_entries")
+	// timerBucket holds the running timer under timerKey, it only lives on this device
+	timerBucket = []byte("timer")
+)
+
+// ErrNotFound is returned when a board or task is not in the cache
+var ErrNotFound = errors.New("not found")
+
+// Remote is the subset of the Eldar server API used by the store
+type Remote interface {
+	ListBoards(ctx context.Context) ([]model.Board, error)
+	CreateBoard(ctx context.Context, board model.Board) (*model.Board, error)
+	UpdateBoard(ctx context.Context, board model.Board) (*model.Board, error)
+	ListTasks(ctx context.Context, boardID string) ([]model.Task, error)
+	CreateTask(ctx context.Context, task model.Task) (*model.Task, error)
+	UpdateTask(ctx context.Context, task model.Task) (*model.Task, error)
+	DeleteTask(ctx context.Context, taskID string) error
+	BatchTasks(ctx context.Context, batch model.TaskBatch) (*model.BatchResult, error)
+	ReorderChecklist(ctx context.Context, taskID string, version int64, itemIDs []string) (*model.Task, error)
+	ConvertChecklistItem(ctx context.Context, taskID, itemID string) (*model.Task, *model.Task, error)
+	ListViews(ctx context.Context) ([]model.View, error)
+	CreateView(ctx context.Context, view model.View) (*model.View, error)
+	UpdateView(ctx context.Context, view model.View) (*model.View, error)
+	DeleteView(ctx context.Context, viewID string) error
+	ListTemplates(ctx context.Context) ([]model.Template, error)
+	CreateTemplate(ctx context.Context, template model.Template) (*model.Template, error)
+	DeleteTemplate(ctx context.Context, templateID string) error
+	ListMembers(ctx context.Context) ([]model.Member, error)
+	ListComments(ctx context.Context, taskID string) ([]model.Comment, error)
+	CreateComment(ctx context.Context, comment model.Comment) (*model.Comment, error)
+	UpdateComment(ctx context.Context, comment model.Comment) (*model.Comment, error)
+	DeleteComment(ctx context.Context, commentID string) (*model.Comment, error)
+	UploadAttachment(ctx context.Context, taskID, name, mediaType string, content io.Reader, size int64, progress func(sent int64)) (*model.Attachment, error)
+	DeleteAttachment(ctx context.Context, attachmentID string) error
+	ListActivity(ctx context.Context, boardID string, after int64) ([]model.Activity, error)
+	ListTrash(ctx context.Context) (*model.Trash, error)
+	RestoreTask(ctx context.Context, taskID string) (*model.Task, error)
+	DeleteBoard(ctx context.Context, boardID string) error
+	RestoreBoard(ctx context.Context, boardID string) (*model.Board, error)
+	ArchiveBoard(ctx context.Context, boardID string, archived bool) (*model.Board, error)
+	ListTimeEntries(ctx context.Context, boardID string) ([]model.TimeEntry, error)
+	CreateTimeEntry(ctx context.Context, entry model.TimeEntry) (*model.TimeEntry, error)
+	DeleteTimeEntry(ctx context.Context, entryID string) error
+}
+
+// Store is the local cache of boards, tasks, comments, saved views, templates, group members, trash and time entries.
+// It is safe for concurrent use.
+type Store struct {
+	db     *bbolt.DB
+	remote Remote
+
+	mu     sync.RWMutex
+	boards map[string]model.Board
+	tasks  map[string]model.Task
+	views  map[string]model.View
+	// templates are the saved templates, the built-in ones are not cached
+	templates map[string]model.Template
+	comments  map[string]model.Comment
+	members   map[string]model.Member
+	// activity holds the cached event log of every board, oldest first
+	activity map[string][]model.Activity
+	// trashedBoards and trashedTasks are kept apart from boards and tasks, so the trash is left out
+	// of everything but the trash page
+	trashedBoards map[string]model.Board
+	trashedTasks  map[string]model.Task
+	timeEntries   map[string]model.TimeEntry
+	// timer is the running timer, or nil
+	timer *model.Timer
+
+	listenersMu sync.Mutex
+	listeners   []func()
+}
+
+// Open opens the cache database in dir, creating it if needed, and loads it into memory.
+//
+// Parameters:
+//   - dir: The account data directory, see credentials.AccountDataDir
+//   - remote: The server the cache is synchronised with
+//
+// Returns:
+//   - The opened Store, which must be closed with Close
+//   - An error if the database could not be opened or read
+func Open(dir string, remote Remote) (*Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	db, err := bbolt.Open(filepath.Join(dir, "cache.db"), 0600, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cache database: %w", err)
+	}
+
+	s := &Store{
+		db:        db,
+		remote:    remote,
+		boards:    map[string]model.Board{},
+		tasks:     map[string]model.Task{},
+		views:     map[string]model.View{},
+		templates: map[string]model.Template{},
+		comments:  map[string]model.Comment{},
+		members:   map[string]model.Member{},
+		activity:  map[string][]model.Activity{},
+
+		trashedBoards: map[string]model.Board{},
+		trashedTasks:  map[string]model.Task{},
+		timeEntries:   map[string]model.TimeEntry{},
+	}
+	if err := db.Update(func(tx *bbolt.Tx) error {
+		for _, name := range [][]byte{boardsBucket, tasksBucket, viewsBucket, templatesBucket, commentsBucket, membersBucket, activityBucket, trashedBoardsBucket, trashedTasksBucket, timeEntriesBucket, timerBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return fmt.Errorf("failed to create bucket %s: %w", name, err)
+			}
+		}
+		if err := loadBucket(tx.Bucket(boardsBucket), s.boards); err != nil {
+			return err
+		}
+		if err := loadBucket(tx.Bucket(tasksBucket), s.tasks); err != nil {
+			return err
+		}
+		if err := loadBucket(tx.Bucket(viewsBucket), s.views); err != nil {
+			return err
+		}
+		if err := loadBucket(tx.Bucket(templatesBucket), s.templates); err != nil {
+			return err
+		}
+		if err := loadBucket(tx.Bucket(commentsBucket), s.comments); err != nil {
+			return err
+		}
+		if err := loadBucket(tx.Bucket(membersBucket), s.members); err != nil {
+			return err
+		}
+		if err := loadBucket(tx.Bucket(trashedBoardsBucket), s.trashedBoards); err != nil {
+			return err
+		}
+		if err := loadBucket(tx.Bucket(trashedTasksBucket), s.trashedTasks); err != nil {
+			return err
+		}
+		if err := loadBucket(tx.Bucket(timeEntriesBucket), s.timeEntries); err != nil {
+			return err
+		}
+		if err := loadTimer(tx.Bucket(timerBucket), &s.timer); err != nil {
+			return err
+		}
+		return loadActivity(tx.Bucket(activityBucket), s.activity)
+	}); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to load cache: %w", err)
+	}
+	return s, nil
+}
+
+// Close closes the cache database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// OnChange registers a function called after the cached data changes.
+// It may be called from any goroutine, so UI code must hand over to fyne.Do.
+func (s *Store) OnChange(fn func()) {
+	s.listenersMu.Lock()
+	defer s.listenersMu.Unlock()
+	s.listeners = append(s.listeners, fn)
+}
+
+// notify calls every registered change listener
+func (s *Store) notify() {
+	s.listenersMu.Lock()
+	listeners := append([]func(){}, s.listeners...)
+	s.listenersMu.Unlock()
+	for _, fn := range listeners {
+		fn()
+	}
+}
+
+// Boards returns the cached boards that are not archived sorted by name, see ArchivedBoards
+func (s *Store) Boards() []model.Board {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	boards := make([]model.Board, 0, len(s.boards))
+	for _, b := range s.boards {
+		if !b.Archived() {
+			boards = append(boards, b)
+		}
+	}
+	sort.Slice(boards, func(i, j int) bool {
+		return boards[i].Name < boards[j].Name
+	})
+	return boards
+}
+
+// Board returns the cached board with the given ID, which may be archived
+func (s *Store) Board(id string) (model.Board, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	b, ok := s.boards[id]
+	return b, ok
+}
+
+// Tasks returns the cached tasks of a board that are not archived sorted by position, then title, see ArchivedTasks
+func (s *Store) Tasks(boardID string) []model.Task {
+	return s.boardTasks(boardID, false)
+}
+
+// boardTasks returns the cached tasks of a board sorted by position, then title, leaving out the archived ones
+// unless withArchived is set
+func (s *Store) boardTasks(boardID string, withArchived bool) []model.Task {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var tasks []model.Task
+	for _, t := range s.tasks {
+		if t.BoardID == boardID && (withArchived || !t.Archived()) {
+			tasks = append(tasks, t.Clone())
+		}
+	}
+	sortTasks(tasks)
+	return tasks
+}
+
+// AllTasks returns the cached tasks that are not archived of every board that is not archived,
+// sorted by position, then title
+func (s *Store) AllTasks() []model.Task {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	tasks := make([]model.Task, 0, len(s.tasks))
+	for _, t := range s.tasks {
+		if b, ok := s.boards[t.BoardID]; ok && b.Archived() || t.Archived() {
+			continue
+		}
+		tasks = append(tasks, t.Clone())
+	}
+	sortTasks(tasks)
+	return tasks
+}
+
+// Task returns the cached task with the given ID
+func (s *Store) Task(id string) (model.Task, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t, ok := s.tasks[id]
+	return t.Clone(), ok
+}
+
+// Subtasks returns the cached subtasks of a task that are not archived sorted by position, then title
+func (s *Store) Subtasks(parentID string) []model.Task {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var tasks []model.Task
+	for _, t := range s.tasks {
+		if t.ParentID == parentID && !t.Archived() {
+			tasks = append(tasks, t.Clone())
+		}
+	}
+	sortTasks(tasks)
+	return tasks
+}
+
+// Progress returns the completion of a task rolled up from its checklist and subtasks, see model.RollUp
+func (s *Store) Progress(id string) model.Progress {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return model.RollUp(id, s.tasks)
+}
+
+// OpenBlockers returns the tasks blocking a task that are not completed yet, see model.OpenBlockers
+func (s *Store) OpenBlockers(id string) []model.Task {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return model.OpenBlockers(id, s.tasks)
+}
+
+// ValidateDependencies checks that a task can be blocked by the given tasks without creating a cycle,
+// see model.ValidateDependencies
+func (s *Store) ValidateDependencies(taskID string, blockedBy []string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return model.ValidateDependencies(taskID, blockedBy, s.tasks)
+}
+
+// Sync replaces the cache with the boards, tasks, views, templates, members, trash and time entries currently on the server.
+// Comments are synced per task with SyncComments, those of tasks that are gone are dropped.
+// The event logs of the boards only grow, so just their new entries are fetched.
+func (s *Store) Sync(ctx context.Context) error {
+	boards, err := s.remote.ListBoards(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list boards: %w", err)
+	}
+	var tasks []model.Task
+	var entries []model.TimeEntry
+	for _, b := range boards {
+		boardTasks, err := s.remote.ListTasks(ctx, b.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list tasks of board %s: %w", b.ID, err)
+		}
+		tasks = append(tasks, boardTasks...)
+		boardEntries, err := s.remote.ListTimeEntries(ctx, b.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list time entries of board %s: %w", b.ID, err)
+		}
+		entries = append(entries, boardEntries...)
+	}
+	views, err := s.remote.ListViews(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list views: %w", err)
+	}
+	templates, err := s.remote.ListTemplates(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list templates: %w", err)
+	}
+	members, err := s.remote.ListMembers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list members: %w", err)
+	}
+	trash, err := s.remote.ListTrash(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list trash: %w", err)
+	}
+	activity := make(map[string][]model.Activity, len(boards))
+	for _, b := range boards {
+		entries, err := s.remote.ListActivity(ctx, b.ID, s.lastActivity(b.ID))
+		if err != nil {
+			return fmt.Errorf("failed to list activity of board %s: %w", b.ID, err)
+		}
+		activity[b.ID] = entries
+	}
+
+	boardsByID := make(map[string]model.Board, len(boards))
+	for _, b := range boards {
+		boardsByID[b.ID] = b
+	}
+	tasksByID := make(map[string]model.Task, len(tasks))
+	for _, t := range tasks {
+		tasksByID[t.ID] = t
+	}
+	viewsByID := make(map[string]model.View, len(views))
+	for _, v := range views {
+		viewsByID[v.ID] = v
+	}
+	templatesByID := make(map[string]model.Template, len(templates))
+	for _, t := range templates {
+		templatesByID[t.ID] = t
+	}
+	membersByName := make(map[string]model.Member, len(members))
+	for _, m := range members {
+		membersByName[m.Username] = m
+	}
+	trashedBoardsByID := make(map[string]model.Board, len(trash.Boards))
+	for _, b := range trash.Boards {
+		trashedBoardsByID[b.ID] = b
+	}
+	trashedTasksByID := make(map[string]model.Task, len(trash.Tasks))
+	for _, t := range trash.Tasks {
+		trashedTasksByID[t.ID] = t
+	}
+	entriesByID := make(map[string]model.TimeEntry, len(entries))
+	for _, e := range entries {
+		entriesByID[e.ID] = e
+	}
+
+	s.mu.Lock()
+	commentsByID := make(map[string]model.Comment, len(s.comments))
+	for id, c := range s.comments {
+		if _, ok := tasksByID[c.TaskID]; ok {
+			commentsByID[id] = c
+		}
+	}
+	err = s.db.Update(func(tx *bbolt.Tx) error {
+		if err := replaceBucket(tx, boardsBucket, boardsByID); err != nil {
+			return err
+		}
+		if err := replaceBucket(tx, tasksBucket, tasksByID); err != nil {
+			return err
+		}
+		if err := replaceBucket(tx, viewsBucket, viewsByID); err != nil {
+			return err
+		}
+		if err := replaceBucket(tx, templatesBucket, templatesByID); err != nil {
+			return err
+		}
+		if err := replaceBucket(tx, commentsBucket, commentsByID); err != nil {
+			return err
+		}
+		if err := replaceBucket(tx, membersBucket, membersByName); err != nil {
+			return err
+		}
+		if err := replaceBucket(tx, trashedBoardsBucket, trashedBoardsByID); err != nil {
+			return err
+		}
+		if err := replaceBucket(tx, trashedTasksBucket, trashedTasksByID); err != nil {
+			return err
+		}
+		if err := replaceBucket(tx, timeEntriesBucket, entriesByID); err != nil {
+			return err
+		}
+		for boardID := range s.activity {
+			if _, ok := boardsByID[boardID]; !ok {
+				if err := deleteActivity(tx.Bucket(activityBucket), boardID); err != nil {
+					return err
+				}
+			}
+		}
+		for boardID, entries := range activity {
+			activity[boardID] = s.newActivity(boardID, entries)
+			if err := putActivity(tx.Bucket(activityBucket), activity[boardID]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		s.boards, s.tasks, s.views, s.templates = boardsByID, tasksByID, viewsByID, templatesByID
+		s.comments, s.members = commentsByID, membersByName
+		s.trashedBoards, s.trashedTasks = trashedBoardsByID, trashedTasksByID
+		s.timeEntries = entriesByID
+		for boardID := range s.activity {
+			if _, ok := boardsByID[boardID]; !ok {
+				delete(s.activity, boardID)
+			}
+		}
+		for boardID, entries := range activity {
+			s.activity[boardID] = append(s.activity[boardID], entries...)
+		}
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to save cache: %w", err)
+	}
+
+	s.notify()
+	return nil
+}
+
+// CreateTask adds a task to the cache and creates it on the server.
+// Like a move, adding the task to its column must not break the blocking policies of the column, see CheckMove.
+// The task is given a temporary ID if it has none, and replaced by the server copy once created.
+// It is removed from the cache again when the server rejects it.
+func (s *Store) CreateTask(ctx context.Context, task model.Task) (model.Task, error) {
+	if err := task.Validate(); err != nil {
+		return model.Task{}, err
+	}
+	if err := s.checkFields(&task); err != nil {
+		return model.Task{}, err
+	}
+	if v := s.CheckMove(task, task.ColumnID); v != nil && v.Blocking() {
+		return model.Task{}, v
+	}
+	if task.ID == "" {
+		id, err := newID()
+		if err != nil {
+			return model.Task{}, err
+		}
+		task.ID = id
+	}
+	if err := s.putTask(task); err != nil {
+		return model.Task{}, err
+	}
+
+	created, err := s.remote.CreateTask(ctx, task)
+	if err != nil {
+		s.rollback(task.ID, task, nil)
+		return model.Task{}, fmt.Errorf("failed to create task: %w", err)
+	}
+	if created.ID != task.ID {
+		if err := s.deleteTask(task.ID); err != nil {
+			return model.Task{}, err
+		}
+	}
+	if err := s.putTask(*created); err != nil {
+		return model.Task{}, err
+	}
+	return *created, nil
+}
+
+// UpdateTask applies an edit to the cached task and sends it to the server.
+// Custom field values must fit the fields of the board, see model.Board.CheckFields, and moves into
+// another column, or unarchiving the task back into its column, must not break its blocking policies, see CheckMove.
+// Changed "blocked by" links are checked first, so an edit creating a dependency cycle is rejected.
+// Completing a recurring task also creates its next occurrence.
+// The previous version is restored when the server rejects the edit, unless the task
+// was changed again in the meantime.
+func (s *Store) UpdateTask(ctx context.Context, task model.Task) error {
+	if err := task.Validate(); err != nil {
+		return err
+	}
+	previous, ok := s.Task(task.ID)
+	if !ok {
+		return fmt.Errorf("task %s: %w", task.ID, ErrNotFound)
+	}
+	if err := s.checkFields(&task); err != nil {
+		return err
+	}
+	if task.ColumnID != previous.ColumnID || previous.Archived() && !task.Archived() {
+		if v := s.CheckMove(task, task.ColumnID); v != nil && v.Blocking() {
+			return v
+		}
+	}
+	if !reflect.DeepEqual(task.BlockedBy, previous.BlockedBy) {
+		if err := s.ValidateDependencies(task.ID, task.BlockedBy); err != nil {
+			return err
+		}
+	}
+	// Attachments are changed through their own endpoints, an upload may have finished while the task was edited
+	task.Attachments = previous.Attachments
+	next, err := scheduleNext(&task, previous)
+	if err != nil {
+		return err
+	}
+	if err := s.putTask(task); err != nil {
+		return err
+	}
+
+	updated, err := s.remote.UpdateTask(ctx, task)
+	if err != nil {
+		s.rollback(task.ID, task, &previous)
+		return fmt.Errorf("failed to update task: %w", err)
+	}
+	if err := s.putTask(*updated); err != nil {
+		return err
+	}
+	if next != nil {
+		// The next occurrence starts afresh, a column it does not qualify for sends it back to the first one
+		if v := s.CheckMove(*next, next.ColumnID); v != nil && v.Blocking() {
+			if board, ok := s.Board(next.BoardID); ok && len(board.Columns) > 0 {
+				next.ColumnID = board.Columns[0].ID
+			}
+		}
+		if _, err := s.CreateTask(ctx, *next); err != nil {
+			return fmt.Errorf("failed to schedule next occurrence: %w", err)
+		}
+	}
+	return nil
+}
+
+// scheduleNext hands the series of a recurring task that is being completed over to its next occurrence.
+// The completed task leaves the series, so completing it again does not create a duplicate,
+// and the occurrence to create is returned. It returns nil when nothing needs to be scheduled.
+func scheduleNext(task *model.Task, previous model.Task) (*model.Task, error) {
+	if !task.Done || previous.Done || task.Recurrence == nil {
+		return nil, nil
+	}
+	next, ok, err := task.NextOccurrence()
+	if err != nil {
+		return nil, err
+	}
+	task.Recurrence = nil
+	if !ok {
+		return nil, nil
+	}
+	return &next, nil
+}
+
+// DeleteTask moves a task to the trash in the cache and on the server, see RestoreTask.
+// The task is put back when the server rejects the deletion.
+func (s *Store) DeleteTask(ctx context.Context, id string) error {
+	previous, ok := s.Task(id)
+	if !ok {
+		return fmt.Errorf("task %s: %w", id, ErrNotFound)
+	}
+	deleted := previous.Clone()
+	now := time.Now()
+	deleted.DeletedAt = &now
+	if err := s.trashTask(deleted); err != nil {
+		return err
+	}
+
+	if err := s.remote.DeleteTask(ctx, id); err != nil {
+		if _, exists := s.Task(id); !exists {
+			_ = s.untrashTask(previous)
+		}
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+	return nil
+}
+
+// MoveChecklistItem moves a checklist item of a task from index from to index to.
+// The cache is updated immediately and restored when the server rejects the new order.
+func (s *Store) MoveChecklistItem(ctx context.Context, taskID string, from, to int) error {
+	previous, ok := s.Task(taskID)
+	if !ok {
+		return fmt.Errorf("task %s: %w", taskID, ErrNotFound)
+	}
+	moved := previous.Clone()
+	if err := moved.MoveChecklistItem(from, to); err != nil {
+		return err
+	}
+	if err := s.putTask(moved); err != nil {
+		return err
+	}
+
+	itemIDs := make([]string, len(moved.Checklist))
+	for i, item := range moved.Checklist {
+		itemIDs[i] = item.ID
+	}
+	updated, err := s.remote.ReorderChecklist(ctx, taskID, moved.Version, itemIDs)
+	if err != nil {
+		s.rollback(taskID, moved, &previous)
+		return fmt.Errorf("failed to reorder checklist: %w", err)
+	}
+	return s.putTask(*updated)
+}
+
+// ConvertChecklistItem turns a checklist item into a subtask of its task.
+// The item is replaced by a temporary subtask in the cache until the server returns the real one,
+// and put back when the server rejects the conversion.
+func (s *Store) ConvertChecklistItem(ctx context.Context, taskID, itemID string) (model.Task, error) {
+	previous, ok := s.Task(taskID)
+	if !ok {
+		return model.Task{}, fmt.Errorf("task %s: %w", taskID, ErrNotFound)
+	}
+	parent := previous.Clone()
+	subtask, err := parent.ConvertChecklistItem(itemID)
+	if err != nil {
+		return model.Task{}, err
+	}
+	if subtask.ID, err = newID(); err != nil {
+		return model.Task{}, err
+	}
+	if err := s.putTask(parent); err != nil {
+		return model.Task{}, err
+	}
+	if err := s.putTask(subtask); err != nil {
+		s.rollback(taskID, parent, &previous)
+		return model.Task{}, err
+	}
+
+	updatedParent, created, err := s.remote.ConvertChecklistItem(ctx, taskID, itemID)
+	if err != nil {
+		s.rollback(subtask.ID, subtask, nil)
+		s.rollback(taskID, parent, &previous)
+		return model.Task{}, fmt.Errorf("failed to convert checklist item: %w", err)
+	}
+	if err := s.deleteTask(subtask.ID); err != nil {
+		return model.Task{}, err
+	}
+	if err := s.putTask(*updatedParent); err != nil {
+		return model.Task{}, err
+	}
+	if err := s.putTask(*created); err != nil {
+		return model.Task{}, err
+	}
+	return *created, nil
+}
+
+// rollback restores previous, or removes the task when previous is nil, if the cached
+// task is still the optimistic value that was sent to the server
+func (s *Store) rollback(id string, optimistic model.Task, previous *model.Task) {
+	current, ok := s.Task(id)
+	if !ok || !reflect.DeepEqual(current, optimistic) {
+		return
+	}
+	if previous == nil {
+		_ = s.deleteTask(id)
+		return
+	}
+	_ = s.putTask(*previous)
+}
+
+// putTask stores a task in memory and in the database, then notifies listeners
+func (s *Store) putTask(task model.Task) error {
+	payload, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to encode task: %w", err)
+	}
+
+	s.mu.Lock()
+	err = s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(tasksBucket).Put([]byte(task.ID), payload)
+	})
+	if err == nil {
+		s.tasks[task.ID] = task.Clone()
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to save task: %w", err)
+	}
+
+	s.notify()
+	return nil
+}
+
+// deleteTask removes a task from memory and from the database, then notifies listeners
+func (s *Store) deleteTask(id string) error {
+	s.mu.Lock()
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(tasksBucket).Delete([]byte(id))
+	})
+	if err == nil {
+		delete(s.tasks, id)
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+
+	s.notify()
+	return nil
+}
+
+// loadBucket decodes every JSON value of a bucket into m
+func loadBucket[T any](b *bbolt.Bucket, m map[string]T) error {
+	return b.ForEach(func(k, v []byte) error {
+		var value T
+		if err := json.Unmarshal(v, &value); err != nil {
+			return fmt.Errorf("failed to decode %s: %w", k, err)
+		}
+		m[string(k)] = value
+		return nil
+	})
+}
+
+// replaceBucket recreates a bucket holding the JSON encoding of every value of m
+func replaceBucket[T any](tx *bbolt.Tx, name []byte, m map[string]T) error {
+	if err := tx.DeleteBucket(name); err != nil && !errors.Is(err, bbolt.ErrBucketNotFound) {
+		return fmt.Errorf("failed to delete bucket %s: %w", name, err)
+	}
+	b, err := tx.CreateBucket(name)
+	if err != nil {
+		return fmt.Errorf("failed to create bucket %s: %w", name, err)
+	}
+	for id, value := range m {
+		payload, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", id, err)
+		}
+		if err := b.Put([]byte(id), payload); err != nil {
+			return fmt.Errorf("failed to save %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// sortTasks orders tasks by position, then title
+func sortTasks(tasks []model.Task) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if tasks[i].Position != tasks[j].Position {
+			return tasks[i].Position < tasks[j].Position
+		}
+		return tasks[i].Title < tasks[j].Title
+	})
+}
+
+// newID returns a random identifier for objects created locally before the server knows them
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to create ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
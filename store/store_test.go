@@ -0,0 +1,770 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"eldar/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRemote is an in-memory server used to exercise the store
+type fakeRemote struct {
+	mu        sync.Mutex
+	boards    []model.Board
+	tasks     map[string]model.Task
+	views     map[string]model.View
+	templates map[string]model.Template
+	comments  map[string]model.Comment
+	members   []model.Member
+	activity  []model.Activity
+	// trashedBoards and trashedTasks are the boards and tasks in the trash
+	trashedBoards map[string]model.Board
+	trashedTasks  map[string]model.Task
+	timeEntries   map[string]model.TimeEntry
+	err           error
+}
+
+// newFakeRemote returns a server holding one board with two columns and one task
+func newFakeRemote() *fakeRemote {
+	return &fakeRemote{
+		boards: []model.Board{{ID: "b1", Name: "Sprint", Columns: []model.Column{{ID: "todo", Name: "To do"}, {ID: "done", Name: "Done"}}}},
+		tasks: map[string]model.Task{
+			"t1": {ID: "t1", BoardID: "b1", ColumnID: "todo", Title: "Write report", Version: 1},
+		},
+		views: map[string]model.View{
+			"v1": {ID: "v1", Name: "Release", Query: "label:release", Owner: "ben", Shared: true, Version: 1},
+		},
+		templates: map[string]model.Template{},
+		comments:  map[string]model.Comment{},
+		members: []model.Member{
+			{Username: "ana@example.com", Name: "Ana Lima", Handle: "ana"},
+			{Username: "ben@example.com", Name: "Ben Kay", Handle: "ben"},
+		},
+		trashedBoards: map[string]model.Board{},
+		trashedTasks:  map[string]model.Task{},
+		timeEntries:   map[string]model.TimeEntry{},
+	}
+}
+
+func (f *fakeRemote) ListBoards(ctx context.Context) ([]model.Board, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.boards, f.err
+}
+
+func (f *fakeRemote) CreateBoard(ctx context.Context, board model.Board) (*model.Board, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	board.ID = "server-" + board.ID
+	board.Version = 1
+	f.boards = append(f.boards, board)
+	return &board, nil
+}
+
+func (f *fakeRemote) UpdateBoard(ctx context.Context, board model.Board) (*model.Board, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	for i, b := range f.boards {
+		if b.ID == board.ID {
+			board.Version++
+			f.boards[i] = board
+			return &board, nil
+		}
+	}
+	return nil, errors.New("board not found")
+}
+
+func (f *fakeRemote) ListTasks(ctx context.Context, boardID string) ([]model.Task, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var tasks []model.Task
+	for _, t := range f.tasks {
+		if t.BoardID == boardID {
+			tasks = append(tasks, t)
+		}
+	}
+	return tasks, f.err
+}
+
+func (f *fakeRemote) CreateTask(ctx context.Context, task model.Task) (*model.Task, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	task.ID = "server-" + task.ID
+	task.Version = 1
+	f.tasks[task.ID] = task
+	return &task, nil
+}
+
+func (f *fakeRemote) UpdateTask(ctx context.Context, task model.Task) (*model.Task, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	task.Version++
+	f.tasks[task.ID] = task
+	return &task, nil
+}
+
+func (f *fakeRemote) DeleteTask(ctx context.Context, taskID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	task := f.tasks[taskID]
+	deleted := time.Now()
+	task.DeletedAt = &deleted
+	task.Version++
+	f.trashedTasks[taskID] = task
+	delete(f.tasks, taskID)
+	return nil
+}
+
+// BatchTasks applies a batch, failing updates of tasks whose version moved on since they were edited
+func (f *fakeRemote) BatchTasks(ctx context.Context, batch model.TaskBatch) (*model.BatchResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	var result model.BatchResult
+	for _, task := range batch.Update {
+		stored, ok := f.tasks[task.ID]
+		if !ok || stored.Version != task.Version {
+			result.Failed = append(result.Failed, model.BatchFailure{TaskID: task.ID, Error: "version conflict"})
+			continue
+		}
+		task.Version++
+		f.tasks[task.ID] = task
+		result.Updated = append(result.Updated, task)
+	}
+	for _, id := range batch.Delete {
+		task, ok := f.tasks[id]
+		if !ok {
+			result.Failed = append(result.Failed, model.BatchFailure{TaskID: id, Error: "task not found"})
+			continue
+		}
+		deleted := time.Now()
+		task.DeletedAt = &deleted
+		task.Version++
+		f.trashedTasks[id] = task
+		delete(f.tasks, id)
+		result.Deleted = append(result.Deleted, id)
+	}
+	return &result, nil
+}
+
+func (f *fakeRemote) ReorderChecklist(ctx context.Context, taskID string, version int64, itemIDs []string) (*model.Task, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	task := f.tasks[taskID].Clone()
+	if task.Version != version {
+		return nil, errors.New("version conflict")
+	}
+	byID := map[string]model.ChecklistItem{}
+	for _, item := range task.Checklist {
+		byID[item.ID] = item
+	}
+	task.Checklist = nil
+	for _, id := range itemIDs {
+		task.Checklist = append(task.Checklist, byID[id])
+	}
+	task.Version++
+	f.tasks[taskID] = task
+	return &task, nil
+}
+
+func (f *fakeRemote) ConvertChecklistItem(ctx context.Context, taskID, itemID string) (*model.Task, *model.Task, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, nil, f.err
+	}
+	parent := f.tasks[taskID].Clone()
+	subtask, err := parent.ConvertChecklistItem(itemID)
+	if err != nil {
+		return nil, nil, err
+	}
+	parent.Version++
+	subtask.ID = "server-" + itemID
+	subtask.Version = 1
+	f.tasks[parent.ID] = parent
+	f.tasks[subtask.ID] = subtask
+	return &parent, &subtask, nil
+}
+
+func (f *fakeRemote) ListViews(ctx context.Context) ([]model.View, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var views []model.View
+	for _, v := range f.views {
+		views = append(views, v)
+	}
+	return views, f.err
+}
+
+func (f *fakeRemote) CreateView(ctx context.Context, view model.View) (*model.View, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	view.ID = "server-" + view.ID
+	view.Version = 1
+	f.views[view.ID] = view
+	return &view, nil
+}
+
+func (f *fakeRemote) UpdateView(ctx context.Context, view model.View) (*model.View, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	view.Version++
+	f.views[view.ID] = view
+	return &view, nil
+}
+
+func (f *fakeRemote) DeleteView(ctx context.Context, viewID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	delete(f.views, viewID)
+	return nil
+}
+
+func (f *fakeRemote) ListTemplates(ctx context.Context) ([]model.Template, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var templates []model.Template
+	for _, t := range f.templates {
+		templates = append(templates, t)
+	}
+	return templates, f.err
+}
+
+func (f *fakeRemote) CreateTemplate(ctx context.Context, template model.Template) (*model.Template, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	template.ID = "server-" + template.ID
+	template.Version = 1
+	f.templates[template.ID] = template
+	return &template, nil
+}
+
+func (f *fakeRemote) DeleteTemplate(ctx context.Context, templateID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	delete(f.templates, templateID)
+	return nil
+}
+
+func (f *fakeRemote) ListMembers(ctx context.Context) ([]model.Member, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.members, f.err
+}
+
+func (f *fakeRemote) ListComments(ctx context.Context, taskID string) ([]model.Comment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var comments []model.Comment
+	for _, c := range f.comments {
+		if c.TaskID == taskID {
+			comments = append(comments, c)
+		}
+	}
+	return comments, f.err
+}
+
+func (f *fakeRemote) CreateComment(ctx context.Context, comment model.Comment) (*model.Comment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	comment.ID = "server-" + comment.ID
+	comment.Version = 1
+	f.comments[comment.ID] = comment
+	return &comment, nil
+}
+
+func (f *fakeRemote) UpdateComment(ctx context.Context, comment model.Comment) (*model.Comment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	comment.Version++
+	f.comments[comment.ID] = comment
+	return &comment, nil
+}
+
+func (f *fakeRemote) DeleteComment(ctx context.Context, commentID string) (*model.Comment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	comment := f.comments[commentID]
+	comment.History = append(comment.History, model.CommentRevision{Body: comment.Body})
+	comment.Body, comment.Deleted, comment.Mentions = "", true, nil
+	comment.Version++
+	f.comments[commentID] = comment
+	return &comment, nil
+}
+
+func (f *fakeRemote) UploadAttachment(ctx context.Context, taskID, name, mediaType string, content io.Reader, size int64, progress func(sent int64)) (*model.Attachment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return nil, err
+	}
+	if progress != nil {
+		progress(int64(len(data)))
+	}
+	task := f.tasks[taskID]
+	attachment := model.Attachment{ID: fmt.Sprintf("a%d", len(task.Attachments)+1), TaskID: taskID, Name: name, MediaType: mediaType, Size: int64(len(data))}
+	task.Attachments = append(task.Attachments, attachment)
+	f.tasks[taskID] = task
+	return &attachment, nil
+}
+
+func (f *fakeRemote) DeleteAttachment(ctx context.Context, attachmentID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	for id, task := range f.tasks {
+		var kept []model.Attachment
+		for _, a := range task.Attachments {
+			if a.ID != attachmentID {
+				kept = append(kept, a)
+			}
+		}
+		task.Attachments = kept
+		f.tasks[id] = task
+	}
+	return nil
+}
+
+func (f *fakeRemote) ListActivity(ctx context.Context, boardID string, after int64) ([]model.Activity, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var entries []model.Activity
+	for _, a := range f.activity {
+		if a.BoardID == boardID && a.Seq > after {
+			entries = append(entries, a)
+		}
+	}
+	return entries, f.err
+}
+
+func (f *fakeRemote) ListTrash(ctx context.Context) (*model.Trash, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var trash model.Trash
+	for _, b := range f.trashedBoards {
+		trash.Boards = append(trash.Boards, b)
+	}
+	for _, t := range f.trashedTasks {
+		trash.Tasks = append(trash.Tasks, t)
+	}
+	return &trash, f.err
+}
+
+func (f *fakeRemote) RestoreTask(ctx context.Context, taskID string) (*model.Task, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	task, ok := f.trashedTasks[taskID]
+	if !ok {
+		return nil, errors.New("not in trash")
+	}
+	task.DeletedAt = nil
+	task.Version++
+	f.tasks[taskID] = task
+	delete(f.trashedTasks, taskID)
+	return &task, nil
+}
+
+func (f *fakeRemote) DeleteBoard(ctx context.Context, boardID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	for i, b := range f.boards {
+		if b.ID == boardID {
+			deleted := time.Now()
+			b.DeletedAt = &deleted
+			f.trashedBoards[boardID] = b
+			f.boards = append(f.boards[:i:i], f.boards[i+1:]...)
+			return nil
+		}
+	}
+	return errors.New("board not found")
+}
+
+func (f *fakeRemote) RestoreBoard(ctx context.Context, boardID string) (*model.Board, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	board, ok := f.trashedBoards[boardID]
+	if !ok {
+		return nil, errors.New("not in trash")
+	}
+	board.DeletedAt = nil
+	board.Version++
+	f.boards = append(f.boards, board)
+	delete(f.trashedBoards, boardID)
+	return &board, nil
+}
+
+func (f *fakeRemote) ArchiveBoard(ctx context.Context, boardID string, archived bool) (*model.Board, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	for i, b := range f.boards {
+		if b.ID == boardID {
+			b.ArchivedAt = nil
+			if archived {
+				at := time.Date(2025, 6, 3, 10, 0, 0, 0, time.UTC)
+				b.ArchivedAt = &at
+			}
+			b.Version++
+			f.boards[i] = b
+			return &b, nil
+		}
+	}
+	return nil, errors.New("board not found")
+}
+
+// record appends an entry to the event log of a board
+func (f *fakeRemote) record(boardID, taskID string, kind model.ActivityKind) model.Activity {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var seq int64 = 1
+	for _, a := range f.activity {
+		if a.BoardID == boardID {
+			seq = a.Seq + 1
+		}
+	}
+	a := model.Activity{Seq: seq, BoardID: boardID, TaskID: taskID, Kind: kind, At: time.Date(2025, 6, 2, 9, 0, int(seq), 0, time.UTC)}
+	f.activity = append(f.activity, a)
+	return a
+}
+
+// fail makes every following request fail with err
+func (f *fakeRemote) fail(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+}
+
+// openSynced opens a store in a temporary directory and syncs it with remote
+func openSynced(t *testing.T, remote Remote) *Store {
+	s, err := Open(t.TempDir(), remote)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = s.Close()
+	})
+	require.NoError(t, s.Sync(context.Background()))
+	return s
+}
+
+func TestStoreSyncPersists(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(dir, newFakeRemote())
+	require.NoError(t, err)
+	changes := 0
+	s.OnChange(func() {
+		changes++
+	})
+	require.NoError(t, s.Sync(context.Background()))
+	assert.Equal(t, 1, changes)
+	require.NoError(t, s.Close())
+
+	// The cache is available offline after reopening
+	remote := newFakeRemote()
+	remote.fail(errors.New("offline"))
+	s, err = Open(dir, remote)
+	require.NoError(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+	assert.Error(t, s.Sync(context.Background()))
+	require.Len(t, s.Boards(), 1)
+	tasks := s.Tasks("b1")
+	require.Len(t, tasks, 1)
+	assert.Equal(t, "Write report", tasks[0].Title)
+	assert.Equal(t, tasks, s.AllTasks())
+}
+
+func TestStoreUpdateTask(t *testing.T) {
+	remote := newFakeRemote()
+	s := openSynced(t, remote)
+
+	task, ok := s.Task("t1")
+	require.True(t, ok)
+	task.Title = "Write monthly report"
+	task.Priority = model.PriorityHigh
+	require.NoError(t, s.UpdateTask(context.Background(), task))
+
+	task, _ = s.Task("t1")
+	assert.Equal(t, "Write monthly report", task.Title)
+	assert.Equal(t, int64(2), task.Version)
+
+	assert.Error(t, s.UpdateTask(context.Background(), model.Task{ID: "t1"}))
+	assert.ErrorIs(t, s.UpdateTask(context.Background(), model.Task{ID: "missing", Title: "x"}), ErrNotFound)
+}
+
+func TestStoreUpdateTaskRollback(t *testing.T) {
+	remote := newFakeRemote()
+	s := openSynced(t, remote)
+
+	// The UI sees the optimistic title before the server answers
+	var seen []string
+	s.OnChange(func() {
+		task, _ := s.Task("t1")
+		seen = append(seen, task.Title)
+	})
+
+	remote.fail(errors.New("server unreachable"))
+	task, _ := s.Task("t1")
+	task.Title = "Lost edit"
+	err := s.UpdateTask(context.Background(), task)
+	assert.ErrorContains(t, err, "server unreachable")
+
+	task, _ = s.Task("t1")
+	assert.Equal(t, "Write report", task.Title)
+	assert.Equal(t, []string{"Lost edit", "Write report"}, seen)
+}
+
+func TestStoreCreateAndDeleteTask(t *testing.T) {
+	remote := newFakeRemote()
+	s := openSynced(t, remote)
+
+	created, err := s.CreateTask(context.Background(), model.Task{BoardID: "b1", ColumnID: "todo", Title: "Review", Position: 1})
+	require.NoError(t, err)
+	assert.Contains(t, created.ID, "server-")
+	assert.Len(t, s.Tasks("b1"), 2)
+
+	_, err = s.CreateTask(context.Background(), model.Task{BoardID: "missing", Title: "Review"})
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	remote.fail(errors.New("server unreachable"))
+	_, err = s.CreateTask(context.Background(), model.Task{BoardID: "b1", ColumnID: "todo", Title: "Rejected"})
+	assert.Error(t, err)
+	assert.Len(t, s.Tasks("b1"), 2)
+
+	assert.Error(t, s.DeleteTask(context.Background(), created.ID))
+	_, ok := s.Task(created.ID)
+	assert.True(t, ok)
+
+	remote.fail(nil)
+	require.NoError(t, s.DeleteTask(context.Background(), created.ID))
+	_, ok = s.Task(created.ID)
+	assert.False(t, ok)
+}
+
+func TestStoreChecklist(t *testing.T) {
+	remote := newFakeRemote()
+	remote.tasks["t1"] = model.Task{ID: "t1", BoardID: "b1", ColumnID: "todo", Title: "Write report", Version: 1,
+		Checklist: []model.ChecklistItem{{ID: "a", Text: "Outline", Done: true}, {ID: "b", Text: "Draft"}, {ID: "c", Text: "Review"}}}
+	s := openSynced(t, remote)
+	assert.Equal(t, model.Progress{Done: 1, Total: 3}, s.Progress("t1"))
+
+	require.NoError(t, s.MoveChecklistItem(context.Background(), "t1", 2, 0))
+	task, _ := s.Task("t1")
+	assert.Equal(t, "c", task.Checklist[0].ID)
+	assert.Equal(t, int64(2), task.Version)
+	assert.Error(t, s.MoveChecklistItem(context.Background(), "t1", 0, 5))
+
+	subtask, err := s.ConvertChecklistItem(context.Background(), "t1", "b")
+	require.NoError(t, err)
+	assert.Equal(t, "server-b", subtask.ID)
+	assert.Equal(t, "t1", subtask.ParentID)
+	assert.Equal(t, []model.Task{subtask}, s.Subtasks("t1"))
+	task, _ = s.Task("t1")
+	assert.Len(t, task.Checklist, 2)
+	// The subtask replaces the item in the progress of the parent
+	assert.Equal(t, model.Progress{Done: 1, Total: 3}, s.Progress("t1"))
+	assert.Len(t, s.Tasks("b1"), 2)
+}
+
+func TestStoreChecklistRollback(t *testing.T) {
+	remote := newFakeRemote()
+	remote.tasks["t1"] = model.Task{ID: "t1", BoardID: "b1", ColumnID: "todo", Title: "Write report", Version: 1,
+		Checklist: []model.ChecklistItem{{ID: "a", Text: "Outline"}, {ID: "b", Text: "Draft"}}}
+	s := openSynced(t, remote)
+	remote.fail(errors.New("server unreachable"))
+
+	assert.Error(t, s.MoveChecklistItem(context.Background(), "t1", 1, 0))
+	task, _ := s.Task("t1")
+	assert.Equal(t, "a", task.Checklist[0].ID)
+
+	_, err := s.ConvertChecklistItem(context.Background(), "t1", "b")
+	assert.ErrorContains(t, err, "server unreachable")
+	task, _ = s.Task("t1")
+	assert.Len(t, task.Checklist, 2)
+	assert.Empty(t, s.Subtasks("t1"))
+	assert.Len(t, s.Tasks("b1"), 1)
+}
+
+func TestStoreDependencies(t *testing.T) {
+	remote := newFakeRemote()
+	remote.tasks["t2"] = model.Task{ID: "t2", BoardID: "b1", ColumnID: "todo", Title: "Review", BlockedBy: []string{"t1"}, Version: 1}
+	s := openSynced(t, remote)
+
+	blockers := s.OpenBlockers("t2")
+	require.Len(t, blockers, 1)
+	assert.Equal(t, "t1", blockers[0].ID)
+
+	// Blocking the report on its own review is rejected before reaching the server
+	task, _ := s.Task("t1")
+	task.BlockedBy = []string{"t2"}
+	var cycle *model.CycleError
+	require.ErrorAs(t, s.UpdateTask(context.Background(), task), &cycle)
+	assert.Equal(t, []string{"t1", "t2", "t1"}, cycle.Path)
+	assert.Empty(t, remote.tasks["t1"].BlockedBy)
+
+	// Completing the blocker unblocks the review
+	task.BlockedBy = nil
+	task.Done = true
+	require.NoError(t, s.UpdateTask(context.Background(), task))
+	assert.Empty(t, s.OpenBlockers("t2"))
+}
+
+func TestStoreRecurringTask(t *testing.T) {
+	remote := newFakeRemote()
+	due := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	remote.tasks["t1"] = model.Task{ID: "t1", BoardID: "b1", ColumnID: "todo", Title: "Water the plants", DueDate: &due, Version: 1,
+		Recurrence: &model.Recurrence{Rule: "FREQ=WEEKLY;COUNT=2", Start: due, TimeZone: "UTC"}}
+	s := openSynced(t, remote)
+
+	task, _ := s.Task("t1")
+	task.Done = true
+	require.NoError(t, s.UpdateTask(context.Background(), task))
+
+	// The completed occurrence leaves the series and the next one is due a week later
+	completed, _ := s.Task("t1")
+	assert.True(t, completed.Done)
+	assert.Nil(t, completed.Recurrence)
+	tasks := s.Tasks("b1")
+	require.Len(t, tasks, 2)
+	var next model.Task
+	for _, task := range tasks {
+		if task.ID != "t1" {
+			next = task
+		}
+	}
+	assert.Equal(t, due.AddDate(0, 0, 7), *next.DueDate)
+	assert.False(t, next.Done)
+	require.NotNil(t, next.Recurrence)
+
+	// Completing the task again does not create a duplicate
+	completed.Title = "Water all the plants"
+	require.NoError(t, s.UpdateTask(context.Background(), completed))
+	assert.Len(t, s.Tasks("b1"), 2)
+
+	// The last occurrence of the series schedules nothing
+	next.Done = true
+	require.NoError(t, s.UpdateTask(context.Background(), next))
+	assert.Len(t, s.Tasks("b1"), 2)
+}
+
+func TestStoreRecurringTaskDefinitionOfDone(t *testing.T) {
+	remote := newFakeRemote()
+	remote.boards[0].Columns = append(remote.boards[0].Columns, model.Column{ID: "checked", Name: "Checked", DefinitionOfDone: []string{"Reviewed"}})
+	due := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	remote.tasks["t1"] = model.Task{ID: "t1", BoardID: "b1", ColumnID: "checked", Title: "Check backups", DueDate: &due, Version: 1,
+		Checklist:  []model.ChecklistItem{{ID: "c1", Text: "Reviewed", Done: true}},
+		Recurrence: &model.Recurrence{Rule: "FREQ=WEEKLY", Start: due, TimeZone: "UTC"}}
+	s := openSynced(t, remote)
+
+	// The next occurrence starts unchecked, so it cannot join the completed one and starts in the first column
+	task, _ := s.Task("t1")
+	task.Done = true
+	require.NoError(t, s.UpdateTask(context.Background(), task))
+	tasks := s.Tasks("b1")
+	require.Len(t, tasks, 2)
+	for _, task := range tasks {
+		if task.ID != "t1" {
+			assert.Equal(t, remote.boards[0].Columns[0].ID, task.ColumnID)
+		}
+	}
+}
+
+func (f *fakeRemote) ListTimeEntries(ctx context.Context, boardID string) ([]model.TimeEntry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var entries []model.TimeEntry
+	for _, e := range f.timeEntries {
+		if e.BoardID == boardID {
+			entries = append(entries, e)
+		}
+	}
+	return entries, f.err
+}
+
+func (f *fakeRemote) CreateTimeEntry(ctx context.Context, entry model.TimeEntry) (*model.TimeEntry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return nil, f.err
+	}
+	entry.ID = "server-" + entry.ID
+	f.timeEntries[entry.ID] = entry
+	return &entry, nil
+}
+
+func (f *fakeRemote) DeleteTimeEntry(ctx context.Context, entryID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	delete(f.timeEntries, entryID)
+	return nil
+}
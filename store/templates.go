@@ -0,0 +1,144 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"eldar/model"
+	"go.etcd.io/bbolt"
+)
+
+// Templates returns the built-in templates of a kind followed by the cached saved ones sorted by name
+func (s *Store) Templates(kind model.TemplateKind) []model.Template {
+	var templates []model.Template
+	for _, t := range model.BuiltinTemplates {
+		if t.Kind == kind {
+			templates = append(templates, t)
+		}
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	saved := make([]model.Template, 0, len(s.templates))
+	for _, t := range s.templates {
+		if t.Kind == kind {
+			saved = append(saved, t)
+		}
+	}
+	sort.Slice(saved, func(i, j int) bool {
+		if saved[i].Name != saved[j].Name {
+			return saved[i].Name < saved[j].Name
+		}
+		return saved[i].ID < saved[j].ID
+	})
+	return append(templates, saved...)
+}
+
+// Template returns the built-in or cached saved template with the given ID
+func (s *Store) Template(id string) (model.Template, bool) {
+	for _, t := range model.BuiltinTemplates {
+		if t.ID == id {
+			return t, true
+		}
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t, ok := s.templates[id]
+	return t, ok
+}
+
+// CreateTemplate adds a template to the cache and saves it on the server.
+// The template is replaced by the server copy once saved, and removed again when the server rejects it.
+func (s *Store) CreateTemplate(ctx context.Context, template model.Template) (model.Template, error) {
+	if err := template.Validate(); err != nil {
+		return model.Template{}, err
+	}
+	id, err := newID()
+	if err != nil {
+		return model.Template{}, err
+	}
+	template.ID = id
+	if err := s.putTemplate(template); err != nil {
+		return model.Template{}, err
+	}
+
+	created, err := s.remote.CreateTemplate(ctx, template)
+	if err != nil {
+		_ = s.deleteTemplate(template.ID)
+		return model.Template{}, fmt.Errorf("failed to create template: %w", err)
+	}
+	if created.ID != template.ID {
+		if err := s.deleteTemplate(template.ID); err != nil {
+			return model.Template{}, err
+		}
+	}
+	if err := s.putTemplate(*created); err != nil {
+		return model.Template{}, err
+	}
+	return *created, nil
+}
+
+// DeleteTemplate removes a template from the cache and deletes it on the server.
+// The template is restored when the server rejects the deletion.
+func (s *Store) DeleteTemplate(ctx context.Context, id string) error {
+	previous, ok := s.Template(id)
+	if !ok {
+		return fmt.Errorf("template %s: %w", id, ErrNotFound)
+	}
+	if previous.Builtin() {
+		return fmt.Errorf("built-in template %s cannot be deleted", previous.Name)
+	}
+	if err := s.deleteTemplate(id); err != nil {
+		return err
+	}
+
+	if err := s.remote.DeleteTemplate(ctx, id); err != nil {
+		if _, exists := s.Template(id); !exists {
+			_ = s.putTemplate(previous)
+		}
+		return fmt.Errorf("failed to delete template: %w", err)
+	}
+	return nil
+}
+
+// putTemplate stores a template in memory and in the database, then notifies listeners
+func (s *Store) putTemplate(template model.Template) error {
+	payload, err := json.Marshal(template)
+	if err != nil {
+		return fmt.Errorf("failed to encode template: %w", err)
+	}
+
+	s.mu.Lock()
+	err = s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(templatesBucket).Put([]byte(template.ID), payload)
+	})
+	if err == nil {
+		s.templates[template.ID] = template
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to save template: %w", err)
+	}
+
+	s.notify()
+	return nil
+}
+
+// deleteTemplate removes a template from memory and from the database, then notifies listeners
+func (s *Store) deleteTemplate(id string) error {
+	s.mu.Lock()
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(templatesBucket).Delete([]byte(id))
+	})
+	if err == nil {
+		delete(s.templates, id)
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to delete template: %w", err)
+	}
+
+	s.notify()
+	return nil
+}
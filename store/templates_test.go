@@ -0,0 +1,86 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"eldar/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreTemplates(t *testing.T) {
+	remote := newFakeRemote()
+	remote.templates["tp1"] = model.Template{ID: "tp1", Name: "Release", Kind: model.TemplateTask, Tasks: []model.Task{{Title: "Release"}}}
+	s := openSynced(t, remote)
+	var builtinTasks int
+	for _, template := range model.BuiltinTemplates {
+		if template.Kind == model.TemplateTask {
+			builtinTasks++
+		}
+	}
+	require.Len(t, s.Templates(model.TemplateTask), builtinTasks+1)
+	assert.Equal(t, "Release", s.Templates(model.TemplateTask)[builtinTasks].Name)
+
+	board, _ := s.Board("b1")
+	task, _ := s.Task("t1")
+	created, err := s.CreateTemplate(context.Background(), model.NewBoardTemplate("Sprint", board, []model.Task{task}))
+	require.NoError(t, err)
+	assert.Contains(t, created.ID, "server-")
+	saved, ok := s.Template(created.ID)
+	require.True(t, ok)
+	assert.Equal(t, "Write report", saved.Tasks[0].Title)
+	assert.Equal(t, saved, s.Templates(model.TemplateBoard)[len(s.Templates(model.TemplateBoard))-1])
+
+	require.NoError(t, s.DeleteTemplate(context.Background(), created.ID))
+	_, ok = s.Template(created.ID)
+	assert.False(t, ok)
+	assert.Error(t, s.DeleteTemplate(context.Background(), model.BuiltinTemplates[0].ID))
+	assert.ErrorIs(t, s.DeleteTemplate(context.Background(), "missing"), ErrNotFound)
+	_, err = s.CreateTemplate(context.Background(), model.Template{Name: "Empty", Kind: model.TemplateTask})
+	assert.Error(t, err)
+
+	// Templates are restored when the server rejects their deletion
+	remote.fail(errors.New("offline"))
+	assert.Error(t, s.DeleteTemplate(context.Background(), "tp1"))
+	_, ok = s.Template("tp1")
+	assert.True(t, ok)
+	_, err = s.CreateTemplate(context.Background(), model.NewTaskTemplate("Retro", model.Task{Title: "Retro"}))
+	assert.Error(t, err)
+	assert.Len(t, s.Templates(model.TemplateTask), builtinTasks+1)
+}
+
+func TestStoreCreateBoard(t *testing.T) {
+	remote := newFakeRemote()
+	s := openSynced(t, remote)
+	var sprint model.Template
+	for _, template := range model.BuiltinTemplates {
+		if template.ID == "builtin:sprint" {
+			sprint = template
+		}
+	}
+	board, tasks, err := sprint.NewBoard("Sprint 13")
+	require.NoError(t, err)
+
+	created, err := s.CreateBoard(context.Background(), board, tasks)
+	require.NoError(t, err)
+	assert.Contains(t, created.ID, "server-")
+	assert.Len(t, s.Boards(), 2)
+	assert.Len(t, s.Tasks(created.ID), len(tasks))
+
+	// Tasks that cannot be created are reported, the board is kept
+	tasks = append(tasks, model.Task{ColumnID: "todo"})
+	created, err = s.CreateBoard(context.Background(), board, tasks)
+	assert.ErrorContains(t, err, "failed to add 1 of 4 tasks to board Sprint 13")
+	_, ok := s.Board(created.ID)
+	assert.True(t, ok)
+	assert.Len(t, s.Tasks(created.ID), 3)
+
+	_, err = s.CreateBoard(context.Background(), model.Board{}, nil)
+	assert.Error(t, err)
+	remote.fail(errors.New("offline"))
+	_, err = s.CreateBoard(context.Background(), board, nil)
+	assert.Error(t, err)
+	assert.Len(t, s.Boards(), 3)
+}
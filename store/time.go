@@ -0,0 +1,250 @@
+package store
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"eldar/model"
+	"go.etcd.io/bbolt"
+)
+
+// timerKey is the key of the running timer in timerBucket
+var timerKey = []byte("active")
+
+// TimeEntries returns the cached time entries on the boards that are not archived, oldest first
+func (s *Store) TimeEntries() []model.TimeEntry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entries := make([]model.TimeEntry, 0, len(s.timeEntries))
+	for _, e := range s.timeEntries {
+		if b, ok := s.boards[e.BoardID]; ok && b.Archived() {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	sortTimeEntries(entries)
+	return entries
+}
+
+// TaskTime returns the cached time entries of a task, oldest first
+func (s *Store) TaskTime(taskID string) []model.TimeEntry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var entries []model.TimeEntry
+	for _, e := range s.timeEntries {
+		if e.TaskID == taskID {
+			entries = append(entries, e)
+		}
+	}
+	sortTimeEntries(entries)
+	return entries
+}
+
+// ActiveTimer returns the running timer, if any
+func (s *Store) ActiveTimer() (model.Timer, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.timer == nil {
+		return model.Timer{}, false
+	}
+	return *s.timer, true
+}
+
+// StartTimer starts timing a task at now. A timer running on another task is stopped first and its
+// time recorded for user, nothing changes when the task is already timed. When that time cannot be
+// recorded, for example because the timer ran for more than a day, the running timer is kept and
+// the error of StopTimer returned, so it can be discarded first.
+func (s *Store) StartTimer(ctx context.Context, user, taskID string, now time.Time) error {
+	task, ok := s.Task(taskID)
+	if !ok {
+		return fmt.Errorf("task %s: %w", taskID, ErrNotFound)
+	}
+	if timer, ok := s.ActiveTimer(); ok {
+		if timer.TaskID == taskID {
+			return nil
+		}
+		if _, err := s.StopTimer(ctx, user, now); err != nil {
+			return err
+		}
+	}
+	return s.putTimer(&model.Timer{TaskID: task.ID, BoardID: task.BoardID, Start: now})
+}
+
+// StopTimer stops the running timer at now and records the time spent for user.
+// The timer keeps running when the time cannot be recorded, so none of it is lost.
+func (s *Store) StopTimer(ctx context.Context, user string, now time.Time) (model.TimeEntry, error) {
+	timer, ok := s.ActiveTimer()
+	if !ok {
+		return model.TimeEntry{}, errors.New("no timer is running")
+	}
+	entry := timer.Stop(user, now)
+	if err := entry.Validate(); err != nil {
+		return model.TimeEntry{}, err
+	}
+	if err := s.putTimer(nil); err != nil {
+		return model.TimeEntry{}, err
+	}
+	created, err := s.CreateTimeEntry(ctx, entry)
+	if err != nil {
+		if _, running := s.ActiveTimer(); !running {
+			_ = s.putTimer(&timer)
+		}
+		return model.TimeEntry{}, err
+	}
+	return created, nil
+}
+
+// DiscardTimer stops the running timer without recording its time, e.g. when it was left running by mistake
+func (s *Store) DiscardTimer() error {
+	return s.putTimer(nil)
+}
+
+// CreateTimeEntry adds a time entry to the cache and records it on the server.
+// The entry is replaced by the server copy once recorded, and removed again when the server rejects it.
+func (s *Store) CreateTimeEntry(ctx context.Context, entry model.TimeEntry) (model.TimeEntry, error) {
+	if err := entry.Validate(); err != nil {
+		return model.TimeEntry{}, err
+	}
+	if entry.ID == "" {
+		id, err := newID()
+		if err != nil {
+			return model.TimeEntry{}, err
+		}
+		entry.ID = id
+	}
+	if err := s.putTimeEntry(entry); err != nil {
+		return model.TimeEntry{}, err
+	}
+
+	created, err := s.remote.CreateTimeEntry(ctx, entry)
+	if err != nil {
+		_ = s.deleteTimeEntry(entry.ID)
+		return model.TimeEntry{}, fmt.Errorf("failed to record time: %w", err)
+	}
+	if created.ID != entry.ID {
+		if err := s.deleteTimeEntry(entry.ID); err != nil {
+			return model.TimeEntry{}, err
+		}
+	}
+	if err := s.putTimeEntry(*created); err != nil {
+		return model.TimeEntry{}, err
+	}
+	return *created, nil
+}
+
+// DeleteTimeEntry removes a time entry from the cache and deletes it on the server.
+// The entry is restored when the server rejects the deletion.
+func (s *Store) DeleteTimeEntry(ctx context.Context, id string) error {
+	s.mu.RLock()
+	previous, ok := s.timeEntries[id]
+	s.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("time entry %s: %w", id, ErrNotFound)
+	}
+	if err := s.deleteTimeEntry(id); err != nil {
+		return err
+	}
+
+	if err := s.remote.DeleteTimeEntry(ctx, id); err != nil {
+		_ = s.putTimeEntry(previous)
+		return fmt.Errorf("failed to delete time entry: %w", err)
+	}
+	return nil
+}
+
+// sortTimeEntries sorts time entries by start, then ID
+func sortTimeEntries(entries []model.TimeEntry) {
+	slices.SortFunc(entries, func(a, b model.TimeEntry) int {
+		return cmp.Or(a.Start.Compare(b.Start), cmp.Compare(a.ID, b.ID))
+	})
+}
+
+// putTimeEntry stores a time entry in memory and in the database, then notifies listeners
+func (s *Store) putTimeEntry(entry model.TimeEntry) error {
+	payload, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode time entry: %w", err)
+	}
+
+	s.mu.Lock()
+	err = s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(timeEntriesBucket).Put([]byte(entry.ID), payload)
+	})
+	if err == nil {
+		s.timeEntries[entry.ID] = entry
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to save time entry: %w", err)
+	}
+
+	s.notify()
+	return nil
+}
+
+// deleteTimeEntry removes a time entry from memory and from the database, then notifies listeners
+func (s *Store) deleteTimeEntry(id string) error {
+	s.mu.Lock()
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(timeEntriesBucket).Delete([]byte(id))
+	})
+	if err == nil {
+		delete(s.timeEntries, id)
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to delete time entry: %w", err)
+	}
+
+	s.notify()
+	return nil
+}
+
+// putTimer stores the running timer in memory and in the database, or clears it when timer is nil,
+// then notifies listeners
+func (s *Store) putTimer(timer *model.Timer) error {
+	var payload []byte
+	if timer != nil {
+		var err error
+		if payload, err = json.Marshal(timer); err != nil {
+			return fmt.Errorf("failed to encode timer: %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		if timer == nil {
+			return tx.Bucket(timerBucket).Delete(timerKey)
+		}
+		return tx.Bucket(timerBucket).Put(timerKey, payload)
+	})
+	if err == nil {
+		s.timer = timer
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to save timer: %w", err)
+	}
+
+	s.notify()
+	return nil
+}
+
+// loadTimer reads the running timer from its bucket, leaving timer nil when there is none
+func loadTimer(b *bbolt.Bucket, timer **model.Timer) error {
+	payload := b.Get(timerKey)
+	if payload == nil {
+		return nil
+	}
+	var t model.Timer
+	if err := json.Unmarshal(payload, &t); err != nil {
+		return fmt.Errorf("failed to decode timer: %w", err)
+	}
+	*timer = &t
+	return nil
+}
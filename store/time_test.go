@@ -0,0 +1,125 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"eldar/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreTimer(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	remote := newFakeRemote()
+	remote.tasks["t2"] = model.Task{ID: "t2", BoardID: "b1", ColumnID: "todo", Title: "Review", Version: 1}
+	s, err := Open(dir, remote)
+	require.NoError(t, err)
+	require.NoError(t, s.Sync(ctx))
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+
+	_, ok := s.ActiveTimer()
+	assert.False(t, ok)
+	assert.ErrorIs(t, s.StartTimer(ctx, "ana", "missing", start), ErrNotFound)
+	require.NoError(t, s.StartTimer(ctx, "ana", "t1", start))
+	// Starting the running timer again changes nothing
+	require.NoError(t, s.StartTimer(ctx, "ana", "t1", start.Add(time.Minute)))
+	timer, ok := s.ActiveTimer()
+	require.True(t, ok)
+	assert.Equal(t, model.Timer{TaskID: "t1", BoardID: "b1", Start: start}, timer)
+
+	// The timer survives a restart
+	require.NoError(t, s.Close())
+	s, err = Open(dir, remote)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = s.Close()
+	})
+	timer, ok = s.ActiveTimer()
+	require.True(t, ok)
+	assert.True(t, timer.Start.Equal(start))
+
+	// Starting another timer stops the running one
+	require.NoError(t, s.StartTimer(ctx, "ana", "t2", start.Add(time.Hour)))
+	timer, _ = s.ActiveTimer()
+	assert.Equal(t, "t2", timer.TaskID)
+	entries := s.TaskTime("t1")
+	require.Len(t, entries, 1)
+	assert.Equal(t, time.Hour, entries[0].Duration())
+	assert.Equal(t, "ana", entries[0].User)
+	assert.False(t, entries[0].Manual)
+
+	// The timer keeps running when its time cannot be recorded
+	remote.err = errors.New("offline")
+	_, err = s.StopTimer(ctx, "ana", start.Add(2*time.Hour))
+	assert.Error(t, err)
+	timer, ok = s.ActiveTimer()
+	require.True(t, ok)
+	assert.Equal(t, "t2", timer.TaskID)
+	assert.Empty(t, s.TaskTime("t2"))
+	remote.err = nil
+
+	entry, err := s.StopTimer(ctx, "ana", start.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, entry.Duration())
+	_, ok = s.ActiveTimer()
+	assert.False(t, ok)
+	_, err = s.StopTimer(ctx, "ana", start.Add(3*time.Hour))
+	assert.Error(t, err)
+
+	// Timers left running for more than a day have to be discarded
+	require.NoError(t, s.StartTimer(ctx, "ana", "t1", start))
+	_, err = s.StopTimer(ctx, "ana", start.Add(30*time.Hour))
+	assert.Error(t, err)
+	// Starting another timer fails the same way, leaving the first one to be discarded
+	assert.Error(t, s.StartTimer(ctx, "ana", "t2", start.Add(30*time.Hour)))
+	timer, _ = s.ActiveTimer()
+	assert.Equal(t, "t1", timer.TaskID)
+	require.NoError(t, s.DiscardTimer())
+	_, ok = s.ActiveTimer()
+	assert.False(t, ok)
+}
+
+func TestStoreTimeEntries(t *testing.T) {
+	ctx := context.Background()
+	remote := newFakeRemote()
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	remote.boards = append(remote.boards, model.Board{ID: "b2", Name: "Old", ArchivedAt: &start})
+	remote.tasks["t2"] = model.Task{ID: "t2", BoardID: "b2", Title: "Archived work", Version: 1}
+	remote.timeEntries["e1"] = model.TimeEntry{ID: "e1", TaskID: "t1", BoardID: "b1", User: "ben", Start: start.Add(time.Hour), End: start.Add(2 * time.Hour)}
+	remote.timeEntries["e2"] = model.TimeEntry{ID: "e2", TaskID: "t2", BoardID: "b2", User: "ben", Start: start, End: start.Add(time.Hour)}
+	s := openSynced(t, remote)
+
+	// Entries on archived boards are left out of the report
+	require.Len(t, s.TimeEntries(), 1)
+
+	created, err := s.CreateTimeEntry(ctx, model.TimeEntry{TaskID: "t1", BoardID: "b1", User: "ana", Start: start, End: start.Add(30 * time.Minute), Manual: true})
+	require.NoError(t, err)
+	entries := s.TaskTime("t1")
+	require.Len(t, entries, 2)
+	assert.Equal(t, created.ID, entries[0].ID)
+	assert.Equal(t, "e1", entries[1].ID)
+
+	_, err = s.CreateTimeEntry(ctx, model.TimeEntry{TaskID: "t1", Start: start, End: start})
+	assert.Error(t, err)
+	remote.err = errors.New("offline")
+	_, err = s.CreateTimeEntry(ctx, model.TimeEntry{TaskID: "t1", BoardID: "b1", Start: start, End: start.Add(time.Hour)})
+	assert.Error(t, err)
+	assert.Len(t, s.TaskTime("t1"), 2)
+
+	// A deletion the server rejects is rolled back
+	assert.Error(t, s.DeleteTimeEntry(ctx, "e1"))
+	assert.Len(t, s.TaskTime("t1"), 2)
+	remote.err = nil
+	require.NoError(t, s.DeleteTimeEntry(ctx, "e1"))
+	assert.Len(t, s.TaskTime("t1"), 1)
+	assert.ErrorIs(t, s.DeleteTimeEntry(ctx, "e1"), ErrNotFound)
+
+	// Sync replaces the entries
+	require.NoError(t, s.Sync(ctx))
+	assert.Len(t, s.TaskTime("t1"), 1)
+	assert.Len(t, s.TaskTime("t2"), 1)
+}
@@ -0,0 +1,287 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"eldar/model"
+	"go.etcd.io/bbolt"
+)
+
+// ArchivedBoards returns the cached archived boards sorted by name
+func (s *Store) ArchivedBoards() []model.Board {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var boards []model.Board
+	for _, b := range s.boards {
+		if b.Archived() {
+			boards = append(boards, b)
+		}
+	}
+	sort.Slice(boards, func(i, j int) bool {
+		return boards[i].Name < boards[j].Name
+	})
+	return boards
+}
+
+// Trash returns the cached boards and tasks in the trash that can still be restored, most recently deleted first
+func (s *Store) Trash() model.Trash {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var trash model.Trash
+	for _, b := range s.trashedBoards {
+		trash.Boards = append(trash.Boards, b)
+	}
+	for _, t := range s.trashedTasks {
+		trash.Tasks = append(trash.Tasks, t.Clone())
+	}
+	return trash.Restorable(time.Now())
+}
+
+// RestoreTask takes a task out of the trash in the cache and restores it on the server.
+// The task must meet the blocking policies of its column again, see CheckMove.
+// The task goes back to the trash when the server rejects the restore.
+func (s *Store) RestoreTask(ctx context.Context, id string) error {
+	s.mu.RLock()
+	previous, ok := s.trashedTasks[id]
+	s.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("task %s: %w", id, ErrNotFound)
+	}
+	if _, ok := s.Board(previous.BoardID); !ok {
+		return fmt.Errorf("board of task %s is not available, restore it first: %w", id, ErrNotFound)
+	}
+	restored := previous.Clone()
+	restored.DeletedAt = nil
+	if !restored.Archived() {
+		if v := s.CheckMove(restored, restored.ColumnID); v != nil && v.Blocking() {
+			return v
+		}
+	}
+	if err := s.untrashTask(restored); err != nil {
+		return err
+	}
+
+	updated, err := s.remote.RestoreTask(ctx, id)
+	if err != nil {
+		if current, ok := s.Task(id); ok && reflect.DeepEqual(current, restored) {
+			_ = s.trashTask(previous)
+		}
+		return fmt.Errorf("failed to restore task: %w", err)
+	}
+	return s.putTask(*updated)
+}
+
+// DeleteBoard moves a board and its tasks to the trash in the cache and on the server.
+// They are put back when the server rejects the deletion.
+func (s *Store) DeleteBoard(ctx context.Context, id string) error {
+	previous, ok := s.Board(id)
+	if !ok {
+		return fmt.Errorf("board %s: %w", id, ErrNotFound)
+	}
+	tasks := s.boardTasks(id, true)
+	deleted := previous
+	now := time.Now()
+	deleted.DeletedAt = &now
+	if err := s.trashBoard(deleted, tasks); err != nil {
+		return err
+	}
+
+	if err := s.remote.DeleteBoard(ctx, id); err != nil {
+		if _, exists := s.Board(id); !exists {
+			_ = s.untrashBoard(previous, tasks)
+		}
+		return fmt.Errorf("failed to delete board: %w", err)
+	}
+	return nil
+}
+
+// RestoreBoard takes a board out of the trash on the server, then puts it back in the cache along with its tasks.
+// Unlike other edits the restore is not applied to the cache first, as the tasks of the board
+// are only cached again once the server has restored them.
+func (s *Store) RestoreBoard(ctx context.Context, id string) error {
+	s.mu.RLock()
+	_, ok := s.trashedBoards[id]
+	s.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("board %s: %w", id, ErrNotFound)
+	}
+
+	restored, err := s.remote.RestoreBoard(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to restore board: %w", err)
+	}
+	tasks, err := s.remote.ListTasks(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to list tasks of board %s: %w", id, err)
+	}
+	return s.untrashBoard(*restored, tasks)
+}
+
+// ArchiveBoard archives or unarchives a cached board and sends the change to the server.
+// The tasks of an archived board are left out of AllTasks, so they no longer show up in
+// search results, the calendar or reminders. The previous state is restored when the server
+// rejects the change, unless the board was changed again in the meantime.
+func (s *Store) ArchiveBoard(ctx context.Context, id string, archived bool) error {
+	previous, ok := s.Board(id)
+	if !ok {
+		return fmt.Errorf("board %s: %w", id, ErrNotFound)
+	}
+	if previous.Archived() == archived {
+		return nil
+	}
+	board := previous
+	board.ArchivedAt = nil
+	if archived {
+		now := time.Now()
+		board.ArchivedAt = &now
+	}
+	if err := s.putBoard(board); err != nil {
+		return err
+	}
+
+	updated, err := s.remote.ArchiveBoard(ctx, id, archived)
+	if err != nil {
+		if current, ok := s.Board(id); ok && reflect.DeepEqual(current, board) {
+			_ = s.putBoard(previous)
+		}
+		return fmt.Errorf("failed to archive board: %w", err)
+	}
+	return s.putBoard(*updated)
+}
+
+// trashTask replaces a cached task by its trashed copy in memory and in the database, then notifies listeners
+func (s *Store) trashTask(task model.Task) error {
+	payload, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to encode task: %w", err)
+	}
+
+	s.mu.Lock()
+	err = s.db.Update(func(tx *bbolt.Tx) error {
+		if err := tx.Bucket(tasksBucket).Delete([]byte(task.ID)); err != nil {
+			return err
+		}
+		return tx.Bucket(trashedTasksBucket).Put([]byte(task.ID), payload)
+	})
+	if err == nil {
+		delete(s.tasks, task.ID)
+		s.trashedTasks[task.ID] = task.Clone()
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to move task to trash: %w", err)
+	}
+
+	s.notify()
+	return nil
+}
+
+// untrashTask replaces a trashed task by task in memory and in the database, then notifies listeners
+func (s *Store) untrashTask(task model.Task) error {
+	payload, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to encode task: %w", err)
+	}
+
+	s.mu.Lock()
+	err = s.db.Update(func(tx *bbolt.Tx) error {
+		if err := tx.Bucket(trashedTasksBucket).Delete([]byte(task.ID)); err != nil {
+			return err
+		}
+		return tx.Bucket(tasksBucket).Put([]byte(task.ID), payload)
+	})
+	if err == nil {
+		delete(s.trashedTasks, task.ID)
+		s.tasks[task.ID] = task.Clone()
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to restore task: %w", err)
+	}
+
+	s.notify()
+	return nil
+}
+
+// trashBoard replaces a cached board by its trashed copy and drops its tasks, in memory and
+// in the database, then notifies listeners
+func (s *Store) trashBoard(board model.Board, tasks []model.Task) error {
+	payload, err := json.Marshal(board)
+	if err != nil {
+		return fmt.Errorf("failed to encode board: %w", err)
+	}
+
+	s.mu.Lock()
+	err = s.db.Update(func(tx *bbolt.Tx) error {
+		if err := tx.Bucket(boardsBucket).Delete([]byte(board.ID)); err != nil {
+			return err
+		}
+		for _, t := range tasks {
+			if err := tx.Bucket(tasksBucket).Delete([]byte(t.ID)); err != nil {
+				return err
+			}
+		}
+		return tx.Bucket(trashedBoardsBucket).Put([]byte(board.ID), payload)
+	})
+	if err == nil {
+		delete(s.boards, board.ID)
+		for _, t := range tasks {
+			delete(s.tasks, t.ID)
+		}
+		s.trashedBoards[board.ID] = board
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to move board to trash: %w", err)
+	}
+
+	s.notify()
+	return nil
+}
+
+// untrashBoard replaces a trashed board by board and caches its tasks, in memory and in the database,
+// then notifies listeners
+func (s *Store) untrashBoard(board model.Board, tasks []model.Task) error {
+	payload, err := json.Marshal(board)
+	if err != nil {
+		return fmt.Errorf("failed to encode board: %w", err)
+	}
+	taskPayloads := make([][]byte, len(tasks))
+	for i, t := range tasks {
+		if taskPayloads[i], err = json.Marshal(t); err != nil {
+			return fmt.Errorf("failed to encode task: %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	err = s.db.Update(func(tx *bbolt.Tx) error {
+		if err := tx.Bucket(trashedBoardsBucket).Delete([]byte(board.ID)); err != nil {
+			return err
+		}
+		for i, t := range tasks {
+			if err := tx.Bucket(tasksBucket).Put([]byte(t.ID), taskPayloads[i]); err != nil {
+				return err
+			}
+		}
+		return tx.Bucket(boardsBucket).Put([]byte(board.ID), payload)
+	})
+	if err == nil {
+		delete(s.trashedBoards, board.ID)
+		for _, t := range tasks {
+			s.tasks[t.ID] = t.Clone()
+		}
+		s.boards[board.ID] = board
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to restore board: %w", err)
+	}
+
+	s.notify()
+	return nil
+}
@@ -0,0 +1,117 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"eldar/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreTrashTask(t *testing.T) {
+	remote := newFakeRemote()
+	s := openSynced(t, remote)
+	ctx := context.Background()
+
+	require.NoError(t, s.DeleteTask(ctx, "t1"))
+	assert.Empty(t, s.AllTasks())
+	trash := s.Trash()
+	require.Len(t, trash.Tasks, 1)
+	assert.Equal(t, "t1", trash.Tasks[0].ID)
+	assert.NotNil(t, trash.Tasks[0].DeletedAt)
+
+	// A rejected restore puts the task back in the trash
+	remote.fail(errors.New("server unreachable"))
+	assert.ErrorContains(t, s.RestoreTask(ctx, "t1"), "server unreachable")
+	_, ok := s.Task("t1")
+	assert.False(t, ok)
+	assert.Len(t, s.Trash().Tasks, 1)
+
+	remote.fail(nil)
+	require.NoError(t, s.RestoreTask(ctx, "t1"))
+	task, ok := s.Task("t1")
+	require.True(t, ok)
+	assert.Nil(t, task.DeletedAt)
+	assert.Empty(t, s.Trash().Tasks)
+	assert.ErrorIs(t, s.RestoreTask(ctx, "t1"), ErrNotFound)
+}
+
+func TestStoreTrashSync(t *testing.T) {
+	remote := newFakeRemote()
+	expired := time.Now().Add(-model.TrashRetention - time.Hour)
+	remote.trashedTasks["t2"] = model.Task{ID: "t2", BoardID: "b1", Title: "Old draft", DeletedAt: &expired}
+	recent := time.Now().Add(-time.Hour)
+	remote.trashedTasks["t3"] = model.Task{ID: "t3", BoardID: "b1", Title: "Notes", DeletedAt: &recent}
+	dir := t.TempDir()
+	s, err := Open(dir, remote)
+	require.NoError(t, err)
+	require.NoError(t, s.Sync(context.Background()))
+	require.NoError(t, s.Close())
+
+	// The trash is cached for offline use, without the items past their retention period
+	s, err = Open(dir, remote)
+	require.NoError(t, err)
+	defer func() {
+		_ = s.Close()
+	}()
+	trash := s.Trash()
+	require.Len(t, trash.Tasks, 1)
+	assert.Equal(t, "t3", trash.Tasks[0].ID)
+	_, ok := s.Task("t3")
+	assert.False(t, ok)
+}
+
+func TestStoreTrashBoard(t *testing.T) {
+	remote := newFakeRemote()
+	s := openSynced(t, remote)
+	ctx := context.Background()
+
+	remote.fail(errors.New("server unreachable"))
+	assert.Error(t, s.DeleteBoard(ctx, "b1"))
+	assert.Len(t, s.Boards(), 1)
+	assert.Len(t, s.Tasks("b1"), 1)
+
+	remote.fail(nil)
+	require.NoError(t, s.DeleteBoard(ctx, "b1"))
+	assert.Empty(t, s.Boards())
+	assert.Empty(t, s.AllTasks())
+	trash := s.Trash()
+	require.Len(t, trash.Boards, 1)
+	assert.Equal(t, "Sprint", trash.Boards[0].Name)
+	// The tasks of the board are restored with it, not one by one
+	assert.Empty(t, trash.Tasks)
+
+	require.NoError(t, s.RestoreBoard(ctx, "b1"))
+	assert.Len(t, s.Boards(), 1)
+	assert.Len(t, s.Tasks("b1"), 1)
+	assert.Empty(t, s.Trash().Boards)
+	assert.ErrorIs(t, s.RestoreBoard(ctx, "b1"), ErrNotFound)
+}
+
+func TestStoreArchiveBoard(t *testing.T) {
+	remote := newFakeRemote()
+	s := openSynced(t, remote)
+	ctx := context.Background()
+
+	require.NoError(t, s.ArchiveBoard(ctx, "b1", true))
+	assert.Empty(t, s.Boards())
+	archived := s.ArchivedBoards()
+	require.Len(t, archived, 1)
+	assert.Equal(t, time.Date(2025, 6, 3, 10, 0, 0, 0, time.UTC), *archived[0].ArchivedAt)
+	// The tasks stay cached for the archived board, but are left out of search and reminders
+	assert.Len(t, s.Tasks("b1"), 1)
+	assert.Empty(t, s.AllTasks())
+
+	remote.fail(errors.New("server unreachable"))
+	assert.Error(t, s.ArchiveBoard(ctx, "b1", false))
+	assert.Len(t, s.ArchivedBoards(), 1)
+
+	remote.fail(nil)
+	require.NoError(t, s.ArchiveBoard(ctx, "b1", false))
+	assert.Len(t, s.Boards(), 1)
+	assert.Len(t, s.AllTasks(), 1)
+	assert.ErrorIs(t, s.ArchiveBoard(ctx, "missing", true), ErrNotFound)
+}
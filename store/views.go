@@ -0,0 +1,176 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"eldar/model"
+	"go.etcd.io/bbolt"
+)
+
+// Views returns the built-in views followed by the cached saved views sorted by name
+func (s *Store) Views() []model.View {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	saved := make([]model.View, 0, len(s.views))
+	for _, v := range s.views {
+		saved = append(saved, v)
+	}
+	sort.Slice(saved, func(i, j int) bool {
+		if saved[i].Name != saved[j].Name {
+			return saved[i].Name < saved[j].Name
+		}
+		return saved[i].ID < saved[j].ID
+	})
+	return append(append([]model.View{}, model.BuiltinViews...), saved...)
+}
+
+// View returns the built-in or cached saved view with the given ID
+func (s *Store) View(id string) (model.View, bool) {
+	for _, v := range model.BuiltinViews {
+		if v.ID == id {
+			return v, true
+		}
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.views[id]
+	return v, ok
+}
+
+// CreateView adds a view to the cache and saves it on the server.
+// The view is replaced by the server copy once saved, and removed again when the server rejects it.
+func (s *Store) CreateView(ctx context.Context, view model.View) (model.View, error) {
+	if err := view.Validate(); err != nil {
+		return model.View{}, err
+	}
+	if view.ID == "" {
+		id, err := newID()
+		if err != nil {
+			return model.View{}, err
+		}
+		view.ID = id
+	}
+	if err := s.putView(view); err != nil {
+		return model.View{}, err
+	}
+
+	created, err := s.remote.CreateView(ctx, view)
+	if err != nil {
+		s.rollbackView(view.ID, view, nil)
+		return model.View{}, fmt.Errorf("failed to create view: %w", err)
+	}
+	if created.ID != view.ID {
+		if err := s.deleteView(view.ID); err != nil {
+			return model.View{}, err
+		}
+	}
+	if err := s.putView(*created); err != nil {
+		return model.View{}, err
+	}
+	return *created, nil
+}
+
+// UpdateView applies an edit to the cached view and sends it to the server.
+// The previous version is restored when the server rejects the edit, unless the view
+// was changed again in the meantime.
+func (s *Store) UpdateView(ctx context.Context, view model.View) error {
+	if err := view.Validate(); err != nil {
+		return err
+	}
+	previous, ok := s.View(view.ID)
+	if !ok {
+		return fmt.Errorf("view %s: %w", view.ID, ErrNotFound)
+	}
+	if err := s.putView(view); err != nil {
+		return err
+	}
+
+	updated, err := s.remote.UpdateView(ctx, view)
+	if err != nil {
+		s.rollbackView(view.ID, view, &previous)
+		return fmt.Errorf("failed to update view: %w", err)
+	}
+	return s.putView(*updated)
+}
+
+// DeleteView removes a view from the cache and deletes it on the server.
+// The view is restored when the server rejects the deletion.
+func (s *Store) DeleteView(ctx context.Context, id string) error {
+	previous, ok := s.View(id)
+	if !ok {
+		return fmt.Errorf("view %s: %w", id, ErrNotFound)
+	}
+	if previous.Builtin() {
+		return fmt.Errorf("built-in view %s cannot be deleted", previous.Name)
+	}
+	if err := s.deleteView(id); err != nil {
+		return err
+	}
+
+	if err := s.remote.DeleteView(ctx, id); err != nil {
+		if _, exists := s.View(id); !exists {
+			_ = s.putView(previous)
+		}
+		return fmt.Errorf("failed to delete view: %w", err)
+	}
+	return nil
+}
+
+// rollbackView restores previous, or removes the view when previous is nil, if the cached
+// view is still the optimistic value that was sent to the server
+func (s *Store) rollbackView(id string, optimistic model.View, previous *model.View) {
+	current, ok := s.View(id)
+	if !ok || !reflect.DeepEqual(current, optimistic) {
+		return
+	}
+	if previous == nil {
+		_ = s.deleteView(id)
+		return
+	}
+	_ = s.putView(*previous)
+}
+
+// putView stores a view in memory and in the database, then notifies listeners
+func (s *Store) putView(view model.View) error {
+	payload, err := json.Marshal(view)
+	if err != nil {
+		return fmt.Errorf("failed to encode view: %w", err)
+	}
+
+	s.mu.Lock()
+	err = s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(viewsBucket).Put([]byte(view.ID), payload)
+	})
+	if err == nil {
+		s.views[view.ID] = view
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to save view: %w", err)
+	}
+
+	s.notify()
+	return nil
+}
+
+// deleteView removes a view from memory and from the database, then notifies listeners
+func (s *Store) deleteView(id string) error {
+	s.mu.Lock()
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(viewsBucket).Delete([]byte(id))
+	})
+	if err == nil {
+		delete(s.views, id)
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to delete view: %w", err)
+	}
+
+	s.notify()
+	return nil
+}
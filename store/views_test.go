@@ -0,0 +1,68 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"eldar/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// viewNames returns the names of views in order
+func viewNames(views []model.View) []string {
+	names := make([]string, len(views))
+	for i, v := range views {
+		names[i] = v.Name
+	}
+	return names
+}
+
+func TestStoreViews(t *testing.T) {
+	remote := newFakeRemote()
+	s := openSynced(t, remote)
+	builtin := viewNames(model.BuiltinViews)
+	assert.Equal(t, append(builtin, "Release"), viewNames(s.Views()))
+
+	created, err := s.CreateView(context.Background(), model.View{ID: "v2", Name: "My open bugs", Query: "label:bug assignee:me is:open", Owner: "ana"})
+	require.NoError(t, err)
+	assert.Equal(t, "server-v2", created.ID)
+	assert.Equal(t, append(builtin, "My open bugs", "Release"), viewNames(s.Views()))
+
+	created.Shared = true
+	require.NoError(t, s.UpdateView(context.Background(), created))
+	view, ok := s.View("server-v2")
+	require.True(t, ok)
+	assert.True(t, view.Shared)
+	assert.Equal(t, int64(2), view.Version)
+
+	require.NoError(t, s.DeleteView(context.Background(), "v1"))
+	assert.Equal(t, append(builtin, "My open bugs"), viewNames(s.Views()))
+
+	_, err = s.CreateView(context.Background(), model.View{Name: "Empty"})
+	assert.Error(t, err)
+	assert.Error(t, s.DeleteView(context.Background(), model.BuiltinViews[0].ID))
+	assert.ErrorIs(t, s.DeleteView(context.Background(), "missing"), ErrNotFound)
+}
+
+func TestStoreViewsRollback(t *testing.T) {
+	remote := newFakeRemote()
+	s := openSynced(t, remote)
+	remote.fail(errors.New("offline"))
+
+	_, err := s.CreateView(context.Background(), model.View{Name: "My open bugs", Query: "label:bug"})
+	assert.Error(t, err)
+	assert.Len(t, s.Views(), len(model.BuiltinViews)+1)
+
+	view, _ := s.View("v1")
+	edited := view
+	edited.Name = "Next release"
+	assert.Error(t, s.UpdateView(context.Background(), edited))
+	restored, _ := s.View("v1")
+	assert.Equal(t, view, restored)
+
+	assert.Error(t, s.DeleteView(context.Background(), "v1"))
+	_, ok := s.View("v1")
+	assert.True(t, ok)
+}
@@ -0,0 +1,353 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"eldar/credentials"
+	"eldar/model"
+	"eldar/store"
+	"eldar/ui"
+	"eldar/undo"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+)
+
+// taskStore caches the boards and tasks of the signed-in account, it is nil while signed out
+var taskStore *store.Store
+
+// taskStoreUser is the account taskStore was opened for
+var taskStoreUser string
+
+// boardsPage displays the boards of the signed-in account
+var boardsPage *ui.BoardsPage
+
+// calendarPage displays the tasks of the signed-in account by due date
+var calendarPage *ui.CalendarPage
+
+// sprintPage charts the progress of the sprints of the boards of the signed-in account
+var sprintPage *ui.SprintPage
+
+// activeDetail holds the panels of the task detail dialog that is open, if any, refreshed on every change of the cache
+var activeDetail struct {
+	taskID      string
+	comments    *ui.CommentsPanel
+	attachments *ui.AttachmentsPanel
+	history     *ui.ActivityFeed
+	time        *ui.TimePanel
+}
+
+// openTaskStore opens the task cache of username, closing the cache of any other account first,
+// and starts a background sync with the server
+func openTaskStore(username string) error {
+	if taskStore != nil && taskStoreUser == username {
+		return nil
+	}
+	closeTaskStore()
+
+	storageDir, err := credentials.GetStorageDir()
+	if err != nil {
+		return err
+	}
+	dir := credentials.AccountDataDir(storageDir, username)
+	st, err := store.Open(dir, apiClient)
+	if err != nil {
+		return err
+	}
+	if err := openReminders(dir, st); err != nil {
+		_ = st.Close()
+		return err
+	}
+	if err := openTaskIndex(dir, st); err != nil {
+		closeReminders()
+		_ = st.Close()
+		return err
+	}
+	if err := openAttachmentCache(dir); err != nil {
+		closeTaskIndex()
+		closeReminders()
+		_ = st.Close()
+		return err
+	}
+	taskStore = st
+	taskStoreUser = username
+	boardsPage = ui.NewBoardsPage(st, searchTasks, showTaskDetail)
+	undoHistory = undo.New(undo.DefaultLimit)
+	calendarPage = ui.NewCalendarPage(st, showTaskDetail, func(task model.Task, due time.Time) {
+		rescheduled := task.Clone()
+		rescheduled.DueDate = &due
+		runTaskCommand(task.BoardID, &undo.UpdateTask{Store: st, Label: "Reschedule task", Before: task, After: rescheduled})
+	})
+	sprintPage = ui.NewSprintPage(st, showTaskDetail)
+	makeTrashPages(st, boardsPage)
+	makeTimePages(st)
+	boardsPage.OnEditBoard = func(board model.Board) {
+		showBoardSettings(st, board)
+	}
+	boardsPage.OnSaveTemplate = func(board model.Board) {
+		showSaveBoardTemplate(st, board)
+	}
+	boardsPage.OnSwimlanes = func(board model.Board, grouping model.Swimlanes) {
+		changed := board
+		changed.Swimlanes = grouping
+		runTaskCommand(board.ID, &undo.UpdateBoard{Store: st, Label: "Change swimlanes", Before: board, After: changed})
+	}
+	boardsPage.OnMoveTask = func(before, after model.Task) {
+		saveTask(st, before, after)
+	}
+	boardsPage.OnBulkEdit = func(e model.BulkEdit, tasks []model.Task) {
+		bulkEdit(st, e, tasks)
+	}
+	page, calendar, sprints, cache := boardsPage, calendarPage, sprintPage, attachmentCache
+	trash, archive, report := trashPage, archivePage, timeReportPage
+	page.Thumbnail = func(task model.Task) image.Image {
+		cover, ok := task.Cover()
+		if !ok {
+			return nil
+		}
+		return attachmentThumbnail(cache, cover, cardThumbnailSize, page.Reload)
+	}
+	st.OnChange(func() {
+		fyne.Do(func() {
+			page.Reload()
+			calendar.Reload()
+			sprints.Reload()
+			trash.Reload()
+			archive.Reload()
+			report.Reload()
+			refreshTimers(st)
+			if activeDetail.comments != nil {
+				activeDetail.comments.SetComments(st.Comments(activeDetail.taskID))
+			}
+			if task, ok := st.Task(activeDetail.taskID); ok && activeDetail.attachments != nil {
+				activeDetail.attachments.SetAttachments(task.Attachments)
+				board, _ := st.Board(task.BoardID)
+				activeDetail.history.SetActivity(board, st.TaskActivity(task.ID))
+				activeDetail.time.SetEntries(st.TaskTime(task.ID))
+			}
+		})
+	})
+	viewsSidebar = newViewsSidebar(st, username, page)
+	startEvents(st, username)
+	go syncTaskStore(st)
+	return nil
+}
+
+// syncTaskStore refreshes the cache from the server, keeping the cached data when offline
+func syncTaskStore(st *store.Store) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := st.Sync(ctx); err != nil {
+		log.Printf("Error syncing tasks: %v", err)
+	}
+}
+
+// closeTaskStore closes the task cache, reminders and search index of the signed-in account, if any
+func closeTaskStore() {
+	if taskStore == nil {
+		return
+	}
+	closeEvents()
+	closeReminders()
+	closeTaskIndex()
+	closeAttachmentCache()
+	if err := taskStore.Close(); err != nil {
+		log.Printf("Error closing task store: %v", err)
+	}
+	taskStore = nil
+	taskStoreUser = ""
+	undoHistory = nil
+	contentTabs = nil
+	boardsPage = nil
+	calendarPage = nil
+	sprintPage = nil
+	trashPage = nil
+	archivePage = nil
+	timeReportPage = nil
+	timerBar = nil
+	viewsSidebar = nil
+}
+
+// showTaskDetail opens the detail editor of task in a dialog.
+// Saving applies the edit to the cache immediately and rolls it back if the server rejects it.
+func showTaskDetail(task model.Task) {
+	st := taskStore
+	var subtasks []ui.TaskProgress
+	for _, subtask := range st.Subtasks(task.ID) {
+		subtasks = append(subtasks, ui.TaskProgress{Task: subtask, Progress: st.Progress(subtask.ID)})
+	}
+
+	board, _ := st.Board(task.BoardID)
+	var d dialog.Dialog
+	form := ui.MakeTaskDetailForm(ui.TaskDetail{
+		Task:       task,
+		Board:      board,
+		Subtasks:   subtasks,
+		Candidates: st.Tasks(task.BoardID),
+		OnSave: func(edited model.Task) {
+			d.Hide()
+			saveTask(st, task, edited)
+		},
+		OnConvertItem: func(item model.ChecklistItem) {
+			d.Hide()
+			runTaskAction("converting checklist item", func(ctx context.Context) error {
+				_, err := st.ConvertChecklistItem(ctx, task.ID, item.ID)
+				return err
+			})
+		},
+		OnOpenSubtask: func(subtask model.Task) {
+			d.Hide()
+			showTaskDetail(subtask)
+		},
+		ValidateBlockers: func(blockedBy []string) error {
+			return st.ValidateDependencies(task.ID, blockedBy)
+		},
+		OnDelete: func() {
+			d.Hide()
+			runTaskCommand(task.BoardID, &undo.DeleteTask{Store: st, Task: task})
+		},
+		OnSaveTemplate: func() {
+			showSaveTaskTemplate(task)
+		},
+	})
+	form.OnCancel = func() {
+		d.Hide()
+	}
+
+	comments := makeCommentsPanel(st, task)
+	attachments := makeAttachmentsPanel(st, task)
+	timePanel := makeTimePanel(st, task)
+	history := ui.NewActivityFeed("No history yet")
+	history.Name = func(username string) string {
+		return memberName(st, username)
+	}
+	history.SetActivity(board, st.TaskActivity(task.ID))
+	tabs := container.NewAppTabs(
+		container.NewTabItem("Details", container.NewVScroll(form)),
+		container.NewTabItem("Comments", comments),
+		container.NewTabItem("Files", attachments),
+		container.NewTabItem("Time", timePanel),
+		container.NewTabItem("History", history),
+	)
+	d = dialog.NewCustomWithoutButtons(task.Title, tabs, w)
+	d.SetOnClosed(func() {
+		if activeDetail.comments == comments {
+			activeDetail.taskID, activeDetail.comments, activeDetail.attachments, activeDetail.history = "", nil, nil, nil
+			activeDetail.time = nil
+		}
+	})
+	d.Resize(fyne.NewSize(560, 640))
+	activeDetail.taskID, activeDetail.comments, activeDetail.attachments, activeDetail.history = task.ID, comments, attachments, history
+	activeDetail.time = timePanel
+	d.Show()
+}
+
+// saveTask applies an edit of a task and records it in the undo history. A move into another column is
+// checked against the policies of the column first: going over a WIP limit that only warns has to be
+// confirmed, and when the definition of done is not met its items can be added to the checklist instead.
+func saveTask(st *store.Store, before, after model.Task) {
+	save := func(edited model.Task) {
+		runTaskCommand(before.BoardID, &undo.UpdateTask{Store: st, Label: "Edit task", Before: before, After: edited})
+	}
+	if after.ColumnID == before.ColumnID {
+		save(after)
+		return
+	}
+	v := st.CheckMove(after, after.ColumnID)
+	switch {
+	case v == nil:
+		save(after)
+	case len(v.Missing) > 0:
+		message := fmt.Sprintf("Tasks moved to %s must have these checklist items done:\n\n• %s\n\n"+
+			"Add them to the checklist and keep the task where it is?", v.Column.Name, strings.Join(v.Missing, "\n• "))
+		dialog.ShowConfirm("Definition of done", message, func(ok bool) {
+			if !ok {
+				return
+			}
+			stay := after.Clone()
+			stay.ColumnID = before.ColumnID
+			for _, text := range v.Missing {
+				if slices.ContainsFunc(stay.Checklist, func(item model.ChecklistItem) bool {
+					return strings.EqualFold(item.Text, text)
+				}) {
+					continue
+				}
+				item, err := model.NewChecklistItem(text)
+				if err != nil {
+					dialog.ShowError(err, w)
+					return
+				}
+				stay.Checklist = append(stay.Checklist, item)
+			}
+			save(stay)
+		}, w)
+	case v.Blocking():
+		dialog.ShowError(v, w)
+	default:
+		message := fmt.Sprintf("%s already holds %d tasks and its WIP limit is %d. Move the task anyway?",
+			v.Column.Name, v.Count, v.Column.WIPLimit)
+		dialog.ShowConfirm("WIP limit reached", message, func(ok bool) {
+			if ok {
+				save(after)
+			}
+		}, w)
+	}
+}
+
+// makeCommentsPanel creates the comments panel of task and refreshes its comments from the server in the background.
+// Posts, edits and deletions are applied to the cache immediately and rolled back if the server rejects them.
+func makeCommentsPanel(st *store.Store, task model.Task) *ui.CommentsPanel {
+	me := taskStoreUser
+	panel := ui.NewCommentsPanel(me, st.Members(), func(body, parentID string) {
+		runTaskAction("posting comment", func(ctx context.Context) error {
+			_, err := st.CreateComment(ctx, model.Comment{TaskID: task.ID, ParentID: parentID, Author: me, Body: body})
+			return err
+		})
+	})
+	panel.OnEdit = func(comment model.Comment, body string) {
+		runTaskAction("editing comment", func(ctx context.Context) error {
+			return st.UpdateComment(ctx, comment.ID, body)
+		})
+	}
+	panel.OnDelete = func(comment model.Comment) {
+		dialog.ShowConfirm("Delete comment", "Delete this comment? Its earlier versions stay in its history.", func(ok bool) {
+			if !ok {
+				return
+			}
+			runTaskAction("deleting comment", func(ctx context.Context) error {
+				return st.DeleteComment(ctx, comment.ID)
+			})
+		}, w)
+	}
+	panel.SetComments(st.Comments(task.ID))
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := st.SyncComments(ctx, task.ID); err != nil {
+			log.Printf("Error syncing comments: %v", err)
+		}
+	}()
+	return panel
+}
+
+// runTaskAction runs a change of the task cache in the background and shows its error, if any.
+// The store has already rolled the cache back when the action fails.
+func runTaskAction(what string, action func(ctx context.Context) error) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := action(ctx); err != nil {
+			log.Printf("Error %s: %v", what, err)
+			fyne.Do(func() {
+				dialog.ShowError(err, w)
+			})
+		}
+	}()
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"eldar/model"
+	"eldar/store"
+	"eldar/ui"
+	"eldar/undo"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// showNewBoard opens a dialog to create a board from a template, then shows the board
+func showNewBoard() {
+	st := taskStore
+	if st == nil {
+		return
+	}
+	name := widget.NewEntry()
+	name.SetPlaceHolder("Board name")
+	picker := newTemplatePicker(st, model.TemplateBoard, func(t model.Template) {
+		if t.Board != nil {
+			name.SetText(t.Board.Name)
+		}
+	})
+	content := container.NewBorder(widget.NewForm(widget.NewFormItem("Name", name)), nil, nil, nil, picker)
+	d := dialog.NewCustomConfirm("New board", "Create", "Cancel", content, func(ok bool) {
+		t, picked := picker.Selected()
+		if !ok || !picked {
+			return
+		}
+		board, tasks, err := t.NewBoard(name.Text)
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		runTaskAction("creating board", func(ctx context.Context) error {
+			created, err := st.CreateBoard(ctx, board, tasks)
+			if created.ID != "" {
+				fyne.Do(func() {
+					if boardsPage != nil {
+						boardsPage.SelectBoard(created.ID)
+					}
+				})
+			}
+			return err
+		})
+	}, w)
+	d.Resize(fyne.NewSize(480, 480))
+	d.Show()
+}
+
+// showNewTask opens a dialog to create a task from a template in the first column of the board
+// shown on the Board tab
+func showNewTask() {
+	st := taskStore
+	if st == nil || boardsPage == nil {
+		return
+	}
+	board, ok := st.Board(boardsPage.SelectedBoard())
+	if !ok || len(board.Columns) == 0 {
+		dialog.ShowInformation("New task", "Select a board with columns to add the task to first.", w)
+		return
+	}
+	title := widget.NewEntry()
+	title.SetPlaceHolder("Task title")
+	picker := newTemplatePicker(st, model.TemplateTask, func(t model.Template) {
+		if len(t.Tasks) > 0 {
+			title.SetText(t.Tasks[0].Title)
+		}
+	})
+	content := container.NewBorder(widget.NewForm(widget.NewFormItem("Title", title)), nil, nil, nil, picker)
+	d := dialog.NewCustomConfirm("New task on "+board.Name, "Create", "Cancel", content, func(ok bool) {
+		t, picked := picker.Selected()
+		if !ok || !picked {
+			return
+		}
+		task, err := t.NewTask(board.ID, board.Columns[0].ID)
+		if err == nil {
+			task.Title = title.Text
+			err = task.Validate()
+		}
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		runTaskCommand(board.ID, &undo.CreateTask{Store: st, Task: task})
+	}, w)
+	d.Resize(fyne.NewSize(480, 480))
+	d.Show()
+}
+
+// newTemplatePicker creates a picker of the templates of a kind, calling onSelect with the template picked.
+// The templates of the signed in account can be deleted from it after confirmation.
+func newTemplatePicker(st *store.Store, kind model.TemplateKind, onSelect func(model.Template)) *ui.TemplatePicker {
+	picker := ui.NewTemplatePicker(taskStoreUser)
+	picker.OnSelect = onSelect
+	picker.OnDelete = func(t model.Template) {
+		dialog.ShowConfirm("Delete template", fmt.Sprintf("Delete the template %s for everyone in your group?", t.Name), func(ok bool) {
+			if !ok {
+				return
+			}
+			runTaskAction("deleting template", func(ctx context.Context) error {
+				err := st.DeleteTemplate(ctx, t.ID)
+				fyne.Do(func() {
+					picker.SetTemplates(st.Templates(kind))
+				})
+				return err
+			})
+		}, w)
+	}
+	picker.SetTemplates(st.Templates(kind))
+	return picker
+}
+
+// showSaveBoardTemplate opens a dialog to save the columns, labels and custom fields of board as a template,
+// optionally along with its tasks
+func showSaveBoardTemplate(st *store.Store, board model.Board) {
+	withTasks := widget.NewCheck("Include tasks", nil)
+	showSaveTemplate(board.Name, withTasks, func(name, description string) model.Template {
+		var tasks []model.Task
+		if withTasks.Checked {
+			for _, task := range st.Tasks(board.ID) {
+				// Subtasks would be created without their parent
+				if task.ParentID == "" {
+					tasks = append(tasks, task)
+				}
+			}
+		}
+		t := model.NewBoardTemplate(name, board, tasks)
+		t.Description, t.Owner = description, taskStoreUser
+		return t
+	})
+}
+
+// showSaveTaskTemplate opens a dialog to save the description, checklist, labels and priority of task as a template
+func showSaveTaskTemplate(task model.Task) {
+	showSaveTemplate(task.Title, nil, func(name, description string) model.Template {
+		t := model.NewTaskTemplate(name, task)
+		t.Description, t.Owner = description, taskStoreUser
+		return t
+	})
+}
+
+// showSaveTemplate asks for the name and description of a new template, then saves the template
+// returned by makeTemplate. Options are shown below the description when not nil.
+func showSaveTemplate(defaultName string, options fyne.CanvasObject, makeTemplate func(name, description string) model.Template) {
+	st := taskStore
+	name := widget.NewEntry()
+	name.SetText(defaultName)
+	description := widget.NewEntry()
+	description.SetPlaceHolder("What the template is for")
+	items := []*widget.FormItem{widget.NewFormItem("Name", name), widget.NewFormItem("Description", description)}
+	if options != nil {
+		items = append(items, widget.NewFormItem("", options))
+	}
+	d := dialog.NewForm("Save as template", "Save", "Cancel", items, func(ok bool) {
+		if !ok {
+			return
+		}
+		t := makeTemplate(name.Text, description.Text)
+		if err := t.Validate(); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		runTaskAction("saving template", func(ctx context.Context) error {
+			_, err := st.CreateTemplate(ctx, t)
+			return err
+		})
+	}, w)
+	d.Resize(fyne.NewSize(420, 0))
+	d.Show()
+}
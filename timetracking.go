@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"eldar/export"
+	"eldar/model"
+	"eldar/store"
+	"eldar/ui"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+)
+
+// timerBar shows the running timer of the signed-in account below every page
+var timerBar *ui.TimerBar
+
+// timeReportPage adds up the time tracked on the boards of the signed-in account
+var timeReportPage *ui.TimeReportPage
+
+// makeTimePages creates the timer bar and the time report page
+func makeTimePages(st *store.Store) {
+	timerBar = ui.NewTimerBar()
+	timerBar.OnOpen = func() {
+		if timer, ok := st.ActiveTimer(); ok {
+			if task, ok := st.Task(timer.TaskID); ok {
+				showTaskDetail(task)
+			}
+		}
+	}
+	timerBar.OnStop = func() {
+		stopTimer(st)
+	}
+	timeReportPage = ui.NewTimeReportPage(st, exportTimeReport)
+	timeReportPage.Name = func(username string) string {
+		return memberName(st, username)
+	}
+	timeReportPage.Reload()
+	refreshTimers(st)
+}
+
+// makeTimePanel creates the time panel of task. Timers and entries are applied to the cache immediately
+// and rolled back if the server rejects them.
+func makeTimePanel(st *store.Store, task model.Task) *ui.TimePanel {
+	me := taskStoreUser
+	panel := ui.NewTimePanel(me)
+	panel.Name = func(username string) string {
+		return memberName(st, username)
+	}
+	panel.OnStart = func() {
+		startTimer(st, task.ID)
+	}
+	panel.OnStop = func() {
+		stopTimer(st)
+	}
+	panel.OnAdd = func(start time.Time, spent time.Duration, note string) {
+		runTaskAction("adding time", func(ctx context.Context) error {
+			_, err := st.CreateTimeEntry(ctx, model.TimeEntry{
+				TaskID: task.ID, BoardID: task.BoardID, User: me, Start: start, End: start.Add(spent), Note: note, Manual: true,
+			})
+			return err
+		})
+	}
+	panel.OnDelete = func(entry model.TimeEntry) {
+		message := fmt.Sprintf("Delete %s tracked on %s?", model.FormatDuration(entry.Duration()), entry.Start.Local().Format("2 Jan"))
+		dialog.ShowConfirm("Delete time", message, func(ok bool) {
+			if !ok {
+				return
+			}
+			runTaskAction("deleting time", func(ctx context.Context) error {
+				return st.DeleteTimeEntry(ctx, entry.ID)
+			})
+		}, w)
+	}
+	panel.SetEntries(st.TaskTime(task.ID))
+	setPanelTimer(st, panel, task.ID, time.Now())
+	return panel
+}
+
+// startTimer starts timing a task, stopping the running timer first. A running timer that ran for too long
+// to be recorded can be discarded instead, as starting another timer would otherwise fail.
+func startTimer(st *store.Store, taskID string) {
+	me := taskStoreUser
+	start := func() {
+		runTaskAction("starting timer", func(ctx context.Context) error {
+			return st.StartTimer(ctx, me, taskID, time.Now())
+		})
+	}
+	if timer, ok := st.ActiveTimer(); ok && timer.TaskID != taskID {
+		if confirmDiscardTimer(st, timer.Stop(me, time.Now()), start) {
+			return
+		}
+	}
+	start()
+}
+
+// stopTimer stops the running timer and records its time. A timer that ran for too long to be recorded
+// can be discarded instead.
+func stopTimer(st *store.Store) {
+	me := taskStoreUser
+	timer, ok := st.ActiveTimer()
+	if !ok {
+		return
+	}
+	if confirmDiscardTimer(st, timer.Stop(me, time.Now()), nil) {
+		return
+	}
+	runTaskAction("stopping timer", func(ctx context.Context) error {
+		_, err := st.StopTimer(ctx, me, time.Now())
+		return err
+	})
+}
+
+// confirmDiscardTimer offers to discard the running timer when entry, the time it would record, is invalid,
+// for example because it ran for longer than a day. It reports whether it did, then calls discarded,
+// if not nil, once the timer is discarded.
+func confirmDiscardTimer(st *store.Store, entry model.TimeEntry, discarded func()) bool {
+	err := entry.Validate()
+	if err == nil {
+		return false
+	}
+	message := fmt.Sprintf("The timer ran for %s and cannot be recorded: %v. Discard it?", model.FormatDuration(entry.Duration()), err)
+	dialog.ShowConfirm("Discard timer", message, func(ok bool) {
+		if !ok {
+			return
+		}
+		if err := st.DiscardTimer(); err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		if discarded != nil {
+			discarded()
+		}
+	}, w)
+	return true
+}
+
+// refreshTimers shows the running timer in the timer bar and the time panel of the open task, if any
+func refreshTimers(st *store.Store) {
+	if timerBar == nil {
+		return
+	}
+	now := time.Now()
+	timer, ok := st.ActiveTimer()
+	if !ok {
+		timerBar.SetTimer(nil, "", now)
+	} else {
+		task, _ := st.Task(timer.TaskID)
+		timerBar.SetTimer(&timer, task.Title, now)
+	}
+	if activeDetail.time != nil {
+		setPanelTimer(st, activeDetail.time, activeDetail.taskID, now)
+	}
+}
+
+// setPanelTimer shows the running timer in the time panel of a task when it times that task
+func setPanelTimer(st *store.Store, panel *ui.TimePanel, taskID string, now time.Time) {
+	if timer, ok := st.ActiveTimer(); ok && timer.TaskID == taskID {
+		panel.SetTimer(&timer, now)
+		return
+	}
+	panel.SetTimer(nil, now)
+}
+
+// tickTimers advances the clocks of the running timer every second for the lifetime of the app
+func tickTimers() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		fyne.Do(func() {
+			if timerBar != nil {
+				timerBar.Tick(now)
+			}
+			if st := taskStore; st != nil && activeDetail.time != nil {
+				setPanelTimer(st, activeDetail.time, activeDetail.taskID, now)
+			}
+		})
+	}
+}
+
+// exportTimeReport writes the totals of the time report to a CSV file picked by the user
+func exportTimeReport(totals []model.TimeTotal) {
+	st := taskStore
+	if st == nil {
+		return
+	}
+	save := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, w)
+			return
+		}
+		if writer == nil {
+			return
+		}
+		err = export.TimeCSV(writer, totals, st.Board, func(username string) string {
+			return memberName(st, username)
+		})
+		if closeErr := writer.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			log.Printf("Error exporting time report: %v", err)
+			dialog.ShowError(fmt.Errorf("failed to export time report: %w", err), w)
+		}
+	}, w)
+	save.SetFileName("time-report.csv")
+	save.Show()
+}
+
+// memberName returns the name of a member of the group, or their username when it is not known
+func memberName(st *store.Store, username string) string {
+	if m, ok := st.Member(username); ok && m.Name != "" {
+		return m.Name
+	}
+	return username
+}
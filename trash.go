@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"eldar/model"
+	"eldar/store"
+	"eldar/ui"
+	"eldar/undo"
+	"fyne.io/fyne/v2/dialog"
+)
+
+// trashPage lists the deleted boards and tasks of the signed-in account that can be restored
+var trashPage *ui.TrashPage
+
+// archivePage lists the archived boards and tasks of the signed-in account
+var archivePage *ui.ArchivePage
+
+// makeTrashPages creates the Trash and Archive pages and the board actions of the Boards page.
+// Each change is applied to the cache immediately and rolled back if the server rejects it.
+func makeTrashPages(st *store.Store, page *ui.BoardsPage) {
+	trashPage = ui.NewTrashPage(st, func(board model.Board) {
+		runTaskAction("restoring board", func(ctx context.Context) error {
+			return st.RestoreBoard(ctx, board.ID)
+		})
+	}, func(task model.Task) {
+		runTaskAction("restoring task", func(ctx context.Context) error {
+			return st.RestoreTask(ctx, task.ID)
+		})
+	})
+	archivePage = ui.NewArchivePage(st, func(board model.Board) {
+		runTaskCommand(board.ID, &undo.ArchiveBoard{Store: st, BoardID: board.ID, Archive: false})
+	})
+	archivePage.OnUnarchiveTask = func(task model.Task) {
+		unarchived := task.Clone()
+		unarchived.ArchivedAt = nil
+		runTaskCommand(task.BoardID, &undo.UpdateTask{Store: st, Label: "Unarchive task", Before: task, After: unarchived})
+	}
+	page.OnArchiveBoard = func(board model.Board) {
+		runTaskCommand(board.ID, &undo.ArchiveBoard{Store: st, BoardID: board.ID, Archive: true})
+	}
+	page.OnDeleteBoard = func(board model.Board) {
+		message := fmt.Sprintf("Move %q and its tasks to the trash? They can be restored from the Trash tab for %d days.",
+			board.Name, int(model.TrashRetention.Hours()/24))
+		dialog.ShowConfirm("Delete board", message, func(ok bool) {
+			if !ok {
+				return
+			}
+			runTaskCommand(board.ID, &undo.DeleteBoard{Store: st, Board: board})
+		}, w)
+	}
+}
@@ -0,0 +1,100 @@
+package ui
+
+import (
+	"eldar/model"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ActivityFeed lists entries of the event log of a board, newest first, such as
+// "Ana moved "Write report" from To do to Done". It is used for the feed of a board and the history of a task.
+type ActivityFeed struct {
+	widget.BaseWidget
+	// Name returns the display name of a username, the username itself is shown when nil
+	Name func(username string) string
+	// OnOpen is called with the entry that was tapped, or nil to make entries inert
+	OnOpen func(model.Activity)
+
+	list  *fyne.Container
+	empty string
+}
+
+// NewActivityFeed creates an activity feed.
+//
+// Parameters:
+//   - empty: The text shown when there are no entries
+//
+// Returns:
+//   - An ActivityFeed, whose entries are set with SetActivity
+func NewActivityFeed(empty string) *ActivityFeed {
+	f := &ActivityFeed{empty: empty}
+	f.list = container.NewVBox()
+	f.ExtendBaseWidget(f)
+	return f
+}
+
+// CreateRenderer implements fyne.Widget
+func (f *ActivityFeed) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(container.NewVScroll(f.list))
+}
+
+// SetActivity replaces the entries shown with those of board, which should be ordered newest first
+func (f *ActivityFeed) SetActivity(board model.Board, entries []model.Activity) {
+	objects := make([]fyne.CanvasObject, 0, len(entries))
+	for _, a := range entries {
+		objects = append(objects, f.makeEntry(board, a))
+	}
+	if len(objects) == 0 {
+		objects = append(objects, widget.NewLabel(f.empty))
+	}
+	f.list.Objects = objects
+	f.list.Refresh()
+}
+
+// name returns the display name of a username
+func (f *ActivityFeed) name(username string) string {
+	if f.Name == nil {
+		return username
+	}
+	return f.Name(username)
+}
+
+// makeEntry creates the row of an entry, naming the actor in bold
+func (f *ActivityFeed) makeEntry(board model.Board, a model.Activity) fyne.CanvasObject {
+	text := widget.NewRichText(
+		&widget.TextSegment{Text: f.name(a.Actor) + " ", Style: widget.RichTextStyle{Inline: true, TextStyle: fyne.TextStyle{Bold: true}}},
+		&widget.TextSegment{Text: a.Describe(board, f.name), Style: widget.RichTextStyleInline},
+	)
+	text.Wrapping = fyne.TextWrapWord
+	at := widget.NewLabel(a.At.Local().Format("2 Jan 15:04"))
+	at.SizeName = theme.SizeNameCaptionText
+	entry := &activityEntry{content: container.NewVBox(text, at)}
+	if f.OnOpen != nil {
+		entry.onTapped = func() {
+			f.OnOpen(a)
+		}
+	}
+	entry.ExtendBaseWidget(entry)
+	return entry
+}
+
+// activityEntry is a row of an ActivityFeed that can be tapped
+type activityEntry struct {
+	widget.BaseWidget
+	content  fyne.CanvasObject
+	onTapped func()
+}
+
+// CreateRenderer implements fyne.Widget
+func (e *activityEntry) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(e.content)
+}
+
+// Tapped implements fyne.Tappable
+func (e *activityEntry) Tapped(*fyne.PointEvent) {
+	if e.onTapped != nil {
+		e.onTapped()
+	}
+}
@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"eldar/model"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// feedTexts returns the text of the entries of a feed
+func feedTexts(f *ActivityFeed) []string {
+	var texts []string
+	for _, o := range f.list.Objects {
+		if e, ok := o.(*activityEntry); ok {
+			texts = append(texts, e.content.(*fyne.Container).Objects[0].(*widget.RichText).String())
+		}
+	}
+	return texts
+}
+
+func TestActivityFeed(t *testing.T) {
+	test.NewTempApp(t)
+	board := model.Board{ID: "b1", Columns: []model.Column{{ID: "todo", Name: "To do"}, {ID: "done", Name: "Done"}}}
+	at := time.Date(2025, 6, 2, 9, 0, 0, 0, time.Local)
+	feed := NewActivityFeed("No history yet")
+	test.WidgetRenderer(feed)
+
+	feed.SetActivity(board, nil)
+	assert.Equal(t, "No history yet", feed.list.Objects[0].(*widget.Label).Text)
+
+	entries := []model.Activity{
+		{Seq: 2, TaskID: "t1", TaskTitle: "Write report", Actor: "ana", Kind: model.ActivityMoved, From: "todo", To: "done", At: at},
+		{Seq: 1, TaskID: "t1", TaskTitle: "Write report", Actor: "ben", Kind: model.ActivityAssigned, To: "ana", At: at},
+	}
+	feed.SetActivity(board, entries)
+	assert.Equal(t, []string{`ana moved "Write report" from To do to Done`, `ben assigned "Write report" to ana`}, feedTexts(feed))
+
+	// Tapping does nothing until OnOpen is set
+	test.Tap(feed.list.Objects[0].(*activityEntry))
+	var opened []int64
+	feed.OnOpen = func(a model.Activity) {
+		opened = append(opened, a.Seq)
+	}
+	feed.Name = func(username string) string {
+		return map[string]string{"ana": "Ana Lima", "ben": "Ben Kay"}[username]
+	}
+	feed.SetActivity(board, entries)
+	assert.Equal(t, `Ben Kay assigned "Write report" to Ana Lima`, feedTexts(feed)[1])
+	test.Tap(feed.list.Objects[1].(*activityEntry))
+	assert.Equal(t, []int64{1}, opened)
+}
+
+func TestBoardsPageActivity(t *testing.T) {
+	test.NewTempApp(t)
+	board := model.Board{ID: "b1", Name: "Sprint", Columns: []model.Column{{ID: "todo", Name: "To do"}}}
+	st := openTestStore(t, []model.Board{board}, []model.Task{{ID: "t1", BoardID: "b1", ColumnID: "todo", Title: "Write report"}})
+	var opened []string
+	page := NewBoardsPage(st, nil, func(task model.Task) {
+		opened = append(opened, task.ID)
+	})
+	test.WidgetRenderer(page)
+	assert.False(t, page.feedPanel.Visible())
+	assert.Empty(t, feedTexts(page.feed))
+
+	for seq, id := range []string{"t1", "t2"} {
+		require.NoError(t, st.ApplyActivity(context.Background(), model.Activity{
+			Seq: int64(seq + 1), BoardID: "b1", TaskID: id, TaskTitle: id, Actor: "ana", Kind: model.ActivityCreated, To: "todo",
+		}))
+	}
+	page.Reload()
+	assert.Equal(t, []string{`ana created "t2" in To do`, `ana created "t1" in To do`}, feedTexts(page.feed))
+
+	test.Tap(page.feedToggle)
+	assert.True(t, page.feedPanel.Visible())
+
+	// Only tasks that still exist open
+	test.Tap(page.feed.list.Objects[0].(*activityEntry))
+	test.Tap(page.feed.list.Objects[1].(*activityEntry))
+	assert.Equal(t, []string{"t1"}, opened)
+
+	test.Tap(page.feedToggle)
+	assert.False(t, page.feedPanel.Visible())
+}
@@ -0,0 +1,107 @@
+package ui
+
+import (
+	"fmt"
+
+	"eldar/model"
+	"eldar/store"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ArchivePage lists the archived boards with their progress, followed by the archived tasks of the other boards,
+// so finished work stays out of the way of the Boards page and search until it is unarchived.
+// It reads from the local cache, so it works offline.
+type ArchivePage struct {
+	widget.BaseWidget
+	store     *store.Store
+	unarchive func(model.Board)
+	// OnUnarchiveTask is called with an archived task when its Unarchive button is tapped
+	OnUnarchiveTask func(model.Task)
+
+	list *fyne.Container
+}
+
+// NewArchivePage creates the Archive page.
+//
+// Parameters:
+//   - st: The cache the page is rendered from
+//   - unarchive: A function to call when the Unarchive button of a board is tapped
+//
+// Returns:
+//   - An ArchivePage, which must be reloaded with Reload after the cache changes
+func NewArchivePage(st *store.Store, unarchive func(model.Board)) *ArchivePage {
+	p := &ArchivePage{store: st, unarchive: unarchive}
+	p.list = container.NewVBox()
+	p.ExtendBaseWidget(p)
+	p.Reload()
+	return p
+}
+
+// CreateRenderer implements fyne.Widget
+func (p *ArchivePage) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(container.NewVScroll(p.list))
+}
+
+// Reload rebuilds the page from the cache
+func (p *ArchivePage) Reload() {
+	boards := p.store.ArchivedBoards()
+	objects := make([]fyne.CanvasObject, 0, len(boards))
+	for _, b := range boards {
+		objects = append(objects, p.makeRow(b))
+	}
+	if len(objects) == 0 {
+		objects = append(objects, widget.NewLabel("No archived boards"))
+	}
+	if tasks := p.store.ArchivedTasks(); len(tasks) > 0 {
+		objects = append(objects, widget.NewLabelWithStyle("Archived tasks", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+		for _, t := range tasks {
+			objects = append(objects, p.makeTaskRow(t))
+		}
+	}
+	p.list.Objects = objects
+	p.list.Refresh()
+}
+
+// makeRow creates the row of an archived board, with its number of completed tasks and an Unarchive button
+func (p *ArchivePage) makeRow(board model.Board) fyne.CanvasObject {
+	title := widget.NewLabel(board.Name)
+	title.Truncation = fyne.TextTruncateEllipsis
+	done, total := 0, 0
+	for _, t := range p.store.Tasks(board.ID) {
+		total++
+		if t.Done {
+			done++
+		}
+	}
+	detail := fmt.Sprintf("%d of %d tasks done", done, total)
+	if board.ArchivedAt != nil {
+		detail += " · archived " + board.ArchivedAt.Local().Format("2 Jan 2006")
+	}
+	caption := widget.NewLabel(detail)
+	caption.SizeName = theme.SizeNameCaptionText
+	button := widget.NewButtonWithIcon("Unarchive", theme.UploadIcon(), func() {
+		p.unarchive(board)
+	})
+	return container.NewBorder(nil, nil, nil, button, container.NewVBox(title, caption))
+}
+
+// makeTaskRow creates the row of an archived task, with its board and an Unarchive button
+func (p *ArchivePage) makeTaskRow(task model.Task) fyne.CanvasObject {
+	title := widget.NewLabel(task.Title)
+	title.Truncation = fyne.TextTruncateEllipsis
+	detail := task.ArchivedAt.Local().Format("archived 2 Jan 2006")
+	if board, ok := p.store.Board(task.BoardID); ok {
+		detail = board.Name + " · " + detail
+	}
+	caption := widget.NewLabel(detail)
+	caption.SizeName = theme.SizeNameCaptionText
+	button := widget.NewButtonWithIcon("Unarchive", theme.UploadIcon(), func() {
+		if p.OnUnarchiveTask != nil {
+			p.OnUnarchiveTask(task)
+		}
+	})
+	return container.NewBorder(nil, nil, nil, button, container.NewVBox(title, caption))
+}
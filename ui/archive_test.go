@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"eldar/model"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchivePage(t *testing.T) {
+	test.NewTempApp(t)
+	archived := time.Date(2025, 6, 3, 10, 0, 0, 0, time.Local)
+	st := openTestStore(t, []model.Board{
+		{ID: "b1", Name: "Sprint"},
+		{ID: "b2", Name: "Launch", ArchivedAt: &archived},
+	}, []model.Task{
+		{ID: "t1", BoardID: "b2", Title: "Ship it", Done: true},
+		{ID: "t2", BoardID: "b2", Title: "Write notes"},
+		{ID: "t3", BoardID: "b1", Title: "Plan retro", ArchivedAt: &archived},
+	})
+
+	var unarchived []string
+	page := NewArchivePage(st, func(b model.Board) {
+		unarchived = append(unarchived, b.ID)
+	})
+	var unarchivedTasks []string
+	page.OnUnarchiveTask = func(task model.Task) {
+		unarchivedTasks = append(unarchivedTasks, task.ID)
+	}
+	test.WidgetRenderer(page)
+	require.Len(t, page.list.Objects, 3)
+	row := page.list.Objects[0].(*fyne.Container)
+	details := row.Objects[0].(*fyne.Container).Objects
+	assert.Equal(t, "Launch", details[0].(*widget.Label).Text)
+	assert.Equal(t, "1 of 2 tasks done · archived 3 Jun 2025", details[1].(*widget.Label).Text)
+	test.Tap(findButton(t, row, "Unarchive"))
+	assert.Equal(t, []string{"b2"}, unarchived)
+
+	// Archived tasks of other boards follow the boards
+	assert.Equal(t, "Archived tasks", page.list.Objects[1].(*widget.Label).Text)
+	row = page.list.Objects[2].(*fyne.Container)
+	details = row.Objects[0].(*fyne.Container).Objects
+	assert.Equal(t, "Plan retro", details[0].(*widget.Label).Text)
+	assert.Equal(t, "Sprint · archived 3 Jun 2025", details[1].(*widget.Label).Text)
+	test.Tap(findButton(t, row, "Unarchive"))
+	assert.Equal(t, []string{"t3"}, unarchivedTasks)
+
+	empty := NewArchivePage(openTestStore(t, nil, nil), nil)
+	assert.Equal(t, "No archived boards", empty.list.Objects[0].(*widget.Label).Text)
+}
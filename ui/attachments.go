@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"image"
+
+	"eldar/model"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// thumbnailSize is the size of the attachment thumbnails in the attachments panel
+const thumbnailSize = 48
+
+// AttachmentsPanel lists the files attached to a task, with image thumbnails,
+// and shows the progress of uploads and downloads
+type AttachmentsPanel struct {
+	widget.BaseWidget
+	OnAttach func()
+	OnOpen   func(model.Attachment)
+	OnDelete func(model.Attachment)
+	// Thumbnail returns the thumbnail of an image attachment, or nil when it is not available yet
+	Thumbnail func(model.Attachment) image.Image
+
+	list     *fyne.Container
+	attach   *widget.Button
+	status   *widget.Label
+	progress *widget.ProgressBar
+}
+
+// NewAttachmentsPanel creates the attachments panel of a task.
+//
+// Parameters:
+//   - onAttach: A function to call when the user wants to attach a file
+//   - onOpen: A function to call with the attachment to open
+//
+// Returns:
+//   - An AttachmentsPanel, whose attachments are set with SetAttachments
+func NewAttachmentsPanel(onAttach func(), onOpen func(model.Attachment)) *AttachmentsPanel {
+	p := &AttachmentsPanel{OnAttach: onAttach, OnOpen: onOpen}
+	p.list = container.NewVBox()
+	p.attach = widget.NewButtonWithIcon("Attach file...", theme.ContentAddIcon(), func() {
+		if p.OnAttach != nil {
+			p.OnAttach()
+		}
+	})
+	p.status = widget.NewLabel("")
+	p.status.SizeName = theme.SizeNameCaptionText
+	p.progress = widget.NewProgressBar()
+	p.HideProgress()
+	p.ExtendBaseWidget(p)
+	return p
+}
+
+// CreateRenderer implements fyne.Widget
+func (p *AttachmentsPanel) CreateRenderer() fyne.WidgetRenderer {
+	limit := widget.NewLabel("Files up to " + model.FormatSize(model.MaxAttachmentSize))
+	limit.SizeName = theme.SizeNameCaptionText
+	top := container.NewVBox(container.NewHBox(p.attach, limit), p.status, p.progress)
+	return widget.NewSimpleRenderer(container.NewBorder(top, nil, nil, nil, container.NewVScroll(p.list)))
+}
+
+// SetAttachments replaces the attachments shown
+func (p *AttachmentsPanel) SetAttachments(attachments []model.Attachment) {
+	objects := make([]fyne.CanvasObject, 0, len(attachments))
+	for _, a := range attachments {
+		objects = append(objects, p.makeAttachment(a))
+	}
+	if len(objects) == 0 {
+		objects = append(objects, widget.NewLabel("No files attached"))
+	}
+	p.list.Objects = objects
+	p.list.Refresh()
+}
+
+// SetProgress shows the progress of a transfer, described by what, of done out of total bytes
+func (p *AttachmentsPanel) SetProgress(what string, done, total int64) {
+	p.status.SetText(what + " · " + model.FormatSize(done) + " of " + model.FormatSize(total))
+	p.status.Show()
+	if total > 0 {
+		p.progress.SetValue(float64(done) / float64(total))
+	}
+	p.progress.Show()
+	p.attach.Disable()
+}
+
+// HideProgress hides the progress of a finished transfer
+func (p *AttachmentsPanel) HideProgress() {
+	p.status.Hide()
+	p.progress.Hide()
+	p.progress.SetValue(0)
+	p.attach.Enable()
+}
+
+// makeAttachment creates the row of an attachment with its thumbnail, size and actions
+func (p *AttachmentsPanel) makeAttachment(a model.Attachment) fyne.CanvasObject {
+	var preview fyne.CanvasObject
+	var thumb image.Image
+	if a.IsImage() && p.Thumbnail != nil {
+		thumb = p.Thumbnail(a)
+	}
+	if thumb != nil {
+		img := canvas.NewImageFromImage(thumb)
+		img.FillMode = canvas.ImageFillContain
+		img.SetMinSize(fyne.NewSquareSize(thumbnailSize))
+		preview = img
+	} else {
+		icon := widget.NewIcon(theme.FileIcon())
+		if a.IsImage() {
+			icon.SetResource(theme.FileImageIcon())
+		}
+		preview = container.NewGridWrap(fyne.NewSquareSize(thumbnailSize), icon)
+	}
+
+	name := widget.NewLabel(a.Name)
+	name.Truncation = fyne.TextTruncateEllipsis
+	details := widget.NewLabel(model.FormatSize(a.Size))
+	if a.MediaType != "" {
+		details.SetText(details.Text + " · " + a.MediaType)
+	}
+	details.SizeName = theme.SizeNameCaptionText
+
+	open := widget.NewButton("Open", func() {
+		if p.OnOpen != nil {
+			p.OnOpen(a)
+		}
+	})
+	remove := widget.NewButtonWithIcon("Delete", theme.DeleteIcon(), func() {
+		if p.OnDelete != nil {
+			p.OnDelete(a)
+		}
+	})
+	actions := container.NewHBox(layout.NewSpacer(), open, remove)
+	return container.NewBorder(nil, nil, preview, actions, container.NewVBox(name, details))
+}
@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"image"
+	"testing"
+
+	"eldar/model"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachmentsPanel(t *testing.T) {
+	test.NewTempApp(t)
+	attached := false
+	var opened, deleted []string
+	panel := NewAttachmentsPanel(func() {
+		attached = true
+	}, func(a model.Attachment) {
+		opened = append(opened, a.ID)
+	})
+	panel.OnDelete = func(a model.Attachment) {
+		deleted = append(deleted, a.ID)
+	}
+	panel.Thumbnail = func(a model.Attachment) image.Image {
+		if a.ID == "a1" {
+			return image.NewRGBA(image.Rect(0, 0, 48, 32))
+		}
+		return nil
+	}
+	test.WidgetRenderer(panel)
+
+	panel.SetAttachments(nil)
+	assert.Equal(t, "No files attached", panel.list.Objects[0].(*widget.Label).Text)
+
+	panel.SetAttachments([]model.Attachment{
+		{ID: "a1", Name: "mockup.png", MediaType: "image/png", Size: 2048},
+		{ID: "a2", Name: "notes.txt", MediaType: "text/plain", Size: 12},
+	})
+	require.Len(t, panel.list.Objects, 2)
+	picture, text := panel.list.Objects[0].(*fyne.Container), panel.list.Objects[1].(*fyne.Container)
+	assert.IsType(t, &canvas.Image{}, picture.Objects[1])
+	assert.Equal(t, "2.0 KB · image/png", picture.Objects[0].(*fyne.Container).Objects[1].(*widget.Label).Text)
+	assert.IsType(t, &fyne.Container{}, text.Objects[1])
+
+	test.Tap(findButton(t, text, "Open"))
+	test.Tap(findButton(t, picture, "Delete"))
+	assert.Equal(t, []string{"a2"}, opened)
+	assert.Equal(t, []string{"a1"}, deleted)
+
+	test.Tap(panel.attach)
+	assert.True(t, attached)
+
+	panel.SetProgress("Uploading report.pdf", 512, 2048)
+	assert.Equal(t, "Uploading report.pdf · 512 B of 2.0 KB", panel.status.Text)
+	assert.Equal(t, 0.25, panel.progress.Value)
+	assert.True(t, panel.attach.Disabled())
+	panel.HideProgress()
+	assert.False(t, panel.progress.Visible())
+	assert.False(t, panel.attach.Disabled())
+}
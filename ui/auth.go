@@ -3,14 +3,28 @@
 package ui
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"log"
+	"math"
 	"net/mail"
 	"regexp"
+	"time"
 
+	"eldar/auth"
+	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/widget"
 )
 
+// loginTimeout bounds a single login request
+const loginTimeout = 30 * time.Second
+
+// afterFunc schedules f to run after d, replaced in tests to control countdowns
+var afterFunc = func(d time.Duration, f func()) {
+	time.AfterFunc(d, f)
+}
+
 // Regular expressions used for password validation
 var (
 	// upperRe matches any uppercase letter
@@ -25,15 +39,22 @@ var (
 
 // MakeLoginForm creates and returns a login form widget.
 // It includes fields for email and password, along with a button to navigate to the registration page.
+// After failed attempts the form stays disabled for the backoff imposed by the throttle, showing a
+// countdown on the submit button.
 //
 // Parameters:
 //   - ap: A pointer to the current AppPage, which will be updated when navigating to the registration page
 //   - updateWindow: A function to call when the app page changes to update the window content
+//   - login: A function signing in with the email and password, called in the background
+//   - throttle: The throttle recording login attempts, shared across forms so the backoff survives navigation
+//   - onError: A function called with the error when login fails
 //
 // Returns:
 //   - A configured widget.Form ready to be displayed
-func MakeLoginForm(ap *AppPage, updateWindow func()) *widget.Form {
+func MakeLoginForm(ap *AppPage, updateWindow func(), login func(ctx context.Context, email, password string) error, throttle *auth.Throttle, onError func(error)) *widget.Form {
 	form := widget.NewForm()
+	// waiting is true while a login attempt is running or the throttle countdown is shown
+	waiting := false
 	emailInput := widget.NewEntry()
 	emailInput.SetPlaceHolder("Enter your email address")
 	emailInput.Validator = func(s string) error {
@@ -47,7 +68,9 @@ func MakeLoginForm(ap *AppPage, updateWindow func()) *widget.Form {
 			return
 		}
 		emailInput.SetValidationError(nil)
-		form.Enable()
+		if !waiting {
+			form.Enable()
+		}
 	}
 	form.AppendItem(widget.NewFormItem("Email", emailInput))
 	passwordInput := widget.NewPasswordEntry()
@@ -59,8 +82,56 @@ func MakeLoginForm(ap *AppPage, updateWindow func()) *widget.Form {
 	})
 	form.AppendItem(widget.NewFormItem("Don't have an account yet?", registerButton))
 	form.SubmitText = "Login"
+
+	// countdown keeps the form disabled until the throttle allows the next attempt
+	var countdown func()
+	countdown = func() {
+		remaining := throttle.Remaining()
+		if remaining <= 0 {
+			waiting = false
+			form.SubmitText = "Login"
+			form.Refresh()
+			form.Enable()
+			return
+		}
+		waiting = true
+		form.Disable()
+		form.SubmitText = fmt.Sprintf("Retry in %ds", int(math.Ceil(remaining.Seconds())))
+		form.Refresh()
+		afterFunc(min(remaining, time.Second), func() {
+			fyne.Do(countdown)
+		})
+	}
+
 	form.OnSubmit = func() {
-		log.Printf("Login user with email: %s, password: %s", emailInput.Text, passwordInput.Text)
+		if waiting || throttle.Remaining() > 0 {
+			countdown()
+			return
+		}
+		email, password := emailInput.Text, passwordInput.Text
+		waiting = true
+		form.Disable()
+		form.SubmitText = "Logging in..."
+		form.Refresh()
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), loginTimeout)
+			defer cancel()
+			err := login(ctx, email, password)
+			throttle.Record(err)
+			fyne.Do(func() {
+				if err == nil {
+					*ap = Boards
+					updateWindow()
+					return
+				}
+				passwordInput.SetText("")
+				countdown()
+				onError(err)
+			})
+		}()
+	}
+	if throttle.Remaining() > 0 {
+		countdown()
 	}
 	return form
 }
@@ -1,9 +1,13 @@
 package ui
 
 import (
+	"context"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"eldar/auth"
 	"fyne.io/fyne/v2/test"
 	"fyne.io/fyne/v2/widget"
 	"github.com/stretchr/testify/assert"
@@ -16,7 +20,9 @@ func TestMakeLoginForm(t *testing.T) {
 		updateWindowCalled = true
 	}
 
-	form := MakeLoginForm(&ap, updateWindow)
+	form := MakeLoginForm(&ap, updateWindow, func(ctx context.Context, email, password string) error {
+		return nil
+	}, auth.NewThrottle(time.Second, time.Minute), func(err error) {})
 	assert.NotNil(t, form)
 	assert.Equal(t, 3, len(form.Items))
 	assert.Equal(t, "Login", form.SubmitText)
@@ -48,6 +54,50 @@ func TestMakeLoginForm(t *testing.T) {
 	assert.True(t, updateWindowCalled)
 }
 
+func TestMakeLoginFormThrottle(t *testing.T) {
+	test.NewTempApp(t)
+	var ticks []func()
+	afterFunc = func(d time.Duration, f func()) {
+		ticks = append(ticks, f)
+	}
+	t.Cleanup(func() {
+		afterFunc = func(d time.Duration, f func()) {
+			time.AfterFunc(d, f)
+		}
+	})
+	ap := Login
+	var reported atomic.Value
+	var attempts atomic.Int32
+	throttle := auth.NewThrottle(2*time.Second, time.Minute)
+	form := MakeLoginForm(&ap, func() {}, func(ctx context.Context, email, password string) error {
+		attempts.Add(1)
+		return auth.ErrInvalidCredentials
+	}, throttle, func(err error) {
+		reported.Store(err)
+	})
+
+	test.Type(form.Items[0].Widget.(*widget.Entry), "eldar@ioluas.dev")
+	test.Type(form.Items[1].Widget.(*widget.Entry), "wrong")
+	form.OnSubmit()
+	assert.Eventually(t, func() bool { return reported.Load() != nil }, time.Second, 10*time.Millisecond)
+	assert.ErrorIs(t, reported.Load().(error), auth.ErrInvalidCredentials)
+	assert.Equal(t, "Retry in 2s", form.SubmitText)
+
+	// Submitting during the backoff does not attempt to log in
+	form.OnSubmit()
+	assert.Equal(t, int32(1), attempts.Load())
+	assert.Equal(t, Login, ap)
+
+	// Re-creating the form keeps the countdown
+	form = MakeLoginForm(&ap, func() {}, nil, throttle, nil)
+	assert.Contains(t, form.SubmitText, "Retry in")
+
+	// The countdown ends once the throttle allows the next attempt
+	throttle.Record(nil)
+	ticks[len(ticks)-1]()
+	assert.Equal(t, "Login", form.SubmitText)
+}
+
 func TestMakeRegisterForm(t *testing.T) {
 	form := MakeRegisterForm()
 	assert.NotNil(t, form)
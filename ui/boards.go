@@ -0,0 +1,630 @@
+package ui
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"slices"
+	"strings"
+
+	"eldar/model"
+	"eldar/store"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Size of the activity feed of the Boards page
+const (
+	feedWidth  = 280
+	feedLength = 100
+)
+
+// cellHeight is the minimum height of the cards of a column in a swimlane, so that cards can be dropped on empty ones
+const cellHeight = 48
+
+// BoardsPage shows the columns and task cards of the selected board, or the results of a search
+// across all boards while the search bar holds a query. The columns can be split into swimlanes, which
+// collapse when their header is tapped, and cards are dragged between columns and swimlanes to move them.
+// In selection mode tapping cards selects them instead of opening them, for changes made to all of them at once.
+// The activity feed of the board can be shown alongside. It reads from the local task cache, so it works offline.
+type BoardsPage struct {
+	widget.BaseWidget
+	store    *store.Store
+	search   func(query string) ([]model.Task, error)
+	openTask func(model.Task)
+	// OnSearch is called with the query whenever the text of the search bar changes
+	OnSearch func(query string)
+	// Thumbnail returns the cover image of a task card, or nil when it has none or it is not available yet
+	Thumbnail func(model.Task) image.Image
+	// OnEditBoard, OnSaveTemplate, OnArchiveBoard and OnDeleteBoard are called with the selected board
+	// from its actions menu
+	OnEditBoard    func(model.Board)
+	OnSaveTemplate func(model.Board)
+	OnArchiveBoard func(model.Board)
+	OnDeleteBoard  func(model.Board)
+	// OnSwimlanes is called with the selected board and the grouping picked for its swimlanes
+	OnSwimlanes func(model.Board, model.Swimlanes)
+	// OnMoveTask is called with a task before and after its card was dragged to another column or swimlane
+	OnMoveTask func(before, after model.Task)
+	// OnBulkEdit is called with a change picked from the selection bar and the selected tasks
+	OnBulkEdit func(model.BulkEdit, []model.Task)
+
+	boardSelect *widget.Select
+	boardIDs    []string
+	laneSelect  *widget.Select
+	collapsed   map[laneID]bool
+	cells       []dropCell
+	searchEntry *widget.Entry
+	columns     *fyne.Container
+	feed        *ActivityFeed
+	feedPanel   *fyne.Container
+	feedToggle  *widget.Button
+	boardMenu   *widget.Button
+	// selecting is set in selection mode, selected holds the IDs of the selected tasks
+	selecting    bool
+	selected     map[string]bool
+	selectToggle *widget.Button
+	bulkBar      *BulkBar
+	// shown are the tasks with a card on the page, cards their cards
+	shown []model.Task
+	cards []*TaskCard
+}
+
+// laneID identifies a swimlane of a board
+type laneID struct {
+	boardID string
+	key     string
+}
+
+// dropCell holds the cards of a column in a swimlane, cards dragged onto it are moved there
+type dropCell struct {
+	columnID   string
+	lane       string
+	area       *fyne.Container
+	background *canvas.Rectangle
+}
+
+// NewBoardsPage creates the Boards page.
+//
+// Parameters:
+//   - st: The task cache the page is rendered from
+//   - search: A function returning the tasks matching a query, see search.Parse for the syntax
+//   - openTask: A function to call when a task card or search result is tapped
+//
+// Returns:
+//   - A BoardsPage, which must be reloaded with Reload after the cache changes
+func NewBoardsPage(st *store.Store, search func(query string) ([]model.Task, error), openTask func(model.Task)) *BoardsPage {
+	p := &BoardsPage{store: st, search: search, openTask: openTask, collapsed: map[laneID]bool{}, selected: map[string]bool{}}
+	p.boardSelect = widget.NewSelect(nil, func(string) {
+		p.reloadColumns()
+	})
+	p.boardSelect.PlaceHolder = "Select a board"
+	options := make([]string, len(model.SwimlaneGroupings))
+	for i, grouping := range model.SwimlaneGroupings {
+		options[i] = swimlanesName(grouping)
+	}
+	p.laneSelect = widget.NewSelect(options, func(string) {
+		if index := p.laneSelect.SelectedIndex(); index >= 0 {
+			p.setSwimlanes(model.SwimlaneGroupings[index])
+		}
+	})
+	p.searchEntry = widget.NewEntry()
+	p.searchEntry.SetPlaceHolder("Search tasks, e.g. report assignee:me due:<7d")
+	p.searchEntry.OnChanged = func(query string) {
+		p.reloadColumns()
+		if p.OnSearch != nil {
+			p.OnSearch(query)
+		}
+	}
+	p.columns = container.NewGridWithColumns(1)
+	p.feed = NewActivityFeed("No activity yet")
+	p.feed.Name = func(username string) string {
+		if m, ok := st.Member(username); ok && m.Name != "" {
+			return m.Name
+		}
+		return username
+	}
+	p.feed.OnOpen = func(a model.Activity) {
+		// Deleted tasks are only in the feed
+		if task, ok := st.Task(a.TaskID); ok {
+			p.openTask(task)
+		}
+	}
+	width := canvas.NewRectangle(color.Transparent)
+	width.SetMinSize(fyne.NewSize(feedWidth, 0))
+	p.feedPanel = container.NewStack(width, p.feed)
+	p.feedPanel.Hide()
+	p.feedToggle = widget.NewButtonWithIcon("Activity", theme.HistoryIcon(), p.ToggleActivity)
+	p.boardMenu = widget.NewButtonWithIcon("", theme.MoreVerticalIcon(), p.showBoardMenu)
+	p.selectToggle = widget.NewButtonWithIcon("Select", theme.CheckButtonCheckedIcon(), func() {
+		p.SetSelecting(!p.selecting)
+	})
+	p.bulkBar = NewBulkBar()
+	p.bulkBar.OnEdit = func(e model.BulkEdit) {
+		if p.OnBulkEdit != nil {
+			p.OnBulkEdit(e, p.SelectedTasks())
+		}
+	}
+	p.bulkBar.OnSelectAll = p.SelectAll
+	p.bulkBar.OnDone = func() {
+		p.SetSelecting(false)
+	}
+	p.bulkBar.Hide()
+	p.ExtendBaseWidget(p)
+	p.Reload()
+	return p
+}
+
+// CreateRenderer implements fyne.Widget
+func (p *BoardsPage) CreateRenderer() fyne.WidgetRenderer {
+	top := container.NewBorder(nil, nil, p.boardSelect, container.NewHBox(p.laneSelect, p.selectToggle, p.feedToggle, p.boardMenu), p.searchEntry)
+	return widget.NewSimpleRenderer(container.NewBorder(top, p.bulkBar, nil, p.feedPanel, container.NewVScroll(p.columns)))
+}
+
+// ToggleActivity shows or hides the activity feed of the selected board
+func (p *BoardsPage) ToggleActivity() {
+	if p.feedPanel.Visible() {
+		p.feedPanel.Hide()
+		p.feedToggle.Importance = widget.MediumImportance
+	} else {
+		p.feedPanel.Show()
+		p.feedToggle.Importance = widget.HighImportance
+	}
+	p.feedToggle.Refresh()
+	p.Refresh()
+}
+
+// showBoardMenu pops up the actions menu of the selected board below its button
+func (p *BoardsPage) showBoardMenu() {
+	if menu := p.boardActions(); menu != nil {
+		showMenuBelow(menu, p.boardMenu)
+	}
+}
+
+// SetSelecting turns selection mode on or off, leaving it clears the selection
+func (p *BoardsPage) SetSelecting(selecting bool) {
+	p.selecting = selecting
+	if selecting {
+		p.selectToggle.Importance = widget.HighImportance
+		p.bulkBar.Show()
+	} else {
+		clear(p.selected)
+		p.selectToggle.Importance = widget.MediumImportance
+		p.bulkBar.Hide()
+	}
+	p.selectToggle.Refresh()
+	p.updateSelection()
+	p.Refresh()
+}
+
+// SelectAll selects every task with a card on the page
+func (p *BoardsPage) SelectAll() {
+	for _, task := range p.shown {
+		p.selected[task.ID] = true
+	}
+	p.updateSelection()
+}
+
+// SelectedTasks returns the selected tasks in the order of their cards
+func (p *BoardsPage) SelectedTasks() []model.Task {
+	var tasks []model.Task
+	for _, task := range p.shown {
+		if p.selected[task.ID] {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+// toggleSelected selects a task or clears its selection
+func (p *BoardsPage) toggleSelected(task model.Task) {
+	if p.selected[task.ID] {
+		delete(p.selected, task.ID)
+	} else {
+		p.selected[task.ID] = true
+	}
+	p.updateSelection()
+}
+
+// updateSelection drops the selected tasks that no longer have a card, outlines the cards of the
+// selected ones and offers the columns and labels of their boards on the selection bar
+func (p *BoardsPage) updateSelection() {
+	shown := map[string]bool{}
+	for _, task := range p.shown {
+		shown[task.ID] = true
+	}
+	for id := range p.selected {
+		if !shown[id] {
+			delete(p.selected, id)
+		}
+	}
+	for _, card := range p.cards {
+		if card.Selected != p.selected[card.Task.ID] {
+			card.Selected = p.selected[card.Task.ID]
+			card.Refresh()
+		}
+	}
+
+	var columns, labels []string
+	add := func(names []string, name string) []string {
+		if slices.ContainsFunc(names, func(n string) bool { return strings.EqualFold(n, name) }) {
+			return names
+		}
+		return append(names, name)
+	}
+	boards := map[string]bool{}
+	selected := p.SelectedTasks()
+	for _, task := range selected {
+		board, ok := p.store.Board(task.BoardID)
+		if !ok || boards[board.ID] {
+			continue
+		}
+		boards[board.ID] = true
+		for _, column := range board.Columns {
+			columns = add(columns, column.Name)
+		}
+		for _, label := range board.Labels {
+			labels = add(labels, label.Name)
+		}
+	}
+	for _, task := range selected {
+		for _, label := range task.Labels {
+			labels = add(labels, label)
+		}
+	}
+	p.bulkBar.SetSelection(len(selected), columns, labels, p.store.Members())
+}
+
+// boardActions returns the actions menu of the selected board, or nil when no board is selected
+func (p *BoardsPage) boardActions() *fyne.Menu {
+	board, ok := p.store.Board(p.SelectedBoard())
+	if !ok {
+		return nil
+	}
+	edit := fyne.NewMenuItem("Board settings...", func() {
+		if p.OnEditBoard != nil {
+			p.OnEditBoard(board)
+		}
+	})
+	edit.Icon = theme.SettingsIcon()
+	template := fyne.NewMenuItem("Save as template...", func() {
+		if p.OnSaveTemplate != nil {
+			p.OnSaveTemplate(board)
+		}
+	})
+	template.Icon = theme.DocumentSaveIcon()
+	archive := fyne.NewMenuItem("Archive board", func() {
+		if p.OnArchiveBoard != nil {
+			p.OnArchiveBoard(board)
+		}
+	})
+	archive.Icon = theme.DownloadIcon()
+	remove := fyne.NewMenuItem("Move board to trash", func() {
+		if p.OnDeleteBoard != nil {
+			p.OnDeleteBoard(board)
+		}
+	})
+	remove.Icon = theme.DeleteIcon()
+	return fyne.NewMenu("", edit, template, fyne.NewMenuItemSeparator(), archive, remove)
+}
+
+// setSwimlanes asks for the selected board to be split into swimlanes by grouping, unless it already is
+func (p *BoardsPage) setSwimlanes(grouping model.Swimlanes) {
+	board, ok := p.store.Board(p.SelectedBoard())
+	if !ok || board.Swimlanes == grouping || p.OnSwimlanes == nil {
+		return
+	}
+	p.OnSwimlanes(board, grouping)
+}
+
+// swimlanesName returns the option of the swimlanes selector for a grouping
+func swimlanesName(grouping model.Swimlanes) string {
+	if grouping == model.SwimlanesNone {
+		return "No swimlanes"
+	}
+	return "Lanes by " + strings.ToLower(grouping.String())
+}
+
+// SelectedBoard returns the ID of the board being shown, or an empty string
+func (p *BoardsPage) SelectedBoard() string {
+	index := p.boardSelect.SelectedIndex()
+	if index < 0 || index >= len(p.boardIDs) {
+		return ""
+	}
+	return p.boardIDs[index]
+}
+
+// SelectBoard shows the board with the given ID, if it is in the board selector
+func (p *BoardsPage) SelectBoard(id string) {
+	if index := slices.Index(p.boardIDs, id); index >= 0 {
+		p.boardSelect.SetSelectedIndex(index)
+	}
+}
+
+// Reload rebuilds the page from the task cache, keeping the selected board when it still exists
+func (p *BoardsPage) Reload() {
+	selected := p.SelectedBoard()
+	boards := p.store.Boards()
+	names := make([]string, len(boards))
+	p.boardIDs = make([]string, len(boards))
+	selectedIndex := 0
+	for i, b := range boards {
+		names[i] = b.Name
+		p.boardIDs[i] = b.ID
+		if b.ID == selected {
+			selectedIndex = i
+		}
+	}
+	p.boardSelect.SetOptions(names)
+	if len(boards) > 0 {
+		// SetSelectedIndex does not fire OnChanged when the selection is unchanged
+		p.boardSelect.SetSelectedIndex(selectedIndex)
+	}
+	p.reloadColumns()
+}
+
+// Search shows the tasks matching query in place of the columns, or the columns again when query is empty
+func (p *BoardsPage) Search(query string) {
+	// SetText calls OnChanged, which reloads the page
+	p.searchEntry.SetText(query)
+}
+
+// Query returns the text of the search bar
+func (p *BoardsPage) Query() string {
+	return p.searchEntry.Text
+}
+
+// reloadColumns rebuilds the columns of the selected board, or the search results while there is a query
+func (p *BoardsPage) reloadColumns() {
+	p.cells, p.shown, p.cards = nil, nil, nil
+	defer p.updateSelection()
+	if query := strings.TrimSpace(p.searchEntry.Text); query != "" {
+		p.laneSelect.Disable()
+		p.showResults(query)
+		return
+	}
+
+	board, ok := p.store.Board(p.SelectedBoard())
+	p.feed.SetActivity(board, p.store.Activity(board.ID, feedLength))
+	if !ok {
+		p.laneSelect.Disable()
+		p.columns.Objects = []fyne.CanvasObject{widget.NewLabel("No boards yet")}
+		p.columns.Layout = container.NewGridWithColumns(1).Layout
+		p.columns.Refresh()
+		return
+	}
+	p.laneSelect.Enable()
+	// setSwimlanes ignores the grouping the board already has
+	p.laneSelect.SetSelectedIndex(max(slices.Index(model.SwimlaneGroupings, board.Swimlanes), 0))
+
+	var tasks []model.Task
+	for _, task := range p.store.Tasks(board.ID) {
+		// Subtasks are listed in the detail of their parent rather than as cards
+		if task.ParentID == "" {
+			tasks = append(tasks, task)
+		}
+	}
+	if board.Swimlanes != model.SwimlanesNone {
+		p.showLanes(board, tasks)
+		return
+	}
+
+	tasksByColumn := map[string][]model.Task{}
+	for _, task := range tasks {
+		tasksByColumn[task.ColumnID] = append(tasksByColumn[task.ColumnID], task)
+	}
+	objects := make([]fyne.CanvasObject, len(board.Columns))
+	for i, column := range board.Columns {
+		objects[i] = p.makeColumn(column, tasksByColumn[column.ID])
+	}
+	p.columns.Objects = objects
+	p.columns.Layout = container.NewGridWithColumns(max(len(objects), 1)).Layout
+	p.columns.Refresh()
+}
+
+// makeColumn renders a column header above its task cards
+func (p *BoardsPage) makeColumn(column model.Column, tasks []model.Task) fyne.CanvasObject {
+	return container.NewBorder(columnHeader(column, len(tasks)), nil, nil, nil, p.makeCell(column.ID, "", tasks))
+}
+
+// showLanes renders the column headers of board above a row of columns for each of its swimlanes.
+// Collapsed swimlanes only show their header.
+func (p *BoardsPage) showLanes(board model.Board, tasks []model.Task) {
+	type cell struct {
+		lane     string
+		columnID string
+	}
+	counts := map[string]int{}
+	laneCounts := map[string]int{}
+	cells := map[cell][]model.Task{}
+	for _, task := range tasks {
+		counts[task.ColumnID]++
+		for _, key := range board.LaneKeys(&task) {
+			laneCounts[key]++
+			cells[cell{key, task.ColumnID}] = append(cells[cell{key, task.ColumnID}], task)
+		}
+	}
+
+	width := max(len(board.Columns), 1)
+	headers := container.NewGridWithColumns(width)
+	for _, column := range board.Columns {
+		headers.Add(columnHeader(column, counts[column.ID]))
+	}
+	objects := []fyne.CanvasObject{headers}
+	for _, lane := range board.Lanes(tasks, p.store.Members()) {
+		id := laneID{board.ID, lane.Key}
+		objects = append(objects, p.laneHeader(id, lane.Name, laneCounts[lane.Key]))
+		if p.collapsed[id] {
+			continue
+		}
+		row := container.NewGridWithColumns(width)
+		for _, column := range board.Columns {
+			row.Add(p.makeCell(column.ID, lane.Key, cells[cell{lane.Key, column.ID}]))
+		}
+		objects = append(objects, row)
+	}
+	p.columns.Objects = objects
+	p.columns.Layout = layout.NewVBoxLayout()
+	p.columns.Refresh()
+}
+
+// laneHeader renders the name and number of cards of a swimlane as a button collapsing or expanding it
+func (p *BoardsPage) laneHeader(id laneID, name string, count int) *widget.Button {
+	icon := theme.MenuDropDownIcon()
+	if p.collapsed[id] {
+		icon = theme.MenuExpandIcon()
+	}
+	header := widget.NewButtonWithIcon(fmt.Sprintf("%s (%d)", name, count), icon, func() {
+		if p.collapsed[id] {
+			delete(p.collapsed, id)
+		} else {
+			p.collapsed[id] = true
+		}
+		p.reloadColumns()
+	})
+	header.Alignment = widget.ButtonAlignLeading
+	header.Importance = widget.LowImportance
+	return header
+}
+
+// makeCell renders the task cards of a column in a swimlane, which are moved when dragged onto another cell
+func (p *BoardsPage) makeCell(columnID, lane string, tasks []model.Task) fyne.CanvasObject {
+	background := canvas.NewRectangle(color.Transparent)
+	background.CornerRadius = theme.InputRadiusSize()
+	background.SetMinSize(fyne.NewSize(0, cellHeight))
+	cards := container.NewVBox()
+	for _, task := range tasks {
+		card := p.makeCard(task)
+		card.OnDragged = p.highlightCell
+		card.OnDrop = func(position fyne.Position) {
+			p.dropTask(task, lane, position)
+		}
+		cards.Add(card)
+	}
+	area := container.NewStack(background, cards)
+	p.cells = append(p.cells, dropCell{columnID: columnID, lane: lane, area: area, background: background})
+	return area
+}
+
+// cellAt returns the cell at an absolute position
+func (p *BoardsPage) cellAt(position fyne.Position) (dropCell, bool) {
+	driver := fyne.CurrentApp().Driver()
+	for _, cell := range p.cells {
+		origin := driver.AbsolutePositionForObject(cell.area)
+		size := cell.area.Size()
+		if position.X >= origin.X && position.Y >= origin.Y &&
+			position.X < origin.X+size.Width && position.Y < origin.Y+size.Height {
+			return cell, true
+		}
+	}
+	return dropCell{}, false
+}
+
+// highlightCell highlights the cell at an absolute position, clearing the highlight of the others
+func (p *BoardsPage) highlightCell(position fyne.Position) {
+	target, _ := p.cellAt(position)
+	for _, cell := range p.cells {
+		fill := color.Color(color.Transparent)
+		if cell.area == target.area {
+			fill = theme.Color(theme.ColorNameHover)
+		}
+		if cell.background.FillColor != fill {
+			cell.background.FillColor = fill
+			cell.background.Refresh()
+		}
+	}
+}
+
+// dropTask moves a task whose card was dragged out of a swimlane to the column and swimlane of the cell
+// at an absolute position
+func (p *BoardsPage) dropTask(task model.Task, lane string, position fyne.Position) {
+	p.highlightCell(fyne.NewPos(-1, -1))
+	target, ok := p.cellAt(position)
+	if !ok || (target.columnID == task.ColumnID && target.lane == lane) || p.OnMoveTask == nil {
+		return
+	}
+	board, ok := p.store.Board(task.BoardID)
+	if !ok {
+		return
+	}
+	after := task.Clone()
+	after.ColumnID = target.columnID
+	if target.lane != lane {
+		if err := board.MoveToLane(&after, lane, target.lane); err != nil {
+			return
+		}
+	}
+	p.OnMoveTask(task, after)
+}
+
+// columnHeader renders the name of a column followed by its policies: the number of cards against
+// the WIP limit, marked as a warning at the limit and as an error over it, and a check mark when
+// the column has a definition of done
+func columnHeader(column model.Column, count int) *fyne.Container {
+	name := widget.NewLabelWithStyle(column.Name, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	policies := container.NewHBox()
+	if column.WIPLimit > 0 {
+		limit := widget.NewLabel(fmt.Sprintf("%d/%d", count, column.WIPLimit))
+		switch {
+		case count > column.WIPLimit:
+			limit.Importance = widget.DangerImportance
+		case count == column.WIPLimit:
+			limit.Importance = widget.WarningImportance
+		}
+		policies.Add(limit)
+	}
+	if len(column.DefinitionOfDone) > 0 {
+		policies.Add(widget.NewIcon(theme.ConfirmIcon()))
+	}
+	return container.NewBorder(nil, nil, nil, policies, name)
+}
+
+// makeCard creates the card of a task, opening it when tapped or selecting it in selection mode
+func (p *BoardsPage) makeCard(task model.Task) *TaskCard {
+	card := NewTaskCard(task, p.store.Progress(task.ID), func() {
+		if p.selecting {
+			p.toggleSelected(task)
+			return
+		}
+		p.openTask(task)
+	})
+	card.Selected = p.selected[task.ID]
+	// Cards of tasks in several swimlanes are shown once per swimlane
+	if !slices.ContainsFunc(p.shown, func(t model.Task) bool { return t.ID == task.ID }) {
+		p.shown = append(p.shown, task)
+	}
+	p.cards = append(p.cards, card)
+	card.Blockers = p.store.OpenBlockers(task.ID)
+	if board, ok := p.store.Board(task.BoardID); ok && len(task.Labels) > 0 {
+		card.Labels = board.TaskLabels(&task)
+	}
+	if p.Thumbnail != nil {
+		card.Cover = p.Thumbnail(task)
+	}
+	return card
+}
+
+// showResults replaces the columns with the tasks matching query
+func (p *BoardsPage) showResults(query string) {
+	results := container.NewVBox()
+	tasks, err := p.search(query)
+	switch {
+	case err != nil:
+		message := widget.NewLabel(err.Error())
+		message.Importance = widget.DangerImportance
+		message.Wrapping = fyne.TextWrapWord
+		results.Add(message)
+	case len(tasks) == 0:
+		results.Add(widget.NewLabel("No matching tasks"))
+	}
+	for _, task := range tasks {
+		results.Add(p.makeCard(task))
+	}
+	p.columns.Objects = []fyne.CanvasObject{results}
+	p.columns.Layout = container.NewGridWithColumns(1).Layout
+	p.columns.Refresh()
+}
@@ -0,0 +1,269 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"testing"
+	"time"
+
+	"eldar/model"
+	"eldar/store"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoardsPageSearch(t *testing.T) {
+	test.NewTempApp(t)
+	st, err := store.Open(t.TempDir(), nil)
+	require.NoError(t, err)
+	defer func() {
+		_ = st.Close()
+	}()
+
+	var queries []string
+	search := func(query string) ([]model.Task, error) {
+		queries = append(queries, query)
+		if query == "due:soon" {
+			return nil, errors.New("invalid due filter")
+		}
+		if query == "report" {
+			return []model.Task{{ID: "t1", Title: "Write report"}}, nil
+		}
+		return nil, nil
+	}
+	var opened []model.Task
+	page := NewBoardsPage(st, search, func(task model.Task) {
+		opened = append(opened, task)
+	})
+	test.WidgetRenderer(page)
+	results := func() []fyne.CanvasObject {
+		return page.columns.Objects[0].(*fyne.Container).Objects
+	}
+
+	page.Search("report")
+	require.Len(t, results(), 1)
+	card := results()[0].(*TaskCard)
+	assert.Equal(t, "Write report", card.Task.Title)
+	test.Tap(card)
+	require.Len(t, opened, 1)
+	assert.Equal(t, "t1", opened[0].ID)
+
+	// The results follow the cache
+	page.Reload()
+	assert.Equal(t, []string{"report", "report"}, queries)
+
+	page.Search("due:soon")
+	assert.Equal(t, "invalid due filter", results()[0].(*widget.Label).Text)
+
+	page.Search("nothing")
+	assert.Equal(t, "No matching tasks", results()[0].(*widget.Label).Text)
+
+	// Clearing the query shows the board again
+	page.Search("  ")
+	assert.Equal(t, "No boards yet", page.columns.Objects[0].(*widget.Label).Text)
+}
+
+func TestBoardsPageBoardActions(t *testing.T) {
+	test.NewTempApp(t)
+	archived := time.Date(2025, 6, 3, 10, 0, 0, 0, time.UTC)
+	st := openTestStore(t, []model.Board{
+		{ID: "b1", Name: "Sprint"},
+		{ID: "b2", Name: "Launch", ArchivedAt: &archived},
+	}, nil)
+	page := NewBoardsPage(st, nil, nil)
+	test.WidgetRenderer(page)
+
+	// Archived boards are left out of the board selector
+	assert.Equal(t, []string{"Sprint"}, page.boardSelect.Options)
+
+	var editedIDs, templateIDs, archivedIDs, deletedIDs []string
+	page.OnEditBoard = func(b model.Board) {
+		editedIDs = append(editedIDs, b.ID)
+	}
+	page.OnSaveTemplate = func(b model.Board) {
+		templateIDs = append(templateIDs, b.ID)
+	}
+	page.OnArchiveBoard = func(b model.Board) {
+		archivedIDs = append(archivedIDs, b.ID)
+	}
+	page.OnDeleteBoard = func(b model.Board) {
+		deletedIDs = append(deletedIDs, b.ID)
+	}
+	menu := page.boardActions()
+	require.Len(t, menu.Items, 5)
+	assert.Equal(t, "Board settings...", menu.Items[0].Label)
+	assert.Equal(t, "Save as template...", menu.Items[1].Label)
+	assert.True(t, menu.Items[2].IsSeparator)
+	assert.Equal(t, "Archive board", menu.Items[3].Label)
+	menu.Items[0].Action()
+	menu.Items[1].Action()
+	menu.Items[3].Action()
+	menu.Items[4].Action()
+	assert.Equal(t, []string{"b1"}, editedIDs)
+	assert.Equal(t, []string{"b1"}, templateIDs)
+	assert.Equal(t, []string{"b1"}, archivedIDs)
+	assert.Equal(t, []string{"b1"}, deletedIDs)
+
+	empty := NewBoardsPage(openTestStore(t, nil, nil), nil, nil)
+	assert.Nil(t, empty.boardActions())
+}
+
+func TestColumnHeader(t *testing.T) {
+	test.NewTempApp(t)
+	policies := func(header *fyne.Container) []fyne.CanvasObject {
+		return header.Objects[1].(*fyne.Container).Objects
+	}
+
+	header := columnHeader(model.Column{Name: "To do"}, 7)
+	assert.Equal(t, "To do", header.Objects[0].(*widget.Label).Text)
+	assert.Empty(t, policies(header))
+
+	column := model.Column{Name: "Doing", WIPLimit: 3, DefinitionOfDone: []string{"Reviewed"}}
+	for count, importance := range map[int]widget.Importance{
+		2: widget.MediumImportance,
+		3: widget.WarningImportance,
+		4: widget.DangerImportance,
+	} {
+		header = columnHeader(column, count)
+		require.Len(t, policies(header), 2)
+		limit := policies(header)[0].(*widget.Label)
+		assert.Equal(t, fmt.Sprintf("%d/3", count), limit.Text)
+		assert.Equal(t, importance, limit.Importance)
+		assert.IsType(t, &widget.Icon{}, policies(header)[1])
+	}
+}
+
+func TestBoardsPageSwimlanes(t *testing.T) {
+	test.NewTempApp(t)
+	st := openTestStore(t, []model.Board{{
+		ID: "b1", Name: "Sprint", Swimlanes: model.SwimlanesPriority,
+		Columns: []model.Column{{ID: "todo", Name: "To do"}, {ID: "doing", Name: "Doing"}},
+	}}, []model.Task{
+		{ID: "t1", BoardID: "b1", ColumnID: "todo", Title: "Fix login", Priority: model.PriorityHigh},
+		{ID: "t2", BoardID: "b1", ColumnID: "doing", Title: "Write docs"},
+		{ID: "t3", BoardID: "b1", ColumnID: "doing", ParentID: "t2", Title: "Outline"},
+	})
+	page := NewBoardsPage(st, nil, nil)
+	w := test.NewWindow(page)
+	defer w.Close()
+	w.Resize(fyne.NewSize(800, 1200))
+	assert.Equal(t, "Lanes by priority", page.laneSelect.Selected)
+
+	// The column headers come first, then a header and a row of cells per lane
+	require.Len(t, page.columns.Objects, 11)
+	high := page.columns.Objects[3].(*widget.Button)
+	assert.Equal(t, "High (1)", high.Text)
+	assert.Equal(t, "No priority (1)", page.columns.Objects[9].(*widget.Button).Text)
+	require.Len(t, page.cells, 10)
+
+	var moves [][2]model.Task
+	page.OnMoveTask = func(before, after model.Task) {
+		moves = append(moves, [2]model.Task{before, after})
+	}
+	center := func(cell dropCell) fyne.Position {
+		origin := fyne.CurrentApp().Driver().AbsolutePositionForObject(cell.area)
+		return origin.Add(fyne.NewPos(cell.area.Size().Width/2, cell.area.Size().Height/2))
+	}
+	card := page.cells[2].area.Objects[1].(*fyne.Container).Objects[0].(*TaskCard)
+	require.Equal(t, "t1", card.Task.ID)
+
+	// Dropping on the cell the card is in changes nothing
+	card.Dragged(&fyne.DragEvent{PointEvent: fyne.PointEvent{AbsolutePosition: center(page.cells[2])}})
+	assert.Equal(t, theme.Color(theme.ColorNameHover), page.cells[2].background.FillColor)
+	card.DragEnd()
+	assert.Empty(t, moves)
+	assert.Equal(t, color.Transparent, page.cells[2].background.FillColor)
+
+	// Dropping on another lane and column changes the priority and column
+	card.Dragged(&fyne.DragEvent{PointEvent: fyne.PointEvent{AbsolutePosition: center(page.cells[9])}})
+	card.DragEnd()
+	require.Len(t, moves, 1)
+	assert.Equal(t, "todo", moves[0][0].ColumnID)
+	assert.Equal(t, "doing", moves[0][1].ColumnID)
+	assert.Equal(t, model.PriorityNone, moves[0][1].Priority)
+
+	// Collapsed lanes only show their header
+	test.Tap(high)
+	require.Len(t, page.columns.Objects, 10)
+	assert.Equal(t, theme.MenuExpandIcon(), page.columns.Objects[3].(*widget.Button).Icon)
+	test.Tap(page.columns.Objects[3].(*widget.Button))
+	assert.Len(t, page.columns.Objects, 11)
+
+	var groupings []model.Swimlanes
+	page.OnSwimlanes = func(board model.Board, grouping model.Swimlanes) {
+		groupings = append(groupings, grouping)
+	}
+	page.laneSelect.SetSelected("Lanes by label")
+	page.laneSelect.SetSelected("No swimlanes")
+	assert.Equal(t, []model.Swimlanes{model.SwimlanesLabel, model.SwimlanesNone}, groupings)
+}
+
+func TestBoardsPageSelection(t *testing.T) {
+	test.NewTempApp(t)
+	st := openTestStore(t, []model.Board{
+		{ID: "b1", Name: "Sprint", Columns: []model.Column{{ID: "todo", Name: "To do"}, {ID: "done", Name: "Done"}},
+			Labels: []model.Label{{Name: "Bug"}}},
+		{ID: "b2", Name: "Launch", Columns: []model.Column{{ID: "next", Name: "Next"}, {ID: "shipped", Name: "done"}}},
+	}, []model.Task{
+		{ID: "t1", BoardID: "b1", ColumnID: "todo", Title: "Write report"},
+		{ID: "t2", BoardID: "b1", ColumnID: "done", Title: "Book venue"},
+		{ID: "t3", BoardID: "b2", ColumnID: "next", Title: "Print flyers", Labels: []string{"print"}},
+	})
+	search := func(query string) ([]model.Task, error) {
+		return st.AllTasks(), nil
+	}
+	var opened []string
+	page := NewBoardsPage(st, search, func(task model.Task) {
+		opened = append(opened, task.ID)
+	})
+	var edits []model.BulkEdit
+	var edited [][]string
+	page.OnBulkEdit = func(e model.BulkEdit, tasks []model.Task) {
+		edits = append(edits, e)
+		var ids []string
+		for _, task := range tasks {
+			ids = append(ids, task.ID)
+		}
+		edited = append(edited, ids)
+	}
+	test.WidgetRenderer(page)
+	page.SelectBoard("b1")
+	require.False(t, page.bulkBar.Visible())
+
+	// In selection mode tapping a card selects it rather than opening it
+	test.Tap(page.selectToggle)
+	assert.True(t, page.bulkBar.Visible())
+	assert.Equal(t, "Tap cards to select them", page.bulkBar.count.Text)
+	test.Tap(page.cards[1])
+	assert.Empty(t, opened)
+	assert.True(t, page.cards[1].Selected)
+	assert.Equal(t, "1 task selected", page.bulkBar.count.Text)
+	page.SelectAll()
+	assert.Equal(t, "2 tasks selected", page.bulkBar.count.Text)
+	page.bulkBar.edit(model.BulkEdit{Action: model.BulkArchive})
+	assert.Equal(t, [][]string{{"t1", "t2"}}, edited)
+
+	// The selection carries over to search results and is kept for the tasks still shown
+	page.Search("all")
+	require.Len(t, page.cards, 3)
+	assert.Equal(t, "2 tasks selected", page.bulkBar.count.Text)
+	test.Tap(page.cards[1])
+	assert.Equal(t, "3 tasks selected", page.bulkBar.count.Text)
+	assert.Equal(t, []string{"To do", "Done", "Next"}, page.bulkBar.columns)
+	assert.Equal(t, []string{"Bug", "print"}, page.bulkBar.labels)
+	page.Search("")
+	assert.Equal(t, "2 tasks selected", page.bulkBar.count.Text)
+
+	// Leaving selection mode clears the selection
+	test.Tap(findButton(t, test.WidgetRenderer(page.bulkBar).Objects()[0], "Done"))
+	assert.False(t, page.bulkBar.Visible())
+	assert.Empty(t, page.SelectedTasks())
+	test.Tap(page.cards[0])
+	assert.Equal(t, []string{"t1"}, opened)
+	assert.Len(t, edits, 1)
+}
@@ -0,0 +1,420 @@
+package ui
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"eldar/model"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// sprintDays is the length of the sprints proposed when adding one
+const sprintDays = 14
+
+// BoardSettingsEditor edits the column policies, label set, custom fields and sprints of a board.
+// Changes are kept in the editor until they are read back with Board.
+type BoardSettingsEditor struct {
+	widget.BaseWidget
+
+	board      model.Board
+	columns    []model.Column
+	labels     []model.Label
+	fields     []model.Field
+	sprints    []model.Sprint
+	labelRows  *fyne.Container
+	fieldRows  *fyne.Container
+	sprintRows *fyne.Container
+
+	newLabel      *widget.Entry
+	newLabelColor *widget.Select
+	newField      *widget.Entry
+	newFieldType  *widget.Select
+	newOptions    *widget.Entry
+	// fieldError explains why the new field could not be added
+	fieldError     *widget.Label
+	newSprint      *widget.Entry
+	newSprintStart *widget.DateEntry
+	newSprintEnd   *widget.DateEntry
+	// sprintError explains why the new sprint could not be added
+	sprintError *widget.Label
+}
+
+// NewBoardSettingsEditor creates an editor for a copy of the column policies, labels, custom fields and sprints of board
+func NewBoardSettingsEditor(board model.Board) *BoardSettingsEditor {
+	e := &BoardSettingsEditor{
+		board:      board,
+		labels:     slices.Clone(board.Labels),
+		sprints:    slices.Clone(board.Sprints),
+		labelRows:  container.NewVBox(),
+		fieldRows:  container.NewVBox(),
+		sprintRows: container.NewVBox(),
+	}
+	for _, column := range board.Columns {
+		column.DefinitionOfDone = slices.Clone(column.DefinitionOfDone)
+		e.columns = append(e.columns, column)
+	}
+	for _, field := range board.Fields {
+		field.Options = slices.Clone(field.Options)
+		e.fields = append(e.fields, field)
+	}
+
+	e.newLabel = widget.NewEntry()
+	e.newLabel.SetPlaceHolder("Add a label")
+	e.newLabel.OnSubmitted = func(string) {
+		e.addLabel()
+	}
+	e.newLabelColor = widget.NewSelect(labelColorNames(), nil)
+	e.newLabelColor.SetSelectedIndex(0)
+
+	types := make([]string, len(model.FieldTypes))
+	for i, t := range model.FieldTypes {
+		types[i] = t.String()
+	}
+	e.newField = widget.NewEntry()
+	e.newField.SetPlaceHolder("Add a field")
+	e.newFieldType = widget.NewSelect(types, nil)
+	e.newOptions = widget.NewEntry()
+	e.newOptions.SetPlaceHolder("Comma separated options")
+	e.newOptions.Disable()
+	e.newFieldType.OnChanged = func(string) {
+		if t := e.newFieldTypeValue(); t == model.FieldSelect || t == model.FieldMultiSelect {
+			e.newOptions.Enable()
+		} else {
+			e.newOptions.Disable()
+		}
+	}
+	e.newFieldType.SetSelectedIndex(0)
+	e.fieldError = widget.NewLabel("")
+	e.fieldError.Importance = widget.DangerImportance
+	e.fieldError.Hide()
+
+	e.newSprint = widget.NewEntry()
+	e.newSprint.SetPlaceHolder("Add a sprint or milestone")
+	e.newSprintStart = widget.NewDateEntry()
+	e.newSprintEnd = widget.NewDateEntry()
+	e.resetNewSprint(time.Now())
+	e.sprintError = widget.NewLabel("")
+	e.sprintError.Importance = widget.DangerImportance
+	e.sprintError.Hide()
+
+	e.ExtendBaseWidget(e)
+	e.rebuild()
+	return e
+}
+
+// CreateRenderer implements fyne.Widget
+func (e *BoardSettingsEditor) CreateRenderer() fyne.WidgetRenderer {
+	addLabel := widget.NewButtonWithIcon("", theme.ContentAddIcon(), e.addLabel)
+	addField := widget.NewButtonWithIcon("", theme.ContentAddIcon(), func() {
+		if err := e.addField(); err != nil {
+			e.fieldError.SetText(err.Error())
+			e.fieldError.Show()
+			return
+		}
+		e.fieldError.Hide()
+	})
+	addSprint := widget.NewButtonWithIcon("", theme.ContentAddIcon(), func() {
+		if err := e.addSprint(); err != nil {
+			e.sprintError.SetText(err.Error())
+			e.sprintError.Show()
+			return
+		}
+		e.sprintError.Hide()
+	})
+	columns := container.NewVBox()
+	for i, column := range e.columns {
+		columns.Add(e.makeColumnRow(i, column))
+	}
+	return widget.NewSimpleRenderer(container.NewVBox(
+		widget.NewLabelWithStyle("Columns", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		columns,
+		widget.NewSeparator(),
+		widget.NewLabelWithStyle("Labels", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		e.labelRows,
+		container.NewBorder(nil, nil, nil, container.NewHBox(e.newLabelColor, addLabel), e.newLabel),
+		widget.NewSeparator(),
+		widget.NewLabelWithStyle("Custom fields", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		e.fieldRows,
+		container.NewBorder(nil, nil, nil, container.NewHBox(e.newFieldType, addField), e.newField),
+		e.newOptions,
+		e.fieldError,
+		widget.NewSeparator(),
+		widget.NewLabelWithStyle("Sprints", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		e.sprintRows,
+		container.NewBorder(nil, nil, nil, container.NewHBox(e.newSprintStart, e.newSprintEnd, addSprint), e.newSprint),
+		e.sprintError,
+	))
+}
+
+// Board returns a copy of the board with the edited column policies, labels, custom fields and sprints,
+// or an error when they cannot be saved
+func (e *BoardSettingsEditor) Board() (model.Board, error) {
+	board := e.board
+	board.Columns = make([]model.Column, len(e.columns))
+	for i, column := range e.columns {
+		column.DefinitionOfDone = slices.Clone(column.DefinitionOfDone)
+		board.Columns[i] = column
+	}
+	board.Labels = slices.Clone(e.labels)
+	board.Fields = make([]model.Field, len(e.fields))
+	for i, field := range e.fields {
+		field.Name = strings.TrimSpace(field.Name)
+		field.Options = slices.Clone(field.Options)
+		board.Fields[i] = field
+	}
+	for i := range board.Labels {
+		board.Labels[i].Name = strings.TrimSpace(board.Labels[i].Name)
+	}
+	board.Sprints = slices.Clone(e.sprints)
+	for i := range board.Sprints {
+		board.Sprints[i].Name = strings.TrimSpace(board.Sprints[i].Name)
+	}
+	if err := board.Validate(); err != nil {
+		return model.Board{}, err
+	}
+	return board, nil
+}
+
+// addLabel appends the label typed in the new label row, blank names are ignored
+func (e *BoardSettingsEditor) addLabel() {
+	name := strings.TrimSpace(e.newLabel.Text)
+	if name == "" {
+		return
+	}
+	e.labels = append(e.labels, model.Label{Name: name, Color: labelColors[max(e.newLabelColor.SelectedIndex(), 0)].Hex})
+	e.newLabel.SetText("")
+	e.rebuild()
+}
+
+// addField appends the field typed in the new field row
+func (e *BoardSettingsEditor) addField() error {
+	if strings.TrimSpace(e.newField.Text) == "" {
+		return errors.New("field name must not be empty")
+	}
+	fieldType := e.newFieldTypeValue()
+	var options []string
+	if fieldType == model.FieldSelect || fieldType == model.FieldMultiSelect {
+		options = splitList(e.newOptions.Text)
+	}
+	field, err := model.NewField(e.newField.Text, fieldType, options)
+	if err != nil {
+		return err
+	}
+	if err := field.Validate(); err != nil {
+		return err
+	}
+	e.fields = append(e.fields, field)
+	e.newField.SetText("")
+	e.newOptions.SetText("")
+	e.rebuild()
+	return nil
+}
+
+// addSprint appends the sprint typed in the new sprint row, then proposes the next one to start the day after it ends
+func (e *BoardSettingsEditor) addSprint() error {
+	if e.newSprintStart.Date == nil || e.newSprintEnd.Date == nil {
+		return errors.New("sprint needs a start and an end date")
+	}
+	sprint, err := model.NewSprint(e.newSprint.Text, dueDay(*e.newSprintStart.Date), dueDay(*e.newSprintEnd.Date))
+	if err != nil {
+		return err
+	}
+	if err := sprint.Validate(); err != nil {
+		return err
+	}
+	e.sprints = append(e.sprints, sprint)
+	e.newSprint.SetText("")
+	e.resetNewSprint(time.Now())
+	e.rebuild()
+	return nil
+}
+
+// resetNewSprint proposes dates for the next sprint: two weeks from the day after the last sprint ends,
+// or from today when the board has none yet
+func (e *BoardSettingsEditor) resetNewSprint(now time.Time) {
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if n := len(e.sprints); n > 0 {
+		start = e.sprints[n-1].End.AddDate(0, 0, 1)
+	}
+	end := start.AddDate(0, 0, sprintDays-1)
+	e.newSprintStart.SetDate(&start)
+	e.newSprintEnd.SetDate(&end)
+}
+
+// newFieldTypeValue returns the type picked in the new field row
+func (e *BoardSettingsEditor) newFieldTypeValue() model.FieldType {
+	return model.FieldTypes[max(e.newFieldType.SelectedIndex(), 0)]
+}
+
+// rebuild recreates a row for every label and field
+func (e *BoardSettingsEditor) rebuild() {
+	labels := make([]fyne.CanvasObject, len(e.labels))
+	for i, l := range e.labels {
+		labels[i] = e.makeLabelRow(i, l)
+	}
+	e.labelRows.Objects = labels
+	e.labelRows.Refresh()
+
+	fields := make([]fyne.CanvasObject, len(e.fields))
+	for i, field := range e.fields {
+		fields[i] = e.makeFieldRow(i, field)
+	}
+	e.fieldRows.Objects = fields
+	e.fieldRows.Refresh()
+
+	sprints := make([]fyne.CanvasObject, len(e.sprints))
+	for i, sprint := range e.sprints {
+		sprints[i] = e.makeSprintRow(i, sprint)
+	}
+	e.sprintRows.Objects = sprints
+	e.sprintRows.Refresh()
+}
+
+// makeColumnRow renders the policies of a column: its WIP limit, whether moves over it are blocked
+// and its definition of done, one item per line
+func (e *BoardSettingsEditor) makeColumnRow(index int, column model.Column) fyne.CanvasObject {
+	limit := widget.NewEntry()
+	limit.SetPlaceHolder("No WIP limit")
+	if column.WIPLimit > 0 {
+		limit.SetText(strconv.Itoa(column.WIPLimit))
+	}
+	limit.Validator = func(s string) error {
+		_, err := parseWIPLimit(s)
+		return err
+	}
+	limit.OnChanged = func(s string) {
+		if n, err := parseWIPLimit(s); err == nil {
+			e.columns[index].WIPLimit = n
+		}
+	}
+	block := widget.NewCheck("Block moves over the limit", nil)
+	block.SetChecked(column.WIPPolicy.Blocks())
+	block.OnChanged = func(checked bool) {
+		e.columns[index].WIPPolicy = model.WIPWarn
+		if checked {
+			e.columns[index].WIPPolicy = model.WIPBlock
+		}
+	}
+	done := widget.NewMultiLineEntry()
+	done.SetPlaceHolder("Definition of done, one item per line")
+	done.SetMinRowsVisible(2)
+	done.SetText(strings.Join(column.DefinitionOfDone, "\n"))
+	done.OnChanged = func(s string) {
+		var items []string
+		for _, line := range strings.Split(s, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				items = append(items, line)
+			}
+		}
+		e.columns[index].DefinitionOfDone = items
+	}
+	name := widget.NewLabelWithStyle(column.Name, fyne.TextAlignLeading, fyne.TextStyle{Italic: true})
+	return container.NewVBox(container.NewBorder(nil, nil, name, block, limit), done)
+}
+
+// parseWIPLimit parses a WIP limit, blank meaning no limit
+func parseWIPLimit(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, errors.New("WIP limit must be a whole number")
+	}
+	return n, nil
+}
+
+// makeLabelRow renders a label as its name and colour, both editable, followed by a delete button
+func (e *BoardSettingsEditor) makeLabelRow(index int, l model.Label) fyne.CanvasObject {
+	name := widget.NewEntry()
+	name.SetText(l.Name)
+	name.OnChanged = func(s string) {
+		e.labels[index].Name = s
+	}
+	names := labelColorNames()
+	if !slices.Contains(names, labelColorName(l.Color)) {
+		// Keep colours picked by other clients
+		names = append(names, l.Color)
+	}
+	colour := widget.NewSelect(names, nil)
+	colour.SetSelected(labelColorName(l.Color))
+	colour.OnChanged = func(string) {
+		if i := colour.SelectedIndex(); i < len(labelColors) {
+			e.labels[index].Color = labelColors[i].Hex
+		}
+	}
+	remove := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+		e.labels = slices.Delete(e.labels, index, index+1)
+		e.rebuild()
+	})
+	return container.NewBorder(nil, nil, nil, container.NewHBox(colour, remove), name)
+}
+
+// makeFieldRow renders a field as its editable name, its type and, for select fields, its editable options,
+// followed by a delete button. Removing a field or an option clears it on the tasks that use it.
+func (e *BoardSettingsEditor) makeFieldRow(index int, field model.Field) fyne.CanvasObject {
+	name := widget.NewEntry()
+	name.SetText(field.Name)
+	name.OnChanged = func(s string) {
+		e.fields[index].Name = s
+	}
+	remove := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+		e.fields = slices.Delete(e.fields, index, index+1)
+		e.rebuild()
+	})
+	row := container.NewBorder(nil, nil, nil, container.NewHBox(widget.NewLabel(field.Type.String()), remove), name)
+	if field.Type != model.FieldSelect && field.Type != model.FieldMultiSelect {
+		return row
+	}
+	options := widget.NewEntry()
+	options.SetText(strings.Join(field.Options, ", "))
+	options.OnChanged = func(s string) {
+		e.fields[index].Options = splitList(s)
+	}
+	return container.NewVBox(row, options)
+}
+
+// labelColorNames returns the names of labelColors in order
+func labelColorNames() []string {
+	names := make([]string, len(labelColors))
+	for i, c := range labelColors {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// makeSprintRow renders a sprint as its editable name and dates, followed by a delete button.
+// Tasks planned into a deleted sprint are left unplanned.
+func (e *BoardSettingsEditor) makeSprintRow(index int, sprint model.Sprint) fyne.CanvasObject {
+	name := widget.NewEntry()
+	name.SetText(sprint.Name)
+	name.OnChanged = func(s string) {
+		e.sprints[index].Name = s
+	}
+	start := widget.NewDateEntry()
+	start.SetDate(&sprint.Start)
+	start.OnChanged = func(day *time.Time) {
+		if day != nil {
+			e.sprints[index].Start = dueDay(*day)
+		}
+	}
+	end := widget.NewDateEntry()
+	end.SetDate(&sprint.End)
+	end.OnChanged = func(day *time.Time) {
+		if day != nil {
+			e.sprints[index].End = dueDay(*day)
+		}
+	}
+	remove := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+		e.sprints = slices.Delete(e.sprints, index, index+1)
+		e.rebuild()
+	})
+	return container.NewBorder(nil, nil, nil, container.NewHBox(start, end, remove), name)
+}
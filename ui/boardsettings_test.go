@@ -0,0 +1,160 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"eldar/model"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoardSettingsEditor(t *testing.T) {
+	test.NewTempApp(t)
+	board := model.Board{
+		ID:      "b1",
+		Name:    "Sprint",
+		Columns: []model.Column{{ID: "todo", Name: "To do"}, {ID: "done", Name: "Done", WIPLimit: 5, DefinitionOfDone: []string{"Reviewed"}}},
+		Labels:  []model.Label{{Name: "Bug", Color: "#e53935"}, {Name: "Odd", Color: "#123456"}},
+		Fields:  []model.Field{{ID: "f1", Name: "Size", Type: model.FieldSelect, Options: []string{"S", "M"}}},
+	}
+	e := NewBoardSettingsEditor(board)
+	test.WidgetRenderer(e)
+	require.Len(t, e.labelRows.Objects, 2)
+
+	// Colours picked by other clients are kept
+	colour := e.labelRows.Objects[1].(*fyne.Container).Objects[1].(*fyne.Container).Objects[0].(*widget.Select)
+	assert.Equal(t, "#123456", colour.Selected)
+
+	e.newLabel.SetText("Docs")
+	e.newLabelColor.SetSelected("Blue")
+	e.addLabel()
+	e.newLabel.SetText(" ")
+	e.addLabel()
+	assert.Len(t, e.labels, 3)
+
+	e.newField.SetText("Platforms")
+	e.newFieldType.SetSelected("Multi select")
+	assert.False(t, e.newOptions.Disabled())
+	require.Error(t, e.addField())
+	e.newOptions.SetText("iOS, Web")
+	require.NoError(t, e.addField())
+	e.newField.SetText("Estimate")
+	e.newFieldType.SetSelected("Number")
+	assert.True(t, e.newOptions.Disabled())
+	require.NoError(t, e.addField())
+
+	// Existing fields keep their ID while their options are edited
+	options := e.fieldRows.Objects[0].(*fyne.Container).Objects[1].(*widget.Entry)
+	options.SetText("S, M, L")
+
+	edited, err := e.Board()
+	require.NoError(t, err)
+	assert.Equal(t, "Sprint", edited.Name)
+	assert.Equal(t, board.Columns, edited.Columns)
+
+	// Column policies
+	limit, block, done := columnRow(t, e, 0)
+	assert.Empty(t, limit.Text)
+	assert.False(t, block.Checked)
+	limit.SetText("3")
+	block.SetChecked(true)
+	done.SetText("Tests pass\n\n Docs updated ")
+	limit, _, done = columnRow(t, e, 1)
+	assert.Equal(t, "5", limit.Text)
+	assert.Equal(t, "Reviewed", done.Text)
+	assert.Error(t, limit.Validator("many"))
+	limit.SetText("many")
+	limit.SetText("")
+	edited, err = e.Board()
+	require.NoError(t, err)
+	assert.Equal(t, []model.Column{
+		{ID: "todo", Name: "To do", WIPLimit: 3, WIPPolicy: model.WIPBlock, DefinitionOfDone: []string{"Tests pass", "Docs updated"}},
+		{ID: "done", Name: "Done", DefinitionOfDone: []string{"Reviewed"}},
+	}, edited.Columns)
+	assert.Equal(t, 5, board.Columns[1].WIPLimit)
+	assert.Equal(t, []model.Label{
+		{Name: "Bug", Color: "#e53935"},
+		{Name: "Odd", Color: "#123456"},
+		{Name: "Docs", Color: "#1e88e5"},
+	}, edited.Labels)
+	require.Len(t, edited.Fields, 3)
+	assert.Equal(t, model.Field{ID: "f1", Name: "Size", Type: model.FieldSelect, Options: []string{"S", "M", "L"}}, edited.Fields[0])
+	assert.Equal(t, "Platforms", edited.Fields[1].Name)
+	assert.Equal(t, []string{"iOS", "Web"}, edited.Fields[1].Options)
+	assert.Equal(t, model.FieldNumber, edited.Fields[2].Type)
+	assert.NotEmpty(t, edited.Fields[2].ID)
+	// The board passed in is left untouched
+	assert.Equal(t, []string{"S", "M"}, board.Fields[0].Options)
+
+	// Duplicate names are rejected
+	e.newLabel.SetText("bug")
+	e.addLabel()
+	_, err = e.Board()
+	assert.Error(t, err)
+}
+
+// columnRow returns the WIP limit, blocking policy and definition of done inputs of a column of the editor
+func columnRow(t *testing.T, e *BoardSettingsEditor, index int) (*widget.Entry, *widget.Check, *widget.Entry) {
+	content := test.WidgetRenderer(e).Objects()[0].(*fyne.Container)
+	row := content.Objects[1].(*fyne.Container).Objects[index].(*fyne.Container)
+	policies := row.Objects[0].(*fyne.Container)
+	return policies.Objects[0].(*widget.Entry), policies.Objects[2].(*widget.Check), row.Objects[1].(*widget.Entry)
+}
+
+func TestBoardSettingsEditorSprints(t *testing.T) {
+	test.NewTempApp(t)
+	day := func(d int) time.Time { return time.Date(2025, 6, d, 0, 0, 0, 0, time.UTC) }
+	board := model.Board{
+		ID:      "b1",
+		Name:    "Sprint",
+		Columns: []model.Column{{ID: "todo", Name: "To do"}},
+		Sprints: []model.Sprint{{ID: "s1", Name: "Sprint 1", Start: day(2), End: day(13)}},
+	}
+	e := NewBoardSettingsEditor(board)
+	test.WidgetRenderer(e)
+	require.Len(t, e.sprintRows.Objects, 1)
+
+	// The next sprint is proposed to start the day after the last one ends
+	assert.Equal(t, day(14), *e.newSprintStart.Date)
+	assert.Equal(t, day(27), *e.newSprintEnd.Date)
+
+	assert.Error(t, e.addSprint())
+	e.newSprint.SetText("Sprint 2")
+	require.NoError(t, e.addSprint())
+	assert.Empty(t, e.newSprint.Text)
+	assert.Equal(t, day(28), *e.newSprintStart.Date)
+	e.newSprint.SetText("Broken")
+	e.newSprintEnd.SetDate(&board.Sprints[0].Start)
+	assert.Error(t, e.addSprint())
+	require.Len(t, e.sprintRows.Objects, 2)
+
+	name := e.sprintRows.Objects[0].(*fyne.Container).Objects[0].(*widget.Entry)
+	name.SetText(" Kick-off ")
+	edited, err := e.Board()
+	require.NoError(t, err)
+	require.Len(t, edited.Sprints, 2)
+	assert.Equal(t, model.Sprint{ID: "s1", Name: "Kick-off", Start: day(2), End: day(13)}, edited.Sprints[0])
+	assert.Equal(t, "Sprint 2", edited.Sprints[1].Name)
+	assert.Equal(t, day(14), edited.Sprints[1].Start)
+	assert.Equal(t, "Sprint 1", board.Sprints[0].Name)
+
+	// Deleting a sprint removes its row
+	remove := e.sprintRows.Objects[0].(*fyne.Container).Objects[1].(*fyne.Container).Objects[2].(*widget.Button)
+	test.Tap(remove)
+	edited, err = e.Board()
+	require.NoError(t, err)
+	require.Len(t, edited.Sprints, 1)
+	assert.Equal(t, "Sprint 2", edited.Sprints[0].Name)
+
+	// Dates picked from the calendar carry a local time of day, sprints keep whole days
+	picked := time.Date(2025, 6, 20, 15, 4, 0, 0, time.Local)
+	end := e.sprintRows.Objects[0].(*fyne.Container).Objects[1].(*fyne.Container).Objects[1].(*widget.DateEntry)
+	end.SetDate(&picked)
+	edited, err = e.Board()
+	require.NoError(t, err)
+	assert.Equal(t, day(20), edited.Sprints[0].End)
+}
@@ -0,0 +1,210 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"eldar/model"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// BulkBar holds the actions applied to every selected task at once: moving them to a column, assigning
+// a member, adding a label, setting or clearing the due date, archiving and deleting them
+type BulkBar struct {
+	widget.BaseWidget
+	// OnEdit is called with the change to make to the selected tasks
+	OnEdit func(model.BulkEdit)
+	// OnSelectAll is called to select every task shown
+	OnSelectAll func()
+	// OnDone is called to leave selection mode
+	OnDone func()
+
+	selected int
+	columns  []string
+	labels   []string
+	members  []model.Member
+
+	count   *widget.Label
+	actions []*widget.Button
+}
+
+// NewBulkBar creates an action bar with nothing selected
+func NewBulkBar() *BulkBar {
+	b := &BulkBar{count: widget.NewLabel("")}
+	b.ExtendBaseWidget(b)
+	b.SetSelection(0, nil, nil, nil)
+	return b
+}
+
+// CreateRenderer implements fyne.Widget
+func (b *BulkBar) CreateRenderer() fyne.WidgetRenderer {
+	var move, assign, label, due *widget.Button
+	move = widget.NewButtonWithIcon("Move to", theme.NavigateNextIcon(), func() {
+		showMenuBelow(b.moveMenu(), move)
+	})
+	assign = widget.NewButtonWithIcon("Assign", theme.AccountIcon(), func() {
+		showMenuBelow(b.assignMenu(), assign)
+	})
+	label = widget.NewButtonWithIcon("Label", theme.ListIcon(), func() {
+		showMenuBelow(b.labelMenu(), label)
+	})
+	due = widget.NewButtonWithIcon("Due date", theme.CalendarIcon(), func() {
+		showMenuBelow(b.dueMenu(), due)
+	})
+	archive := widget.NewButtonWithIcon("Archive", theme.DownloadIcon(), func() {
+		b.edit(model.BulkEdit{Action: model.BulkArchive})
+	})
+	remove := widget.NewButtonWithIcon("Delete", theme.DeleteIcon(), func() {
+		b.edit(model.BulkEdit{Action: model.BulkDelete})
+	})
+	remove.Importance = widget.DangerImportance
+	b.actions = []*widget.Button{move, assign, label, due, archive, remove}
+	b.updateActions()
+
+	all := widget.NewButton("Select all", func() {
+		if b.OnSelectAll != nil {
+			b.OnSelectAll()
+		}
+	})
+	done := widget.NewButtonWithIcon("Done", theme.CancelIcon(), func() {
+		if b.OnDone != nil {
+			b.OnDone()
+		}
+	})
+	actions := container.NewHBox(move, assign, label, due, archive, remove)
+	return widget.NewSimpleRenderer(container.NewBorder(nil, nil, b.count, container.NewHBox(all, done), container.NewHScroll(actions)))
+}
+
+// SetSelection updates the bar for count selected tasks, offering the columns and labels of their boards
+// and the members of the group
+func (b *BulkBar) SetSelection(count int, columns, labels []string, members []model.Member) {
+	b.selected, b.columns, b.labels, b.members = count, columns, labels, members
+	switch count {
+	case 0:
+		b.count.SetText("Tap cards to select them")
+	case 1:
+		b.count.SetText("1 task selected")
+	default:
+		b.count.SetText(fmt.Sprintf("%d tasks selected", count))
+	}
+	b.updateActions()
+}
+
+// updateActions enables the actions while tasks are selected
+func (b *BulkBar) updateActions() {
+	for _, action := range b.actions {
+		if b.selected == 0 {
+			action.Disable()
+		} else {
+			action.Enable()
+		}
+	}
+}
+
+// edit calls OnEdit with a change
+func (b *BulkBar) edit(e model.BulkEdit) {
+	if b.OnEdit != nil {
+		b.OnEdit(e)
+	}
+}
+
+// moveMenu lists the columns the selected tasks can be moved to
+func (b *BulkBar) moveMenu() *fyne.Menu {
+	items := make([]*fyne.MenuItem, len(b.columns))
+	for i, column := range b.columns {
+		items[i] = fyne.NewMenuItem(column, func() {
+			b.edit(model.BulkEdit{Action: model.BulkMove, Column: column})
+		})
+	}
+	return fyne.NewMenu("", items...)
+}
+
+// assignMenu lists the members the selected tasks can be assigned to
+func (b *BulkBar) assignMenu() *fyne.Menu {
+	items := make([]*fyne.MenuItem, len(b.members))
+	for i, m := range b.members {
+		name := m.Name
+		if name == "" {
+			name = m.Username
+		}
+		items[i] = fyne.NewMenuItem(name, func() {
+			b.edit(model.BulkEdit{Action: model.BulkAssign, Assignee: m.Username})
+		})
+	}
+	return fyne.NewMenu("", items...)
+}
+
+// labelMenu lists the labels that can be added to the selected tasks
+func (b *BulkBar) labelMenu() *fyne.Menu {
+	items := make([]*fyne.MenuItem, len(b.labels))
+	for i, label := range b.labels {
+		items[i] = fyne.NewMenuItem(label, func() {
+			b.edit(model.BulkEdit{Action: model.BulkLabel, Label: label})
+		})
+	}
+	return fyne.NewMenu("", items...)
+}
+
+// dueMenu offers due dates relative to today, a date picker and clearing the due date
+func (b *BulkBar) dueMenu() *fyne.Menu {
+	setDue := func(day time.Time) {
+		day = dueDay(day)
+		b.edit(model.BulkEdit{Action: model.BulkDue, DueDate: &day})
+	}
+	now := time.Now()
+	return fyne.NewMenu("",
+		fyne.NewMenuItem("Today", func() {
+			setDue(now)
+		}),
+		fyne.NewMenuItem("Tomorrow", func() {
+			setDue(now.AddDate(0, 0, 1))
+		}),
+		fyne.NewMenuItem("In a week", func() {
+			setDue(now.AddDate(0, 0, 7))
+		}),
+		fyne.NewMenuItem("Pick a date...", func() {
+			b.pickDate(setDue)
+		}),
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem("Clear due date", func() {
+			b.edit(model.BulkEdit{Action: model.BulkDue})
+		}),
+	)
+}
+
+// pickDate shows a calendar over the window, calling onPicked with the day picked
+func (b *BulkBar) pickDate(onPicked func(time.Time)) {
+	c := fyne.CurrentApp().Driver().CanvasForObject(b)
+	if c == nil {
+		return
+	}
+	var popUp *widget.PopUp
+	calendar := widget.NewCalendar(time.Now(), func(day time.Time) {
+		popUp.Hide()
+		onPicked(day)
+	})
+	cancel := widget.NewButton("Cancel", func() {
+		popUp.Hide()
+	})
+	popUp = widget.NewModalPopUp(container.NewBorder(nil, cancel, nil, nil, calendar), c)
+	popUp.Show()
+}
+
+// dueDay returns the day of t in its location as due dates are stored, at midnight UTC like the dates typed
+// into widget.DateEntry
+func dueDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// showMenuBelow pops up a menu below a button, unless the menu is empty
+func showMenuBelow(menu *fyne.Menu, button *widget.Button) {
+	c := fyne.CurrentApp().Driver().CanvasForObject(button)
+	if len(menu.Items) == 0 || c == nil {
+		return
+	}
+	position := fyne.CurrentApp().Driver().AbsolutePositionForObject(button).AddXY(0, button.Size().Height)
+	widget.ShowPopUpMenuAtPosition(menu, c, position)
+}
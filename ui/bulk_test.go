@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"eldar/model"
+	"fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkBar(t *testing.T) {
+	test.NewTempApp(t)
+	bar := NewBulkBar()
+	var edits []model.BulkEdit
+	bar.OnEdit = func(e model.BulkEdit) {
+		edits = append(edits, e)
+	}
+	test.WidgetRenderer(bar)
+	archive, remove := bar.actions[4], bar.actions[5]
+	assert.Equal(t, "Archive", archive.Text)
+	assert.True(t, archive.Disabled())
+
+	bar.SetSelection(2, []string{"To do", "Done"}, []string{"Bug"}, []model.Member{{Username: "ana@example.com", Name: "Ana Lima"}, {Username: "ben@example.com"}})
+	assert.Equal(t, "2 tasks selected", bar.count.Text)
+	assert.False(t, archive.Disabled())
+	test.Tap(archive)
+	test.Tap(remove)
+
+	move := bar.moveMenu()
+	require.Len(t, move.Items, 2)
+	move.Items[1].Action()
+	assign := bar.assignMenu()
+	assert.Equal(t, "Ana Lima", assign.Items[0].Label)
+	assert.Equal(t, "ben@example.com", assign.Items[1].Label)
+	assign.Items[1].Action()
+	bar.labelMenu().Items[0].Action()
+	assert.Equal(t, []model.BulkEdit{
+		{Action: model.BulkArchive},
+		{Action: model.BulkDelete},
+		{Action: model.BulkMove, Column: "Done"},
+		{Action: model.BulkAssign, Assignee: "ben@example.com"},
+		{Action: model.BulkLabel, Label: "Bug"},
+	}, edits)
+
+	// Due dates are whole days, stored like those of the date entry of the task detail
+	edits = nil
+	due := bar.dueMenu()
+	due.Items[1].Action()
+	due.Items[len(due.Items)-1].Action()
+	require.Len(t, edits, 2)
+	tomorrow := time.Now().AddDate(0, 0, 1)
+	assert.Equal(t, time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 0, 0, 0, 0, time.UTC), *edits[0].DueDate)
+	assert.Equal(t, model.BulkEdit{Action: model.BulkDue}, edits[1])
+}
@@ -0,0 +1,241 @@
+package ui
+
+import (
+	"image/color"
+
+	"eldar/model"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// BurnMode selects the chart drawn for the progress of a sprint
+type BurnMode int
+
+const (
+	BurnDown BurnMode = iota // The work left, burning down to nothing
+	BurnUp                   // The work completed, climbing up to the scope
+)
+
+// String returns the string representation of a BurnMode
+func (m BurnMode) String() string {
+	if m == BurnUp {
+		return "Burnup"
+	}
+	return "Burndown"
+}
+
+// burnDotRadius is the radius of the dots marking the end of each day on the chart
+const burnDotRadius = 3
+
+// BurnChart draws the burndown or burnup chart of a sprint: the actual progress of each day of the sprint
+// so far against the ideal steady pace over the whole sprint
+type BurnChart struct {
+	widget.BaseWidget
+	burn model.Burn
+	mode BurnMode
+}
+
+// NewBurnChart creates an empty burndown chart
+func NewBurnChart() *BurnChart {
+	c := &BurnChart{}
+	c.ExtendBaseWidget(c)
+	return c
+}
+
+// SetBurn sets the progress charted
+func (c *BurnChart) SetBurn(burn model.Burn) {
+	c.burn = burn
+	c.Refresh()
+}
+
+// SetMode switches between the burndown and burnup chart
+func (c *BurnChart) SetMode(mode BurnMode) {
+	c.mode = mode
+	c.Refresh()
+}
+
+// Mode returns the chart drawn
+func (c *BurnChart) Mode() BurnMode {
+	return c.mode
+}
+
+// CreateRenderer implements fyne.Widget
+func (c *BurnChart) CreateRenderer() fyne.WidgetRenderer {
+	r := &burnChartRenderer{
+		chart: c,
+		xAxis: canvas.NewLine(color.Transparent),
+		yAxis: canvas.NewLine(color.Transparent),
+		top:   canvas.NewText("", color.Transparent),
+		zero:  canvas.NewText("0", color.Transparent),
+		start: canvas.NewText("", color.Transparent),
+		end:   canvas.NewText("", color.Transparent),
+		empty: canvas.NewText("Nothing to chart until the sprint starts", color.Transparent),
+	}
+	r.top.Alignment, r.zero.Alignment = fyne.TextAlignTrailing, fyne.TextAlignTrailing
+	r.end.Alignment = fyne.TextAlignTrailing
+	r.empty.Alignment = fyne.TextAlignCenter
+	r.Refresh()
+	return r
+}
+
+// burnSeries is a line of the chart, its values being the value of each day of the sprint
+type burnSeries struct {
+	values []float64
+	lines  []*canvas.Line
+	// dots mark the values of the days that are over, they are left out of the ideal line
+	dots []*canvas.Circle
+}
+
+// burnChartRenderer draws the chart from lines, dots and text, scaled to its size when laid out
+type burnChartRenderer struct {
+	chart        *BurnChart
+	xAxis, yAxis *canvas.Line
+	// top and zero label the value axis, start and end the first and last day of the sprint
+	top, zero, start, end *canvas.Text
+	empty                 *canvas.Text
+	series                []burnSeries
+	// days is the number of days of the sprint and most the highest value charted
+	days    int
+	most    float64
+	objects []fyne.CanvasObject
+}
+
+// Destroy implements fyne.WidgetRenderer
+func (r *burnChartRenderer) Destroy() {}
+
+// Objects implements fyne.WidgetRenderer
+func (r *burnChartRenderer) Objects() []fyne.CanvasObject {
+	return r.objects
+}
+
+// MinSize implements fyne.WidgetRenderer
+func (r *burnChartRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(240, 160)
+}
+
+// Refresh implements fyne.WidgetRenderer, rebuilding the lines of the chart from its progress
+func (r *burnChartRenderer) Refresh() {
+	burn := r.chart.burn
+	r.days = burn.Sprint.Days()
+	ideal := make([]float64, r.days)
+	for i := range ideal {
+		ideal[i] = burn.Ideal(i)
+	}
+	actual := make([]float64, len(burn.Days))
+	var scope []float64
+	switch r.chart.mode {
+	case BurnUp:
+		scope = make([]float64, len(burn.Days))
+		for i, d := range burn.Days {
+			actual[i], scope[i] = d.Completed, d.Scope
+		}
+		for i, left := range ideal {
+			ideal[i] = burn.Ideal(0) - left
+		}
+	default:
+		for i, d := range burn.Days {
+			actual[i] = d.Remaining()
+		}
+	}
+
+	foreground := theme.Color(theme.ColorNameForeground)
+	r.series = []burnSeries{newBurnSeries(ideal, theme.Color(theme.ColorNameDisabled), 1, false)}
+	if scope != nil {
+		r.series = append(r.series, newBurnSeries(scope, foreground, 2, true))
+	}
+	actualColor := theme.Color(theme.ColorNamePrimary)
+	if r.chart.mode == BurnUp {
+		actualColor = theme.Color(theme.ColorNameSuccess)
+	}
+	r.series = append(r.series, newBurnSeries(actual, actualColor, 2, true))
+
+	r.most = 1
+	for _, s := range r.series {
+		for _, v := range s.values {
+			r.most = max(r.most, v)
+		}
+	}
+
+	r.xAxis.StrokeColor, r.yAxis.StrokeColor = foreground, foreground
+	r.xAxis.StrokeWidth, r.yAxis.StrokeWidth = 1, 1
+	r.top.Text = formatEstimate(r.most)
+	r.start.Text = burn.Sprint.Start.Format("2 Jan")
+	r.end.Text = burn.Sprint.End.Format("2 Jan")
+	for _, t := range []*canvas.Text{r.top, r.zero, r.start, r.end, r.empty} {
+		t.Color = foreground
+		t.TextSize = theme.CaptionTextSize()
+	}
+
+	r.objects = []fyne.CanvasObject{r.xAxis, r.yAxis, r.top, r.zero, r.start, r.end}
+	for _, s := range r.series {
+		for _, l := range s.lines {
+			r.objects = append(r.objects, l)
+		}
+		for _, d := range s.dots {
+			r.objects = append(r.objects, d)
+		}
+	}
+	if len(burn.Days) == 0 {
+		r.objects = append(r.objects, r.empty)
+	}
+	r.Layout(r.chart.Size())
+	canvas.Refresh(r.chart)
+}
+
+// Layout implements fyne.WidgetRenderer, scaling the days of the sprint to the width of the chart
+// and the values to its height
+func (r *burnChartRenderer) Layout(size fyne.Size) {
+	pad := theme.Padding()
+	label := fyne.MeasureText(r.top.Text, r.top.TextSize, r.top.TextStyle)
+	left, right := label.Width+2*pad, size.Width-pad
+	top, bottom := label.Height/2+pad, size.Height-label.Height-2*pad
+	point := func(day int, v float64) fyne.Position {
+		x := left
+		if r.days > 1 {
+			x += (right - left) * float32(day) / float32(r.days-1)
+		}
+		return fyne.NewPos(x, bottom-(bottom-top)*float32(v/r.most))
+	}
+
+	r.xAxis.Position1, r.xAxis.Position2 = fyne.NewPos(left, bottom), fyne.NewPos(right, bottom)
+	r.yAxis.Position1, r.yAxis.Position2 = fyne.NewPos(left, top), fyne.NewPos(left, bottom)
+	r.top.Move(fyne.NewPos(0, top-label.Height/2))
+	r.top.Resize(fyne.NewSize(left-pad, label.Height))
+	r.zero.Move(fyne.NewPos(0, bottom-label.Height/2))
+	r.zero.Resize(fyne.NewSize(left-pad, label.Height))
+	r.start.Move(fyne.NewPos(left, bottom+pad))
+	r.start.Resize(fyne.NewSize((right-left)/2, label.Height))
+	r.end.Move(fyne.NewPos(left+(right-left)/2, bottom+pad))
+	r.end.Resize(fyne.NewSize((right-left)/2, label.Height))
+	r.empty.Move(fyne.NewPos(left, top))
+	r.empty.Resize(fyne.NewSize(right-left, bottom-top))
+
+	for _, s := range r.series {
+		for i, l := range s.lines {
+			l.Position1, l.Position2 = point(i, s.values[i]), point(i+1, s.values[i+1])
+		}
+		for i, d := range s.dots {
+			centre := point(i, s.values[i])
+			d.Move(centre.SubtractXY(burnDotRadius, burnDotRadius))
+			d.Resize(fyne.NewSquareSize(2 * burnDotRadius))
+		}
+	}
+}
+
+// newBurnSeries creates the lines joining the values of a series, and its dots when dotted
+func newBurnSeries(values []float64, c color.Color, width float32, dotted bool) burnSeries {
+	s := burnSeries{values: values}
+	for i := 1; i < len(values); i++ {
+		l := canvas.NewLine(c)
+		l.StrokeWidth = width
+		s.lines = append(s.lines, l)
+	}
+	if dotted {
+		for range values {
+			s.dots = append(s.dots, canvas.NewCircle(c))
+		}
+	}
+	return s
+}
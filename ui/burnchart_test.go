@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"eldar/model"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBurnChart(t *testing.T) {
+	test.NewTempApp(t)
+	day := func(d int) time.Time { return time.Date(2025, 6, d, 0, 0, 0, 0, time.UTC) }
+	burn := model.Burn{
+		Sprint: model.Sprint{ID: "s1", Name: "Sprint 1", Start: day(2), End: day(6)},
+		Unit:   model.BurnPoints,
+		Days: []model.BurnDay{
+			{Day: day(2), Scope: 8},
+			{Day: day(3), Scope: 10, Completed: 3},
+			{Day: day(4), Scope: 10, Completed: 5},
+		},
+	}
+	c := NewBurnChart()
+	c.SetBurn(burn)
+	c.Resize(fyne.NewSize(400, 200))
+	r := test.WidgetRenderer(c).(*burnChartRenderer)
+
+	require.Len(t, r.series, 2)
+	ideal, remaining := r.series[0], r.series[1]
+	assert.Equal(t, []float64{8, 6, 4, 2, 0}, ideal.values)
+	assert.Len(t, ideal.lines, 4)
+	assert.Empty(t, ideal.dots)
+	assert.Equal(t, []float64{8, 7, 5}, remaining.values)
+	assert.Len(t, remaining.dots, 3)
+	assert.Equal(t, "8", r.top.Text)
+	assert.Equal(t, "2 Jun", r.start.Text)
+	assert.Equal(t, "6 Jun", r.end.Text)
+
+	// The ideal line runs from the top of the value axis to the end of the day axis
+	assert.Equal(t, r.yAxis.Position1, ideal.lines[0].Position1)
+	assert.Equal(t, r.xAxis.Position2, ideal.lines[3].Position2)
+	assert.Less(t, remaining.lines[1].Position2.X, r.xAxis.Position2.X)
+
+	c.SetMode(BurnUp)
+	require.Len(t, r.series, 3)
+	assert.Equal(t, []float64{0, 2, 4, 6, 8}, r.series[0].values)
+	assert.Equal(t, []float64{8, 10, 10}, r.series[1].values)
+	assert.Equal(t, []float64{0, 3, 5}, r.series[2].values)
+	assert.Equal(t, "10", r.top.Text)
+	assert.NotContains(t, r.Objects(), r.empty)
+
+	// A sprint yet to start has no progress to chart
+	c.SetBurn(model.Burn{Sprint: burn.Sprint, Unit: model.BurnTasks})
+	assert.Contains(t, r.Objects(), r.empty)
+	assert.Equal(t, "1", r.top.Text)
+}
@@ -0,0 +1,342 @@
+package ui
+
+import (
+	"image/color"
+	"sort"
+	"time"
+
+	"eldar/model"
+	"eldar/store"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// CalendarMode selects how much time the calendar page shows at once
+type CalendarMode int
+
+const (
+	CalendarMonth CalendarMode = iota // The weeks of a month
+	CalendarWeek                      // A single week
+)
+
+// String returns the string representation of a CalendarMode
+func (m CalendarMode) String() string {
+	if m == CalendarWeek {
+		return "Week"
+	}
+	return "Month"
+}
+
+// boardPalette colours the tasks of each board on the calendar, in the order of the boards
+var boardPalette = []color.NRGBA{
+	{R: 0x42, G: 0x85, B: 0xf4, A: 0xff},
+	{R: 0x34, G: 0xa8, B: 0x53, A: 0xff},
+	{R: 0xfb, G: 0xbc, B: 0x05, A: 0xff},
+	{R: 0xea, G: 0x43, B: 0x35, A: 0xff},
+	{R: 0xab, G: 0x47, B: 0xbc, A: 0xff},
+	{R: 0x00, G: 0xac, B: 0xc1, A: 0xff},
+	{R: 0xff, G: 0x70, B: 0x43, A: 0xff},
+	{R: 0x9e, G: 0x9d, B: 0x24, A: 0xff},
+}
+
+// boardColor returns the calendar colour of the board at index in the list of boards
+func boardColor(index int) color.NRGBA {
+	return boardPalette[index%len(boardPalette)]
+}
+
+// CalendarPage shows the tasks with a due date on a month or week calendar, coloured by board.
+// Dragging a task to another day reschedules it. Like the Boards page it reads from the local
+// task cache, so it works offline.
+type CalendarPage struct {
+	widget.BaseWidget
+	store      *store.Store
+	openTask   func(model.Task)
+	reschedule func(model.Task, time.Time)
+
+	mode   CalendarMode
+	anchor time.Time
+
+	title      *widget.Label
+	modeSelect *widget.Select
+	legend     *fyne.Container
+	grid       *fyne.Container
+	days       []calendarDay
+}
+
+// calendarDay is a cell of the calendar grid
+type calendarDay struct {
+	date time.Time
+	box  *fyne.Container
+}
+
+// NewCalendarPage creates the Calendar page showing the current month.
+//
+// Parameters:
+//   - st: The task cache the page is rendered from
+//   - openTask: A function to call when a task is tapped
+//   - reschedule: A function to call with a task and its new due date when it is dragged to another day
+//
+// Returns:
+//   - A CalendarPage, which must be reloaded with Reload after the cache changes
+func NewCalendarPage(st *store.Store, openTask func(model.Task), reschedule func(model.Task, time.Time)) *CalendarPage {
+	p := &CalendarPage{store: st, openTask: openTask, reschedule: reschedule, anchor: time.Now()}
+	p.title = widget.NewLabelWithStyle("", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	p.legend = container.NewHBox()
+	p.grid = container.NewGridWithColumns(7)
+	p.modeSelect = widget.NewSelect([]string{CalendarMonth.String(), CalendarWeek.String()}, func(s string) {
+		if s == CalendarWeek.String() {
+			p.SetMode(CalendarWeek)
+		} else {
+			p.SetMode(CalendarMonth)
+		}
+	})
+	p.modeSelect.SetSelected(CalendarMonth.String())
+	p.ExtendBaseWidget(p)
+	return p
+}
+
+// CreateRenderer implements fyne.Widget
+func (p *CalendarPage) CreateRenderer() fyne.WidgetRenderer {
+	navigation := container.NewHBox(
+		widget.NewButtonWithIcon("", theme.NavigateBackIcon(), p.Previous),
+		widget.NewButton("Today", func() {
+			p.SetDate(time.Now())
+		}),
+		widget.NewButtonWithIcon("", theme.NavigateNextIcon(), p.Next),
+	)
+	header := container.NewBorder(nil, nil, navigation, p.modeSelect, p.title)
+	return widget.NewSimpleRenderer(container.NewBorder(container.NewVBox(header, p.legend), nil, nil, nil, container.NewVScroll(p.grid)))
+}
+
+// SetMode switches between the month and week calendar
+func (p *CalendarPage) SetMode(mode CalendarMode) {
+	p.mode = mode
+	if p.modeSelect.Selected != mode.String() {
+		// SetSelected calls back into SetMode, which then reloads
+		p.modeSelect.SetSelected(mode.String())
+		return
+	}
+	p.Reload()
+}
+
+// SetDate shows the month or week containing date
+func (p *CalendarPage) SetDate(date time.Time) {
+	p.anchor = date
+	p.Reload()
+}
+
+// Previous shows the previous month or week
+func (p *CalendarPage) Previous() {
+	p.step(-1)
+}
+
+// Next shows the next month or week
+func (p *CalendarPage) Next() {
+	p.step(1)
+}
+
+// step moves the calendar by n months or weeks
+func (p *CalendarPage) step(n int) {
+	y, m, d := p.anchor.Date()
+	if p.mode == CalendarWeek {
+		p.SetDate(time.Date(y, m, d+7*n, 0, 0, 0, 0, p.anchor.Location()))
+		return
+	}
+	// Move from the first of the month, so 31 January does not skip February
+	p.SetDate(time.Date(y, m+time.Month(n), 1, 0, 0, 0, 0, p.anchor.Location()))
+}
+
+// Reload rebuilds the calendar from the task cache
+func (p *CalendarPage) Reload() {
+	start, count := calendarRange(p.mode, p.anchor)
+	end := start.AddDate(0, 0, count)
+	p.title.SetText(calendarTitle(p.mode, start, p.anchor))
+
+	boards := p.store.Boards()
+	colors := make(map[string]color.NRGBA, len(boards))
+	legend := make([]fyne.CanvasObject, 0, len(boards))
+	for i, b := range boards {
+		colors[b.ID] = boardColor(i)
+		swatch := canvas.NewRectangle(colors[b.ID])
+		swatch.SetMinSize(fyne.NewSquareSize(theme.Padding() * 3))
+		legend = append(legend, container.NewCenter(swatch), widget.NewLabel(b.Name))
+	}
+	p.legend.Objects = legend
+	p.legend.Refresh()
+
+	byDay := tasksByDay(p.store.AllTasks(), start, end)
+	objects := make([]fyne.CanvasObject, 0, 7+count)
+	for i := 0; i < 7; i++ {
+		objects = append(objects, widget.NewLabelWithStyle(start.AddDate(0, 0, i).Format("Mon"), fyne.TextAlignCenter, fyne.TextStyle{Bold: true}))
+	}
+	p.days = make([]calendarDay, count)
+	for i := range p.days {
+		date := start.AddDate(0, 0, i)
+		dayLabel := widget.NewLabel(date.Format("2"))
+		if date.Month() != p.anchor.Month() && p.mode == CalendarMonth {
+			dayLabel.Importance = widget.LowImportance
+		}
+		if sameDay(date, time.Now()) {
+			dayLabel.TextStyle = fyne.TextStyle{Bold: true}
+			dayLabel.Importance = widget.HighImportance
+		}
+		box := container.NewVBox(dayLabel)
+		for _, task := range byDay[dayKey(date)] {
+			box.Add(newCalendarChip(p, task, colors[task.BoardID]))
+		}
+		p.days[i] = calendarDay{date: date, box: box}
+		objects = append(objects, widget.NewCard("", "", box))
+	}
+	p.grid.Objects = objects
+	p.grid.Refresh()
+}
+
+// dropAt reschedules task to the day under the absolute canvas position pos, keeping its time of day
+func (p *CalendarPage) dropAt(task model.Task, pos fyne.Position) {
+	if task.DueDate == nil || p.reschedule == nil {
+		return
+	}
+	driver := fyne.CurrentApp().Driver()
+	for _, day := range p.days {
+		topLeft := driver.AbsolutePositionForObject(day.box)
+		size := day.box.Size()
+		if pos.X < topLeft.X || pos.Y < topLeft.Y || pos.X >= topLeft.X+size.Width || pos.Y >= topLeft.Y+size.Height {
+			continue
+		}
+		if sameDay(day.date, model.DueIn(*task.DueDate, day.date.Location())) {
+			return
+		}
+		p.reschedule(task, moveToDay(*task.DueDate, day.date))
+		return
+	}
+}
+
+// calendarRange returns the first day shown for the month or week containing anchor, and the
+// number of days shown. Weeks start on Monday and a month is shown as whole weeks.
+func calendarRange(mode CalendarMode, anchor time.Time) (time.Time, int) {
+	y, m, d := anchor.Date()
+	loc := anchor.Location()
+	if mode == CalendarWeek {
+		day := time.Date(y, m, d, 0, 0, 0, 0, loc)
+		return day.AddDate(0, 0, -mondayOffset(day)), 7
+	}
+	first := time.Date(y, m, 1, 0, 0, 0, 0, loc)
+	start := first.AddDate(0, 0, -mondayOffset(first))
+	last := first.AddDate(0, 1, -1)
+	weeks := (mondayOffset(first) + last.Day() + 6) / 7
+	return start, weeks * 7
+}
+
+// calendarTitle describes the month or week shown, such as "June 2025" or "2 Jun – 8 Jun 2025"
+func calendarTitle(mode CalendarMode, start, anchor time.Time) string {
+	if mode == CalendarWeek {
+		return start.Format("2 Jan") + " – " + start.AddDate(0, 0, 6).Format("2 Jan 2006")
+	}
+	return anchor.Format("January 2006")
+}
+
+// mondayOffset returns the number of days since the last Monday
+func mondayOffset(day time.Time) int {
+	return (int(day.Weekday()) + 6) % 7
+}
+
+// tasksByDay groups the tasks due from start until end by dayKey, ordered by due time, then title
+func tasksByDay(tasks []model.Task, start, end time.Time) map[string][]model.Task {
+	byDay := map[string][]model.Task{}
+	for _, task := range tasks {
+		if task.DueDate == nil {
+			continue
+		}
+		due := model.DueIn(*task.DueDate, start.Location())
+		if due.Before(start) || !due.Before(end) {
+			continue
+		}
+		byDay[dayKey(due)] = append(byDay[dayKey(due)], task)
+	}
+	for _, tasks := range byDay {
+		sort.SliceStable(tasks, func(i, j int) bool {
+			if !tasks[i].DueDate.Equal(*tasks[j].DueDate) {
+				return tasks[i].DueDate.Before(*tasks[j].DueDate)
+			}
+			return tasks[i].Title < tasks[j].Title
+		})
+	}
+	return byDay
+}
+
+// dayKey identifies the calendar day of t in its location
+func dayKey(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+// sameDay reports whether a and b fall on the same calendar day in the location of a
+func sameDay(a, b time.Time) bool {
+	return dayKey(a) == dayKey(b.In(a.Location()))
+}
+
+// moveToDay returns due moved to the calendar day of day, keeping the time of day of due in its location.
+// Date-only due dates stay at midnight UTC.
+func moveToDay(due, day time.Time) time.Time {
+	if model.DateOnly(due) {
+		return dueDay(day)
+	}
+	y, m, d := day.Date()
+	return time.Date(y, m, d, due.Hour(), due.Minute(), due.Second(), due.Nanosecond(), due.Location())
+}
+
+// calendarChip is a task on the calendar. Tapping it opens the task, dragging it to another day reschedules it.
+type calendarChip struct {
+	widget.BaseWidget
+	page  *CalendarPage
+	task  model.Task
+	color color.NRGBA
+
+	dragPos fyne.Position
+}
+
+// newCalendarChip creates the chip of a task drawn in the colour of its board
+func newCalendarChip(page *CalendarPage, task model.Task, c color.NRGBA) *calendarChip {
+	chip := &calendarChip{page: page, task: task, color: c}
+	chip.ExtendBaseWidget(chip)
+	return chip
+}
+
+// CreateRenderer implements fyne.Widget
+func (c *calendarChip) CreateRenderer() fyne.WidgetRenderer {
+	fill := c.color
+	fill.A = 0x40
+	background := canvas.NewRectangle(fill)
+	background.StrokeColor = c.color
+	background.StrokeWidth = 1
+	background.CornerRadius = theme.InputRadiusSize()
+	label := widget.NewLabel(c.task.Title)
+	label.SizeName = theme.SizeNameCaptionText
+	label.Truncation = fyne.TextTruncateEllipsis
+	if c.task.Done {
+		label.Importance = widget.LowImportance
+	}
+	return widget.NewSimpleRenderer(container.NewStack(background, label))
+}
+
+// Tapped implements fyne.Tappable
+func (c *calendarChip) Tapped(*fyne.PointEvent) {
+	if c.page.openTask != nil {
+		c.page.openTask(c.task)
+	}
+}
+
+// Dragged implements fyne.Draggable
+func (c *calendarChip) Dragged(e *fyne.DragEvent) {
+	c.dragPos = e.AbsolutePosition
+	c.Move(c.Position().Add(e.Dragged))
+}
+
+// DragEnd implements fyne.Draggable
+func (c *calendarChip) DragEnd() {
+	c.page.dropAt(c.task, c.dragPos)
+	// Put the chip back in its day, the calendar is rebuilt again once the cache has the new due date
+	c.page.Reload()
+}
@@ -0,0 +1,221 @@
+package ui
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"eldar/model"
+	"eldar/store"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRemote serves fixed boards and tasks to a store, the other requests are not used by these tests
+type fakeRemote struct {
+	store.Remote
+	boards []model.Board
+	tasks  []model.Task
+	// trashedBoards and trashedTasks are the boards and tasks in the trash
+	trashedBoards []model.Board
+	trashedTasks  []model.Task
+	timeEntries   []model.TimeEntry
+	templates     []model.Template
+}
+
+func (f *fakeRemote) ListBoards(ctx context.Context) ([]model.Board, error) {
+	return f.boards, nil
+}
+
+func (f *fakeRemote) ListTasks(ctx context.Context, boardID string) ([]model.Task, error) {
+	var tasks []model.Task
+	for _, t := range f.tasks {
+		if t.BoardID == boardID {
+			tasks = append(tasks, t)
+		}
+	}
+	return tasks, nil
+}
+
+func (f *fakeRemote) ListViews(ctx context.Context) ([]model.View, error) {
+	return nil, nil
+}
+
+func (f *fakeRemote) ListMembers(ctx context.Context) ([]model.Member, error) {
+	return nil, nil
+}
+
+func (f *fakeRemote) ListActivity(ctx context.Context, boardID string, after int64) ([]model.Activity, error) {
+	return nil, nil
+}
+
+func (f *fakeRemote) ListTrash(ctx context.Context) (*model.Trash, error) {
+	return &model.Trash{Boards: f.trashedBoards, Tasks: f.trashedTasks}, nil
+}
+
+func (f *fakeRemote) ListTemplates(ctx context.Context) ([]model.Template, error) {
+	return f.templates, nil
+}
+
+func (f *fakeRemote) ListTimeEntries(ctx context.Context, boardID string) ([]model.TimeEntry, error) {
+	var entries []model.TimeEntry
+	for _, e := range f.timeEntries {
+		if e.BoardID == boardID {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+// openTestStore opens a store in a temporary directory synced with the given boards and tasks
+func openTestStore(t *testing.T, boards []model.Board, tasks []model.Task) *store.Store {
+	st, err := store.Open(t.TempDir(), &fakeRemote{boards: boards, tasks: tasks})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = st.Close()
+	})
+	require.NoError(t, st.Sync(context.Background()))
+	return st
+}
+
+func TestCalendarRange(t *testing.T) {
+	// June 2025 starts on a Sunday and ends on a Monday, so it spans six weeks
+	start, days := calendarRange(CalendarMonth, time.Date(2025, 6, 18, 15, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2025, 5, 26, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, 42, days)
+
+	// February 2021 starts on a Monday and has exactly four weeks
+	start, days = calendarRange(CalendarMonth, time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, 28, days)
+
+	start, days = calendarRange(CalendarWeek, time.Date(2025, 6, 8, 23, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, 7, days)
+	assert.Equal(t, "2 Jun – 8 Jun 2025", calendarTitle(CalendarWeek, start, start))
+}
+
+func TestMoveToDay(t *testing.T) {
+	due := time.Date(2025, 6, 3, 9, 30, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 6, 12, 9, 30, 0, 0, time.UTC), moveToDay(due, time.Date(2025, 6, 12, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestCalendarPage(t *testing.T) {
+	test.NewTempApp(t)
+	due := time.Date(2025, 6, 3, 9, 30, 0, 0, time.Local)
+	later := due.Add(2 * time.Hour)
+	nextMonth := due.AddDate(0, 1, 0)
+	st := openTestStore(t,
+		[]model.Board{{ID: "b1", Name: "Marketing"}, {ID: "b2", Name: "Sprint"}},
+		[]model.Task{
+			{ID: "t1", BoardID: "b2", Title: "Write report", DueDate: &due},
+			{ID: "t2", BoardID: "b1", Title: "Plan launch", DueDate: &later},
+			{ID: "t3", BoardID: "b1", Title: "Review budget", DueDate: &nextMonth},
+			{ID: "t4", BoardID: "b1", Title: "Someday"},
+		})
+
+	var opened []string
+	var moved []time.Time
+	page := NewCalendarPage(st, func(task model.Task) {
+		opened = append(opened, task.ID)
+	}, func(task model.Task, due time.Time) {
+		moved = append(moved, due)
+	})
+	page.SetDate(due)
+	w := test.NewWindow(page)
+	defer w.Close()
+	w.Resize(fyne.NewSize(1200, 900))
+
+	chips := func(day int) []*calendarChip {
+		var chips []*calendarChip
+		for _, d := range page.days {
+			if d.date.Month() == time.June && d.date.Day() == day {
+				for _, obj := range d.box.Objects[1:] {
+					chips = append(chips, obj.(*calendarChip))
+				}
+			}
+		}
+		return chips
+	}
+	assert.Equal(t, "June 2025", page.title.Text)
+	june3 := chips(3)
+	require.Len(t, june3, 2)
+	assert.Equal(t, "t1", june3[0].task.ID)
+	assert.Equal(t, boardColor(1), june3[0].color)
+	assert.Equal(t, boardColor(0), june3[1].color)
+
+	test.Tap(june3[1])
+	assert.Equal(t, []string{"t2"}, opened)
+
+	// Dropping a task on another day keeps its time of day
+	var target fyne.CanvasObject
+	for _, d := range page.days {
+		if d.date.Day() == 12 {
+			target = d.box
+		}
+	}
+	pos := fyne.CurrentApp().Driver().AbsolutePositionForObject(target).AddXY(5, 5)
+	june3[0].Dragged(&fyne.DragEvent{PointEvent: fyne.PointEvent{AbsolutePosition: pos}})
+	june3[0].DragEnd()
+	assert.Equal(t, []time.Time{time.Date(2025, 6, 12, 9, 30, 0, 0, time.Local)}, moved)
+
+	page.Next()
+	assert.Equal(t, "July 2025", page.title.Text)
+	page.SetMode(CalendarWeek)
+	page.SetDate(nextMonth)
+	require.Len(t, page.days, 7)
+	assert.Len(t, page.days[mondayOffset(nextMonth)].box.Objects, 2)
+}
+
+func TestCalendarPageWestOfUTC(t *testing.T) {
+	test.NewTempApp(t)
+	local := time.Local
+	time.Local = time.FixedZone("UTC-5", -5*60*60)
+	t.Cleanup(func() {
+		time.Local = local
+	})
+	// Date-only due dates are stored at midnight UTC, 7pm the day before in this zone
+	due := time.Date(2025, 6, 3, 0, 0, 0, 0, time.UTC)
+	timed := time.Date(2025, 6, 4, 2, 0, 0, 0, time.UTC)
+	st := openTestStore(t, []model.Board{{ID: "b1", Name: "Marketing"}}, []model.Task{
+		{ID: "t1", BoardID: "b1", Title: "Write report", DueDate: &due},
+		{ID: "t2", BoardID: "b1", Title: "Call", DueDate: &timed},
+	})
+	var moved []time.Time
+	page := NewCalendarPage(st, func(model.Task) {}, func(task model.Task, due time.Time) {
+		moved = append(moved, due)
+	})
+	page.SetDate(time.Date(2025, 6, 10, 12, 0, 0, 0, time.Local))
+	w := test.NewWindow(page)
+	defer w.Close()
+	w.Resize(fyne.NewSize(1200, 900))
+
+	box := func(day int) *fyne.Container {
+		for _, d := range page.days {
+			if d.date.Month() == time.June && d.date.Day() == day {
+				return d.box
+			}
+		}
+		return nil
+	}
+	// A due date with a time of day shows on its local date, 9pm on 3 June
+	require.Len(t, box(3).Objects, 3)
+	assert.Equal(t, "t1", box(3).Objects[1].(*calendarChip).task.ID)
+	assert.Equal(t, "t2", box(3).Objects[2].(*calendarChip).task.ID)
+	assert.Len(t, box(2).Objects, 1)
+	assert.Len(t, box(4).Objects, 1)
+
+	// Dropping on the same day does nothing, dropping on another keeps it date-only
+	chip := box(3).Objects[1].(*calendarChip)
+	drop := func(day int) {
+		pos := fyne.CurrentApp().Driver().AbsolutePositionForObject(box(day)).AddXY(5, 5)
+		chip.Dragged(&fyne.DragEvent{PointEvent: fyne.PointEvent{AbsolutePosition: pos}})
+		chip.DragEnd()
+	}
+	drop(3)
+	assert.Empty(t, moved)
+	drop(12)
+	assert.Equal(t, []time.Time{time.Date(2025, 6, 12, 0, 0, 0, 0, time.UTC)}, moved)
+}
@@ -0,0 +1,196 @@
+package ui
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+
+	"eldar/model"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// coverHeight is the height of the cover image of a card
+const coverHeight = 96
+
+// TaskCard is the compact representation of a task shown in a board column.
+// Tapping it calls OnTapped, usually to open the task detail or select it, and dragging it calls OnDragged and OnDrop.
+type TaskCard struct {
+	widget.BaseWidget
+	Task model.Task
+	// Progress is the completion of the checklist and subtasks, hidden when the task has none
+	Progress model.Progress
+	// Blockers are the open tasks this task is blocked by, the card is marked as blocked when there are any
+	Blockers []model.Task
+	// Cover is the thumbnail of the cover image of the task, see model.Task.Cover, or nil to show none
+	Cover image.Image
+	// Labels are the labels of the task in the colours of its board, see model.Board.TaskLabels
+	Labels []model.Label
+	// Selected outlines the card, for the tasks picked for a bulk change
+	Selected bool
+	OnTapped func()
+	// OnDragged is called with the absolute position of the pointer while the card is dragged
+	OnDragged func(fyne.Position)
+	// OnDrop is called with the absolute position the card was dragged to when the drag ends
+	OnDrop func(fyne.Position)
+
+	dragging  bool
+	dropAt    fyne.Position
+	selection *canvas.Rectangle
+	cover     *canvas.Image
+	title     *widget.Label
+	labels    *fyne.Container
+	blocked   *widget.Label
+	details   *widget.Label
+	progress  *widget.ProgressBar
+}
+
+// NewTaskCard creates a card for the given task and its rolled up progress
+func NewTaskCard(task model.Task, progress model.Progress, onTapped func()) *TaskCard {
+	c := &TaskCard{Task: task, Progress: progress, OnTapped: onTapped}
+	c.ExtendBaseWidget(c)
+	return c
+}
+
+// CreateRenderer implements fyne.Widget
+func (c *TaskCard) CreateRenderer() fyne.WidgetRenderer {
+	c.selection = canvas.NewRectangle(color.Transparent)
+	c.selection.StrokeColor = theme.Color(theme.ColorNamePrimary)
+	c.selection.StrokeWidth = 2 * theme.InputBorderSize()
+	c.selection.CornerRadius = theme.InputRadiusSize()
+	c.cover = canvas.NewImageFromImage(nil)
+	c.cover.FillMode = canvas.ImageFillContain
+	c.cover.SetMinSize(fyne.NewSize(0, coverHeight))
+	c.title = widget.NewLabel("")
+	c.title.TextStyle = fyne.TextStyle{Bold: true}
+	c.title.Wrapping = fyne.TextWrapWord
+	c.labels = container.NewHBox()
+	c.blocked = widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	c.blocked.Importance = widget.DangerImportance
+	c.blocked.SizeName = theme.SizeNameCaptionText
+	c.blocked.Wrapping = fyne.TextWrapWord
+	c.details = widget.NewLabel("")
+	c.details.SizeName = theme.SizeNameCaptionText
+	c.progress = widget.NewProgressBar()
+	c.progress.TextFormatter = func() string {
+		return c.Progress.String()
+	}
+	c.update()
+	card := widget.NewCard("", "", container.NewVBox(c.cover, c.labels, c.title, c.blocked, c.details, c.progress))
+	return widget.NewSimpleRenderer(container.NewStack(card, c.selection))
+}
+
+// Refresh implements fyne.Widget
+func (c *TaskCard) Refresh() {
+	if c.title != nil {
+		c.update()
+	}
+	c.BaseWidget.Refresh()
+}
+
+// Tapped implements fyne.Tappable
+func (c *TaskCard) Tapped(*fyne.PointEvent) {
+	if c.OnTapped != nil {
+		c.OnTapped()
+	}
+}
+
+// Dragged implements fyne.Draggable
+func (c *TaskCard) Dragged(e *fyne.DragEvent) {
+	c.dragging = true
+	c.dropAt = e.AbsolutePosition
+	if c.OnDragged != nil {
+		c.OnDragged(e.AbsolutePosition)
+	}
+}
+
+// DragEnd implements fyne.Draggable
+func (c *TaskCard) DragEnd() {
+	if c.dragging && c.OnDrop != nil {
+		c.OnDrop(c.dropAt)
+	}
+	c.dragging = false
+}
+
+// update copies the task fields into the card labels
+func (c *TaskCard) update() {
+	if c.Selected {
+		c.selection.Show()
+	} else {
+		c.selection.Hide()
+	}
+	c.cover.Image = c.Cover
+	if c.Cover == nil {
+		c.cover.Hide()
+	} else {
+		c.cover.Show()
+		c.cover.Refresh()
+	}
+	c.labels.Objects = newLabelChips(c.Labels)
+	if len(c.Labels) == 0 {
+		c.labels.Hide()
+	} else {
+		c.labels.Show()
+		c.labels.Refresh()
+	}
+	c.title.SetText(c.Task.Title)
+	c.blocked.SetText(blockedText(c.Blockers))
+	if c.blocked.Text == "" {
+		c.blocked.Hide()
+	} else {
+		c.blocked.Show()
+	}
+	c.details.SetText(taskCardDetails(c.Task))
+	if c.details.Text == "" {
+		c.details.Hide()
+	} else {
+		c.details.Show()
+	}
+	c.progress.SetValue(c.Progress.Fraction())
+	if c.Progress.Total == 0 {
+		c.progress.Hide()
+	} else {
+		c.progress.Show()
+	}
+}
+
+// taskCardDetails summarises the secondary task fields on a single line
+func taskCardDetails(task model.Task) string {
+	var parts []string
+	if task.Priority != model.PriorityNone {
+		parts = append(parts, task.Priority.String())
+	}
+	if task.DueDate != nil {
+		parts = append(parts, "Due "+task.DueDate.Format("2 Jan"))
+	}
+	if task.Recurrence != nil {
+		parts = append(parts, "Repeats")
+	}
+	if len(task.Assignees) > 0 {
+		parts = append(parts, strings.Join(task.Assignees, ", "))
+	}
+	switch n := len(task.Attachments); n {
+	case 0:
+	case 1:
+		parts = append(parts, "1 file")
+	default:
+		parts = append(parts, fmt.Sprintf("%d files", n))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// blockedText describes the open blockers of a task, or returns an empty string when there are none
+func blockedText(blockers []model.Task) string {
+	switch len(blockers) {
+	case 0:
+		return ""
+	case 1:
+		return "Blocked by " + blockers[0].Title
+	default:
+		return fmt.Sprintf("Blocked by %d tasks", len(blockers))
+	}
+}
@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"image"
+	"image/color"
+	"testing"
+	"time"
+
+	"eldar/model"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskCard(t *testing.T) {
+	test.NewTempApp(t)
+	due := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	tapped := false
+	card := NewTaskCard(model.Task{Title: "Write report", Priority: model.PriorityHigh, DueDate: &due}, model.Progress{}, func() {
+		tapped = true
+	})
+	test.WidgetRenderer(card)
+
+	assert.Equal(t, "High · Due 1 Jun", card.details.Text)
+	assert.False(t, card.progress.Visible())
+	assert.False(t, card.blocked.Visible())
+
+	card.Progress = model.Progress{Done: 3, Total: 7}
+	card.Blockers = []model.Task{{ID: "t2", Title: "Collect numbers"}}
+	card.Refresh()
+	assert.True(t, card.progress.Visible())
+	assert.Equal(t, "3/7", card.progress.TextFormatter())
+	assert.Equal(t, "Blocked by Collect numbers", card.blocked.Text)
+
+	card.Blockers = append(card.Blockers, model.Task{ID: "t3", Title: "Review"})
+	card.Refresh()
+	assert.Equal(t, "Blocked by 2 tasks", card.blocked.Text)
+
+	assert.False(t, card.cover.Visible())
+	card.Task.Attachments = []model.Attachment{{ID: "a1", MediaType: "image/png"}, {ID: "a2", MediaType: "text/plain"}}
+	card.Cover = image.NewRGBA(image.Rect(0, 0, 8, 8))
+	card.Refresh()
+	assert.True(t, card.cover.Visible())
+	assert.Equal(t, "High · Due 1 Jun · 2 files", card.details.Text)
+
+	assert.False(t, card.labels.Visible())
+	card.Labels = []model.Label{{Name: "bug", Color: "#e53935"}, {Name: "docs"}, {Name: "ui"}, {Name: "api"}, {Name: "web"}}
+	card.Refresh()
+	assert.True(t, card.labels.Visible())
+	require.Len(t, card.labels.Objects, 4)
+	chip := card.labels.Objects[0].(*fyne.Container)
+	assert.Equal(t, color.NRGBA{R: 0xe5, G: 0x39, B: 0x35, A: 0xff}, chip.Objects[0].(*canvas.Rectangle).FillColor)
+	assert.Equal(t, "+2", card.labels.Objects[3].(*canvas.Text).Text)
+
+	test.Tap(card)
+	assert.True(t, tapped)
+}
+
+func TestTaskCardDrag(t *testing.T) {
+	test.NewTempApp(t)
+	card := NewTaskCard(model.Task{Title: "Fix login"}, model.Progress{}, nil)
+	var dragged, dropped []fyne.Position
+	card.OnDragged = func(position fyne.Position) {
+		dragged = append(dragged, position)
+	}
+	card.OnDrop = func(position fyne.Position) {
+		dropped = append(dropped, position)
+	}
+
+	// Ending a drag that did not start drops nothing
+	card.DragEnd()
+	assert.Empty(t, dropped)
+
+	card.Dragged(&fyne.DragEvent{PointEvent: fyne.PointEvent{AbsolutePosition: fyne.NewPos(10, 20)}})
+	card.Dragged(&fyne.DragEvent{PointEvent: fyne.PointEvent{AbsolutePosition: fyne.NewPos(30, 40)}})
+	card.DragEnd()
+	assert.Equal(t, []fyne.Position{fyne.NewPos(10, 20), fyne.NewPos(30, 40)}, dragged)
+	assert.Equal(t, []fyne.Position{fyne.NewPos(30, 40)}, dropped)
+	card.DragEnd()
+	assert.Len(t, dropped, 1)
+}
@@ -0,0 +1,134 @@
+package ui
+
+import (
+	"strings"
+
+	"eldar/model"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ChecklistEditor edits the ordered checklist of a task.
+// Changes are kept in the editor until they are read back with Items.
+type ChecklistEditor struct {
+	widget.BaseWidget
+	// OnConvert is called to turn a saved item into a subtask, the button is hidden when it is nil
+	OnConvert func(model.ChecklistItem)
+
+	items []model.ChecklistItem
+	// saved holds the IDs of the items known to the server, only those can be converted
+	saved   map[string]bool
+	rows    *fyne.Container
+	newItem *widget.Entry
+}
+
+// NewChecklistEditor creates an editor for a copy of items
+func NewChecklistEditor(items []model.ChecklistItem, onConvert func(model.ChecklistItem)) *ChecklistEditor {
+	e := &ChecklistEditor{
+		OnConvert: onConvert,
+		items:     append([]model.ChecklistItem{}, items...),
+		saved:     map[string]bool{},
+		rows:      container.NewVBox(),
+	}
+	for _, item := range items {
+		e.saved[item.ID] = true
+	}
+	e.newItem = widget.NewEntry()
+	e.newItem.SetPlaceHolder("Add an item")
+	e.newItem.OnSubmitted = func(text string) {
+		e.AddItem(text)
+	}
+	e.ExtendBaseWidget(e)
+	e.rebuild()
+	return e
+}
+
+// CreateRenderer implements fyne.Widget
+func (e *ChecklistEditor) CreateRenderer() fyne.WidgetRenderer {
+	add := widget.NewButtonWithIcon("", theme.ContentAddIcon(), func() {
+		e.AddItem(e.newItem.Text)
+	})
+	return widget.NewSimpleRenderer(container.NewVBox(e.rows, container.NewBorder(nil, nil, nil, add, e.newItem)))
+}
+
+// Items returns a copy of the edited checklist
+func (e *ChecklistEditor) Items() []model.ChecklistItem {
+	return append([]model.ChecklistItem{}, e.items...)
+}
+
+// AddItem appends an unchecked item, blank text is ignored
+func (e *ChecklistEditor) AddItem(text string) {
+	if strings.TrimSpace(text) == "" {
+		return
+	}
+	item, err := model.NewChecklistItem(text)
+	if err != nil {
+		fyne.LogError("Failed to add checklist item", err)
+		return
+	}
+	e.items = append(e.items, item)
+	e.newItem.SetText("")
+	e.rebuild()
+}
+
+// MoveItem moves the item at index from to index to
+func (e *ChecklistEditor) MoveItem(from, to int) {
+	task := model.Task{Checklist: e.items}
+	if err := task.MoveChecklistItem(from, to); err != nil {
+		return
+	}
+	e.items = task.Checklist
+	e.rebuild()
+}
+
+// RemoveItem deletes the item at index
+func (e *ChecklistEditor) RemoveItem(index int) {
+	if index < 0 || index >= len(e.items) {
+		return
+	}
+	e.items = append(e.items[:index:index], e.items[index+1:]...)
+	e.rebuild()
+}
+
+// rebuild recreates a row for every item
+func (e *ChecklistEditor) rebuild() {
+	objects := make([]fyne.CanvasObject, len(e.items))
+	for i, item := range e.items {
+		objects[i] = e.makeRow(i, item)
+	}
+	e.rows.Objects = objects
+	e.rows.Refresh()
+}
+
+// makeRow renders an item as a check box followed by its actions
+func (e *ChecklistEditor) makeRow(index int, item model.ChecklistItem) fyne.CanvasObject {
+	check := widget.NewCheck(item.Text, func(done bool) {
+		e.items[index].Done = done
+	})
+	check.SetChecked(item.Done)
+
+	up := widget.NewButtonWithIcon("", theme.MoveUpIcon(), func() {
+		e.MoveItem(index, index-1)
+	})
+	if index == 0 {
+		up.Disable()
+	}
+	down := widget.NewButtonWithIcon("", theme.MoveDownIcon(), func() {
+		e.MoveItem(index, index+1)
+	})
+	if index == len(e.items)-1 {
+		down.Disable()
+	}
+	actions := container.NewHBox(up, down)
+	if e.OnConvert != nil && e.saved[item.ID] {
+		actions.Add(widget.NewButton("To task", func() {
+			e.OnConvert(item)
+		}))
+	}
+	actions.Add(widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+		e.RemoveItem(index)
+	}))
+	return container.NewBorder(nil, nil, nil, actions, check)
+}
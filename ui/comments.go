@@ -0,0 +1,418 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"eldar/model"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// maxMentionSuggestions is how many members the mention autocomplete offers at once
+const maxMentionSuggestions = 5
+
+// CommentsPanel shows the comment threads of a task with a composer to post, reply and edit.
+// Comments are rendered as Markdown; edited comments show their earlier versions on request
+// and deleted ones keep their place so the replies to them still make sense.
+type CommentsPanel struct {
+	widget.BaseWidget
+	// Me is the username of the signed in account, which can edit and delete its own comments
+	Me       string
+	Members  []model.Member
+	OnPost   func(body, parentID string)
+	OnEdit   func(comment model.Comment, body string)
+	OnDelete func(model.Comment)
+
+	entries  []model.ThreadEntry
+	thread   *fyne.Container
+	composer *MentionEntry
+	status   *widget.Label
+	cancel   *widget.Button
+	post     *widget.Button
+	replyTo  string
+	editing  *model.Comment
+}
+
+// NewCommentsPanel creates the comments panel of a task.
+//
+// Parameters:
+//   - me: The username of the signed in account
+//   - members: The group members that can be mentioned
+//   - onPost: A function to call with the body of a new comment and the ID of the comment it replies to, if any
+//
+// Returns:
+//   - A CommentsPanel, whose comments are set with SetComments
+func NewCommentsPanel(me string, members []model.Member, onPost func(body, parentID string)) *CommentsPanel {
+	p := &CommentsPanel{Me: me, Members: members, OnPost: onPost}
+	p.thread = container.NewVBox()
+	p.composer = NewMentionEntry(members)
+	p.composer.SetPlaceHolder("Write a comment using Markdown, @ to mention someone")
+	p.status = widget.NewLabel("")
+	p.status.Hide()
+	p.cancel = widget.NewButton("Cancel", p.resetComposer)
+	p.cancel.Hide()
+	p.post = widget.NewButtonWithIcon("Comment", theme.MailSendIcon(), p.submit)
+	p.post.Importance = widget.HighImportance
+	p.ExtendBaseWidget(p)
+	return p
+}
+
+// CreateRenderer implements fyne.Widget
+func (p *CommentsPanel) CreateRenderer() fyne.WidgetRenderer {
+	actions := container.NewHBox(layout.NewSpacer(), p.cancel, p.post)
+	composer := container.NewVBox(p.status, p.composer, actions)
+	return widget.NewSimpleRenderer(container.NewBorder(nil, composer, nil, nil, container.NewVScroll(p.thread)))
+}
+
+// SetComments replaces the comments shown, ordered as threads by model.Thread
+func (p *CommentsPanel) SetComments(entries []model.ThreadEntry) {
+	p.entries = entries
+	objects := make([]fyne.CanvasObject, 0, len(entries))
+	for _, e := range entries {
+		objects = append(objects, p.makeComment(e))
+	}
+	if len(objects) == 0 {
+		objects = append(objects, widget.NewLabel("No comments yet"))
+	}
+	p.thread.Objects = objects
+	p.thread.Refresh()
+}
+
+// Reply starts a reply to comment in the composer
+func (p *CommentsPanel) Reply(comment model.Comment) {
+	p.resetComposer()
+	p.replyTo = comment.ID
+	p.status.SetText("Replying to " + p.authorName(comment.Author))
+	p.status.Show()
+	p.cancel.Show()
+}
+
+// Edit loads comment into the composer, so submitting replaces its body
+func (p *CommentsPanel) Edit(comment model.Comment) {
+	p.resetComposer()
+	p.editing = &comment
+	p.composer.SetText(comment.Body)
+	p.status.SetText("Editing your comment")
+	p.status.Show()
+	p.cancel.Show()
+	p.post.SetText("Save")
+}
+
+// submit posts the composer text as a new comment or reply, or saves the comment being edited
+func (p *CommentsPanel) submit() {
+	body := strings.TrimSpace(p.composer.Text)
+	if body == "" {
+		return
+	}
+	switch {
+	case p.editing != nil:
+		if p.OnEdit != nil {
+			p.OnEdit(*p.editing, body)
+		}
+	case p.OnPost != nil:
+		p.OnPost(body, p.replyTo)
+	}
+	p.resetComposer()
+}
+
+// resetComposer clears the composer and leaves reply or edit mode
+func (p *CommentsPanel) resetComposer() {
+	p.replyTo, p.editing = "", nil
+	p.composer.SetText("")
+	p.status.Hide()
+	p.cancel.Hide()
+	p.post.SetText("Comment")
+}
+
+// makeComment renders a comment with its actions, indented by its depth in the thread
+func (p *CommentsPanel) makeComment(e model.ThreadEntry) fyne.CanvasObject {
+	c := e.Comment
+	header := widget.NewLabelWithStyle(commentHeader(c, p.authorName(c.Author)), fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	header.SizeName = theme.SizeNameCaptionText
+	content := container.NewVBox(header)
+
+	if c.Deleted {
+		deleted := widget.NewLabelWithStyle("Comment deleted", fyne.TextAlignLeading, fyne.TextStyle{Italic: true})
+		deleted.Importance = widget.LowImportance
+		content.Add(deleted)
+	} else {
+		body := widget.NewRichTextFromMarkdown(highlightMentions(c.Body, p.Members))
+		body.Wrapping = fyne.TextWrapWord
+		content.Add(body)
+	}
+
+	actions := container.NewHBox()
+	if !c.Deleted {
+		actions.Add(widget.NewButton("Reply", func() {
+			p.Reply(c)
+		}))
+	}
+	if !c.Deleted && c.Author == p.Me {
+		actions.Add(widget.NewButton("Edit", func() {
+			p.Edit(c)
+		}))
+		actions.Add(widget.NewButton("Delete", func() {
+			if p.OnDelete != nil {
+				p.OnDelete(c)
+			}
+		}))
+	}
+	if c.Edited() {
+		history := makeCommentHistory(c)
+		history.Hide()
+		toggle := widget.NewButton(fmt.Sprintf("History (%d)", len(c.History)), nil)
+		toggle.OnTapped = func() {
+			if history.Visible() {
+				history.Hide()
+			} else {
+				history.Show()
+			}
+		}
+		actions.Add(toggle)
+		content.Add(history)
+	}
+	for _, obj := range actions.Objects {
+		obj.(*widget.Button).Importance = widget.LowImportance
+	}
+	content.Add(actions)
+
+	indent := canvas.NewRectangle(theme.Color(theme.ColorNameSeparator))
+	indent.SetMinSize(fyne.NewSize(float32(e.Depth)*theme.Padding()*4, 0))
+	if e.Depth == 0 {
+		indent.Hide()
+	}
+	return container.NewBorder(nil, nil, indent, nil, widget.NewCard("", "", content))
+}
+
+// authorName returns the display name of a member, or username when it is not a known member
+func (p *CommentsPanel) authorName(username string) string {
+	for _, m := range p.Members {
+		if m.Username == username && m.Name != "" {
+			return m.Name
+		}
+	}
+	return username
+}
+
+// commentHeader describes the author and time of a comment, such as "Ana Lima · 2 Jun 09:00 · edited"
+func commentHeader(c model.Comment, author string) string {
+	parts := []string{author}
+	if !c.CreatedAt.IsZero() {
+		parts = append(parts, c.CreatedAt.Local().Format("2 Jan 15:04"))
+	}
+	if c.Edited() && !c.Deleted {
+		parts = append(parts, "edited")
+	}
+	return strings.Join(parts, " · ")
+}
+
+// makeCommentHistory lists the earlier versions of an edited comment, newest first
+func makeCommentHistory(c model.Comment) *fyne.Container {
+	history := container.NewVBox()
+	for i := len(c.History) - 1; i >= 0; i-- {
+		revision := c.History[i]
+		if revision.Body == "" {
+			continue
+		}
+		label := widget.NewLabel("Before " + revision.EditedAt.Local().Format("2 Jan 15:04") + ":")
+		label.SizeName = theme.SizeNameCaptionText
+		body := widget.NewRichTextFromMarkdown(revision.Body)
+		body.Wrapping = fyne.TextWrapWord
+		history.Add(label)
+		history.Add(body)
+	}
+	return history
+}
+
+// highlightMentions makes the mentions of known members bold in a Markdown comment body
+func highlightMentions(body string, members []model.Member) string {
+	known := make(map[string]bool, len(members))
+	for _, m := range members {
+		known[strings.ToLower(m.Handle)] = m.Handle != ""
+	}
+	runes := []rune(body)
+	var b strings.Builder
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '@' || (i > 0 && model.IsHandleRune(runes[i-1])) {
+			b.WriteRune(runes[i])
+			continue
+		}
+		j := i + 1
+		for j < len(runes) && model.IsHandleRune(runes[j]) {
+			j++
+		}
+		// As in model.ParseMentions, dots ending a mention end the sentence
+		word := string(runes[i+1 : j])
+		handle := strings.TrimRight(word, ".")
+		if known[strings.ToLower(handle)] {
+			b.WriteString("**@" + handle + "**" + word[len(handle):])
+		} else {
+			b.WriteString("@" + word)
+		}
+		i = j - 1
+	}
+	return b.String()
+}
+
+// MentionEntry is a multi-line entry that offers to complete @mentions of group members as they are typed
+type MentionEntry struct {
+	widget.Entry
+	Members []model.Member
+
+	// cursor is the position in the text, in runes, of the cursor after the last typed edit, or -1.
+	// CursorRow counts wrapped rows rather than lines, so the position is worked out from the edit instead.
+	cursor int
+	popUp  *widget.PopUpMenu
+}
+
+// NewMentionEntry creates an entry completing mentions of members
+func NewMentionEntry(members []model.Member) *MentionEntry {
+	e := &MentionEntry{Members: members, cursor: -1}
+	e.MultiLine = true
+	e.Wrapping = fyne.TextWrapWord
+	e.ExtendBaseWidget(e)
+	return e
+}
+
+// TypedRune implements fyne.Focusable
+func (e *MentionEntry) TypedRune(r rune) {
+	before := e.Text
+	e.Entry.TypedRune(r)
+	e.cursor = editEnd(before, e.Text)
+	e.showSuggestions()
+}
+
+// TypedKey implements fyne.Focusable
+func (e *MentionEntry) TypedKey(key *fyne.KeyEvent) {
+	before := e.Text
+	e.Entry.TypedKey(key)
+	if key.Name == fyne.KeyBackspace || key.Name == fyne.KeyDelete {
+		e.cursor = editEnd(before, e.Text)
+		e.showSuggestions()
+		return
+	}
+	// The cursor may have moved anywhere
+	e.cursor = -1
+	e.hideSuggestions()
+}
+
+// SetText sets the text of the entry and hides the suggestions
+func (e *MentionEntry) SetText(text string) {
+	e.cursor = -1
+	e.hideSuggestions()
+	e.Entry.SetText(text)
+}
+
+// Suggestions returns the members matching the partial mention before the cursor, if any
+func (e *MentionEntry) Suggestions() []model.Member {
+	partial, _, ok := e.partialMention()
+	if !ok {
+		return nil
+	}
+	return mentionSuggestions(e.Members, partial)
+}
+
+// Complete replaces the partial mention before the cursor with a mention of member
+func (e *MentionEntry) Complete(member model.Member) {
+	partial, _, ok := e.partialMention()
+	if !ok {
+		return
+	}
+	// Edited through the entry so it keeps the cursor in place, whatever the wrapping
+	for range len([]rune(partial)) + 1 {
+		e.Entry.TypedKey(&fyne.KeyEvent{Name: fyne.KeyBackspace})
+	}
+	for _, r := range "@" + member.Handle + " " {
+		e.Entry.TypedRune(r)
+	}
+	e.cursor = -1
+	e.hideSuggestions()
+}
+
+// partialMention returns the handle typed so far after an @ just before the cursor and the position of the @
+func (e *MentionEntry) partialMention() (string, int, bool) {
+	text := []rune(e.Text)
+	if e.cursor < 0 || e.cursor > len(text) {
+		return "", 0, false
+	}
+	start := e.cursor
+	for start > 0 && model.IsHandleRune(text[start-1]) {
+		start--
+	}
+	if start == 0 || text[start-1] != '@' {
+		return "", 0, false
+	}
+	at := start - 1
+	if at > 0 && model.IsHandleRune(text[at-1]) {
+		// An e-mail address rather than a mention
+		return "", 0, false
+	}
+	return string(text[start:e.cursor]), at, true
+}
+
+// editEnd returns the position, in runes, just after the part of after that differs from before,
+// which is where the cursor is after typing or deleting a character
+func editEnd(before, after string) int {
+	b, a := []rune(before), []rune(after)
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	return len(a) - suffix
+}
+
+// showSuggestions shows a menu of the members matching the partial mention, or hides it when there are none
+func (e *MentionEntry) showSuggestions() {
+	e.hideSuggestions()
+	suggestions := e.Suggestions()
+	c := fyne.CurrentApp().Driver().CanvasForObject(e)
+	if len(suggestions) == 0 || c == nil {
+		return
+	}
+	items := make([]*fyne.MenuItem, len(suggestions))
+	for i, m := range suggestions {
+		member := m
+		items[i] = fyne.NewMenuItem("@"+m.Handle+" · "+m.Name, func() {
+			e.Complete(member)
+			c.Focus(e)
+		})
+	}
+	e.popUp = widget.NewPopUpMenu(fyne.NewMenu("", items...), c)
+	position := fyne.CurrentApp().Driver().AbsolutePositionForObject(e)
+	e.popUp.ShowAtPosition(position.AddXY(0, e.Size().Height))
+}
+
+// hideSuggestions hides the suggestion menu, if shown
+func (e *MentionEntry) hideSuggestions() {
+	if e.popUp != nil {
+		e.popUp.Hide()
+		e.popUp = nil
+	}
+}
+
+// mentionSuggestions returns the members whose handle or name starts with partial, ignoring case
+func mentionSuggestions(members []model.Member, partial string) []model.Member {
+	partial = strings.ToLower(partial)
+	var matches []model.Member
+	for _, m := range members {
+		if m.Handle == "" {
+			continue
+		}
+		if strings.HasPrefix(strings.ToLower(m.Handle), partial) || strings.HasPrefix(strings.ToLower(m.Name), partial) {
+			matches = append(matches, m)
+			if len(matches) == maxMentionSuggestions {
+				break
+			}
+		}
+	}
+	return matches
+}
@@ -0,0 +1,120 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"eldar/model"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testMembers is a group of members to mention
+var testMembers = []model.Member{
+	{Username: "ana@example.com", Name: "Ana Lima", Handle: "ana"},
+	{Username: "andre@example.com", Name: "André Silva", Handle: "andre"},
+	{Username: "ben@example.com", Name: "Ben Kay", Handle: "ben"},
+}
+
+func TestCommentsPanel(t *testing.T) {
+	test.NewTempApp(t)
+	type post struct{ body, parentID string }
+	var posted []post
+	var edited []string
+	var deleted []string
+	panel := NewCommentsPanel("ana@example.com", testMembers, func(body, parentID string) {
+		posted = append(posted, post{body, parentID})
+	})
+	panel.OnEdit = func(c model.Comment, body string) {
+		edited = append(edited, c.ID+":"+body)
+	}
+	panel.OnDelete = func(c model.Comment) {
+		deleted = append(deleted, c.ID)
+	}
+	test.WidgetRenderer(panel)
+
+	panel.SetComments(nil)
+	assert.Equal(t, "No comments yet", panel.thread.Objects[0].(*widget.Label).Text)
+
+	at := time.Date(2025, 6, 2, 9, 0, 0, 0, time.Local)
+	panel.SetComments(model.Thread([]model.Comment{
+		{ID: "c1", Author: "ben@example.com", Body: "Draft is up, @ana", CreatedAt: at},
+		{ID: "c2", ParentID: "c1", Author: "ana@example.com", Body: "Thanks!", CreatedAt: at.Add(time.Minute),
+			History: []model.CommentRevision{{Body: "Thanks", EditedAt: at.Add(2 * time.Minute)}}},
+		{ID: "c3", Author: "ben@example.com", Deleted: true, CreatedAt: at.Add(3 * time.Minute)},
+	}))
+	require.Len(t, panel.thread.Objects, 3)
+	first, reply, removed := panel.thread.Objects[0], panel.thread.Objects[1], panel.thread.Objects[2]
+
+	// Only your own comments can be edited and deleted, deleted ones cannot be answered
+	assert.NotNil(t, findButtonOrNil(first.(*fyne.Container).Objects[0].(*widget.Card).Content, "Reply"))
+	assert.Nil(t, findButtonOrNil(first.(*fyne.Container).Objects[0].(*widget.Card).Content, "Edit"))
+	replyCard := reply.(*fyne.Container).Objects[0].(*widget.Card).Content
+	assert.NotNil(t, findButtonOrNil(replyCard, "Edit"))
+	assert.NotNil(t, findButtonOrNil(replyCard, "History (1)"))
+	assert.Nil(t, findButtonOrNil(removed.(*fyne.Container).Objects[0].(*widget.Card).Content, "Reply"))
+	assert.Equal(t, "André Silva", panel.authorName("andre@example.com"))
+	assert.Equal(t, "Ben Kay · 2 Jun 09:00", commentHeader(model.Comment{CreatedAt: at}, "Ben Kay"))
+
+	test.Tap(findButton(t, first.(*fyne.Container).Objects[0].(*widget.Card).Content, "Reply"))
+	assert.Equal(t, "Replying to Ben Kay", panel.status.Text)
+	panel.composer.SetText("  On it  ")
+	test.Tap(panel.post)
+	assert.Equal(t, []post{{"On it", "c1"}}, posted)
+	assert.False(t, panel.status.Visible())
+
+	test.Tap(findButton(t, replyCard, "Edit"))
+	assert.Equal(t, "Thanks!", panel.composer.Text)
+	assert.Equal(t, "Save", panel.post.Text)
+	panel.composer.SetText("Thanks a lot!")
+	test.Tap(panel.post)
+	assert.Equal(t, []string{"c2:Thanks a lot!"}, edited)
+	assert.Equal(t, "Comment", panel.post.Text)
+
+	test.Tap(findButton(t, replyCard, "Delete"))
+	assert.Equal(t, []string{"c2"}, deleted)
+
+	// An empty composer posts nothing
+	test.Tap(panel.post)
+	assert.Len(t, posted, 1)
+}
+
+func TestHighlightMentions(t *testing.T) {
+	assert.Equal(t, "**@Ana** and **@ben**. Mail ana@example.com, not @carol",
+		highlightMentions("@Ana and @ben. Mail ana@example.com, not @carol", testMembers))
+}
+
+func TestMentionEntry(t *testing.T) {
+	test.NewTempApp(t)
+	entry := NewMentionEntry(testMembers)
+	w := test.NewWindow(entry)
+	defer w.Close()
+
+	test.Type(entry, "Thanks @an")
+	suggestions := entry.Suggestions()
+	require.Len(t, suggestions, 2)
+	assert.Equal(t, "ana", suggestions[0].Handle)
+	assert.NotNil(t, entry.popUp)
+
+	entry.Complete(suggestions[1])
+	assert.Equal(t, "Thanks @andre ", entry.Text)
+	assert.Nil(t, entry.popUp)
+	assert.Empty(t, entry.Suggestions())
+
+	// Names match as well as handles, and e-mail addresses are not mentions
+	test.Type(entry, "and @Ben")
+	assert.Equal(t, []model.Member{testMembers[2]}, entry.Suggestions())
+	test.Type(entry, "\nmail ana@ex")
+	assert.Empty(t, entry.Suggestions())
+
+	// Deleting back into a mention suggests again
+	entry.SetText("")
+	test.Type(entry, "@bx")
+	entry.TypedKey(&fyne.KeyEvent{Name: fyne.KeyBackspace})
+	assert.Equal(t, []model.Member{testMembers[2]}, entry.Suggestions())
+	entry.TypedKey(&fyne.KeyEvent{Name: fyne.KeyLeft})
+	assert.Empty(t, entry.Suggestions())
+}
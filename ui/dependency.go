@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"eldar/model"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// DependencyEditor edits the tasks a task is blocked by.
+// Blockers are picked from a list of candidates and checked before they are added,
+// so a link that would create a dependency cycle is refused with its reason.
+type DependencyEditor struct {
+	widget.BaseWidget
+
+	taskID     string
+	blockedBy  []string
+	candidates []model.Task
+	validate   func(blockedBy []string) error
+
+	rows   *fyne.Container
+	picker *widget.Select
+	// pickerIDs holds the task IDs of the picker options, in the same order
+	pickerIDs []string
+	errLabel  *widget.Label
+}
+
+// NewDependencyEditor creates an editor for the blockers of task.
+//
+// Parameters:
+//   - task: The task whose blockers are edited, it is not modified
+//   - candidates: The tasks that can be picked as blockers
+//   - validate: A function checking a new list of blockers, may be nil
+//
+// Returns:
+//   - A DependencyEditor whose result is read with BlockedBy
+func NewDependencyEditor(task model.Task, candidates []model.Task, validate func(blockedBy []string) error) *DependencyEditor {
+	e := &DependencyEditor{
+		taskID:     task.ID,
+		blockedBy:  append([]string{}, task.BlockedBy...),
+		candidates: candidates,
+		validate:   validate,
+		rows:       container.NewVBox(),
+		errLabel:   widget.NewLabel(""),
+	}
+	e.errLabel.Importance = widget.DangerImportance
+	e.errLabel.Wrapping = fyne.TextWrapWord
+	e.errLabel.Hide()
+	e.picker = widget.NewSelect(nil, func(string) {
+		index := e.picker.SelectedIndex()
+		if index < 0 || index >= len(e.pickerIDs) {
+			return
+		}
+		e.AddBlocker(e.pickerIDs[index])
+	})
+	e.picker.PlaceHolder = "Add a blocking task"
+	e.ExtendBaseWidget(e)
+	e.rebuild()
+	return e
+}
+
+// CreateRenderer implements fyne.Widget
+func (e *DependencyEditor) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(container.NewVBox(e.rows, e.picker, e.errLabel))
+}
+
+// BlockedBy returns the IDs of the edited blockers
+func (e *DependencyEditor) BlockedBy() []string {
+	if len(e.blockedBy) == 0 {
+		return nil
+	}
+	return append([]string{}, e.blockedBy...)
+}
+
+// AddBlocker adds the task with the given ID as a blocker, unless validation refuses it.
+// The reason of a refusal is displayed below the picker.
+func (e *DependencyEditor) AddBlocker(id string) {
+	blockedBy := append(append([]string{}, e.blockedBy...), id)
+	if e.validate != nil {
+		if err := e.validate(blockedBy); err != nil {
+			e.errLabel.SetText(err.Error())
+			e.errLabel.Show()
+			e.rebuild()
+			return
+		}
+	}
+	e.errLabel.Hide()
+	e.blockedBy = blockedBy
+	e.rebuild()
+}
+
+// RemoveBlocker removes the task with the given ID from the blockers
+func (e *DependencyEditor) RemoveBlocker(id string) {
+	for i, blockerID := range e.blockedBy {
+		if blockerID == id {
+			e.blockedBy = append(e.blockedBy[:i:i], e.blockedBy[i+1:]...)
+			break
+		}
+	}
+	e.errLabel.Hide()
+	e.rebuild()
+}
+
+// rebuild recreates the blocker rows and the picker options
+func (e *DependencyEditor) rebuild() {
+	titles := map[string]string{}
+	for _, t := range e.candidates {
+		titles[t.ID] = t.Title
+	}
+	blocked := map[string]bool{}
+	objects := make([]fyne.CanvasObject, len(e.blockedBy))
+	for i, id := range e.blockedBy {
+		blocked[id] = true
+		title, ok := titles[id]
+		if !ok {
+			title = "Unknown task"
+		}
+		remove := widget.NewButtonWithIcon("", theme.ContentRemoveIcon(), func() {
+			e.RemoveBlocker(id)
+		})
+		objects[i] = container.NewBorder(nil, nil, nil, remove, widget.NewLabel(title))
+	}
+	e.rows.Objects = objects
+	e.rows.Refresh()
+
+	var options []string
+	e.pickerIDs = nil
+	for _, t := range e.candidates {
+		if t.ID == e.taskID || blocked[t.ID] {
+			continue
+		}
+		options = append(options, t.Title)
+		e.pickerIDs = append(e.pickerIDs, t.ID)
+	}
+	e.picker.ClearSelected()
+	e.picker.SetOptions(options)
+}
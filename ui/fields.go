@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+
+	"eldar/model"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+// fieldInput edits the value of a custom field in the task detail form
+type fieldInput struct {
+	object fyne.CanvasObject
+	// value returns the edited value, which is empty when it is cleared or does not parse
+	value func() model.FieldValue
+}
+
+// newFieldInput creates the input of a custom field suited to its type, showing value
+func newFieldInput(field model.Field, value model.FieldValue) fieldInput {
+	switch field.Type {
+	case model.FieldNumber:
+		entry := widget.NewEntry()
+		entry.SetPlaceHolder("A number")
+		entry.SetText(field.Format(value))
+		entry.Validator = func(s string) error {
+			_, err := field.Parse(s)
+			return err
+		}
+		return fieldInput{object: entry, value: func() model.FieldValue {
+			v, _ := field.Parse(entry.Text)
+			return v
+		}}
+	case model.FieldDate:
+		entry := widget.NewDateEntry()
+		entry.SetDate(value.Date)
+		return fieldInput{object: entry, value: func() model.FieldValue {
+			if entry.Date == nil {
+				return model.FieldValue{}
+			}
+			day := dueDay(*entry.Date)
+			return model.FieldValue{Date: &day}
+		}}
+	case model.FieldSelect:
+		// Tapping the selected option again clears it
+		radio := widget.NewRadioGroup(field.Options, nil)
+		radio.Horizontal = true
+		if choices := knownChoices(field, value); len(choices) > 0 {
+			radio.SetSelected(choices[0])
+		}
+		return fieldInput{object: radio, value: func() model.FieldValue {
+			if radio.Selected == "" {
+				return model.FieldValue{}
+			}
+			return model.FieldValue{Choices: []string{radio.Selected}}
+		}}
+	case model.FieldMultiSelect:
+		group := widget.NewCheckGroup(field.Options, nil)
+		group.Horizontal = true
+		group.SetSelected(knownChoices(field, value))
+		return fieldInput{object: group, value: func() model.FieldValue {
+			// Keep the order of the options rather than the order they were ticked in
+			var choices []string
+			for _, option := range field.Options {
+				if slices.Contains(group.Selected, option) {
+					choices = append(choices, option)
+				}
+			}
+			return model.FieldValue{Choices: choices}
+		}}
+	default:
+		entry := widget.NewEntry()
+		entry.SetText(value.Text)
+		return fieldInput{object: entry, value: func() model.FieldValue {
+			return model.FieldValue{Text: strings.TrimSpace(entry.Text)}
+		}}
+	}
+}
+
+// knownChoices returns the choices of value that are still options of field, choices of removed options are cleared
+func knownChoices(field model.Field, value model.FieldValue) []string {
+	var choices []string
+	for _, choice := range value.Choices {
+		if slices.Contains(field.Options, choice) {
+			choices = append(choices, choice)
+		}
+	}
+	return choices
+}
+
+// labelOptions returns the names of the label set of a board followed by the labels of task that are not in it
+func labelOptions(board model.Board, task model.Task) []string {
+	options := make([]string, 0, len(board.Labels))
+	for _, l := range board.Labels {
+		options = append(options, l.Name)
+	}
+	for _, name := range task.Labels {
+		if _, ok := board.Label(name); !ok {
+			options = append(options, name)
+		}
+	}
+	return options
+}
+
+// selectedLabels returns the labels of a task spelt as in the label set of its board
+func selectedLabels(board model.Board, task model.Task) []string {
+	selected := make([]string, 0, len(task.Labels))
+	for _, name := range task.Labels {
+		if l, ok := board.Label(name); ok {
+			name = l.Name
+		}
+		selected = append(selected, name)
+	}
+	return selected
+}
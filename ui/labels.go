@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+
+	"eldar/model"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+)
+
+// maxCardLabels is the number of labels shown on a task card, the others are counted
+const maxCardLabels = 3
+
+// labelColor is a colour offered for labels
+type labelColor struct {
+	Name string
+	Hex  string
+}
+
+// labelColors are the colours offered for labels, in the order of the colour picker
+var labelColors = []labelColor{
+	{"Red", "#e53935"},
+	{"Orange", "#fb8c00"},
+	{"Yellow", "#fdd835"},
+	{"Green", "#43a047"},
+	{"Teal", "#00897b"},
+	{"Blue", "#1e88e5"},
+	{"Purple", "#8e24aa"},
+	{"Grey", "#757575"},
+}
+
+// unsetLabelColor colours labels that are not in the label set of their board
+var unsetLabelColor = color.NRGBA{R: 0x9e, G: 0x9e, B: 0x9e, A: 0xff}
+
+// parseLabelColor returns the colour of a "#rrggbb" string, or unsetLabelColor when it is not one
+func parseLabelColor(hex string) color.NRGBA {
+	if len(hex) != 7 || hex[0] != '#' {
+		return unsetLabelColor
+	}
+	rgb, err := strconv.ParseUint(hex[1:], 16, 32)
+	if err != nil {
+		return unsetLabelColor
+	}
+	return color.NRGBA{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 0xff}
+}
+
+// labelColorName returns the name of a colour of labelColors, or the colour itself
+func labelColorName(hex string) string {
+	for _, c := range labelColors {
+		if c.Hex == hex {
+			return c.Name
+		}
+	}
+	return hex
+}
+
+// newLabelChip creates a small rounded tag showing a label in its colour
+func newLabelChip(l model.Label) fyne.CanvasObject {
+	text := canvas.NewText(l.Name, color.White)
+	text.TextSize = theme.CaptionTextSize()
+	background := canvas.NewRectangle(parseLabelColor(l.Color))
+	background.CornerRadius = theme.InputRadiusSize()
+	padding := theme.InnerPadding() / 2
+	return container.NewStack(background, container.New(layout.NewCustomPaddedLayout(0, 0, padding, padding), text))
+}
+
+// newLabelChips creates the chips of the first labels, followed by the number of the others
+func newLabelChips(labels []model.Label) []fyne.CanvasObject {
+	chips := make([]fyne.CanvasObject, 0, min(len(labels), maxCardLabels+1))
+	for i, l := range labels {
+		if i == maxCardLabels {
+			more := canvas.NewText(fmt.Sprintf("+%d", len(labels)-maxCardLabels), theme.Color(theme.ColorNameForeground))
+			more.TextSize = theme.CaptionTextSize()
+			chips = append(chips, more)
+			break
+		}
+		chips = append(chips, newLabelChip(l))
+	}
+	return chips
+}
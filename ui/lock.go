@@ -0,0 +1,323 @@
+package ui
+
+import (
+	"errors"
+	"regexp"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+// pinRe matches a valid local unlock PIN
+var pinRe = regexp.MustCompile(`^[0-9]{4,12}$`)
+
+// ErrUsePassword is returned by an unlock function once too many wrong PINs were entered,
+// switching the unlock form to the account password
+var ErrUsePassword = errors.New("too many wrong PINs, unlock with your account password")
+
+// lockTimeoutOptions maps the auto-lock choices shown to the user to minutes, 0 disables auto-lock
+var lockTimeoutOptions = []struct {
+	label   string
+	minutes int
+}{
+	{"Never", 0},
+	{"1 minute", 1},
+	{"5 minutes", 5},
+	{"15 minutes", 15},
+	{"30 minutes", 30},
+	{"1 hour", 60},
+}
+
+// IdleMonitor calls a function once no activity has been reported for a given time.
+// It is safe for concurrent use; the callback runs on its own goroutine.
+type IdleMonitor struct {
+	mu      sync.Mutex
+	timeout time.Duration
+	timer   *time.Timer
+	paused  bool
+	onIdle  func()
+}
+
+// NewIdleMonitor creates and starts an IdleMonitor.
+// A timeout of zero or less disables it until SetTimeout is called.
+func NewIdleMonitor(timeout time.Duration, onIdle func()) *IdleMonitor {
+	m := &IdleMonitor{timeout: timeout, onIdle: onIdle}
+	m.reset()
+	return m
+}
+
+// Touch reports user activity, restarting the idle countdown
+func (m *IdleMonitor) Touch() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reset()
+}
+
+// SetTimeout changes the idle timeout and restarts the countdown
+func (m *IdleMonitor) SetTimeout(timeout time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.timeout = timeout
+	m.reset()
+}
+
+// Pause stops the countdown until Resume is called, e.g. while the window is locked
+func (m *IdleMonitor) Pause() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.paused = true
+	m.reset()
+}
+
+// Resume restarts the countdown after Pause
+func (m *IdleMonitor) Resume() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.paused = false
+	m.reset()
+}
+
+// reset stops the running timer and starts a new one if the monitor is active.
+// The caller must hold m.mu.
+func (m *IdleMonitor) reset() {
+	if m.timer != nil {
+		m.timer.Stop()
+		m.timer = nil
+	}
+	if m.paused || m.timeout <= 0 {
+		return
+	}
+	m.timer = time.AfterFunc(m.timeout, m.onIdle)
+}
+
+// ActivityTracker is a widget wrapping content that reports pointer movement and taps
+// over it to an activity callback, used to feed an IdleMonitor
+type ActivityTracker struct {
+	widget.BaseWidget
+	content    fyne.CanvasObject
+	onActivity func()
+}
+
+// NewActivityTracker wraps content so that pointer activity over it calls onActivity
+func NewActivityTracker(content fyne.CanvasObject, onActivity func()) *ActivityTracker {
+	t := &ActivityTracker{content: content, onActivity: onActivity}
+	t.ExtendBaseWidget(t)
+	return t
+}
+
+// CreateRenderer implements fyne.Widget
+func (t *ActivityTracker) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(t.content)
+}
+
+// MouseIn implements desktop.Hoverable
+func (t *ActivityTracker) MouseIn(*desktop.MouseEvent) {
+	t.onActivity()
+}
+
+// MouseMoved implements desktop.Hoverable
+func (t *ActivityTracker) MouseMoved(*desktop.MouseEvent) {
+	t.onActivity()
+}
+
+// MouseOut implements desktop.Hoverable
+func (t *ActivityTracker) MouseOut() {}
+
+// Tapped implements fyne.Tappable
+func (t *ActivityTracker) Tapped(*fyne.PointEvent) {
+	t.onActivity()
+}
+
+// typing is what the focused object of a canvas looked like when last checked
+type typing struct {
+	focused fyne.Focusable
+	text    string
+	row     int
+	column  int
+}
+
+// typingWatcher notices typing into the focused entry of a canvas
+type typingWatcher struct {
+	canvas     fyne.Canvas
+	onActivity func()
+	last       typing
+}
+
+// check reports activity when the focus moved or the text or cursor of the focused entry changed since the last check
+func (t *typingWatcher) check() {
+	now := typing{focused: t.canvas.Focused()}
+	var entry *widget.Entry
+	switch o := now.focused.(type) {
+	case *widget.Entry:
+		entry = o
+	case *widget.SelectEntry:
+		entry = &o.Entry
+	case *widget.DateEntry:
+		entry = &o.Entry
+	}
+	if entry != nil {
+		now.text, now.row, now.column = entry.Text, entry.CursorRow, entry.CursorColumn
+	}
+	if now != t.last && now.focused != nil {
+		t.onActivity()
+	}
+	t.last = now
+}
+
+// WatchTyping reports typing into the focused entry of c to onActivity, checking every interval for the
+// lifetime of the app. Typed keys only reach the handlers of the canvas while nothing has focus, so
+// writing a long description or comment would otherwise look like inactivity.
+func WatchTyping(c fyne.Canvas, interval time.Duration, onActivity func()) {
+	t := &typingWatcher{canvas: c, onActivity: onActivity}
+	go func() {
+		for range time.Tick(interval) {
+			fyne.Do(t.check)
+		}
+	}()
+}
+
+// MakeUnlockForm creates the form shown while the window is locked.
+// The user unlocks with the local PIN, when one is set, or the account password.
+// Once unlock returns ErrUsePassword the PIN can no longer be chosen.
+//
+// Parameters:
+//   - hasPIN: Whether a local PIN is set, offering it as the default unlock method
+//   - unlock: A function checking the secret, called in the background; usePIN tells which method was chosen
+//   - onUnlocked: A function to call once the secret was accepted
+//   - onError: A function called with the error when the secret was rejected
+//
+// Returns:
+//   - A configured widget.Form ready to be displayed
+func MakeUnlockForm(hasPIN bool, unlock func(secret string, usePIN bool) error, onUnlocked func(), onError func(error)) *widget.Form {
+	form := widget.NewForm()
+	method := widget.NewRadioGroup([]string{"PIN", "Account password"}, nil)
+	method.Horizontal = true
+	method.Required = true
+	secretInput := widget.NewPasswordEntry()
+	method.OnChanged = func(s string) {
+		secretInput.SetText("")
+		if s == "PIN" {
+			secretInput.SetPlaceHolder("Enter your PIN")
+		} else {
+			secretInput.SetPlaceHolder("Enter your account password")
+		}
+	}
+	if hasPIN {
+		form.AppendItem(widget.NewFormItem("Unlock with", method))
+		method.SetSelected("PIN")
+	} else {
+		method.SetSelected("Account password")
+	}
+	form.AppendItem(widget.NewFormItem("Secret", secretInput))
+	form.SubmitText = "Unlock"
+	form.OnSubmit = func() {
+		secret, usePIN := secretInput.Text, method.Selected == "PIN"
+		form.Disable()
+		go func() {
+			err := unlock(secret, usePIN)
+			fyne.Do(func() {
+				form.Enable()
+				secretInput.SetText("")
+				if err != nil {
+					if errors.Is(err, ErrUsePassword) {
+						method.SetSelected("Account password")
+						method.Disable()
+					}
+					onError(err)
+					return
+				}
+				onUnlocked()
+			})
+		}()
+	}
+	return form
+}
+
+// LockSettings holds the auto-lock preferences chosen by the user
+type LockSettings struct {
+	// TimeoutMinutes is the idle time before the window locks, 0 disables auto-lock
+	TimeoutMinutes int
+	// PIN is the new local unlock PIN, empty keeps the current one
+	PIN string
+	// RemovePIN removes the local unlock PIN so only the account password unlocks
+	RemovePIN bool
+}
+
+// MakeLockSettingsForm creates a form to configure the idle timeout and local unlock PIN.
+//
+// Parameters:
+//   - timeoutMinutes: The currently configured idle timeout in minutes
+//   - hasPIN: Whether a local PIN is currently set
+//   - onSave: A function to call with the chosen settings when the form is submitted
+//
+// Returns:
+//   - A configured widget.Form ready to be displayed
+func MakeLockSettingsForm(timeoutMinutes int, hasPIN bool, onSave func(LockSettings)) *widget.Form {
+	form := widget.NewForm()
+	labels := make([]string, len(lockTimeoutOptions))
+	for i, option := range lockTimeoutOptions {
+		labels[i] = option.label
+	}
+	timeoutSelect := widget.NewSelect(labels, nil)
+	timeoutSelect.SetSelected(lockTimeoutLabel(timeoutMinutes))
+	form.AppendItem(widget.NewFormItem("Lock after", timeoutSelect))
+
+	pinInput := widget.NewPasswordEntry()
+	pinInput.SetPlaceHolder("4 to 12 digits")
+	if hasPIN {
+		pinInput.SetPlaceHolder("Leave empty to keep the current PIN")
+	}
+	pinInput.Validator = func(s string) error {
+		if s != "" && !pinRe.MatchString(s) {
+			return errors.New("PIN must be 4 to 12 digits")
+		}
+		return nil
+	}
+	form.AppendItem(widget.NewFormItem("PIN", pinInput))
+	removePIN := widget.NewCheck("Remove PIN", nil)
+	if !hasPIN {
+		removePIN.Disable()
+	}
+	form.AppendItem(widget.NewFormItem("", removePIN))
+
+	form.SubmitText = "Save"
+	form.OnSubmit = func() {
+		settings := LockSettings{TimeoutMinutes: timeoutMinutes, PIN: pinInput.Text, RemovePIN: removePIN.Checked}
+		for _, option := range lockTimeoutOptions {
+			if option.label == timeoutSelect.Selected {
+				settings.TimeoutMinutes = option.minutes
+			}
+		}
+		onSave(settings)
+	}
+	return form
+}
+
+// lockTimeoutLabel returns the option label for the given timeout in minutes,
+// or an empty string when the timeout is not one of the options
+func lockTimeoutLabel(minutes int) string {
+	for _, option := range lockTimeoutOptions {
+		if option.minutes == minutes {
+			return option.label
+		}
+	}
+	return ""
+}
+
+// MakeSecurityMenu creates the Security menu shown in the main menu while signed in.
+//
+// Parameters:
+//   - onLockNow: A function to call when the user locks the window immediately
+//   - onLockSettings: A function to call when the user opens the auto-lock settings
+//
+// Returns:
+//   - A configured fyne.Menu ready to be added to the main menu
+func MakeSecurityMenu(onLockNow func(), onLockSettings func()) *fyne.Menu {
+	return fyne.NewMenu("Security",
+		fyne.NewMenuItem("Lock now", onLockNow),
+		fyne.NewMenuItem("Lock settings...", onLockSettings),
+	)
+}
@@ -0,0 +1,184 @@
+package ui
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdleMonitor(t *testing.T) {
+	var idle atomic.Int32
+	m := NewIdleMonitor(50*time.Millisecond, func() {
+		idle.Add(1)
+	})
+
+	// Activity keeps postponing the callback
+	for i := 0; i < 4; i++ {
+		time.Sleep(20 * time.Millisecond)
+		m.Touch()
+	}
+	assert.Equal(t, int32(0), idle.Load())
+	assert.Eventually(t, func() bool { return idle.Load() == 1 }, time.Second, 5*time.Millisecond)
+
+	// Paused monitors never fire
+	m.Pause()
+	m.Touch()
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int32(1), idle.Load())
+
+	// A zero timeout disables the monitor
+	m.Resume()
+	m.SetTimeout(0)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int32(1), idle.Load())
+}
+
+func TestActivityTracker(t *testing.T) {
+	test.NewTempApp(t)
+	activity := 0
+	tracker := NewActivityTracker(widget.NewLabel("content"), func() {
+		activity++
+	})
+	tracker.MouseMoved(nil)
+	test.Tap(tracker)
+	assert.Equal(t, 2, activity)
+}
+
+func TestMakeUnlockForm(t *testing.T) {
+	test.NewTempApp(t)
+	var unlocked atomic.Bool
+	var gotPIN atomic.Bool
+	var reported atomic.Value
+	form := MakeUnlockForm(true, func(secret string, usePIN bool) error {
+		gotPIN.Store(usePIN)
+		if secret != "2468" {
+			return errors.New("wrong PIN")
+		}
+		return nil
+	}, func() {
+		unlocked.Store(true)
+	}, func(err error) {
+		reported.Store(err)
+	})
+	assert.Equal(t, 2, len(form.Items))
+	assert.Equal(t, "Unlock", form.SubmitText)
+	method := form.Items[0].Widget.(*widget.RadioGroup)
+	assert.Equal(t, "PIN", method.Selected)
+
+	secretEntry := form.Items[1].Widget.(*widget.Entry)
+	assert.True(t, secretEntry.Password)
+	assert.Equal(t, "Enter your PIN", secretEntry.PlaceHolder)
+
+	test.Type(secretEntry, "1111")
+	form.OnSubmit()
+	assert.Eventually(t, func() bool { return reported.Load() != nil }, time.Second, 10*time.Millisecond)
+	assert.EqualError(t, reported.Load().(error), "wrong PIN")
+	assert.False(t, unlocked.Load())
+	assert.True(t, gotPIN.Load())
+	assert.Empty(t, secretEntry.Text)
+
+	test.Type(secretEntry, "2468")
+	form.OnSubmit()
+	assert.Eventually(t, unlocked.Load, time.Second, 10*time.Millisecond)
+}
+
+func TestMakeUnlockFormWithoutPIN(t *testing.T) {
+	test.NewTempApp(t)
+	form := MakeUnlockForm(false, func(string, bool) error { return nil }, func() {}, func(error) {})
+	assert.Equal(t, 1, len(form.Items))
+	assert.Equal(t, "Enter your account password", form.Items[0].Widget.(*widget.Entry).PlaceHolder)
+}
+
+func TestMakeLockSettingsForm(t *testing.T) {
+	test.NewTempApp(t)
+	var saved LockSettings
+	form := MakeLockSettingsForm(15, false, func(settings LockSettings) {
+		saved = settings
+	})
+	assert.Equal(t, 3, len(form.Items))
+	timeoutSelect := form.Items[0].Widget.(*widget.Select)
+	assert.Equal(t, "15 minutes", timeoutSelect.Selected)
+
+	pinEntry := form.Items[1].Widget.(*widget.Entry)
+	assert.Error(t, pinEntry.Validator("12"))
+	assert.Error(t, pinEntry.Validator("abcd"))
+	assert.Nil(t, pinEntry.Validator(""))
+	assert.Nil(t, pinEntry.Validator("2468"))
+	assert.True(t, form.Items[2].Widget.(*widget.Check).Disabled())
+
+	timeoutSelect.SetSelected("5 minutes")
+	test.Type(pinEntry, "2468")
+	form.OnSubmit()
+	assert.Equal(t, LockSettings{TimeoutMinutes: 5, PIN: "2468"}, saved)
+}
+
+func TestMakeSecurityMenu(t *testing.T) {
+	lockNowCalled := false
+	menu := MakeSecurityMenu(func() {
+		lockNowCalled = true
+	}, func() {})
+	assert.Equal(t, "Security", menu.Label)
+	assert.Equal(t, 2, len(menu.Items))
+	menu.Items[0].Action()
+	assert.True(t, lockNowCalled)
+	assert.Equal(t, "Lock settings...", menu.Items[1].Label)
+}
+
+func TestTypingWatcher(t *testing.T) {
+	test.NewTempApp(t)
+	entry := widget.NewEntry()
+	other := widget.NewEntry()
+	w := test.NewWindow(container.NewVBox(entry, other))
+	defer w.Close()
+	activity := 0
+	watcher := &typingWatcher{canvas: w.Canvas(), onActivity: func() {
+		activity++
+	}}
+
+	// Nothing focused is no activity
+	watcher.check()
+	assert.Zero(t, activity)
+
+	w.Canvas().Focus(entry)
+	watcher.check()
+	assert.Equal(t, 1, activity)
+	watcher.check()
+	assert.Equal(t, 1, activity)
+
+	// Typed keys do not reach the canvas while an entry has focus, the text changing tells
+	test.Type(entry, "A long description")
+	watcher.check()
+	assert.Equal(t, 2, activity)
+	watcher.check()
+	assert.Equal(t, 2, activity)
+
+	w.Canvas().Focus(other)
+	watcher.check()
+	assert.Equal(t, 3, activity)
+}
+
+func TestMakeUnlockFormUsePassword(t *testing.T) {
+	test.NewTempApp(t)
+	var reported atomic.Value
+	form := MakeUnlockForm(true, func(secret string, usePIN bool) error {
+		if usePIN {
+			return ErrUsePassword
+		}
+		return errors.New("wrong password")
+	}, func() {}, func(err error) {
+		reported.Store(err)
+	})
+	method := form.Items[0].Widget.(*widget.RadioGroup)
+	test.Type(form.Items[1].Widget.(*widget.Entry), "1111")
+	form.OnSubmit()
+	assert.Eventually(t, func() bool { return reported.Load() != nil }, time.Second, 10*time.Millisecond)
+	assert.ErrorIs(t, reported.Load().(error), ErrUsePassword)
+	assert.Equal(t, "Account password", method.Selected)
+	assert.True(t, method.Disabled())
+}
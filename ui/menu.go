@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+)
+
+// MakeAccountMenu creates the Account menu shown in the main menu while signed in.
+//
+// Parameters:
+//   - onLogout: A function to call when the user signs out of this device
+//   - onLogoutAll: A function to call when the user signs out of all sessions
+//
+// Returns:
+//   - A configured fyne.Menu ready to be added to the main menu
+func MakeAccountMenu(onLogout func(), onLogoutAll func()) *fyne.Menu {
+	return fyne.NewMenu("Account",
+		fyne.NewMenuItem("Logout", onLogout),
+		fyne.NewMenuItem("Sign out all sessions", onLogoutAll),
+	)
+}
+
+// UndoShortcut and RedoShortcut undo and redo the last change of the current board
+var (
+	UndoShortcut = &desktop.CustomShortcut{KeyName: fyne.KeyZ, Modifier: fyne.KeyModifierShortcutDefault}
+	RedoShortcut = &desktop.CustomShortcut{KeyName: fyne.KeyZ, Modifier: fyne.KeyModifierShortcutDefault | fyne.KeyModifierShift}
+)
+
+// MakeTasksMenu creates the Tasks menu shown in the main menu while signed in.
+//
+// Parameters:
+//   - onNewBoard: A function to call to create a board from a template
+//   - onNewTask: A function to call to create a task from a template on the current board
+//   - onUndo: A function to call to undo the last change of the current board
+//   - onRedo: A function to call to redo the last undone change of the current board
+//   - onExport: A function to call to export the tasks on screen as CSV
+//   - onReminderSettings: A function to call to configure due-date reminders
+//
+// Returns:
+//   - A configured fyne.Menu ready to be added to the main menu
+func MakeTasksMenu(onNewBoard, onNewTask, onUndo, onRedo, onExport, onReminderSettings func()) *fyne.Menu {
+	undo := fyne.NewMenuItem("Undo", onUndo)
+	undo.Shortcut = UndoShortcut
+	redo := fyne.NewMenuItem("Redo", onRedo)
+	redo.Shortcut = RedoShortcut
+	return fyne.NewMenu("Tasks",
+		fyne.NewMenuItem("New board...", onNewBoard),
+		fyne.NewMenuItem("New task from template...", onNewTask),
+		fyne.NewMenuItemSeparator(),
+		undo,
+		redo,
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem("Export tasks as CSV...", onExport),
+		fyne.NewMenuItem("Reminder settings...", onReminderSettings),
+	)
+}
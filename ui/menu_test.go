@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMakeAccountMenu(t *testing.T) {
+	logoutCalled, logoutAllCalled := false, false
+	menu := MakeAccountMenu(func() {
+		logoutCalled = true
+	}, func() {
+		logoutAllCalled = true
+	})
+	assert.Equal(t, "Account", menu.Label)
+	assert.Equal(t, 2, len(menu.Items))
+
+	assert.Equal(t, "Logout", menu.Items[0].Label)
+	menu.Items[0].Action()
+	assert.True(t, logoutCalled)
+	assert.False(t, logoutAllCalled)
+
+	assert.Equal(t, "Sign out all sessions", menu.Items[1].Label)
+	menu.Items[1].Action()
+	assert.True(t, logoutAllCalled)
+}
+
+func TestMakeTasksMenu(t *testing.T) {
+	var called []string
+	menu := MakeTasksMenu(func() {
+		called = append(called, "board")
+	}, func() {
+		called = append(called, "task")
+	}, func() {
+		called = append(called, "undo")
+	}, func() {
+		called = append(called, "redo")
+	}, func() {
+		called = append(called, "export")
+	}, func() {
+		called = append(called, "reminders")
+	})
+	assert.Equal(t, "Tasks", menu.Label)
+	assert.Equal(t, 8, len(menu.Items))
+	assert.Equal(t, "New board...", menu.Items[0].Label)
+	assert.Equal(t, "New task from template...", menu.Items[1].Label)
+	assert.True(t, menu.Items[2].IsSeparator)
+	assert.Equal(t, "Undo", menu.Items[3].Label)
+	assert.Equal(t, UndoShortcut, menu.Items[3].Shortcut)
+	assert.Equal(t, "Redo", menu.Items[4].Label)
+	assert.True(t, menu.Items[5].IsSeparator)
+	assert.Equal(t, "Export tasks as CSV...", menu.Items[6].Label)
+	assert.Equal(t, "Reminder settings...", menu.Items[7].Label)
+	for _, i := range []int{0, 1, 3, 4, 6, 7} {
+		menu.Items[i].Action()
+	}
+	assert.Equal(t, []string{"board", "task", "undo", "redo", "export", "reminders"}, called)
+}
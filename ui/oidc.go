@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"context"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+// oidcSignInTimeout bounds how long the user has to complete sign-in in the browser
+const oidcSignInTimeout = 5 * time.Minute
+
+// MakeOIDCLoginButton creates a button that signs in through the company identity provider.
+// The sign-in runs in the background while the user completes it in the browser, and the
+// button is disabled until it finishes.
+//
+// Parameters:
+//   - ap: A pointer to the current AppPage, which will be updated after a successful sign-in
+//   - updateWindow: A function to call when the app page changes to update the window content
+//   - signIn: A function running the OpenID Connect flow and storing the resulting credentials
+//   - onError: A function called with the error when sign-in fails
+//
+// Returns:
+//   - A configured widget.Button ready to be displayed
+func MakeOIDCLoginButton(ap *AppPage, updateWindow func(), signIn func(ctx context.Context) error, onError func(error)) *widget.Button {
+	var button *widget.Button
+	button = widget.NewButton("Sign in with SSO", func() {
+		button.Disable()
+		button.SetText("Waiting for identity provider...")
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), oidcSignInTimeout)
+			defer cancel()
+			err := signIn(ctx)
+			fyne.Do(func() {
+				button.SetText("Sign in with SSO")
+				button.Enable()
+				if err != nil {
+					onError(err)
+					return
+				}
+				*ap = Boards
+				updateWindow()
+			})
+		}()
+	})
+	return button
+}
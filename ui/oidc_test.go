@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMakeOIDCLoginButton(t *testing.T) {
+	test.NewTempApp(t)
+	ap := Login
+	var updateWindowCalled atomic.Bool
+	button := MakeOIDCLoginButton(&ap, func() {
+		updateWindowCalled.Store(true)
+	}, func(ctx context.Context) error {
+		return nil
+	}, func(err error) {
+		t.Errorf("unexpected error: %v", err)
+	})
+	assert.Equal(t, "Sign in with SSO", button.Text)
+
+	test.Tap(button)
+	assert.Eventually(t, updateWindowCalled.Load, time.Second, 10*time.Millisecond)
+	assert.Equal(t, Boards, ap)
+	assert.False(t, button.Disabled())
+}
+
+func TestMakeOIDCLoginButtonError(t *testing.T) {
+	test.NewTempApp(t)
+	ap := Login
+	var reported atomic.Value
+	button := MakeOIDCLoginButton(&ap, func() {
+		t.Errorf("updateWindow must not be called on failure")
+	}, func(ctx context.Context) error {
+		return errors.New("access_denied")
+	}, func(err error) {
+		reported.Store(err)
+	})
+
+	test.Tap(button)
+	assert.Eventually(t, func() bool { return reported.Load() != nil }, time.Second, 10*time.Millisecond)
+	assert.EqualError(t, reported.Load().(error), "access_denied")
+	assert.Equal(t, Login, ap)
+}
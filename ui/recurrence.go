@@ -0,0 +1,118 @@
+package ui
+
+import (
+	"eldar/rrule"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// customRecurrence is the picker option revealing an entry for any supported RRULE
+const customRecurrence = "Custom…"
+
+// recurrencePresets maps the common repeat patterns shown to the user to their RRULE,
+// an empty rule meaning the task does not repeat. Weekly, monthly and yearly rules repeat
+// on the weekday or date of the due date.
+var recurrencePresets = []struct {
+	label string
+	rule  string
+}{
+	{"Does not repeat", ""},
+	{"Daily", "FREQ=DAILY"},
+	{"Every weekday", "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR"},
+	{"Weekly", "FREQ=WEEKLY"},
+	{"Every 2 weeks", "FREQ=WEEKLY;INTERVAL=2"},
+	{"Monthly", "FREQ=MONTHLY"},
+	{"Monthly on the last day", "FREQ=MONTHLY;BYMONTHDAY=-1"},
+	{"Yearly", "FREQ=YEARLY"},
+}
+
+// RecurrencePicker chooses how a task repeats, from common patterns or a custom RRULE
+type RecurrencePicker struct {
+	widget.BaseWidget
+
+	choice *widget.Select
+	custom *widget.Entry
+
+	onValidationChanged func(error)
+}
+
+// NewRecurrencePicker creates a picker showing rule, which may be empty for a task that does not repeat
+func NewRecurrencePicker(rule string) *RecurrencePicker {
+	p := &RecurrencePicker{}
+	options := make([]string, 0, len(recurrencePresets)+1)
+	for _, preset := range recurrencePresets {
+		options = append(options, preset.label)
+	}
+	options = append(options, customRecurrence)
+
+	p.custom = widget.NewEntry()
+	p.custom.SetPlaceHolder("FREQ=MONTHLY;BYDAY=-1FR")
+	p.custom.Validator = func(s string) error {
+		_, err := rrule.Parse(s)
+		return err
+	}
+	p.choice = widget.NewSelect(options, func(selected string) {
+		if selected == customRecurrence {
+			p.custom.Show()
+		} else {
+			p.custom.Hide()
+		}
+		if p.onValidationChanged != nil {
+			p.onValidationChanged(p.Validate())
+		}
+	})
+	p.custom.SetOnValidationChanged(func(error) {
+		if p.onValidationChanged != nil {
+			p.onValidationChanged(p.Validate())
+		}
+	})
+	p.custom.Hide()
+
+	p.choice.SetSelected(customRecurrence)
+	p.custom.SetText(rule)
+	if r, err := rrule.Parse(rule); err == nil {
+		rule = r.String()
+	}
+	for _, preset := range recurrencePresets {
+		if preset.rule == rule {
+			p.choice.SetSelected(preset.label)
+			p.custom.SetText("")
+		}
+	}
+	p.ExtendBaseWidget(p)
+	return p
+}
+
+// CreateRenderer implements fyne.Widget
+func (p *RecurrencePicker) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(container.NewVBox(p.choice, p.custom))
+}
+
+// Rule returns the chosen RRULE, or an empty string when the task does not repeat
+func (p *RecurrencePicker) Rule() string {
+	if p.choice.Selected == customRecurrence {
+		return p.custom.Text
+	}
+	for _, preset := range recurrencePresets {
+		if preset.label == p.choice.Selected {
+			return preset.rule
+		}
+	}
+	return ""
+}
+
+// SetOnValidationChanged implements fyne.Validatable
+func (p *RecurrencePicker) SetOnValidationChanged(fn func(error)) {
+	p.onValidationChanged = fn
+}
+
+// Validate implements fyne.Validatable, a custom rule must be supported
+func (p *RecurrencePicker) Validate() error {
+	if p.choice.Selected != customRecurrence {
+		return nil
+	}
+	// Entry.Validate reports to its validation callback, which calls back here
+	_, err := rrule.Parse(p.custom.Text)
+	return err
+}
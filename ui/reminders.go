@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"time"
+
+	"eldar/reminder"
+	"fyne.io/fyne/v2/widget"
+)
+
+// reminderLeadOptions are the lead times offered for due-date reminders
+var reminderLeadOptions = []time.Duration{
+	15 * time.Minute,
+	time.Hour,
+	24 * time.Hour,
+	7 * 24 * time.Hour,
+}
+
+// MakeReminderSettingsForm creates a form to choose how long before due dates reminders are shown.
+//
+// Parameters:
+//   - leadTimes: The currently configured lead times, lead times that are not an option are kept
+//   - onSave: A function to call with the chosen lead times when the form is submitted, none disabling reminders
+//
+// Returns:
+//   - A configured widget.Form ready to be displayed
+func MakeReminderSettingsForm(leadTimes []time.Duration, onSave func([]time.Duration)) *widget.Form {
+	form := widget.NewForm()
+	labels := make([]string, len(reminderLeadOptions))
+	for i, lead := range reminderLeadOptions {
+		labels[i] = reminder.FormatLeadTime(lead) + " before"
+	}
+	var selected, custom []time.Duration
+	for _, lead := range leadTimes {
+		if isReminderLeadOption(lead) {
+			selected = append(selected, lead)
+		} else {
+			custom = append(custom, lead)
+		}
+	}
+
+	leadGroup := widget.NewCheckGroup(labels, nil)
+	for i, lead := range reminderLeadOptions {
+		for _, s := range selected {
+			if s == lead {
+				leadGroup.Selected = append(leadGroup.Selected, labels[i])
+			}
+		}
+	}
+	form.AppendItem(widget.NewFormItem("Remind me", leadGroup))
+
+	form.SubmitText = "Save"
+	form.OnSubmit = func() {
+		chosen := append([]time.Duration{}, custom...)
+		for i, lead := range reminderLeadOptions {
+			for _, label := range leadGroup.Selected {
+				if label == labels[i] {
+					chosen = append(chosen, lead)
+				}
+			}
+		}
+		onSave(chosen)
+	}
+	return form
+}
+
+// isReminderLeadOption reports whether lead is one of the offered lead times
+func isReminderLeadOption(lead time.Duration) bool {
+	for _, option := range reminderLeadOptions {
+		if option == lead {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMakeReminderSettingsForm(t *testing.T) {
+	test.NewTempApp(t)
+	var saved []time.Duration
+	form := MakeReminderSettingsForm([]time.Duration{24 * time.Hour, 90 * time.Minute}, func(leadTimes []time.Duration) {
+		saved = leadTimes
+	})
+	assert.Equal(t, 1, len(form.Items))
+	group := form.Items[0].Widget.(*widget.CheckGroup)
+	assert.Equal(t, []string{"15 minutes before", "1 hour before", "1 day before", "1 week before"}, group.Options)
+	assert.Equal(t, []string{"1 day before"}, group.Selected)
+
+	// Lead times configured outside the options are kept
+	group.SetSelected([]string{"1 hour before", "1 week before"})
+	form.OnSubmit()
+	assert.Equal(t, []time.Duration{90 * time.Minute, time.Hour, 7 * 24 * time.Hour}, saved)
+
+	group.SetSelected(nil)
+	form.OnSubmit()
+	assert.Equal(t, []time.Duration{90 * time.Minute}, saved)
+}
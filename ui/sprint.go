@@ -0,0 +1,245 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"eldar/model"
+	"eldar/store"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// noSprint is the sprint option of tasks that are not planned into a sprint
+const noSprint = "No sprint"
+
+// SprintPage charts the progress of a sprint or milestone of a board as a burndown or burnup chart,
+// worked out from the activity log of the board, and lists the tasks planned into it with their estimates.
+// It reads from the local cache, so it works offline.
+type SprintPage struct {
+	widget.BaseWidget
+	store    *store.Store
+	openTask func(model.Task)
+
+	board     *widget.Select
+	sprint    *widget.Select
+	mode      *widget.RadioGroup
+	chart     *BurnChart
+	summary   *widget.Label
+	list      *fyne.Container
+	boardIDs  []string
+	sprintIDs []string
+}
+
+// NewSprintPage creates the Sprints page showing the current sprint of the first board with sprints.
+//
+// Parameters:
+//   - st: The cache the page is rendered from
+//   - openTask: A function to call when a task of the sprint is tapped
+//
+// Returns:
+//   - A SprintPage, which must be reloaded with Reload after the cache changes
+func NewSprintPage(st *store.Store, openTask func(model.Task)) *SprintPage {
+	p := &SprintPage{store: st, openTask: openTask, chart: NewBurnChart(), list: container.NewVBox()}
+	p.summary = widget.NewLabel("")
+	p.summary.Wrapping = fyne.TextWrapWord
+	p.board = widget.NewSelect(nil, func(string) {
+		p.showSprints("")
+	})
+	p.sprint = widget.NewSelect(nil, func(string) {
+		p.showSprint()
+	})
+	p.mode = widget.NewRadioGroup([]string{BurnDown.String(), BurnUp.String()}, func(s string) {
+		if s == BurnUp.String() {
+			p.chart.SetMode(BurnUp)
+		} else {
+			p.chart.SetMode(BurnDown)
+		}
+	})
+	p.mode.Horizontal = true
+	p.mode.Required = true
+	p.mode.SetSelected(BurnDown.String())
+	p.ExtendBaseWidget(p)
+	p.Reload()
+	return p
+}
+
+// CreateRenderer implements fyne.Widget
+func (p *SprintPage) CreateRenderer() fyne.WidgetRenderer {
+	top := container.NewBorder(nil, nil, container.NewHBox(p.board, p.sprint), p.mode)
+	content := container.NewVBox(p.summary, p.chart, widget.NewSeparator(), p.list)
+	return widget.NewSimpleRenderer(container.NewBorder(top, nil, nil, nil, container.NewVScroll(content)))
+}
+
+// Reload rebuilds the page from the cache, keeping the board and sprint picked when they still exist
+func (p *SprintPage) Reload() {
+	boardID, sprintID := p.selectedBoard(), p.selectedSprint()
+	p.boardIDs = nil
+	var names []string
+	for _, b := range p.store.Boards() {
+		if len(b.Sprints) > 0 {
+			p.boardIDs = append(p.boardIDs, b.ID)
+			names = append(names, b.Name)
+		}
+	}
+	// Select fires OnChanged, so the sprints are shown once the board is set
+	onBoard := p.board.OnChanged
+	p.board.OnChanged = nil
+	p.board.SetOptions(names)
+	if i := slices.Index(p.boardIDs, boardID); i >= 0 {
+		p.board.SetSelectedIndex(i)
+	} else if len(p.boardIDs) > 0 {
+		p.board.SetSelectedIndex(0)
+		sprintID = ""
+	} else {
+		p.board.ClearSelected()
+	}
+	p.board.OnChanged = onBoard
+	p.showSprints(sprintID)
+}
+
+// showSprints lists the sprints of the board picked and shows the one with the given ID,
+// or the current sprint of the board when it has none with that ID
+func (p *SprintPage) showSprints(sprintID string) {
+	board, _ := p.store.Board(p.selectedBoard())
+	p.sprintIDs = nil
+	names := make([]string, len(board.Sprints))
+	for i, s := range board.Sprints {
+		p.sprintIDs = append(p.sprintIDs, s.ID)
+		names[i] = s.Name
+	}
+	onSprint := p.sprint.OnChanged
+	p.sprint.OnChanged = nil
+	p.sprint.SetOptions(names)
+	i := slices.Index(p.sprintIDs, sprintID)
+	if i < 0 {
+		i = currentSprint(board.Sprints, time.Now())
+	}
+	if i >= 0 {
+		p.sprint.SetSelectedIndex(i)
+	} else {
+		p.sprint.ClearSelected()
+	}
+	p.sprint.OnChanged = onSprint
+	p.showSprint()
+}
+
+// showSprint charts the progress of the sprint picked and lists its tasks
+func (p *SprintPage) showSprint() {
+	burn, err := p.store.Burn(p.selectedBoard(), p.selectedSprint(), time.Now())
+	if err != nil {
+		p.chart.Hide()
+		p.summary.SetText("No sprints yet, add them in the settings of a board")
+		p.list.Objects = nil
+		p.list.Refresh()
+		return
+	}
+	p.chart.SetBurn(burn)
+	p.chart.Show()
+	p.summary.SetText(burnSummary(burn, time.Now()))
+
+	var objects []fyne.CanvasObject
+	for _, t := range p.store.Tasks(p.selectedBoard()) {
+		if t.SprintID == burn.Sprint.ID && t.ParentID == "" {
+			objects = append(objects, p.makeRow(t))
+		}
+	}
+	if len(objects) == 0 {
+		objects = append(objects, widget.NewLabel("No tasks planned into this sprint"))
+	}
+	p.list.Objects = objects
+	p.list.Refresh()
+}
+
+// makeRow creates the row of a task of the sprint, with its estimate and whether it is done
+func (p *SprintPage) makeRow(task model.Task) fyne.CanvasObject {
+	open := widget.NewButton(task.Title, func() {
+		p.openTask(task)
+	})
+	open.Alignment = widget.ButtonAlignLeading
+	if task.Done {
+		open.Icon = theme.ConfirmIcon()
+	}
+	estimate := "Not estimated"
+	if task.Estimate > 0 {
+		estimate = formatEstimate(task.Estimate) + " points"
+	}
+	return container.NewBorder(nil, nil, nil, widget.NewLabel(estimate), open)
+}
+
+// selectedBoard returns the ID of the board picked, or an empty string when no board has sprints
+func (p *SprintPage) selectedBoard() string {
+	if i := p.board.SelectedIndex(); i >= 0 && i < len(p.boardIDs) {
+		return p.boardIDs[i]
+	}
+	return ""
+}
+
+// selectedSprint returns the ID of the sprint picked, or an empty string when the board has no sprints
+func (p *SprintPage) selectedSprint() string {
+	if i := p.sprint.SelectedIndex(); i >= 0 && i < len(p.sprintIDs) {
+		return p.sprintIDs[i]
+	}
+	return ""
+}
+
+// currentSprint returns the index of the sprint running on the day of now, or of the last one to have started
+// when none is running, or of the first one when none has started yet. It returns -1 when there are no sprints.
+func currentSprint(sprints []model.Sprint, now time.Time) int {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	current := -1
+	for i, s := range sprints {
+		if !s.Start.After(today) && (current < 0 || s.Start.After(sprints[current].Start)) {
+			current = i
+		}
+	}
+	if current < 0 && len(sprints) > 0 {
+		current = 0
+	}
+	return current
+}
+
+// burnSummary describes the progress of a sprint and the time left on the day of now,
+// e.g. "8 of 13 points done · 4 days left"
+func burnSummary(burn model.Burn, now time.Time) string {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	sprint := burn.Sprint
+	if today.Before(sprint.Start) {
+		return fmt.Sprintf("Starts %s · %d days", sprint.Start.Format("2 Jan 2006"), sprint.Days())
+	}
+	last := burn.Days[len(burn.Days)-1]
+	summary := fmt.Sprintf("%s of %s %s done", formatEstimate(last.Completed), formatEstimate(last.Scope), burn.Unit)
+	switch left := int(sprint.End.Sub(today).Hours()/24) + 1; {
+	case left <= 0:
+		return summary + " · ended " + sprint.End.Format("2 Jan 2006")
+	case left == 1:
+		return summary + " · last day"
+	default:
+		return fmt.Sprintf("%s · %d days left", summary, left)
+	}
+}
+
+// formatEstimate formats an estimate or amount of points without trailing zeros
+func formatEstimate(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// parseEstimate parses an estimate typed in the task detail form, an empty string clearing it
+func parseEstimate(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v < 0 || math.IsInf(v, 0) || math.IsNaN(v) {
+		return 0, errors.New("estimate must be a positive number of points")
+	}
+	return v, nil
+}
@@ -0,0 +1,117 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"eldar/model"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSprintPage(t *testing.T) {
+	test.NewTempApp(t)
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	past := model.Sprint{ID: "s1", Name: "Sprint 1", Start: today.AddDate(0, 0, -20), End: today.AddDate(0, 0, -7)}
+	current := model.Sprint{ID: "s2", Name: "Sprint 2", Start: today.AddDate(0, 0, -6), End: today.AddDate(0, 0, 3)}
+	boards := []model.Board{
+		{ID: "b1", Name: "Backlog"},
+		{ID: "b2", Name: "Sprint", Sprints: []model.Sprint{past, current}},
+	}
+	tasks := []model.Task{
+		{ID: "t1", BoardID: "b2", Title: "Login", SprintID: "s2", Estimate: 5, Done: true},
+		{ID: "t2", BoardID: "b2", Title: "Logout", SprintID: "s2", Estimate: 3},
+		{ID: "t3", BoardID: "b2", Title: "Login form", ParentID: "t1", SprintID: "s2", Estimate: 2},
+		{ID: "t4", BoardID: "b2", Title: "Signup", SprintID: "s1"},
+	}
+	st := openTestStore(t, boards, tasks)
+	var opened string
+	p := NewSprintPage(st, func(task model.Task) {
+		opened = task.ID
+	})
+	test.WidgetRenderer(p)
+	rows := func() []string {
+		var rows []string
+		for _, o := range p.list.Objects {
+			switch o := o.(type) {
+			case *widget.Label:
+				rows = append(rows, o.Text)
+			case *fyne.Container:
+				rows = append(rows, o.Objects[0].(*widget.Button).Text+" "+o.Objects[1].(*widget.Label).Text)
+			}
+		}
+		return rows
+	}
+
+	// Only boards with sprints are listed, and the sprint running today is shown
+	assert.Equal(t, []string{"Sprint"}, p.board.Options)
+	assert.Equal(t, []string{"Sprint 1", "Sprint 2"}, p.sprint.Options)
+	assert.Equal(t, "Sprint 2", p.sprint.Selected)
+	assert.Equal(t, "5 of 8 points done · 4 days left", p.summary.Text)
+	assert.Equal(t, []string{"Login 5 points", "Logout 3 points"}, rows())
+	require.Len(t, p.chart.burn.Days, 7)
+	assert.Equal(t, float64(3), p.chart.burn.Days[6].Remaining())
+
+	p.list.Objects[1].(*fyne.Container).Objects[0].(*widget.Button).OnTapped()
+	assert.Equal(t, "t2", opened)
+
+	p.mode.SetSelected(BurnUp.String())
+	assert.Equal(t, BurnUp, p.chart.Mode())
+
+	p.sprint.SetSelected("Sprint 1")
+	assert.Equal(t, "0 of 1 tasks done · ended "+past.End.Format("2 Jan 2006"), p.summary.Text)
+	assert.Equal(t, []string{"Signup Not estimated"}, rows())
+	// The sprint picked is kept when the cache changes
+	p.Reload()
+	assert.Equal(t, "Sprint 1", p.sprint.Selected)
+}
+
+func TestSprintPageEmpty(t *testing.T) {
+	test.NewTempApp(t)
+	p := NewSprintPage(openTestStore(t, []model.Board{{ID: "b1", Name: "Backlog"}}, nil), nil)
+	test.WidgetRenderer(p)
+	assert.Empty(t, p.board.Options)
+	assert.False(t, p.chart.Visible())
+	assert.Equal(t, "No sprints yet, add them in the settings of a board", p.summary.Text)
+}
+
+func TestCurrentSprint(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 6, d, 0, 0, 0, 0, time.UTC) }
+	sprints := []model.Sprint{
+		{ID: "s2", Start: day(16), End: day(29)},
+		{ID: "s1", Start: day(2), End: day(13)},
+	}
+	assert.Equal(t, -1, currentSprint(nil, day(1)))
+	assert.Equal(t, 0, currentSprint(sprints, day(1)))
+	assert.Equal(t, 1, currentSprint(sprints, day(14)))
+	assert.Equal(t, 0, currentSprint(sprints, day(30)))
+}
+
+func TestBurnSummary(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 6, d, 0, 0, 0, 0, time.UTC) }
+	burn := model.Burn{
+		Sprint: model.Sprint{Start: day(2), End: day(6)},
+		Unit:   model.BurnPoints,
+		Days:   []model.BurnDay{{Day: day(2), Scope: 13}, {Day: day(3), Scope: 13, Completed: 2.5}},
+	}
+	assert.Equal(t, "Starts 2 Jun 2025 · 5 days", burnSummary(model.Burn{Sprint: burn.Sprint}, day(1)))
+	assert.Equal(t, "2.5 of 13 points done · 4 days left", burnSummary(burn, day(3).Add(15*time.Hour)))
+	assert.Equal(t, "2.5 of 13 points done · last day", burnSummary(burn, day(6)))
+}
+
+func TestParseEstimate(t *testing.T) {
+	v, err := parseEstimate(" 2.5 ")
+	require.NoError(t, err)
+	assert.Equal(t, 2.5, v)
+	v, err = parseEstimate("")
+	require.NoError(t, err)
+	assert.Zero(t, v)
+	for _, s := range []string{"-1", "lots", "Inf", "NaN"} {
+		_, err = parseEstimate(s)
+		assert.Error(t, err, s)
+	}
+}
@@ -0,0 +1,333 @@
+package ui
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"time"
+
+	"eldar/model"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// TaskProgress pairs a task with the progress rolled up from its checklist and subtasks
+type TaskProgress struct {
+	Task     model.Task
+	Progress model.Progress
+}
+
+// TaskDetail holds what the task detail form shows and the actions it can take
+type TaskDetail struct {
+	// Task is the task being edited, it is not modified
+	Task model.Task
+	// Board is the board of the task, whose columns, label set and custom fields the form offers
+	Board model.Board
+	// Subtasks are the subtasks of the task with their rolled up progress
+	Subtasks []TaskProgress
+	// Candidates are the tasks that can be picked as blockers of the task
+	Candidates []model.Task
+
+	// OnSave is called with the edited copy of the task when the form is submitted
+	OnSave func(model.Task)
+	// OnConvertItem turns a saved checklist item into a subtask, discarding unsaved edits
+	OnConvertItem func(model.ChecklistItem)
+	// OnOpenSubtask is called when a subtask is tapped
+	OnOpenSubtask func(model.Task)
+	// OnDelete deletes the task, the form has no delete button when it is nil
+	OnDelete func()
+	// OnSaveTemplate saves the task as a template, the form has no button for it when it is nil
+	OnSaveTemplate func()
+	// ValidateBlockers checks a new list of blockers of the task, e.g. to reject dependency cycles
+	ValidateBlockers func(blockedBy []string) error
+}
+
+// MakeTaskDetailForm creates a form to edit the details of a task.
+// The description is Markdown and rendered in a live preview below the editor.
+//
+// Parameters:
+//   - detail: The task, its related tasks and the actions of the form
+//
+// Returns:
+//   - A configured widget.Form ready to be displayed
+func MakeTaskDetailForm(detail TaskDetail) *widget.Form {
+	task := detail.Task
+	form := widget.NewForm()
+
+	titleInput := widget.NewEntry()
+	titleInput.SetPlaceHolder("What needs to be done?")
+	titleInput.SetText(task.Title)
+	titleInput.Validator = func(s string) error {
+		if strings.TrimSpace(s) == "" {
+			return errors.New("title must not be empty")
+		}
+		return nil
+	}
+	form.AppendItem(widget.NewFormItem("Title", titleInput))
+
+	preview := widget.NewRichTextFromMarkdown(task.Description)
+	preview.Wrapping = fyne.TextWrapWord
+	descriptionInput := widget.NewMultiLineEntry()
+	descriptionInput.SetPlaceHolder("Describe the task using Markdown")
+	descriptionInput.Wrapping = fyne.TextWrapWord
+	descriptionInput.SetMinRowsVisible(6)
+	descriptionInput.SetText(task.Description)
+	descriptionInput.OnChanged = func(s string) {
+		preview.ParseMarkdown(s)
+	}
+	form.AppendItem(widget.NewFormItem("Description", descriptionInput))
+	previewScroll := container.NewVScroll(preview)
+	previewScroll.SetMinSize(fyne.NewSize(0, 120))
+	form.AppendItem(widget.NewFormItem("Preview", previewScroll))
+
+	assigneesInput := widget.NewEntry()
+	assigneesInput.SetPlaceHolder("Comma separated email addresses")
+	assigneesInput.SetText(strings.Join(task.Assignees, ", "))
+	form.AppendItem(widget.NewFormItem("Assignees", assigneesInput))
+
+	// Boards without a label set take any label
+	var labels func() []string
+	if len(detail.Board.Labels) > 0 {
+		labelsInput := widget.NewCheckGroup(labelOptions(detail.Board, task), nil)
+		labelsInput.Horizontal = true
+		labelsInput.SetSelected(selectedLabels(detail.Board, task))
+		labels = func() []string {
+			return labelsInput.Selected
+		}
+		form.AppendItem(widget.NewFormItem("Labels", labelsInput))
+	} else {
+		labelsInput := widget.NewEntry()
+		labelsInput.SetPlaceHolder("Comma separated labels")
+		labelsInput.SetText(strings.Join(task.Labels, ", "))
+		labels = func() []string {
+			return splitList(labelsInput.Text)
+		}
+		form.AppendItem(widget.NewFormItem("Labels", labelsInput))
+	}
+
+	var columnSelect *widget.Select
+	if columns := detail.Board.Columns; len(columns) > 0 {
+		names := make([]string, len(columns))
+		for i, c := range columns {
+			names[i] = c.Name
+		}
+		columnSelect = widget.NewSelect(names, nil)
+		for i, c := range columns {
+			if c.ID == task.ColumnID {
+				columnSelect.SetSelectedIndex(i)
+			}
+		}
+		form.AppendItem(widget.NewFormItem("Column", columnSelect))
+	}
+
+	priorities := make([]string, len(model.Priorities))
+	for i, p := range model.Priorities {
+		priorities[i] = p.String()
+	}
+	prioritySelect := widget.NewSelect(priorities, nil)
+	prioritySelect.SetSelected(task.Priority.String())
+	form.AppendItem(widget.NewFormItem("Priority", prioritySelect))
+
+	dueDateInput := widget.NewDateEntry()
+	dueDateInput.SetDate(task.DueDate)
+	form.AppendItem(widget.NewFormItem("Due date", dueDateInput))
+
+	rule := ""
+	if task.Recurrence != nil {
+		rule = task.Recurrence.Rule
+	}
+	recurrencePicker := NewRecurrencePicker(rule)
+	form.AppendItem(widget.NewFormItem("Repeats", recurrencePicker))
+
+	var sprintSelect *widget.Select
+	var estimateInput *widget.Entry
+	if sprints := detail.Board.Sprints; len(sprints) > 0 {
+		names := []string{noSprint}
+		for _, s := range sprints {
+			names = append(names, s.Name)
+		}
+		sprintSelect = widget.NewSelect(names, nil)
+		sprintSelect.SetSelectedIndex(0)
+		for i, s := range sprints {
+			if s.ID == task.SprintID {
+				sprintSelect.SetSelectedIndex(i + 1)
+			}
+		}
+		form.AppendItem(widget.NewFormItem("Sprint", sprintSelect))
+
+		estimateInput = widget.NewEntry()
+		estimateInput.SetPlaceHolder("Story points")
+		if task.Estimate > 0 {
+			estimateInput.SetText(formatEstimate(task.Estimate))
+		}
+		estimateInput.Validator = func(s string) error {
+			_, err := parseEstimate(s)
+			return err
+		}
+		form.AppendItem(widget.NewFormItem("Estimate", estimateInput))
+	}
+
+	fields := make([]fieldInput, len(detail.Board.Fields))
+	for i, field := range detail.Board.Fields {
+		fields[i] = newFieldInput(field, task.Fields[field.ID])
+		form.AppendItem(widget.NewFormItem(field.Name, fields[i].object))
+	}
+
+	doneCheck := widget.NewCheck("Completed", nil)
+	doneCheck.SetChecked(task.Done)
+	form.AppendItem(widget.NewFormItem("Status", doneCheck))
+
+	checklist := NewChecklistEditor(task.Checklist, detail.OnConvertItem)
+	form.AppendItem(widget.NewFormItem("Checklist", checklist))
+
+	if len(detail.Subtasks) > 0 {
+		list := container.NewVBox()
+		for _, subtask := range detail.Subtasks {
+			list.Add(widget.NewButton(subtaskLabel(subtask.Task, subtask.Progress), func() {
+				detail.OnOpenSubtask(subtask.Task)
+			}))
+		}
+		form.AppendItem(widget.NewFormItem("Subtasks", list))
+	}
+
+	blockers := NewDependencyEditor(task, detail.Candidates, detail.ValidateBlockers)
+	form.AppendItem(widget.NewFormItem("Blocked by", blockers))
+
+	actions := container.NewHBox()
+	if detail.OnSaveTemplate != nil {
+		actions.Add(widget.NewButtonWithIcon("Save as template", theme.DocumentSaveIcon(), detail.OnSaveTemplate))
+	}
+	if detail.OnDelete != nil {
+		deleteButton := widget.NewButtonWithIcon("Delete task", theme.DeleteIcon(), detail.OnDelete)
+		deleteButton.Importance = widget.DangerImportance
+		actions.Add(deleteButton)
+	}
+	if len(actions.Objects) > 0 {
+		form.AppendItem(widget.NewFormItem("", actions))
+	}
+
+	form.SubmitText = "Save"
+	form.OnSubmit = func() {
+		edited := task.Clone()
+		edited.Title = strings.TrimSpace(titleInput.Text)
+		edited.Description = descriptionInput.Text
+		edited.Assignees = splitList(assigneesInput.Text)
+		edited.Labels = labels()
+		if columnSelect != nil && columnSelect.SelectedIndex() >= 0 {
+			edited.ColumnID = detail.Board.Columns[columnSelect.SelectedIndex()].ID
+		}
+		if priority, err := model.ParsePriority(prioritySelect.Selected); err == nil {
+			edited.Priority = priority
+		}
+		edited.DueDate = nil
+		if due := dueDateInput.Date; due != nil {
+			// Dates picked from the calendar of the entry carry the current time of day, typed ones do not.
+			// A due date left as it was keeps its own time of day.
+			day := dueDay(*due)
+			if task.DueDate != nil && due.Equal(*task.DueDate) {
+				day = *task.DueDate
+			}
+			edited.DueDate = &day
+		}
+		edited.Recurrence = editRecurrence(task, edited.DueDate, recurrencePicker.Rule(), time.Now())
+		if edited.Recurrence != nil && edited.DueDate == nil {
+			// A series starts on its due date, default it to today
+			start := edited.Recurrence.Start
+			edited.DueDate = &start
+		}
+		if sprintSelect != nil {
+			// Tasks of a deleted sprint are left unplanned once saved
+			edited.SprintID = ""
+			if i := sprintSelect.SelectedIndex(); i > 0 {
+				edited.SprintID = detail.Board.Sprints[i-1].ID
+			}
+			if estimate, err := parseEstimate(estimateInput.Text); err == nil {
+				edited.Estimate = estimate
+			}
+		}
+		edited.Fields = nil
+		for i, field := range detail.Board.Fields {
+			if v := fields[i].value(); !v.Empty() {
+				if edited.Fields == nil {
+					edited.Fields = map[string]model.FieldValue{}
+				}
+				edited.Fields[field.ID] = v
+			}
+		}
+		edited.Done = doneCheck.Checked
+		edited.Checklist = checklist.Items()
+		edited.BlockedBy = blockers.BlockedBy()
+		detail.OnSave(edited)
+	}
+	return form
+}
+
+// splitList splits a comma separated list, trimming spaces and dropping empty entries
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// subtaskLabel describes a subtask with its completion, such as "✓ Draft" or "Review (1/3)"
+func subtaskLabel(task model.Task, progress model.Progress) string {
+	if task.Done || progress.Complete() {
+		return "✓ " + task.Title
+	}
+	if progress.Total > 0 {
+		return task.Title + " (" + progress.String() + ")"
+	}
+	return task.Title
+}
+
+// editRecurrence returns the recurrence of a task after its rule was edited.
+// The series keeps its start while the rule is unchanged, otherwise it restarts on the due date,
+// or on the local date of today when there is none. Series of date-only due dates, stored at midnight UTC
+// like those of widget.DateEntry, are expanded in UTC so they stay on the same date, while series of due dates
+// with a time of day are expanded in the local time zone so they keep their wall clock time across DST changes.
+func editRecurrence(task model.Task, due *time.Time, rule string, now time.Time) *model.Recurrence {
+	if rule == "" {
+		return nil
+	}
+	if task.Recurrence != nil && task.Recurrence.Rule == rule {
+		recurrence := *task.Recurrence
+		return &recurrence
+	}
+	if due != nil && !model.DateOnly(*due) {
+		return &model.Recurrence{Rule: rule, Start: *due, TimeZone: localZoneName()}
+	}
+	start := dueDay(now)
+	if due != nil {
+		start = due.UTC()
+	}
+	return &model.Recurrence{Rule: rule, Start: start, TimeZone: "UTC"}
+}
+
+// localZoneName returns the IANA name of the local time zone, which time.Local only knows as "Local",
+// from the TZ variable or the /etc/localtime link. It returns "UTC" when the name cannot be found out.
+func localZoneName() string {
+	if name := time.Local.String(); name != "Local" {
+		return name
+	}
+	names := []string{strings.TrimPrefix(os.Getenv("TZ"), ":")}
+	if target, err := os.Readlink("/etc/localtime"); err == nil {
+		if _, name, ok := strings.Cut(target, "zoneinfo/"); ok {
+			names = append(names, name)
+		}
+	}
+	for _, name := range names {
+		if name == "" || name == "Local" {
+			continue
+		}
+		if _, err := time.LoadLocation(name); err == nil {
+			return name
+		}
+	}
+	return "UTC"
+}
@@ -0,0 +1,458 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"eldar/model"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// findButtonOrNil returns the button with the given text inside obj, or nil
+func findButtonOrNil(obj fyne.CanvasObject, text string) *widget.Button {
+	switch o := obj.(type) {
+	case *widget.Button:
+		if o.Text == text {
+			return o
+		}
+	case *fyne.Container:
+		for _, child := range o.Objects {
+			if b := findButtonOrNil(child, text); b != nil {
+				return b
+			}
+		}
+	}
+	return nil
+}
+
+// findButton returns the button with the given text inside obj, failing the test when there is none
+func findButton(t *testing.T, obj fyne.CanvasObject, text string) *widget.Button {
+	b := findButtonOrNil(obj, text)
+	require.NotNil(t, b, "button %q not found", text)
+	return b
+}
+
+func TestMakeTaskDetailForm(t *testing.T) {
+	test.NewTempApp(t)
+	due := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	task := model.Task{
+		ID:          "t1",
+		Title:       "Write report",
+		Description: "# Report",
+		Labels:      []string{"docs"},
+		DueDate:     &due,
+		Version:     3,
+	}
+	var saved model.Task
+	form := MakeTaskDetailForm(TaskDetail{
+		Task:       task,
+		Candidates: []model.Task{task, {ID: "t2", Title: "Collect numbers"}},
+		OnSave: func(edited model.Task) {
+			saved = edited
+		},
+	})
+	assert.Equal(t, 11, len(form.Items))
+	assert.Equal(t, "Save", form.SubmitText)
+
+	titleEntry := form.Items[0].Widget.(*widget.Entry)
+	assert.Equal(t, "Write report", titleEntry.Text)
+	assert.Error(t, titleEntry.Validator(" "))
+
+	// The preview follows the Markdown description
+	descriptionEntry := form.Items[1].Widget.(*widget.Entry)
+	descriptionEntry.SetText("**Quarterly** numbers")
+	preview := test.WidgetRenderer(form).Objects()
+	assert.NotEmpty(t, preview)
+
+	form.Items[3].Widget.(*widget.Entry).SetText("ana@ioluas.dev, , omar@ioluas.dev")
+	form.Items[4].Widget.(*widget.Entry).SetText("docs, finance")
+	form.Items[5].Widget.(*widget.Select).SetSelected("High")
+	form.Items[7].Widget.(*RecurrencePicker).choice.SetSelected("Weekly")
+	form.Items[8].Widget.(*widget.Check).SetChecked(true)
+	form.Items[9].Widget.(*ChecklistEditor).AddItem("Collect numbers")
+	form.Items[10].Widget.(*DependencyEditor).AddBlocker("t2")
+	form.OnSubmit()
+
+	assert.Equal(t, "t1", saved.ID)
+	assert.Equal(t, int64(3), saved.Version)
+	assert.Equal(t, "**Quarterly** numbers", saved.Description)
+	assert.Equal(t, []string{"ana@ioluas.dev", "omar@ioluas.dev"}, saved.Assignees)
+	assert.Equal(t, []string{"docs", "finance"}, saved.Labels)
+	assert.Equal(t, model.PriorityHigh, saved.Priority)
+	assert.Equal(t, due, *saved.DueDate)
+	assert.True(t, saved.Done)
+	require.Len(t, saved.Checklist, 1)
+	assert.Equal(t, "Collect numbers", saved.Checklist[0].Text)
+	assert.Equal(t, []string{"t2"}, saved.BlockedBy)
+	assert.Equal(t, &model.Recurrence{Rule: "FREQ=WEEKLY", Start: due, TimeZone: "UTC"}, saved.Recurrence)
+
+	// The original task is left untouched
+	assert.Equal(t, []string{"docs"}, task.Labels)
+	assert.Equal(t, "# Report", task.Description)
+	assert.Empty(t, task.Checklist)
+}
+
+func TestMakeTaskDetailFormSubtasks(t *testing.T) {
+	test.NewTempApp(t)
+	task := model.Task{ID: "t1", Title: "Write report"}
+	subtasks := []TaskProgress{
+		{Task: model.Task{ID: "t2", ParentID: "t1", Title: "Draft", Done: true}},
+		{Task: model.Task{ID: "t3", ParentID: "t1", Title: "Review"}, Progress: model.Progress{Done: 1, Total: 3}},
+	}
+	var opened model.Task
+	form := MakeTaskDetailForm(TaskDetail{
+		Task:     task,
+		Subtasks: subtasks,
+		OnSave:   func(model.Task) {},
+		OnOpenSubtask: func(subtask model.Task) {
+			opened = subtask
+		},
+	})
+	require.Equal(t, 12, len(form.Items))
+
+	draft := findButton(t, form.Items[10].Widget, "✓ Draft")
+	review := findButton(t, form.Items[10].Widget, "Review (1/3)")
+	test.Tap(review)
+	assert.Equal(t, "t3", opened.ID)
+	test.Tap(draft)
+	assert.Equal(t, "t2", opened.ID)
+}
+
+func TestMakeTaskDetailFormDelete(t *testing.T) {
+	test.NewTempApp(t)
+	task := model.Task{ID: "t1", Title: "Write report"}
+	form := MakeTaskDetailForm(TaskDetail{Task: task, OnSave: func(model.Task) {}})
+	for _, item := range form.Items {
+		assert.Nil(t, findButtonOrNil(item.Widget, "Delete task"))
+	}
+
+	deleted := false
+	form = MakeTaskDetailForm(TaskDetail{Task: task, OnSave: func(model.Task) {}, OnDelete: func() {
+		deleted = true
+	}})
+	test.Tap(findButton(t, form.Items[len(form.Items)-1].Widget, "Delete task"))
+	assert.True(t, deleted)
+}
+
+func TestMakeTaskDetailFormSaveTemplate(t *testing.T) {
+	test.NewTempApp(t)
+	task := model.Task{ID: "t1", Title: "Write report"}
+	form := MakeTaskDetailForm(TaskDetail{Task: task, OnSave: func(model.Task) {}})
+	for _, item := range form.Items {
+		assert.Nil(t, findButtonOrNil(item.Widget, "Save as template"))
+	}
+
+	saved := false
+	form = MakeTaskDetailForm(TaskDetail{Task: task, OnSave: func(model.Task) {}, OnSaveTemplate: func() {
+		saved = true
+	}, OnDelete: func() {}})
+	actions := form.Items[len(form.Items)-1].Widget
+	assert.NotNil(t, findButtonOrNil(actions, "Delete task"))
+	test.Tap(findButton(t, actions, "Save as template"))
+	assert.True(t, saved)
+}
+
+func TestChecklistEditor(t *testing.T) {
+	test.NewTempApp(t)
+	var converted model.ChecklistItem
+	editor := NewChecklistEditor([]model.ChecklistItem{
+		{ID: "a", Text: "Outline", Done: true},
+		{ID: "b", Text: "Draft"},
+	}, func(item model.ChecklistItem) {
+		converted = item
+	})
+	test.WidgetRenderer(editor)
+
+	editor.AddItem("  ")
+	editor.AddItem("Review")
+	editor.MoveItem(2, 0)
+	editor.RemoveItem(2)
+	items := editor.Items()
+	require.Len(t, items, 2)
+	assert.Equal(t, "Review", items[0].Text)
+	assert.NotEmpty(t, items[0].ID)
+	assert.Equal(t, "a", items[1].ID)
+
+	// Only items known to the server can be converted
+	assert.Nil(t, findButtonOrNil(editor.rows.Objects[0], "To task"))
+	test.Tap(findButton(t, editor.rows.Objects[1], "To task"))
+	assert.Equal(t, "a", converted.ID)
+}
+
+func TestDependencyEditor(t *testing.T) {
+	test.NewTempApp(t)
+	task := model.Task{ID: "t1", Title: "Write report", BlockedBy: []string{"t2"}}
+	candidates := []model.Task{
+		task,
+		{ID: "t2", Title: "Collect numbers"},
+		{ID: "t3", Title: "Review", BlockedBy: []string{"t1"}},
+		{ID: "t4", Title: "Print"},
+	}
+	editor := NewDependencyEditor(task, candidates, func(blockedBy []string) error {
+		return model.ValidateDependencies("t1", blockedBy, map[string]model.Task{
+			"t1": candidates[0], "t2": candidates[1], "t3": candidates[2], "t4": candidates[3],
+		})
+	})
+	test.WidgetRenderer(editor)
+
+	// The task itself and its current blockers cannot be picked
+	assert.Equal(t, []string{"Review", "Print"}, editor.picker.Options)
+
+	// Picking a task that waits on this one is refused with the cycle
+	editor.picker.SetSelected("Review")
+	assert.Equal(t, []string{"t2"}, editor.BlockedBy())
+	assert.True(t, editor.errLabel.Visible())
+	assert.Equal(t, "dependency cycle: Write report → Review → Write report", editor.errLabel.Text)
+
+	editor.picker.SetSelected("Print")
+	assert.Equal(t, []string{"t2", "t4"}, editor.BlockedBy())
+	assert.False(t, editor.errLabel.Visible())
+	assert.Equal(t, []string{"Review"}, editor.picker.Options)
+
+	editor.RemoveBlocker("t2")
+	assert.Equal(t, []string{"t4"}, editor.BlockedBy())
+	assert.Equal(t, []string{"Collect numbers", "Review"}, editor.picker.Options)
+}
+
+func TestRecurrencePicker(t *testing.T) {
+	test.NewTempApp(t)
+	picker := NewRecurrencePicker("")
+	assert.Equal(t, "Does not repeat", picker.choice.Selected)
+	assert.Equal(t, "", picker.Rule())
+	assert.False(t, picker.custom.Visible())
+
+	// Presets are recognised whatever the order of the rule parts
+	picker = NewRecurrencePicker("BYDAY=MO,TU,WE,TH,FR;FREQ=WEEKLY")
+	assert.Equal(t, "Every weekday", picker.choice.Selected)
+
+	picker = NewRecurrencePicker("FREQ=MONTHLY;BYDAY=-1FR")
+	assert.Equal(t, customRecurrence, picker.choice.Selected)
+	assert.True(t, picker.custom.Visible())
+	assert.Equal(t, "FREQ=MONTHLY;BYDAY=-1FR", picker.Rule())
+	assert.NoError(t, picker.Validate())
+
+	var validation error
+	picker.SetOnValidationChanged(func(err error) {
+		validation = err
+	})
+	test.Type(picker.custom, ";BYHOUR=9")
+	assert.Error(t, validation)
+	picker.choice.SetSelected("Yearly")
+	assert.NoError(t, validation)
+	assert.False(t, picker.custom.Visible())
+	assert.Equal(t, "FREQ=YEARLY", picker.Rule())
+}
+
+func TestEditRecurrence(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	local := time.Local
+	time.Local = berlin
+	t.Cleanup(func() {
+		time.Local = local
+	})
+	now := time.Date(2025, 3, 28, 15, 0, 0, 0, berlin)
+	due := time.Date(2025, 3, 28, 9, 0, 0, 0, berlin)
+	series := &model.Recurrence{Rule: "FREQ=WEEKLY", Start: due.AddDate(0, 0, -14), TimeZone: "Europe/Berlin"}
+
+	assert.Nil(t, editRecurrence(model.Task{Recurrence: series}, &due, "", now))
+	// An unchanged rule keeps the start of the series
+	assert.Equal(t, series, editRecurrence(model.Task{Recurrence: series}, &due, "FREQ=WEEKLY", now))
+
+	// A new rule starts on the due date, expanded in the named local zone rather than "Local",
+	// which other clients cannot load, so it keeps its time of day across the DST change of 30 March
+	recurrence := editRecurrence(model.Task{Recurrence: series}, &due, "FREQ=DAILY", now)
+	assert.Equal(t, &model.Recurrence{Rule: "FREQ=DAILY", Start: due, TimeZone: "Europe/Berlin"}, recurrence)
+	require.NoError(t, recurrence.Validate())
+	next, ok, err := recurrence.Next(time.Date(2025, 3, 31, 0, 0, 0, 0, berlin))
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, time.Date(2025, 3, 31, 9, 0, 0, 0, berlin).Equal(next), next)
+
+	// Without a due date the series starts on the local date of today, as a date-only due date
+	assert.Equal(t, &model.Recurrence{Rule: "FREQ=DAILY", Start: time.Date(2025, 3, 28, 0, 0, 0, 0, time.UTC), TimeZone: "UTC"},
+		editRecurrence(model.Task{}, nil, "FREQ=DAILY", time.Date(2025, 3, 28, 23, 30, 0, 0, berlin)))
+
+	// Date-only due dates are expanded in UTC, so they stay on midnight UTC across the DST change
+	day := time.Date(2025, 3, 28, 0, 0, 0, 0, time.UTC)
+	recurrence = editRecurrence(model.Task{}, &day, "FREQ=DAILY", now)
+	assert.Equal(t, "UTC", recurrence.TimeZone)
+	next, ok, err = recurrence.Next(day.AddDate(0, 0, 3))
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), next)
+}
+
+func TestMakeTaskDetailFormBoard(t *testing.T) {
+	test.NewTempApp(t)
+	board := model.Board{
+		ID:     "b1",
+		Labels: []model.Label{{Name: "Bug", Color: "#e53935"}, {Name: "Docs", Color: "#1e88e5"}},
+		Fields: []model.Field{
+			{ID: "f1", Name: "Customer", Type: model.FieldText},
+			{ID: "f2", Name: "Estimate", Type: model.FieldNumber},
+			{ID: "f3", Name: "Launch", Type: model.FieldDate},
+			{ID: "f4", Name: "Size", Type: model.FieldSelect, Options: []string{"S", "M", "L"}},
+			{ID: "f5", Name: "Platforms", Type: model.FieldMultiSelect, Options: []string{"iOS", "Android", "Web"}},
+		},
+	}
+	estimate := 3.0
+	task := model.Task{
+		ID:      "t1",
+		BoardID: "b1",
+		Title:   "Ship importer",
+		Labels:  []string{"bug", "legacy"},
+		Fields: map[string]model.FieldValue{
+			"f2":   {Number: &estimate},
+			"f4":   {Choices: []string{"XL"}},
+			"f5":   {Choices: []string{"Web"}},
+			"gone": {Text: "from a removed field"},
+		},
+	}
+	var saved model.Task
+	form := MakeTaskDetailForm(TaskDetail{Task: task, Board: board, OnSave: func(edited model.Task) {
+		saved = edited
+	}})
+	require.Equal(t, 16, len(form.Items))
+
+	// The label set is offered along with the labels of the task that are not in it
+	labels := form.Items[4].Widget.(*widget.CheckGroup)
+	assert.Equal(t, []string{"Bug", "Docs", "legacy"}, labels.Options)
+	assert.Equal(t, []string{"Bug", "legacy"}, labels.Selected)
+	labels.SetSelected([]string{"Bug", "Docs"})
+
+	assert.Equal(t, "Customer", form.Items[8].Text)
+	form.Items[8].Widget.(*widget.Entry).SetText(" Acme ")
+	estimateEntry := form.Items[9].Widget.(*widget.Entry)
+	assert.Equal(t, "3", estimateEntry.Text)
+	assert.Error(t, estimateEntry.Validator("three"))
+	estimateEntry.SetText("5.5")
+	launch := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	form.Items[10].Widget.(*widget.DateEntry).SetDate(&launch)
+	// The choice of an option that was removed is cleared
+	size := form.Items[11].Widget.(*widget.RadioGroup)
+	assert.Empty(t, size.Selected)
+	size.SetSelected("M")
+	platforms := form.Items[12].Widget.(*widget.CheckGroup)
+	assert.Equal(t, []string{"Web"}, platforms.Selected)
+	platforms.SetSelected([]string{"Web", "iOS"})
+	form.OnSubmit()
+
+	assert.Equal(t, []string{"Bug", "Docs"}, saved.Labels)
+	assert.Equal(t, map[string]model.FieldValue{
+		"f1": {Text: "Acme"},
+		"f2": {Number: func() *float64 { n := 5.5; return &n }()},
+		"f3": {Date: &launch},
+		"f4": {Choices: []string{"M"}},
+		"f5": {Choices: []string{"iOS", "Web"}},
+	}, saved.Fields)
+
+	// Clearing every field leaves no values
+	form.Items[8].Widget.(*widget.Entry).SetText("")
+	estimateEntry.SetText("")
+	form.Items[10].Widget.(*widget.DateEntry).SetDate(nil)
+	size.SetSelected("")
+	platforms.SetSelected(nil)
+	form.OnSubmit()
+	assert.Nil(t, saved.Fields)
+}
+
+func TestMakeTaskDetailFormColumn(t *testing.T) {
+	test.NewTempApp(t)
+	board := model.Board{ID: "b1", Columns: []model.Column{{ID: "todo", Name: "To do"}, {ID: "done", Name: "Done"}}}
+	var saved model.Task
+	form := MakeTaskDetailForm(TaskDetail{
+		Task:  model.Task{ID: "t1", BoardID: "b1", ColumnID: "todo", Title: "Write report"},
+		Board: board,
+		OnSave: func(edited model.Task) {
+			saved = edited
+		},
+	})
+	require.Equal(t, 12, len(form.Items))
+	assert.Equal(t, "Column", form.Items[5].Text)
+	columns := form.Items[5].Widget.(*widget.Select)
+	assert.Equal(t, "To do", columns.Selected)
+	columns.SetSelected("Done")
+	form.OnSubmit()
+	assert.Equal(t, "done", saved.ColumnID)
+}
+
+func TestMakeTaskDetailFormSprint(t *testing.T) {
+	test.NewTempApp(t)
+	start := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	board := model.Board{ID: "b1", Sprints: []model.Sprint{
+		{ID: "s1", Name: "Sprint 1", Start: start, End: start.AddDate(0, 0, 13)},
+		{ID: "s2", Name: "Sprint 2", Start: start.AddDate(0, 0, 14), End: start.AddDate(0, 0, 27)},
+	}}
+	var saved model.Task
+	form := MakeTaskDetailForm(TaskDetail{
+		Task:  model.Task{ID: "t1", BoardID: "b1", Title: "Write report", SprintID: "gone", Estimate: 2},
+		Board: board,
+		OnSave: func(edited model.Task) {
+			saved = edited
+		},
+	})
+	require.Equal(t, 13, len(form.Items))
+	assert.Equal(t, "Sprint", form.Items[8].Text)
+	sprints := form.Items[8].Widget.(*widget.Select)
+	estimate := form.Items[9].Widget.(*widget.Entry)
+	// A task of a deleted sprint is shown unplanned
+	assert.Equal(t, noSprint, sprints.Selected)
+	assert.Equal(t, "2", estimate.Text)
+
+	sprints.SetSelected("Sprint 2")
+	estimate.SetText("3.5")
+	form.OnSubmit()
+	assert.Equal(t, "s2", saved.SprintID)
+	assert.Equal(t, 3.5, saved.Estimate)
+
+	sprints.SetSelected(noSprint)
+	assert.Error(t, estimate.Validator("-1"))
+	estimate.SetText("")
+	form.OnSubmit()
+	assert.Empty(t, saved.SprintID)
+	assert.Zero(t, saved.Estimate)
+}
+
+func TestMakeTaskDetailFormPickedDueDate(t *testing.T) {
+	test.NewTempApp(t)
+	var saved model.Task
+	form := MakeTaskDetailForm(TaskDetail{
+		Task: model.Task{ID: "t1", Title: "Write report"},
+		OnSave: func(edited model.Task) {
+			saved = edited
+		},
+	})
+	require.Equal(t, "Due date", form.Items[6].Text)
+	// Days picked from the calendar of the entry carry the time they were picked at
+	picked := time.Date(2025, 6, 3, 14, 22, 0, 0, time.FixedZone("UTC-5", -5*60*60))
+	form.Items[6].Widget.(*widget.DateEntry).SetDate(&picked)
+	form.OnSubmit()
+	require.NotNil(t, saved.DueDate)
+	assert.Equal(t, time.Date(2025, 6, 3, 0, 0, 0, 0, time.UTC), *saved.DueDate)
+}
+
+func TestMakeTaskDetailFormTimedDueDate(t *testing.T) {
+	test.NewTempApp(t)
+	due := time.Date(2025, 6, 3, 14, 30, 0, 0, time.UTC)
+	var saved model.Task
+	form := MakeTaskDetailForm(TaskDetail{
+		Task: model.Task{ID: "t1", Title: "Write report", DueDate: &due},
+		OnSave: func(edited model.Task) {
+			saved = edited
+		},
+	})
+	// A due date with a time of day, e.g. set through the API, keeps it while its date is left as it is
+	form.OnSubmit()
+	require.NotNil(t, saved.DueDate)
+	assert.Equal(t, due, *saved.DueDate)
+
+	next := time.Date(2025, 6, 4, 0, 0, 0, 0, time.UTC)
+	form.Items[6].Widget.(*widget.DateEntry).SetDate(&next)
+	form.OnSubmit()
+	assert.Equal(t, next, *saved.DueDate)
+}
@@ -0,0 +1,110 @@
+package ui
+
+import (
+	"slices"
+
+	"eldar/model"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// TemplatePicker lists board or task templates with their descriptions to pick one from.
+// The templates saved by the signed in account can be deleted.
+type TemplatePicker struct {
+	widget.BaseWidget
+	// Me is the username of the signed in account, which owns the templates it can delete
+	Me string
+	// OnSelect is called with the template picked
+	OnSelect func(model.Template)
+	// OnDelete is called with a template of the signed in account to delete it
+	OnDelete func(model.Template)
+
+	templates []model.Template
+	selected  string
+	list      *fyne.Container
+}
+
+// NewTemplatePicker creates an empty template picker for the signed in account me.
+// Its templates are set with SetTemplates.
+func NewTemplatePicker(me string) *TemplatePicker {
+	p := &TemplatePicker{Me: me, list: container.NewVBox()}
+	p.ExtendBaseWidget(p)
+	return p
+}
+
+// CreateRenderer implements fyne.Widget
+func (p *TemplatePicker) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(container.NewVScroll(p.list))
+}
+
+// SetTemplates replaces the listed templates, keeping the selection when the selected template
+// is still listed and selecting the first one otherwise
+func (p *TemplatePicker) SetTemplates(templates []model.Template) {
+	p.templates = slices.Clone(templates)
+	if _, ok := p.Selected(); !ok && len(p.templates) > 0 {
+		p.Select(p.templates[0].ID)
+		return
+	}
+	p.rebuild()
+}
+
+// Select picks the template with the given ID and calls OnSelect
+func (p *TemplatePicker) Select(id string) {
+	p.selected = id
+	p.rebuild()
+	if t, ok := p.Selected(); ok && p.OnSelect != nil {
+		p.OnSelect(t)
+	}
+}
+
+// Selected returns the picked template
+func (p *TemplatePicker) Selected() (model.Template, bool) {
+	for _, t := range p.templates {
+		if t.ID == p.selected {
+			return t, true
+		}
+	}
+	return model.Template{}, false
+}
+
+// rebuild recreates the rows of the templates
+func (p *TemplatePicker) rebuild() {
+	p.list.Objects = nil
+	if len(p.templates) == 0 {
+		p.list.Add(widget.NewLabel("No templates yet"))
+	}
+	for _, t := range p.templates {
+		p.list.Add(p.makeRow(t))
+	}
+	p.list.Refresh()
+}
+
+// makeRow creates the row of a template: its name to pick it, its description, and a delete button
+// when it belongs to the signed in account
+func (p *TemplatePicker) makeRow(t model.Template) fyne.CanvasObject {
+	name := widget.NewButton(t.Name, func() {
+		p.Select(t.ID)
+	})
+	name.Alignment = widget.ButtonAlignLeading
+	name.Importance = widget.LowImportance
+	if t.ID == p.selected {
+		name.Importance = widget.HighImportance
+	}
+	details := container.NewVBox(name)
+	if t.Description != "" {
+		description := widget.NewLabel(t.Description)
+		description.SizeName = theme.SizeNameCaptionText
+		description.Wrapping = fyne.TextWrapWord
+		details.Add(description)
+	}
+	if t.Builtin() || t.Owner != p.Me || p.OnDelete == nil {
+		return details
+	}
+	remove := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+		p.OnDelete(t)
+	})
+	remove.Importance = widget.LowImportance
+	return container.NewBorder(nil, nil, nil, container.NewVBox(remove), details)
+}
@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"testing"
+
+	"eldar/model"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplatePicker(t *testing.T) {
+	test.NewTempApp(t)
+	picker := NewTemplatePicker("ana")
+	test.WidgetRenderer(picker)
+	var selected []string
+	picker.OnSelect = func(t model.Template) {
+		selected = append(selected, t.ID)
+	}
+	var deleted []string
+	picker.OnDelete = func(t model.Template) {
+		deleted = append(deleted, t.ID)
+	}
+
+	picker.SetTemplates(nil)
+	assert.Equal(t, "No templates yet", picker.list.Objects[0].(*widget.Label).Text)
+	_, ok := picker.Selected()
+	assert.False(t, ok)
+
+	templates := []model.Template{
+		model.BuiltinTemplates[0],
+		{ID: "tp1", Name: "Release", Description: "Ship a version", Owner: "ana"},
+		{ID: "tp2", Name: "Retro", Owner: "ben"},
+	}
+	// The first template is selected by default
+	picker.SetTemplates(templates)
+	assert.Equal(t, []string{model.BuiltinTemplates[0].ID}, selected)
+	require.Len(t, picker.list.Objects, 3)
+
+	// Only the templates of the signed in account can be deleted
+	assert.Nil(t, findButtonOrNil(picker.list.Objects[0], ""))
+	assert.Nil(t, findButtonOrNil(picker.list.Objects[2], ""))
+	test.Tap(findButton(t, picker.list.Objects[1], ""))
+	assert.Equal(t, []string{"tp1"}, deleted)
+
+	test.Tap(findButton(t, picker.list.Objects[2], "Retro"))
+	current, ok := picker.Selected()
+	require.True(t, ok)
+	assert.Equal(t, "tp2", current.ID)
+	assert.Equal(t, widget.HighImportance, findButton(t, picker.list.Objects[2], "Retro").Importance)
+	description := picker.list.Objects[1].(*fyne.Container).Objects[0].(*fyne.Container).Objects[1].(*widget.Label)
+	assert.Equal(t, "Ship a version", description.Text)
+
+	// The selection is kept while the template is listed
+	picker.SetTemplates(templates[1:])
+	current, _ = picker.Selected()
+	assert.Equal(t, "tp2", current.ID)
+	picker.SetTemplates(templates[:2])
+	current, _ = picker.Selected()
+	assert.Equal(t, model.BuiltinTemplates[0].ID, current.ID)
+}
@@ -0,0 +1,222 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"eldar/model"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// TimePanel shows the time spent on a task with a start/stop timer, lists its time entries and
+// takes entries by hand
+type TimePanel struct {
+	widget.BaseWidget
+	// OnStart and OnStop start and stop the timer of the task
+	OnStart func()
+	OnStop  func()
+	// OnAdd records time spent by hand, starting at start
+	OnAdd func(start time.Time, spent time.Duration, note string)
+	// OnDelete deletes an entry of the signed-in member
+	OnDelete func(model.TimeEntry)
+	// Name returns the name of a member shown next to their entries
+	Name func(username string) string
+
+	me string
+	// timer is the running timer of the task, or nil
+	timer   *model.Timer
+	toggle  *widget.Button
+	elapsed *widget.Label
+	total   *widget.Label
+	list    *fyne.Container
+
+	day   *widget.DateEntry
+	spent *widget.Entry
+	note  *widget.Entry
+}
+
+// NewTimePanel creates the time panel of a task for the signed-in member me.
+// Its entries are set with SetEntries and its timer with SetTimer.
+func NewTimePanel(me string) *TimePanel {
+	p := &TimePanel{me: me, list: container.NewVBox()}
+	p.toggle = widget.NewButtonWithIcon("Start timer", theme.MediaPlayIcon(), func() {
+		if p.timer != nil {
+			if p.OnStop != nil {
+				p.OnStop()
+			}
+		} else if p.OnStart != nil {
+			p.OnStart()
+		}
+	})
+	p.elapsed = widget.NewLabel("")
+	p.total = widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+
+	p.day = widget.NewDateEntry()
+	today := time.Now()
+	p.day.SetDate(&today)
+	p.spent = widget.NewEntry()
+	p.spent.SetPlaceHolder("Time, e.g. 1h30m")
+	p.spent.Validator = func(s string) error {
+		_, err := model.ParseDuration(s)
+		return err
+	}
+	p.note = widget.NewEntry()
+	p.note.SetPlaceHolder("What was done (optional)")
+	p.note.OnSubmitted = func(string) {
+		p.addEntry()
+	}
+	p.ExtendBaseWidget(p)
+	p.SetEntries(nil)
+	return p
+}
+
+// CreateRenderer implements fyne.Widget
+func (p *TimePanel) CreateRenderer() fyne.WidgetRenderer {
+	add := widget.NewButtonWithIcon("Add time", theme.ContentAddIcon(), p.addEntry)
+	manual := container.NewVBox(
+		widget.NewLabelWithStyle("Add time by hand", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		container.NewGridWithColumns(2, p.day, p.spent),
+		container.NewBorder(nil, nil, nil, add, p.note),
+	)
+	top := container.NewVBox(container.NewHBox(p.toggle, p.elapsed), p.total)
+	return widget.NewSimpleRenderer(container.NewBorder(top, manual, nil, nil, container.NewVScroll(p.list)))
+}
+
+// SetEntries replaces the time entries shown, oldest first
+func (p *TimePanel) SetEntries(entries []model.TimeEntry) {
+	var total time.Duration
+	objects := make([]fyne.CanvasObject, 0, len(entries))
+	for i := len(entries) - 1; i >= 0; i-- {
+		total += entries[i].Duration()
+		objects = append(objects, p.makeEntry(entries[i]))
+	}
+	if len(objects) == 0 {
+		objects = append(objects, widget.NewLabel("No time tracked yet"))
+	}
+	p.total.SetText("Total: " + model.FormatDuration(total))
+	p.list.Objects = objects
+	p.list.Refresh()
+}
+
+// SetTimer shows the running timer of the task at now, or a Start button when timer is nil
+func (p *TimePanel) SetTimer(timer *model.Timer, now time.Time) {
+	p.timer = timer
+	if timer == nil {
+		p.toggle.SetText("Start timer")
+		p.toggle.SetIcon(theme.MediaPlayIcon())
+		p.toggle.Importance = widget.MediumImportance
+		p.elapsed.SetText("")
+	} else {
+		p.toggle.SetText("Stop timer")
+		p.toggle.SetIcon(theme.MediaStopIcon())
+		p.toggle.Importance = widget.HighImportance
+		p.elapsed.SetText(formatElapsed(timer.Elapsed(now)))
+	}
+	p.toggle.Refresh()
+}
+
+// addEntry records the time typed in the manual entry row, starting at midnight of its day
+func (p *TimePanel) addEntry() {
+	spent, err := model.ParseDuration(p.spent.Text)
+	if err != nil || p.day.Date == nil || p.OnAdd == nil {
+		return
+	}
+	day := *p.day.Date
+	p.OnAdd(time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.Local), spent, strings.TrimSpace(p.note.Text))
+	p.spent.SetText("")
+	p.note.SetText("")
+}
+
+// makeEntry renders a time entry as its day, time spent, member and note, with a delete button on entries of me
+func (p *TimePanel) makeEntry(entry model.TimeEntry) fyne.CanvasObject {
+	who := entry.User
+	if p.Name != nil {
+		who = p.Name(entry.User)
+	}
+	text := fmt.Sprintf("%s · %s · %s", entry.Start.Local().Format("Mon 2 Jan"), model.FormatDuration(entry.Duration()), who)
+	if entry.Manual {
+		text += " · by hand"
+	}
+	label := widget.NewLabel(text)
+	row := container.NewVBox(label)
+	if entry.Note != "" {
+		note := widget.NewLabel(entry.Note)
+		note.SizeName = theme.SizeNameCaptionText
+		note.Wrapping = fyne.TextWrapWord
+		row.Add(note)
+	}
+	if entry.User != p.me || p.OnDelete == nil {
+		return row
+	}
+	remove := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+		p.OnDelete(entry)
+	})
+	return container.NewBorder(nil, nil, nil, remove, row)
+}
+
+// formatElapsed returns the time a timer has been running as hours, minutes and seconds, such as "1:05:09"
+func formatElapsed(d time.Duration) string {
+	d = d.Truncate(time.Second)
+	return fmt.Sprintf("%d:%02d:%02d", int(d.Hours()), int(d/time.Minute)%60, int(d/time.Second)%60)
+}
+
+// TimerBar shows the running timer of the signed-in member across the pages of the app, with a Stop button.
+// It is hidden while no timer runs.
+type TimerBar struct {
+	widget.BaseWidget
+	// OnOpen opens the timed task, OnStop stops the timer
+	OnOpen func()
+	OnStop func()
+
+	timer *model.Timer
+	task  *widget.Button
+	clock *widget.Label
+}
+
+// NewTimerBar creates a hidden timer bar, shown by SetTimer
+func NewTimerBar() *TimerBar {
+	b := &TimerBar{}
+	b.task = widget.NewButtonWithIcon("", theme.HistoryIcon(), func() {
+		if b.OnOpen != nil {
+			b.OnOpen()
+		}
+	})
+	b.task.Importance = widget.LowImportance
+	b.clock = widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Monospace: true})
+	b.ExtendBaseWidget(b)
+	b.Hide()
+	return b
+}
+
+// CreateRenderer implements fyne.Widget
+func (b *TimerBar) CreateRenderer() fyne.WidgetRenderer {
+	stop := widget.NewButtonWithIcon("Stop", theme.MediaStopIcon(), func() {
+		if b.OnStop != nil {
+			b.OnStop()
+		}
+	})
+	return widget.NewSimpleRenderer(container.NewBorder(widget.NewSeparator(), nil, nil, container.NewHBox(b.clock, stop), b.task))
+}
+
+// SetTimer shows timer, running on the task titled title, at now, or hides the bar when timer is nil
+func (b *TimerBar) SetTimer(timer *model.Timer, title string, now time.Time) {
+	b.timer = timer
+	if timer == nil {
+		b.Hide()
+		return
+	}
+	b.task.SetText(title)
+	b.Tick(now)
+	b.Show()
+}
+
+// Tick updates the elapsed time of the running timer to now
+func (b *TimerBar) Tick(now time.Time) {
+	if b.timer != nil {
+		b.clock.SetText(formatElapsed(b.timer.Elapsed(now)))
+	}
+}
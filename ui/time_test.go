@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"eldar/model"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimePanel(t *testing.T) {
+	test.NewTempApp(t)
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.Local)
+	p := NewTimePanel("ana")
+	test.WidgetRenderer(p)
+	var calls []string
+	p.OnStart = func() {
+		calls = append(calls, "start")
+	}
+	p.OnStop = func() {
+		calls = append(calls, "stop")
+	}
+	var added []time.Duration
+	p.OnAdd = func(day time.Time, spent time.Duration, note string) {
+		assert.Equal(t, 0, day.Hour())
+		assert.Equal(t, "Call with client", note)
+		added = append(added, spent)
+	}
+	var deleted []string
+	p.OnDelete = func(e model.TimeEntry) {
+		deleted = append(deleted, e.ID)
+	}
+	p.Name = func(username string) string {
+		return "Ben Kay"
+	}
+
+	assert.Equal(t, "No time tracked yet", p.list.Objects[0].(*widget.Label).Text)
+	p.SetEntries([]model.TimeEntry{
+		{ID: "e1", User: "ben", Start: start, End: start.Add(time.Hour)},
+		{ID: "e2", User: "ana", Start: start.Add(24 * time.Hour), End: start.Add(24*time.Hour + 30*time.Minute), Manual: true, Note: "Review"},
+	})
+	assert.Equal(t, "Total: 1h 30m", p.total.Text)
+	require.Len(t, p.list.Objects, 2)
+	// Only entries of the signed-in member can be deleted, most recent entries come first
+	test.Tap(findButton(t, p.list.Objects[0], ""))
+	assert.Equal(t, []string{"e2"}, deleted)
+	assert.Nil(t, findButtonOrNil(p.list.Objects[1], ""))
+
+	test.Tap(p.toggle)
+	p.SetTimer(&model.Timer{TaskID: "t1", Start: start}, start.Add(65*time.Minute+9*time.Second))
+	assert.Equal(t, "Stop timer", p.toggle.Text)
+	assert.Equal(t, "1:05:09", p.elapsed.Text)
+	test.Tap(p.toggle)
+	p.SetTimer(nil, start)
+	assert.Equal(t, "Start timer", p.toggle.Text)
+	assert.Equal(t, []string{"start", "stop"}, calls)
+
+	assert.Error(t, p.spent.Validator("soon"))
+	p.spent.SetText("soon")
+	p.note.SetText("Call with client")
+	p.addEntry()
+	assert.Empty(t, added)
+	p.spent.SetText("1h15m")
+	p.addEntry()
+	assert.Equal(t, []time.Duration{75 * time.Minute}, added)
+	assert.Empty(t, p.spent.Text)
+}
+
+func TestTimerBar(t *testing.T) {
+	test.NewTempApp(t)
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+	b := NewTimerBar()
+	test.WidgetRenderer(b)
+	assert.False(t, b.Visible())
+	var opened, stopped int
+	b.OnOpen = func() {
+		opened++
+	}
+	b.OnStop = func() {
+		stopped++
+	}
+
+	b.SetTimer(&model.Timer{TaskID: "t1", Start: start}, "Write report", start.Add(time.Minute))
+	assert.True(t, b.Visible())
+	assert.Equal(t, "Write report", b.task.Text)
+	assert.Equal(t, "0:01:00", b.clock.Text)
+	b.Tick(start.Add(2*time.Hour + 3*time.Second))
+	assert.Equal(t, "2:00:03", b.clock.Text)
+	test.Tap(b.task)
+	test.Tap(findButton(t, test.WidgetRenderer(b).Objects()[0], "Stop"))
+	assert.Equal(t, 1, opened)
+	assert.Equal(t, 1, stopped)
+
+	b.SetTimer(nil, "", start)
+	assert.False(t, b.Visible())
+}
@@ -0,0 +1,166 @@
+package ui
+
+import (
+	"slices"
+	"time"
+
+	"eldar/model"
+	"eldar/store"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Options of the time report filters that do not filter
+const (
+	everyone  = "Everyone"
+	allBoards = "All boards"
+)
+
+// TimeReportPage adds the time tracked on the tasks of every board up by member, board and week,
+// most recent week first, and can narrow it down to a member or a board. It reads from the local
+// cache, so the report works offline.
+type TimeReportPage struct {
+	widget.BaseWidget
+	// Name returns the name of a member shown in the report
+	Name func(username string) string
+
+	store    *store.Store
+	onExport func([]model.TimeTotal)
+
+	user     *widget.Select
+	board    *widget.Select
+	users    []string
+	boardIDs []string
+	list     *fyne.Container
+}
+
+// NewTimeReportPage creates the time report page.
+//
+// Parameters:
+//   - st: The cache the page is rendered from
+//   - onExport: A function to call with the totals shown when the Export button is tapped
+//
+// Returns:
+//   - A TimeReportPage, which must be reloaded with Reload after the cache changes
+func NewTimeReportPage(st *store.Store, onExport func([]model.TimeTotal)) *TimeReportPage {
+	p := &TimeReportPage{store: st, onExport: onExport, list: container.NewVBox()}
+	p.user = widget.NewSelect(nil, func(string) {
+		p.showTotals()
+	})
+	p.board = widget.NewSelect(nil, func(string) {
+		p.showTotals()
+	})
+	p.ExtendBaseWidget(p)
+	p.Reload()
+	return p
+}
+
+// CreateRenderer implements fyne.Widget
+func (p *TimeReportPage) CreateRenderer() fyne.WidgetRenderer {
+	export := widget.NewButtonWithIcon("Export CSV...", theme.DocumentSaveIcon(), func() {
+		if p.onExport != nil {
+			p.onExport(p.Totals())
+		}
+	})
+	top := container.NewBorder(nil, nil, container.NewHBox(p.user, p.board), export)
+	return widget.NewSimpleRenderer(container.NewBorder(top, nil, nil, nil, container.NewVScroll(p.list)))
+}
+
+// Reload rebuilds the page from the cache, keeping the member and board picked when they still have time
+func (p *TimeReportPage) Reload() {
+	user, boardID := p.selectedUser(), p.selectedBoard()
+	p.users, p.boardIDs = nil, nil
+	for _, e := range p.store.TimeEntries() {
+		if !slices.Contains(p.users, e.User) {
+			p.users = append(p.users, e.User)
+		}
+		if !slices.Contains(p.boardIDs, e.BoardID) {
+			p.boardIDs = append(p.boardIDs, e.BoardID)
+		}
+	}
+	slices.Sort(p.users)
+	slices.Sort(p.boardIDs)
+
+	users := []string{everyone}
+	for _, u := range p.users {
+		users = append(users, p.name(u))
+	}
+	boards := []string{allBoards}
+	for _, id := range p.boardIDs {
+		b, _ := p.store.Board(id)
+		boards = append(boards, b.Name)
+	}
+	// Select fires OnChanged, so the totals are shown once both filters are set
+	onUser, onBoard := p.user.OnChanged, p.board.OnChanged
+	p.user.OnChanged, p.board.OnChanged = nil, nil
+	p.user.SetOptions(users)
+	p.user.SetSelectedIndex(slices.Index(p.users, user) + 1)
+	p.board.SetOptions(boards)
+	p.board.SetSelectedIndex(slices.Index(p.boardIDs, boardID) + 1)
+	p.user.OnChanged, p.board.OnChanged = onUser, onBoard
+	p.showTotals()
+}
+
+// Totals returns the totals shown, for the member and board picked
+func (p *TimeReportPage) Totals() []model.TimeTotal {
+	user, boardID := p.selectedUser(), p.selectedBoard()
+	var entries []model.TimeEntry
+	for _, e := range p.store.TimeEntries() {
+		if (user == "" || e.User == user) && (boardID == "" || e.BoardID == boardID) {
+			entries = append(entries, e)
+		}
+	}
+	return model.SumTime(entries, time.Local)
+}
+
+// showTotals lists the totals by week, each week headed by its date and total
+func (p *TimeReportPage) showTotals() {
+	var objects []fyne.CanvasObject
+	totals := p.Totals()
+	for i := 0; i < len(totals); {
+		week := totals[i].Week
+		var sum time.Duration
+		var rows []fyne.CanvasObject
+		for ; i < len(totals) && totals[i].Week.Equal(week); i++ {
+			sum += totals[i].Duration
+			b, _ := p.store.Board(totals[i].BoardID)
+			rows = append(rows, container.NewBorder(nil, nil, nil,
+				widget.NewLabel(model.FormatDuration(totals[i].Duration)),
+				widget.NewLabel(b.Name+" · "+p.name(totals[i].User))))
+		}
+		header := widget.NewLabelWithStyle("Week of "+week.Format("2 Jan 2006")+" · "+model.FormatDuration(sum),
+			fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+		objects = append(append(objects, header), rows...)
+	}
+	if len(objects) == 0 {
+		objects = append(objects, widget.NewLabel("No time tracked yet"))
+	}
+	p.list.Objects = objects
+	p.list.Refresh()
+}
+
+// selectedUser returns the member picked, or an empty string for everyone
+func (p *TimeReportPage) selectedUser() string {
+	if i := p.user.SelectedIndex() - 1; i >= 0 && i < len(p.users) {
+		return p.users[i]
+	}
+	return ""
+}
+
+// selectedBoard returns the ID of the board picked, or an empty string for all boards
+func (p *TimeReportPage) selectedBoard() string {
+	if i := p.board.SelectedIndex() - 1; i >= 0 && i < len(p.boardIDs) {
+		return p.boardIDs[i]
+	}
+	return ""
+}
+
+// name returns the name of a member, or their username when Name is not set
+func (p *TimeReportPage) name(username string) string {
+	if p.Name == nil {
+		return username
+	}
+	return p.Name(username)
+}
@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"eldar/model"
+	"eldar/store"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeReportPage(t *testing.T) {
+	test.NewTempApp(t)
+	monday := time.Date(2025, 6, 2, 9, 0, 0, 0, time.Local)
+	nextMonday := monday.AddDate(0, 0, 7)
+	remote := &fakeRemote{
+		boards: []model.Board{{ID: "b1", Name: "Sprint"}, {ID: "b2", Name: "Support"}},
+		timeEntries: []model.TimeEntry{
+			{ID: "e1", TaskID: "t1", BoardID: "b1", User: "ana", Start: monday, End: monday.Add(time.Hour)},
+			{ID: "e2", TaskID: "t1", BoardID: "b1", User: "ana", Start: monday.Add(time.Hour), End: monday.Add(90 * time.Minute)},
+			{ID: "e3", TaskID: "t2", BoardID: "b2", User: "ben", Start: monday, End: monday.Add(2 * time.Hour)},
+			{ID: "e4", TaskID: "t1", BoardID: "b1", User: "ben", Start: nextMonday, End: nextMonday.Add(45 * time.Minute)},
+		},
+	}
+	st, err := store.Open(t.TempDir(), remote)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = st.Close()
+	})
+	require.NoError(t, st.Sync(context.Background()))
+
+	var exported []model.TimeTotal
+	p := NewTimeReportPage(st, func(totals []model.TimeTotal) {
+		exported = totals
+	})
+	p.Name = func(username string) string {
+		return map[string]string{"ana": "Ana Lima", "ben": "Ben Kay"}[username]
+	}
+	p.Reload()
+	test.WidgetRenderer(p)
+	texts := func() []string {
+		var texts []string
+		for _, o := range p.list.Objects {
+			switch o := o.(type) {
+			case *widget.Label:
+				texts = append(texts, o.Text)
+			case *fyne.Container:
+				texts = append(texts, o.Objects[0].(*widget.Label).Text+" "+o.Objects[1].(*widget.Label).Text)
+			}
+		}
+		return texts
+	}
+
+	assert.Equal(t, []string{everyone, "Ana Lima", "Ben Kay"}, p.user.Options)
+	assert.Equal(t, []string{allBoards, "Sprint", "Support"}, p.board.Options)
+	assert.Equal(t, []string{
+		"Week of 9 Jun 2025 · 45m",
+		"Sprint · Ben Kay 45m",
+		"Week of 2 Jun 2025 · 3h 30m",
+		"Sprint · Ana Lima 1h 30m",
+		"Support · Ben Kay 2h",
+	}, texts())
+
+	p.user.SetSelected("Ben Kay")
+	p.board.SetSelected("Support")
+	assert.Equal(t, []string{"Week of 2 Jun 2025 · 2h", "Support · Ben Kay 2h"}, texts())
+	// The filters are kept when the cache changes
+	p.Reload()
+	assert.Equal(t, "Ben Kay", p.user.Selected)
+	assert.Equal(t, "Support", p.board.Selected)
+
+	test.Tap(findButton(t, test.WidgetRenderer(p).Objects()[0].(*fyne.Container).Objects[1], "Export CSV..."))
+	require.Len(t, exported, 1)
+	assert.Equal(t, 2*time.Hour, exported[0].Duration)
+
+	p.user.SetSelected("Ana Lima")
+	assert.Equal(t, []string{"No time tracked yet"}, texts())
+}
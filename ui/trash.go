@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"eldar/model"
+	"eldar/store"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// TrashPage lists the deleted boards and tasks that can still be restored, most recently deleted first,
+// along with how long they are kept before the server purges them. Like the Boards page it reads
+// from the local cache, so the trash can be browsed offline.
+type TrashPage struct {
+	widget.BaseWidget
+	store        *store.Store
+	restoreBoard func(model.Board)
+	restoreTask  func(model.Task)
+
+	list *fyne.Container
+}
+
+// NewTrashPage creates the Trash page.
+//
+// Parameters:
+//   - st: The cache the page is rendered from
+//   - restoreBoard: A function to call when the Restore button of a board is tapped
+//   - restoreTask: A function to call when the Restore button of a task is tapped
+//
+// Returns:
+//   - A TrashPage, which must be reloaded with Reload after the cache changes
+func NewTrashPage(st *store.Store, restoreBoard func(model.Board), restoreTask func(model.Task)) *TrashPage {
+	p := &TrashPage{store: st, restoreBoard: restoreBoard, restoreTask: restoreTask}
+	p.list = container.NewVBox()
+	p.ExtendBaseWidget(p)
+	p.Reload()
+	return p
+}
+
+// CreateRenderer implements fyne.Widget
+func (p *TrashPage) CreateRenderer() fyne.WidgetRenderer {
+	note := widget.NewLabel(fmt.Sprintf("Deleted boards and tasks are kept for %d days before they are removed for good.",
+		int(model.TrashRetention.Hours()/24)))
+	note.Wrapping = fyne.TextWrapWord
+	return widget.NewSimpleRenderer(container.NewBorder(note, nil, nil, nil, container.NewVScroll(p.list)))
+}
+
+// Reload rebuilds the page from the cache
+func (p *TrashPage) Reload() {
+	trash := p.store.Trash()
+	now := time.Now()
+	var objects []fyne.CanvasObject
+	if len(trash.Boards) > 0 {
+		objects = append(objects, widget.NewLabelWithStyle("Boards", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+		for _, b := range trash.Boards {
+			objects = append(objects, trashRow(b.Name, "Board", b.DeletedAt, now, func() {
+				p.restoreBoard(b)
+			}))
+		}
+	}
+	if len(trash.Tasks) > 0 {
+		objects = append(objects, widget.NewLabelWithStyle("Tasks", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+		for _, t := range trash.Tasks {
+			board, _ := p.store.Board(t.BoardID)
+			objects = append(objects, trashRow(t.Title, "On "+board.Name, t.DeletedAt, now, func() {
+				p.restoreTask(t)
+			}))
+		}
+	}
+	if len(objects) == 0 {
+		objects = append(objects, widget.NewLabel("The trash is empty"))
+	}
+	p.list.Objects = objects
+	p.list.Refresh()
+}
+
+// trashRow creates the row of a deleted item, with when it was deleted and a Restore button
+func trashRow(name, detail string, deletedAt *time.Time, now time.Time, restore func()) fyne.CanvasObject {
+	title := widget.NewLabel(name)
+	title.Truncation = fyne.TextTruncateEllipsis
+	if deletedAt != nil {
+		detail += " · deleted " + deletedAt.Local().Format("2 Jan 15:04") + " · " + purgeIn(*deletedAt, now)
+	}
+	caption := widget.NewLabel(detail)
+	caption.SizeName = theme.SizeNameCaptionText
+	button := widget.NewButtonWithIcon("Restore", theme.ContentUndoIcon(), restore)
+	return container.NewBorder(nil, nil, nil, button, container.NewVBox(title, caption))
+}
+
+// purgeIn describes how long until an item deleted at deletedAt is removed from the trash
+func purgeIn(deletedAt, now time.Time) string {
+	days := int(math.Ceil(model.PurgeAt(deletedAt).Sub(now).Hours() / 24))
+	if days <= 1 {
+		return "removed within a day"
+	}
+	return fmt.Sprintf("removed in %d days", days)
+}
@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"eldar/model"
+	"eldar/store"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrashPage(t *testing.T) {
+	test.NewTempApp(t)
+	deleted := time.Now().Add(-48 * time.Hour)
+	remote := &fakeRemote{
+		boards:        []model.Board{{ID: "b1", Name: "Sprint"}},
+		trashedBoards: []model.Board{{ID: "b2", Name: "Launch", DeletedAt: &deleted}},
+		trashedTasks:  []model.Task{{ID: "t1", BoardID: "b1", Title: "Write report", DeletedAt: &deleted}},
+	}
+	st, err := store.Open(t.TempDir(), remote)
+	require.NoError(t, err)
+	defer func() {
+		_ = st.Close()
+	}()
+
+	var restored []string
+	page := NewTrashPage(st, func(b model.Board) {
+		restored = append(restored, b.ID)
+	}, func(task model.Task) {
+		restored = append(restored, task.ID)
+	})
+	test.WidgetRenderer(page)
+	assert.Equal(t, "The trash is empty", page.list.Objects[0].(*widget.Label).Text)
+
+	require.NoError(t, st.Sync(context.Background()))
+	page.Reload()
+	require.Len(t, page.list.Objects, 4)
+	assert.Equal(t, "Boards", page.list.Objects[0].(*widget.Label).Text)
+	assert.Equal(t, "Tasks", page.list.Objects[2].(*widget.Label).Text)
+	task := page.list.Objects[3].(*fyne.Container)
+	details := task.Objects[0].(*fyne.Container).Objects
+	assert.Equal(t, "Write report", details[0].(*widget.Label).Text)
+	assert.Contains(t, details[1].(*widget.Label).Text, "On Sprint · deleted ")
+	assert.Contains(t, details[1].(*widget.Label).Text, "removed in 28 days")
+
+	test.Tap(findButton(t, page.list.Objects[1], "Restore"))
+	test.Tap(findButton(t, task, "Restore"))
+	assert.Equal(t, []string{"b2", "t1"}, restored)
+}
+
+func TestPurgeIn(t *testing.T) {
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, "removed in 30 days", purgeIn(now, now))
+	assert.Equal(t, "removed in 2 days", purgeIn(now.Add(-model.TrashRetention+36*time.Hour), now))
+	assert.Equal(t, "removed within a day", purgeIn(now.Add(-model.TrashRetention+time.Hour), now))
+	assert.Equal(t, "removed within a day", purgeIn(now.Add(-model.TrashRetention-time.Hour), now))
+}
@@ -0,0 +1,186 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+
+	"eldar/model"
+	"eldar/search"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ViewsSidebar lists the built-in and saved views next to the Boards page.
+// Selecting a view shows the tasks matching its query, the views of the signed in account can be
+// edited and deleted, and views shared by other members of the group are marked with their owner.
+type ViewsSidebar struct {
+	widget.BaseWidget
+	// Me is the username of the signed in account, which owns the views it can change
+	Me       string
+	OnSelect func(model.View)
+	OnEdit   func(model.View)
+	OnDelete func(model.View)
+
+	views    []model.View
+	selected string
+	list     *fyne.Container
+	save     *widget.Button
+}
+
+// NewViewsSidebar creates the views sidebar.
+//
+// Parameters:
+//   - me: The username of the signed in account
+//   - onSelect: A function to call when a view is selected
+//   - onSaveSearch: A function to call when the current search should be saved as a new view
+//
+// Returns:
+//   - A ViewsSidebar, whose views are set with SetViews
+func NewViewsSidebar(me string, onSelect func(model.View), onSaveSearch func()) *ViewsSidebar {
+	s := &ViewsSidebar{Me: me, OnSelect: onSelect}
+	s.list = container.NewVBox()
+	s.save = widget.NewButtonWithIcon("Save search...", theme.ContentAddIcon(), onSaveSearch)
+	s.ExtendBaseWidget(s)
+	return s
+}
+
+// CreateRenderer implements fyne.Widget
+func (s *ViewsSidebar) CreateRenderer() fyne.WidgetRenderer {
+	header := widget.NewLabelWithStyle("Views", fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	return widget.NewSimpleRenderer(container.NewBorder(header, s.save, nil, nil, container.NewVScroll(s.list)))
+}
+
+// SetViews replaces the listed views, keeping the selection when the selected view still exists
+func (s *ViewsSidebar) SetViews(views []model.View) {
+	s.views = append([]model.View{}, views...)
+	s.rebuild()
+}
+
+// Select marks the view with the given ID as selected and calls OnSelect, an empty ID clears the selection
+func (s *ViewsSidebar) Select(id string) {
+	s.selected = id
+	s.rebuild()
+	if s.OnSelect == nil {
+		return
+	}
+	for _, v := range s.views {
+		if v.ID == id {
+			s.OnSelect(v)
+			return
+		}
+	}
+}
+
+// Selected returns the ID of the selected view, or an empty string
+func (s *ViewsSidebar) Selected() string {
+	return s.selected
+}
+
+// rebuild recreates a row for every view
+func (s *ViewsSidebar) rebuild() {
+	objects := make([]fyne.CanvasObject, 0, len(s.views))
+	found := false
+	for _, v := range s.views {
+		objects = append(objects, s.makeRow(v))
+		found = found || v.ID == s.selected
+	}
+	if !found {
+		s.selected = ""
+	}
+	s.list.Objects = objects
+	s.list.Refresh()
+}
+
+// makeRow renders a view as a button selecting it, followed by edit and delete buttons when it can be changed
+func (s *ViewsSidebar) makeRow(v model.View) fyne.CanvasObject {
+	name := widget.NewButton(viewLabel(v, s.Me), func() {
+		s.Select(v.ID)
+	})
+	name.Alignment = widget.ButtonAlignLeading
+	if v.ID == s.selected {
+		name.Importance = widget.HighImportance
+	} else {
+		name.Importance = widget.LowImportance
+	}
+	if v.Builtin() || v.Owner != s.Me {
+		return name
+	}
+	edit := widget.NewButtonWithIcon("", theme.DocumentCreateIcon(), func() {
+		if s.OnEdit != nil {
+			s.OnEdit(v)
+		}
+	})
+	remove := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+		if s.OnDelete != nil {
+			s.OnDelete(v)
+		}
+	})
+	return container.NewBorder(nil, nil, nil, container.NewHBox(edit, remove), name)
+}
+
+// viewLabel describes a view in the sidebar, naming the owner of views shared by someone else
+func viewLabel(v model.View, me string) string {
+	switch {
+	case v.Builtin() || v.Owner == me:
+		return v.Name
+	case v.Owner != "":
+		return v.Name + " (" + v.Owner + ")"
+	}
+	return v.Name
+}
+
+// MakeViewForm creates a form to save a search as a view or to edit a saved view.
+//
+// Parameters:
+//   - view: The view to edit, a view without ID is created
+//   - onSave: A function to call with the edited view when the form is submitted
+//
+// Returns:
+//   - A configured widget.Form ready to be displayed
+func MakeViewForm(view model.View, onSave func(model.View)) *widget.Form {
+	form := widget.NewForm()
+
+	name := widget.NewEntry()
+	name.SetText(view.Name)
+	name.SetPlaceHolder("My open bugs")
+	name.Validator = func(s string) error {
+		if strings.TrimSpace(s) == "" {
+			return errors.New("name must not be empty")
+		}
+		return nil
+	}
+	form.AppendItem(widget.NewFormItem("Name", name))
+
+	query := widget.NewEntry()
+	query.SetText(view.Query)
+	query.SetPlaceHolder("label:bug assignee:me is:open")
+	query.Validator = func(s string) error {
+		q, err := search.Parse(s)
+		if err != nil {
+			return err
+		}
+		if q.Empty() {
+			return errors.New("query must not be empty")
+		}
+		return nil
+	}
+	queryItem := widget.NewFormItem("Query", query)
+	queryItem.HintText = "Words and filters such as assignee:me, label:bug, due:<7d or is:open"
+	form.AppendItem(queryItem)
+
+	shared := widget.NewCheck("Share with my group", nil)
+	shared.SetChecked(view.Shared)
+	form.AppendItem(widget.NewFormItem("Sharing", shared))
+
+	form.SubmitText = "Save"
+	form.OnSubmit = func() {
+		edited := view
+		edited.Name = strings.TrimSpace(name.Text)
+		edited.Query = strings.TrimSpace(query.Text)
+		edited.Shared = shared.Checked
+		onSave(edited)
+	}
+	return form
+}
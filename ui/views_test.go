@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"testing"
+
+	"eldar/model"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestViewsSidebar(t *testing.T) {
+	test.NewTempApp(t)
+	var selected, edited, deleted []string
+	sidebar := NewViewsSidebar("ana", func(v model.View) {
+		selected = append(selected, v.Query)
+	}, nil)
+	sidebar.OnEdit = func(v model.View) {
+		edited = append(edited, v.ID)
+	}
+	sidebar.OnDelete = func(v model.View) {
+		deleted = append(deleted, v.ID)
+	}
+	test.WidgetRenderer(sidebar)
+	sidebar.SetViews(append(append([]model.View{}, model.BuiltinViews[:1]...),
+		model.View{ID: "v1", Name: "My open bugs", Query: "label:bug assignee:me is:open", Owner: "ana"},
+		model.View{ID: "v2", Name: "Release", Query: "label:release", Owner: "ben", Shared: true},
+	))
+
+	rows := sidebar.list.Objects
+	require.Len(t, rows, 3)
+	// Only the views of the signed in account can be changed
+	assert.IsType(t, &widget.Button{}, rows[0])
+	assert.Equal(t, "Release (ben)", rows[2].(*widget.Button).Text)
+	actions := rows[1].(*fyne.Container).Objects[1].(*fyne.Container).Objects
+	test.Tap(actions[0].(*widget.Button))
+	test.Tap(actions[1].(*widget.Button))
+	assert.Equal(t, []string{"v1"}, edited)
+	assert.Equal(t, []string{"v1"}, deleted)
+
+	test.Tap(findButton(t, sidebar.list, "My open bugs"))
+	assert.Equal(t, []string{"label:bug assignee:me is:open"}, selected)
+	assert.Equal(t, "v1", sidebar.Selected())
+	assert.Equal(t, widget.HighImportance, findButton(t, sidebar.list, "My open bugs").Importance)
+
+	// The selection is kept while the view exists
+	sidebar.SetViews(sidebar.views)
+	assert.Equal(t, "v1", sidebar.Selected())
+	sidebar.SetViews(model.BuiltinViews)
+	assert.Empty(t, sidebar.Selected())
+}
+
+func TestMakeViewForm(t *testing.T) {
+	test.NewTempApp(t)
+	var saved model.View
+	form := MakeViewForm(model.View{Query: "label:bug"}, func(v model.View) {
+		saved = v
+	})
+	test.WidgetRenderer(form)
+	name := form.Items[0].Widget.(*widget.Entry)
+	query := form.Items[1].Widget.(*widget.Entry)
+	shared := form.Items[2].Widget.(*widget.Check)
+
+	assert.Error(t, name.Validate())
+	test.Type(name, " My open bugs ")
+	query.SetText("label:bug is:closed")
+	assert.ErrorContains(t, query.Validate(), "unknown filter")
+	query.SetText("label:bug assignee:me is:open")
+	assert.NoError(t, query.Validate())
+	shared.SetChecked(true)
+
+	form.OnSubmit()
+	assert.Equal(t, model.View{Name: "My open bugs", Query: "label:bug assignee:me is:open", Shared: true}, saved)
+}
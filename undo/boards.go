@@ -0,0 +1,147 @@
+package undo
+
+import (
+	"context"
+	"errors"
+
+	"eldar/model"
+	"eldar/store"
+)
+
+// UpdateBoard is a Command replacing the settings of a board with an edited copy, undone by restoring the original
+type UpdateBoard struct {
+	Store *store.Store
+	// Label is the name of the command, such as "Change swimlanes"
+	Label         string
+	Before, After model.Board
+
+	tracking
+	// applied is set once the edit was made, from then on a step needs the board as the history last left it
+	applied bool
+}
+
+// Name implements Command
+func (c *UpdateBoard) Name() string {
+	return c.Label
+}
+
+// Do implements Command
+func (c *UpdateBoard) Do(ctx context.Context) error {
+	return c.apply(ctx, c.After)
+}
+
+// Undo implements Command
+func (c *UpdateBoard) Undo(ctx context.Context) error {
+	return c.apply(ctx, c.Before)
+}
+
+// apply replaces the cached board with target, checking first that nobody changed it since the history last did
+func (c *UpdateBoard) apply(ctx context.Context, target model.Board) error {
+	current, ok := c.Store.Board(target.ID)
+	if !ok {
+		return ErrConflict
+	}
+	// The first Do sends the version the edit was made on, so the server rejects it when that is outdated
+	if c.applied {
+		if version, ok := c.version(boardKey(target.ID)); !ok || current.Version != version {
+			return ErrConflict
+		}
+		target.Version = current.Version
+	}
+	// Archiving is a command of its own, an edit of the settings leaves it as it is
+	target.ArchivedAt = current.ArchivedAt
+	if err := c.Store.UpdateBoard(ctx, target); err != nil {
+		return err
+	}
+	updated, _ := c.Store.Board(target.ID)
+	c.left(boardKey(target.ID), updated.Version)
+	c.applied = true
+	return nil
+}
+
+// ArchiveBoard is a Command archiving or unarchiving a board, undone by the opposite change
+type ArchiveBoard struct {
+	Store   *store.Store
+	BoardID string
+	// Archive is true to archive the board and false to unarchive it
+	Archive bool
+
+	tracking
+}
+
+// Name implements Command
+func (c *ArchiveBoard) Name() string {
+	if c.Archive {
+		return "Archive board"
+	}
+	return "Unarchive board"
+}
+
+// Do implements Command
+func (c *ArchiveBoard) Do(ctx context.Context) error {
+	return c.apply(ctx, c.Archive)
+}
+
+// Undo implements Command
+func (c *ArchiveBoard) Undo(ctx context.Context) error {
+	return c.apply(ctx, !c.Archive)
+}
+
+// apply archives or unarchives the board, unless it was deleted or already changed elsewhere
+func (c *ArchiveBoard) apply(ctx context.Context, archived bool) error {
+	current, ok := c.Store.Board(c.BoardID)
+	if !ok || current.Archived() == archived {
+		return ErrConflict
+	}
+	if err := c.Store.ArchiveBoard(ctx, c.BoardID, archived); err != nil {
+		return err
+	}
+	// Archiving changes the version of the board, which edits of its settings earlier in the history check
+	updated, _ := c.Store.Board(c.BoardID)
+	c.left(boardKey(c.BoardID), updated.Version)
+	return nil
+}
+
+// DeleteBoard is a Command moving a board and its tasks to the trash, undone by restoring them from there
+type DeleteBoard struct {
+	Store *store.Store
+	Board model.Board
+
+	tracking
+	// undone is set once the deletion was undone, from then on a redo needs the board as the history last left it
+	undone bool
+}
+
+// Name implements Command
+func (c *DeleteBoard) Name() string {
+	return "Delete board"
+}
+
+// Do implements Command
+func (c *DeleteBoard) Do(ctx context.Context) error {
+	if c.undone {
+		current, ok := c.Store.Board(c.Board.ID)
+		if !ok {
+			return ErrConflict
+		}
+		if version, ok := c.version(boardKey(c.Board.ID)); !ok || current.Version != version {
+			return ErrConflict
+		}
+	}
+	return c.Store.DeleteBoard(ctx, c.Board.ID)
+}
+
+// Undo implements Command
+func (c *DeleteBoard) Undo(ctx context.Context) error {
+	if err := c.Store.RestoreBoard(ctx, c.Board.ID); err != nil {
+		// The board was restored elsewhere or purged from the trash in the meantime
+		if errors.Is(err, store.ErrNotFound) {
+			return ErrConflict
+		}
+		return err
+	}
+	restored, _ := c.Store.Board(c.Board.ID)
+	c.left(boardKey(restored.ID), restored.Version)
+	c.undone = true
+	return nil
+}
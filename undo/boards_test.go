@@ -0,0 +1,122 @@
+package undo
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"eldar/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateBoard(t *testing.T) {
+	ctx := context.Background()
+	st, remote := openStore(t)
+	h := New(DefaultLimit)
+	before, _ := st.Board("b1")
+	after := before
+	after.Swimlanes = model.SwimlanesAssignee
+
+	require.NoError(t, h.Run(ctx, "b1", &UpdateBoard{Store: st, Label: "Change swimlanes", Before: before, After: after}))
+	assert.Equal(t, "Change swimlanes", h.UndoName("b1"))
+	_, err := h.Undo(ctx, "b1")
+	require.NoError(t, err)
+	board, _ := st.Board("b1")
+	assert.Equal(t, model.SwimlanesNone, board.Swimlanes)
+	_, err = h.Redo(ctx, "b1")
+	require.NoError(t, err)
+	board, _ = st.Board("b1")
+	assert.Equal(t, model.SwimlanesAssignee, board.Swimlanes)
+
+	// Someone else renames the board, so the change can no longer be undone
+	remote.mu.Lock()
+	remote.board.Name, remote.board.Version = "Sprint 2", remote.board.Version+1
+	remote.mu.Unlock()
+	require.NoError(t, st.Sync(ctx))
+	_, err = h.Undo(ctx, "b1")
+	assert.ErrorIs(t, err, ErrConflict)
+	board, _ = st.Board("b1")
+	assert.Equal(t, model.SwimlanesAssignee, board.Swimlanes)
+}
+
+func TestUpdateBoardSeveralSteps(t *testing.T) {
+	ctx := context.Background()
+	st, _ := openStore(t)
+	h := New(DefaultLimit)
+	edit := func(label string, change func(*model.Board)) {
+		before, _ := st.Board("b1")
+		after := before
+		change(&after)
+		require.NoError(t, h.Run(ctx, "b1", &UpdateBoard{Store: st, Label: label, Before: before, After: after}))
+	}
+	edit("Change swimlanes", func(board *model.Board) { board.Swimlanes = model.SwimlanesAssignee })
+	require.NoError(t, h.Run(ctx, "b1", &ArchiveBoard{Store: st, BoardID: "b1", Archive: true}))
+	edit("Rename board", func(board *model.Board) { board.Name = "Sprint 2" })
+	state := func() string {
+		board, _ := st.Board("b1")
+		return fmt.Sprintf("%s %v %v", board.Name, board.Swimlanes == model.SwimlanesAssignee, board.Archived())
+	}
+
+	// Each command checks the board against the version the more recent ones left it at
+	for _, want := range []string{"Sprint true true", "Sprint true false", "Sprint false false"} {
+		_, err := h.Undo(ctx, "b1")
+		require.NoError(t, err)
+		assert.Equal(t, want, state())
+	}
+	for _, want := range []string{"Sprint true false", "Sprint true true", "Sprint 2 true true"} {
+		_, err := h.Redo(ctx, "b1")
+		require.NoError(t, err)
+		assert.Equal(t, want, state())
+	}
+	assert.Equal(t, "Rename board", h.UndoName("b1"))
+}
+
+func TestArchiveBoard(t *testing.T) {
+	ctx := context.Background()
+	st, _ := openStore(t)
+	h := New(DefaultLimit)
+
+	require.NoError(t, h.Run(ctx, "b1", &ArchiveBoard{Store: st, BoardID: "b1", Archive: true}))
+	assert.Equal(t, "Archive board", h.UndoName(""))
+	assert.Len(t, st.ArchivedBoards(), 1)
+	_, err := h.Undo(ctx, "")
+	require.NoError(t, err)
+	assert.Empty(t, st.ArchivedBoards())
+	_, err = h.Redo(ctx, "")
+	require.NoError(t, err)
+	assert.Len(t, st.ArchivedBoards(), 1)
+
+	// Once the board is unarchived from the archive page, archiving it can no longer be undone
+	require.NoError(t, st.ArchiveBoard(ctx, "b1", false))
+	_, err = h.Undo(ctx, "")
+	assert.ErrorIs(t, err, ErrConflict)
+	assert.Empty(t, h.UndoName("b1"))
+}
+
+func TestDeleteBoard(t *testing.T) {
+	ctx := context.Background()
+	st, _ := openStore(t)
+	h := New(DefaultLimit)
+	board, _ := st.Board("b1")
+
+	require.NoError(t, h.Run(ctx, "b1", &DeleteBoard{Store: st, Board: board}))
+	_, ok := st.Board("b1")
+	assert.False(t, ok)
+	assert.Empty(t, st.Tasks("b1"))
+	_, err := h.Undo(ctx, "")
+	require.NoError(t, err)
+	_, ok = st.Board("b1")
+	assert.True(t, ok)
+	assert.Len(t, st.Tasks("b1"), 1)
+	_, err = h.Redo(ctx, "")
+	require.NoError(t, err)
+	_, ok = st.Board("b1")
+	assert.False(t, ok)
+
+	// Once the board is restored from the trash page, the deletion can no longer be undone
+	require.NoError(t, st.RestoreBoard(ctx, "b1"))
+	_, err = h.Undo(ctx, "")
+	assert.ErrorIs(t, err, ErrConflict)
+	assert.Empty(t, h.UndoName("b1"))
+}
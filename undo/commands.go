@@ -0,0 +1,196 @@
+package undo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"eldar/model"
+	"eldar/store"
+)
+
+// UpdateTask is a Command replacing a task with an edited copy, undone by restoring the original
+type UpdateTask struct {
+	Store *store.Store
+	// Label is the name of the command, such as "Move task"
+	Label         string
+	Before, After model.Task
+
+	tracking
+	// applied is set once the edit was made, from then on a step needs the task as the history last left it
+	applied bool
+}
+
+// UpdatedTask returns an UpdateTask for an edit of before that was already made and left the task as after,
+// so it can be recorded with History.Record
+func UpdatedTask(st *store.Store, label string, before, after model.Task) *UpdateTask {
+	c := &UpdateTask{Store: st, Label: label, Before: before, After: after, applied: true}
+	c.left(taskKey(after.ID), after.Version)
+	return c
+}
+
+// Name implements Command
+func (c *UpdateTask) Name() string {
+	return c.Label
+}
+
+// Do implements Command
+func (c *UpdateTask) Do(ctx context.Context) error {
+	return c.apply(ctx, c.After)
+}
+
+// Undo implements Command
+func (c *UpdateTask) Undo(ctx context.Context) error {
+	return c.apply(ctx, c.Before)
+}
+
+// apply replaces the cached task with target, checking first that nobody changed it since the history last did
+func (c *UpdateTask) apply(ctx context.Context, target model.Task) error {
+	current, ok := c.Store.Task(target.ID)
+	if !ok {
+		return ErrConflict
+	}
+	// The first Do sends the version the edit was made on, so the server rejects it when that is outdated
+	if c.applied {
+		if version, ok := c.version(taskKey(target.ID)); !ok || current.Version != version {
+			return ErrConflict
+		}
+		target.Version = current.Version
+	}
+	if err := c.Store.UpdateTask(ctx, target); err != nil {
+		return err
+	}
+	updated, _ := c.Store.Task(target.ID)
+	c.left(taskKey(target.ID), updated.Version)
+	c.applied = true
+	return nil
+}
+
+// CreateTask is a Command creating a task, undone by deleting it again
+type CreateTask struct {
+	Store *store.Store
+	Task  model.Task
+
+	tracking
+	// created is the task as created by the last Do
+	created model.Task
+}
+
+// Name implements Command
+func (c *CreateTask) Name() string {
+	return "Create task"
+}
+
+// Do implements Command
+func (c *CreateTask) Do(ctx context.Context) error {
+	task := c.Task
+	task.ID, task.Version = c.created.ID, 0
+	created, err := c.Store.CreateTask(ctx, task)
+	if err != nil {
+		return err
+	}
+	c.created = created
+	c.left(taskKey(created.ID), created.Version)
+	return nil
+}
+
+// Undo implements Command
+func (c *CreateTask) Undo(ctx context.Context) error {
+	return deleteUnchanged(ctx, c.Store, &c.tracking, c.created.ID)
+}
+
+// DeleteTask is a Command moving a task to the trash, undone by restoring it from there
+type DeleteTask struct {
+	Store *store.Store
+	Task  model.Task
+
+	tracking
+	// undone is set once the deletion was undone, from then on a redo needs the task as the history last left it
+	undone bool
+}
+
+// Name implements Command
+func (c *DeleteTask) Name() string {
+	return "Delete task"
+}
+
+// Do implements Command
+func (c *DeleteTask) Do(ctx context.Context) error {
+	if !c.undone {
+		return c.Store.DeleteTask(ctx, c.Task.ID)
+	}
+	return deleteUnchanged(ctx, c.Store, &c.tracking, c.Task.ID)
+}
+
+// Undo implements Command
+func (c *DeleteTask) Undo(ctx context.Context) error {
+	if err := c.Store.RestoreTask(ctx, c.Task.ID); err != nil {
+		// The task was restored elsewhere or purged from the trash in the meantime
+		if errors.Is(err, store.ErrNotFound) {
+			return ErrConflict
+		}
+		return err
+	}
+	restored, _ := c.Store.Task(c.Task.ID)
+	c.left(taskKey(restored.ID), restored.Version)
+	c.undone = true
+	return nil
+}
+
+// deleteUnchanged deletes a task unless it was changed since the history last did
+func deleteUnchanged(ctx context.Context, st *store.Store, t *tracking, id string) error {
+	current, ok := st.Task(id)
+	if !ok {
+		return ErrConflict
+	}
+	if version, ok := t.version(taskKey(id)); !ok || current.Version != version {
+		return ErrConflict
+	}
+	if err := st.DeleteTask(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+	return nil
+}
+
+// Batch is a Command made of several commands undone and redone together, such as the tasks of a bulk edit.
+// Undo reverses them in the opposite order. A step stops at the first command that fails, leaving the
+// commands before it done or undone.
+type Batch struct {
+	// Label is the name of the command, such as "Move tasks"
+	Label    string
+	Commands []Command
+}
+
+// Name implements Command
+func (c *Batch) Name() string {
+	return c.Label
+}
+
+// Do implements Command
+func (c *Batch) Do(ctx context.Context) error {
+	for _, command := range c.Commands {
+		if err := command.Do(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Undo implements Command
+func (c *Batch) Undo(ctx context.Context) error {
+	for i := len(c.Commands) - 1; i >= 0; i-- {
+		if err := c.Commands[i].Undo(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// track implements tracker for the commands of the batch
+func (c *Batch) track(v *versions) {
+	for _, command := range c.Commands {
+		if t, ok := command.(tracker); ok {
+			t.track(v)
+		}
+	}
+}
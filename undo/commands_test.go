@@ -0,0 +1,352 @@
+package undo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"eldar/api"
+	"eldar/model"
+	"eldar/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRemote is an in-memory server for the board and task requests, the other requests are not used by these tests
+type fakeRemote struct {
+	store.Remote
+	mu      sync.Mutex
+	board   model.Board
+	tasks   map[string]model.Task
+	trash   map[string]model.Task
+	created int
+}
+
+func (f *fakeRemote) ListBoards(ctx context.Context) ([]model.Board, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.board.DeletedAt != nil {
+		return nil, nil
+	}
+	return []model.Board{f.board}, nil
+}
+
+func (f *fakeRemote) UpdateBoard(ctx context.Context, board model.Board) (*model.Board, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.board.Version != board.Version {
+		return nil, &api.Error{StatusCode: http.StatusConflict}
+	}
+	board.Version++
+	f.board = board
+	return &board, nil
+}
+
+func (f *fakeRemote) ArchiveBoard(ctx context.Context, boardID string, archived bool) (*model.Board, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.board.ArchivedAt = nil
+	if archived {
+		now := time.Now()
+		f.board.ArchivedAt = &now
+	}
+	f.board.Version++
+	board := f.board
+	return &board, nil
+}
+
+func (f *fakeRemote) DeleteBoard(ctx context.Context, boardID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := time.Now()
+	f.board.DeletedAt = &now
+	return nil
+}
+
+func (f *fakeRemote) RestoreBoard(ctx context.Context, boardID string) (*model.Board, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.board.DeletedAt = nil
+	f.board.Version++
+	board := f.board
+	return &board, nil
+}
+
+func (f *fakeRemote) ListTasks(ctx context.Context, boardID string) ([]model.Task, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var tasks []model.Task
+	for _, t := range f.tasks {
+		tasks = append(tasks, t)
+	}
+	return tasks, nil
+}
+
+func (f *fakeRemote) ListViews(ctx context.Context) ([]model.View, error) {
+	return nil, nil
+}
+
+func (f *fakeRemote) ListMembers(ctx context.Context) ([]model.Member, error) {
+	return nil, nil
+}
+
+func (f *fakeRemote) ListTrash(ctx context.Context) (*model.Trash, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var trash model.Trash
+	if f.board.DeletedAt != nil {
+		trash.Boards = append(trash.Boards, f.board)
+	}
+	for _, t := range f.trash {
+		trash.Tasks = append(trash.Tasks, t)
+	}
+	return &trash, nil
+}
+
+func (f *fakeRemote) ListTimeEntries(ctx context.Context, boardID string) ([]model.TimeEntry, error) {
+	return nil, nil
+}
+
+func (f *fakeRemote) ListTemplates(ctx context.Context) ([]model.Template, error) {
+	return nil, nil
+}
+
+func (f *fakeRemote) ListActivity(ctx context.Context, boardID string, after int64) ([]model.Activity, error) {
+	return nil, nil
+}
+
+func (f *fakeRemote) CreateTask(ctx context.Context, task model.Task) (*model.Task, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.created++
+	task.ID, task.Version = fmt.Sprintf("s%d", f.created), 1
+	f.tasks[task.ID] = task
+	return &task, nil
+}
+
+func (f *fakeRemote) UpdateTask(ctx context.Context, task model.Task) (*model.Task, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.tasks[task.ID].Version != task.Version {
+		return nil, &api.Error{StatusCode: http.StatusConflict}
+	}
+	task.Version++
+	f.tasks[task.ID] = task
+	return &task, nil
+}
+
+func (f *fakeRemote) DeleteTask(ctx context.Context, taskID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.trash[taskID] = f.tasks[taskID]
+	delete(f.tasks, taskID)
+	return nil
+}
+
+func (f *fakeRemote) RestoreTask(ctx context.Context, taskID string) (*model.Task, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	task := f.trash[taskID]
+	task.Version++
+	f.tasks[taskID] = task
+	delete(f.trash, taskID)
+	return &task, nil
+}
+
+// openStore opens a store synced with a server holding one task
+func openStore(t *testing.T) (*store.Store, *fakeRemote) {
+	remote := &fakeRemote{board: model.Board{
+		ID: "b1", Name: "Sprint", Columns: []model.Column{{ID: "todo", Name: "To do"}, {ID: "done", Name: "Done"}}, Version: 1,
+	}, tasks: map[string]model.Task{
+		"t1": {ID: "t1", BoardID: "b1", ColumnID: "todo", Title: "Write report", Version: 1},
+	}, trash: map[string]model.Task{}}
+	st, err := store.Open(t.TempDir(), remote)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = st.Close()
+	})
+	require.NoError(t, st.Sync(context.Background()))
+	return st, remote
+}
+
+func TestUpdateTask(t *testing.T) {
+	ctx := context.Background()
+	st, remote := openStore(t)
+	h := New(DefaultLimit)
+	before, _ := st.Task("t1")
+	after := before.Clone()
+	after.ColumnID = "done"
+
+	require.NoError(t, h.Run(ctx, "b1", &UpdateTask{Store: st, Label: "Move task", Before: before, After: after}))
+	assert.Equal(t, "Move task", h.UndoName("b1"))
+	_, err := h.Undo(ctx, "b1")
+	require.NoError(t, err)
+	task, _ := st.Task("t1")
+	assert.Equal(t, "todo", task.ColumnID)
+	assert.Equal(t, int64(3), task.Version)
+	_, err = h.Redo(ctx, "b1")
+	require.NoError(t, err)
+	task, _ = st.Task("t1")
+	assert.Equal(t, "done", task.ColumnID)
+
+	// Someone else edits the task, so the move can no longer be undone
+	remote.mu.Lock()
+	task.Title, task.Version = "Write final report", task.Version+1
+	remote.tasks["t1"] = task
+	remote.mu.Unlock()
+	require.NoError(t, st.Sync(ctx))
+	_, err = h.Undo(ctx, "b1")
+	assert.ErrorIs(t, err, ErrConflict)
+	task, _ = st.Task("t1")
+	assert.Equal(t, "done", task.ColumnID)
+	assert.Empty(t, h.UndoName("b1"))
+
+	// Edits made on an outdated copy are rejected rather than recorded
+	stale := before.Clone()
+	stale.Title = "Outdated"
+	err = h.Run(ctx, "b1", &UpdateTask{Store: st, Label: "Edit task", Before: before, After: stale})
+	assert.True(t, api.IsStatus(err, http.StatusConflict))
+	assert.Empty(t, h.UndoName("b1"))
+}
+
+func TestUpdateTaskSeveralSteps(t *testing.T) {
+	ctx := context.Background()
+	st, _ := openStore(t)
+	h := New(DefaultLimit)
+	edit := func(label string, change func(*model.Task)) {
+		before, _ := st.Task("t1")
+		after := before.Clone()
+		change(&after)
+		require.NoError(t, h.Run(ctx, "b1", &UpdateTask{Store: st, Label: label, Before: before, After: after}))
+	}
+	edit("Move task", func(task *model.Task) { task.ColumnID = "done" })
+	edit("Rename task", func(task *model.Task) { task.Title = "Write final report" })
+	state := func() [2]string {
+		task, _ := st.Task("t1")
+		return [2]string{task.ColumnID, task.Title}
+	}
+
+	// Each command checks the task against the version the more recent ones left it at
+	for _, want := range [][2]string{{"done", "Write report"}, {"todo", "Write report"}} {
+		_, err := h.Undo(ctx, "b1")
+		require.NoError(t, err)
+		assert.Equal(t, want, state())
+	}
+	for _, want := range [][2]string{{"done", "Write report"}, {"done", "Write final report"}} {
+		_, err := h.Redo(ctx, "b1")
+		require.NoError(t, err)
+		assert.Equal(t, want, state())
+	}
+	_, err := h.Undo(ctx, "b1")
+	require.NoError(t, err)
+	assert.Equal(t, "Move task", h.UndoName("b1"))
+
+	// A task edited after it was created, then deleted, is undone step by step
+	create := &CreateTask{Store: st, Task: model.Task{BoardID: "b1", ColumnID: "todo", Title: "Review"}}
+	require.NoError(t, h.Run(ctx, "b1", create))
+	before, _ := st.Task("s1")
+	after := before.Clone()
+	after.ColumnID = "done"
+	require.NoError(t, h.Run(ctx, "b1", &UpdateTask{Store: st, Label: "Move task", Before: before, After: after}))
+	after, _ = st.Task("s1")
+	require.NoError(t, h.Run(ctx, "b1", &DeleteTask{Store: st, Task: after}))
+	for _, name := range []string{"Delete task", "Move task", "Create task"} {
+		undone, err := h.Undo(ctx, "b1")
+		require.NoError(t, err)
+		assert.Equal(t, name, undone)
+	}
+	_, ok := st.Task("s1")
+	assert.False(t, ok)
+	assert.Equal(t, "Move task", h.UndoName("b1"))
+}
+
+func TestCreateAndDeleteTask(t *testing.T) {
+	ctx := context.Background()
+	st, _ := openStore(t)
+	h := New(DefaultLimit)
+
+	create := &CreateTask{Store: st, Task: model.Task{BoardID: "b1", ColumnID: "todo", Title: "Review"}}
+	require.NoError(t, h.Run(ctx, "b1", create))
+	assert.Len(t, st.Tasks("b1"), 2)
+	_, err := h.Undo(ctx, "b1")
+	require.NoError(t, err)
+	assert.Len(t, st.Tasks("b1"), 1)
+	_, err = h.Redo(ctx, "b1")
+	require.NoError(t, err)
+	assert.Len(t, st.Tasks("b1"), 2)
+
+	task, _ := st.Task("t1")
+	require.NoError(t, h.Run(ctx, "b1", &DeleteTask{Store: st, Task: task}))
+	_, ok := st.Task("t1")
+	assert.False(t, ok)
+	trashed := func() []string {
+		var ids []string
+		for _, task := range st.Trash().Tasks {
+			ids = append(ids, task.ID)
+		}
+		return ids
+	}
+	// Undoing the creation moved the first copy of the review to the trash as well
+	assert.ElementsMatch(t, []string{"s1", "t1"}, trashed())
+	_, err = h.Undo(ctx, "b1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"s1"}, trashed())
+	titles := func() []string {
+		var titles []string
+		for _, task := range st.Tasks("b1") {
+			titles = append(titles, task.Title)
+		}
+		return titles
+	}
+	assert.ElementsMatch(t, []string{"Write report", "Review"}, titles())
+	_, err = h.Redo(ctx, "b1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Review"}, titles())
+
+	// Once the task is restored from the trash page, the deletion can no longer be undone
+	require.NoError(t, st.RestoreTask(ctx, "t1"))
+	_, err = h.Undo(ctx, "b1")
+	assert.ErrorIs(t, err, ErrConflict)
+	assert.Empty(t, h.UndoName("b1"))
+}
+
+func TestBatch(t *testing.T) {
+	ctx := context.Background()
+	st, _ := openStore(t)
+	h := New(DefaultLimit)
+	require.NoError(t, h.Run(ctx, "b1", &CreateTask{Store: st, Task: model.Task{BoardID: "b1", ColumnID: "todo", Title: "Review"}}))
+
+	// A bulk edit made elsewhere is recorded afterwards, with the server copies of the tasks
+	var commands []Command
+	for _, before := range st.Tasks("b1") {
+		after := before.Clone()
+		after.ColumnID = "done"
+		require.NoError(t, st.UpdateTask(ctx, after))
+		after, _ = st.Task(before.ID)
+		commands = append(commands, UpdatedTask(st, "Move tasks", before, after))
+	}
+	h.Record("b1", &Batch{Label: "Move tasks", Commands: commands})
+	assert.Equal(t, "Move tasks", h.UndoName("b1"))
+
+	columns := func() []string {
+		var columns []string
+		for _, task := range st.Tasks("b1") {
+			columns = append(columns, task.ColumnID)
+		}
+		return columns
+	}
+	_, err := h.Undo(ctx, "b1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"todo", "todo"}, columns())
+	_, err = h.Redo(ctx, "b1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"done", "done"}, columns())
+
+	// The changes made before the batch come next
+	_, err = h.Undo(ctx, "b1")
+	require.NoError(t, err)
+	assert.Equal(t, "Create task", h.UndoName("b1"))
+}
@@ -0,0 +1,285 @@
+// Package undo keeps a per-board history of the changes made to the task cache so they can be undone and redone.
+// Each change is a Command that knows how to reverse itself with a compensating request to the server.
+// A command refuses to undo or redo over a change made by someone else, and the history of the board is then cleared.
+package undo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	"eldar/api"
+)
+
+// DefaultLimit is how many commands are kept per board
+const DefaultLimit = 50
+
+// ErrConflict is returned when a command cannot be undone or redone because the task or board changed in the meantime
+var ErrConflict = errors.New("the task or board was changed elsewhere, so this can no longer be undone")
+
+// ErrEmpty is returned by Undo and Redo when there is nothing to undo or redo
+var ErrEmpty = errors.New("nothing to undo or redo")
+
+// Command is a reversible change of the task cache
+type Command interface {
+	// Name describes the change, as in "Move task"
+	Name() string
+	// Do makes the change, it is called again to redo it after Undo
+	Do(ctx context.Context) error
+	// Undo reverses the change, returning ErrConflict when the task or board changed since
+	Undo(ctx context.Context) error
+}
+
+// stacks are the commands of a board that can be undone and redone, the most recent last
+type stacks struct {
+	undo, redo []Command
+	// seq orders the boards by their most recent command
+	seq int64
+}
+
+// versions are the versions of the tasks and boards as the commands of a history last left them, by taskKey or boardKey.
+// A command checks against them rather than against the version it left itself, which is outdated as soon as a more
+// recent command of the history changed the same task or board. It is safe for concurrent use.
+type versions struct {
+	mu sync.Mutex
+	m  map[string]int64
+}
+
+// get returns the version a task or board was last left at, and false when no command changed it
+func (v *versions) get(key string) (int64, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	version, ok := v.m[key]
+	return version, ok
+}
+
+// set records the version a command left a task or board at
+func (v *versions) set(key string, version int64) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.m == nil {
+		v.m = map[string]int64{}
+	}
+	v.m[key] = version
+}
+
+// taskKey returns the key of the version of a task
+func taskKey(id string) string {
+	return "task/" + id
+}
+
+// boardKey returns the key of the version of a board
+func boardKey(id string) string {
+	return "board/" + id
+}
+
+// tracker is implemented by the commands checking the versions of what they change, see versions
+type tracker interface {
+	// track makes the command share the versions of a history, adding those it already knows
+	track(v *versions)
+}
+
+// tracking is embedded in the commands that implement tracker. Until they are added to a history
+// they keep the versions they know to themselves.
+type tracking struct {
+	versions *versions
+}
+
+// track implements tracker
+func (t *tracking) track(v *versions) {
+	if t.versions != nil && t.versions != v {
+		t.versions.mu.Lock()
+		for key, version := range t.versions.m {
+			v.set(key, version)
+		}
+		t.versions.mu.Unlock()
+	}
+	t.versions = v
+}
+
+// version returns the version the history last left a task or board at, see versions.get
+func (t *tracking) version(key string) (int64, bool) {
+	if t.versions == nil {
+		return 0, false
+	}
+	return t.versions.get(key)
+}
+
+// left records the version a step left a task or board at
+func (t *tracking) left(key string, version int64) {
+	if t.versions == nil {
+		t.versions = &versions{}
+	}
+	t.versions.set(key, version)
+}
+
+// History is the undo history of every board. It is safe for concurrent use.
+type History struct {
+	mu       sync.Mutex
+	limit    int
+	seq      int64
+	boards   map[string]*stacks
+	versions *versions
+}
+
+// New creates an empty history keeping at most limit commands per board
+func New(limit int) *History {
+	return &History{limit: limit, boards: map[string]*stacks{}, versions: &versions{}}
+}
+
+// Run makes a change and adds it to the history of a board, clearing what could be redone there
+func (h *History) Run(ctx context.Context, boardID string, c Command) error {
+	if t, ok := c.(tracker); ok {
+		t.track(h.versions)
+	}
+	if err := c.Do(ctx); err != nil {
+		return err
+	}
+	h.Record(boardID, c)
+	return nil
+}
+
+// Record adds a change that was already made to the history of a board, like Run without calling Do
+func (h *History) Record(boardID string, c Command) {
+	if t, ok := c.(tracker); ok {
+		t.track(h.versions)
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s := h.stacks(boardID)
+	s.undo = append(s.undo, c)
+	if len(s.undo) > h.limit {
+		s.undo = s.undo[len(s.undo)-h.limit:]
+	}
+	s.redo = nil
+}
+
+// Undo reverses the most recent change of a board, or of the board changed most recently when boardID is empty.
+// The history of the board is cleared when the change conflicts with a change made elsewhere.
+//
+// Returns:
+//   - The name of the command that was undone
+//   - ErrEmpty when there is nothing to undo, ErrConflict on a conflict, or the error of the command
+func (h *History) Undo(ctx context.Context, boardID string) (string, error) {
+	return h.step(ctx, boardID, true)
+}
+
+// Redo makes the most recently undone change of a board again, see Undo
+func (h *History) Redo(ctx context.Context, boardID string) (string, error) {
+	return h.step(ctx, boardID, false)
+}
+
+// UndoName returns the name of the command Undo would reverse, or an empty string
+func (h *History) UndoName(boardID string) string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if s := h.boards[h.resolve(boardID)]; s != nil && len(s.undo) > 0 {
+		return s.undo[len(s.undo)-1].Name()
+	}
+	return ""
+}
+
+// RedoName returns the name of the command Redo would make again, or an empty string
+func (h *History) RedoName(boardID string) string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if s := h.boards[h.resolve(boardID)]; s != nil && len(s.redo) > 0 {
+		return s.redo[len(s.redo)-1].Name()
+	}
+	return ""
+}
+
+// Latest returns the board changed most recently, or an empty string when the history is empty
+func (h *History) Latest() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.resolve("")
+}
+
+// Clear forgets the history of a board, for example after someone else changed it
+func (h *History) Clear(boardID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.boards, boardID)
+}
+
+// step undoes or redoes the last command of a board. The command is taken off its stack while it runs,
+// so a second Undo does not run it twice, and put back when it failed for another reason than a conflict.
+func (h *History) step(ctx context.Context, boardID string, undo bool) (string, error) {
+	h.mu.Lock()
+	boardID = h.resolve(boardID)
+	s := h.boards[boardID]
+	from := func(s *stacks) *[]Command {
+		if undo {
+			return &s.undo
+		}
+		return &s.redo
+	}
+	if s == nil || len(*from(s)) == 0 {
+		h.mu.Unlock()
+		return "", ErrEmpty
+	}
+	stack := from(s)
+	c := (*stack)[len(*stack)-1]
+	*stack = (*stack)[:len(*stack)-1]
+	h.mu.Unlock()
+
+	var err error
+	if undo {
+		err = c.Undo(ctx)
+	} else {
+		err = c.Do(ctx)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s = h.boards[boardID]
+	switch {
+	case errors.Is(err, ErrConflict) || api.IsStatus(err, http.StatusConflict):
+		delete(h.boards, boardID)
+		return c.Name(), ErrConflict
+	case err != nil:
+		if s != nil {
+			*from(s) = append(*from(s), c)
+		}
+		return c.Name(), err
+	}
+	if s == nil {
+		// Cleared while the command ran
+		return c.Name(), nil
+	}
+	if undo {
+		s.redo = append(s.redo, c)
+	} else {
+		s.undo = append(s.undo, c)
+	}
+	return c.Name(), nil
+}
+
+// stacks returns the stacks of a board, creating them if needed, and marks the board as changed most recently.
+// The caller must hold h.mu.
+func (h *History) stacks(boardID string) *stacks {
+	s := h.boards[boardID]
+	if s == nil {
+		s = &stacks{}
+		h.boards[boardID] = s
+	}
+	h.seq++
+	s.seq = h.seq
+	return s
+}
+
+// resolve returns boardID, or the board changed most recently when it is empty. The caller must hold h.mu.
+func (h *History) resolve(boardID string) string {
+	if boardID != "" {
+		return boardID
+	}
+	var latest int64
+	for id, s := range h.boards {
+		if s.seq > latest {
+			boardID, latest = id, s.seq
+		}
+	}
+	return boardID
+}
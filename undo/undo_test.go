@@ -0,0 +1,117 @@
+package undo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// counter is a command adding to a value, failing with err while set
+type counter struct {
+	value *int
+	by    int
+	err   error
+}
+
+func (c *counter) Name() string {
+	return "Add"
+}
+
+func (c *counter) Do(ctx context.Context) error {
+	if c.err != nil {
+		return c.err
+	}
+	*c.value += c.by
+	return nil
+}
+
+func (c *counter) Undo(ctx context.Context) error {
+	if c.err != nil {
+		return c.err
+	}
+	*c.value -= c.by
+	return nil
+}
+
+func TestHistory(t *testing.T) {
+	ctx := context.Background()
+	h := New(2)
+	value := 0
+	require.NoError(t, h.Run(ctx, "b1", &counter{value: &value, by: 1}))
+	require.NoError(t, h.Run(ctx, "b1", &counter{value: &value, by: 10}))
+	require.NoError(t, h.Run(ctx, "b1", &counter{value: &value, by: 100}))
+	assert.Equal(t, 111, value)
+	assert.Equal(t, "Add", h.UndoName("b1"))
+	assert.Empty(t, h.RedoName("b1"))
+
+	// Only the last two commands are kept
+	for range 2 {
+		_, err := h.Undo(ctx, "b1")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, value)
+	_, err := h.Undo(ctx, "b1")
+	assert.ErrorIs(t, err, ErrEmpty)
+
+	name, err := h.Redo(ctx, "b1")
+	require.NoError(t, err)
+	assert.Equal(t, "Add", name)
+	assert.Equal(t, 11, value)
+
+	// A new command drops what could be redone
+	require.NoError(t, h.Run(ctx, "b1", &counter{value: &value, by: 1000}))
+	_, err = h.Redo(ctx, "b1")
+	assert.ErrorIs(t, err, ErrEmpty)
+
+	// Commands that fail are not recorded
+	assert.Error(t, h.Run(ctx, "b1", &counter{value: &value, err: errors.New("offline")}))
+	assert.Equal(t, 1011, value)
+}
+
+func TestHistoryPerBoard(t *testing.T) {
+	ctx := context.Background()
+	h := New(DefaultLimit)
+	a, b := 0, 0
+	require.NoError(t, h.Run(ctx, "b1", &counter{value: &a, by: 1}))
+	require.NoError(t, h.Run(ctx, "b2", &counter{value: &b, by: 1}))
+	assert.Equal(t, "b2", h.Latest())
+
+	// An empty board ID picks the board changed most recently
+	_, err := h.Undo(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, a)
+	assert.Equal(t, 0, b)
+	_, err = h.Undo(ctx, "b1")
+	require.NoError(t, err)
+	assert.Equal(t, 0, a)
+
+	h.Clear("b1")
+	_, err = h.Redo(ctx, "b1")
+	assert.ErrorIs(t, err, ErrEmpty)
+	assert.Equal(t, "Add", h.RedoName("b2"))
+}
+
+func TestHistoryFailures(t *testing.T) {
+	ctx := context.Background()
+	h := New(DefaultLimit)
+	value := 0
+	c := &counter{value: &value, by: 1}
+	require.NoError(t, h.Run(ctx, "b1", &counter{value: &value, by: 5}))
+	require.NoError(t, h.Run(ctx, "b1", c))
+
+	// A failed undo can be tried again
+	c.err = errors.New("offline")
+	_, err := h.Undo(ctx, "b1")
+	assert.Error(t, err)
+	assert.Equal(t, "Add", h.UndoName("b1"))
+
+	// A conflict clears the history of the board
+	c.err = ErrConflict
+	_, err = h.Undo(ctx, "b1")
+	assert.ErrorIs(t, err, ErrConflict)
+	assert.Empty(t, h.UndoName("b1"))
+	assert.Equal(t, 6, value)
+}
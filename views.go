@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"strings"
+
+	"eldar/model"
+	"eldar/store"
+	"eldar/ui"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+)
+
+// viewsSidebar lists the saved views of the signed-in account, it is nil while signed out
+var viewsSidebar *ui.ViewsSidebar
+
+// newViewsSidebar creates the views sidebar of the task cache st for the account username.
+// Selecting a view runs its query on the Boards page, and the sidebar follows the cache.
+func newViewsSidebar(st *store.Store, username string, page *ui.BoardsPage) *ui.ViewsSidebar {
+	sidebar := ui.NewViewsSidebar(username, func(view model.View) {
+		page.Search(view.Query)
+	}, func() {
+		showViewForm(model.View{Owner: username, Query: strings.TrimSpace(page.Query())})
+	})
+	sidebar.OnEdit = showViewForm
+	sidebar.OnDelete = func(view model.View) {
+		dialog.ShowConfirm("Delete view", "Delete the view \""+view.Name+"\"?", func(ok bool) {
+			if ok {
+				runTaskAction("deleting view", func(ctx context.Context) error {
+					return st.DeleteView(ctx, view.ID)
+				})
+			}
+		}, w)
+	}
+	// Typing another query in the search bar leaves the selected view
+	page.OnSearch = func(query string) {
+		if view, ok := st.View(sidebar.Selected()); ok && view.Query != query {
+			sidebar.Select("")
+		}
+	}
+	sidebar.SetViews(st.Views())
+	st.OnChange(func() {
+		fyne.Do(func() {
+			sidebar.SetViews(st.Views())
+		})
+	})
+	return sidebar
+}
+
+// showViewForm opens a dialog to save a new view, or to edit view when it has an ID
+func showViewForm(view model.View) {
+	st := taskStore
+	title := "Save search"
+	if view.ID != "" {
+		title = "Edit view"
+	}
+	var d dialog.Dialog
+	form := ui.MakeViewForm(view, func(edited model.View) {
+		d.Hide()
+		runTaskAction("saving view", func(ctx context.Context) error {
+			if edited.ID == "" {
+				_, err := st.CreateView(ctx, edited)
+				return err
+			}
+			return st.UpdateView(ctx, edited)
+		})
+	})
+	form.OnCancel = func() {
+		d.Hide()
+	}
+	d = dialog.NewCustomWithoutButtons(title, form, w)
+	d.Resize(fyne.NewSize(480, 0))
+	d.Show()
+}